@@ -0,0 +1,228 @@
+package logk
+
+import (
+	"io"
+	"sync"
+	"sync/atomic"
+
+	"github.com/go-konsultin/logk/level"
+	logkOption "github.com/go-konsultin/logk/option"
+)
+
+// Default async printer options
+const (
+	defaultAsyncQueueSize = 1024
+	defaultAsyncWorkers   = 1
+)
+
+type AsyncOptions struct {
+	// QueueSize limits number of entries waiting to be printed
+	QueueSize int
+	// Workers is number of background goroutines. Entries are printed in order only with a single worker
+	Workers int
+	// Overflow is applied when queue is full
+	Overflow OverflowPolicy
+	// OnOverflow is called when queue is full, before Overflow is applied. It is called on the logging goroutine,
+	// so it must be fast
+	OnOverflow func()
+}
+
+type AsyncOption = func(*AsyncOptions)
+
+func WithAsyncQueueSize(n int) AsyncOption {
+	return func(o *AsyncOptions) {
+		o.QueueSize = n
+	}
+}
+
+func WithAsyncWorkers(n int) AsyncOption {
+	return func(o *AsyncOptions) {
+		o.Workers = n
+	}
+}
+
+func WithAsyncOverflow(p OverflowPolicy) AsyncOption {
+	return func(o *AsyncOptions) {
+		o.Overflow = p
+	}
+}
+
+func WithAsyncOnOverflow(fn func()) AsyncOption {
+	return func(o *AsyncOptions) {
+		o.OnOverflow = fn
+	}
+}
+
+// AsyncPrinter queues entries into a bounded buffer and prints them with underlying printer from background
+// goroutines, so logging doesn't block on slow writers
+type AsyncPrinter struct {
+	printer Printer
+	options AsyncOptions
+	queue   chan asyncEntry
+	dropped atomic.Uint64
+	wg      sync.WaitGroup
+
+	// mu guards pending and closed
+	mu      sync.Mutex
+	cond    *sync.Cond
+	pending int
+	closed  bool
+}
+
+type asyncEntry struct {
+	namespace string
+	level     level.LogLevel
+	msg       string
+	options   *logkOption.Options
+}
+
+func NewAsyncPrinter(printer Printer, args ...AsyncOption) *AsyncPrinter {
+	o := AsyncOptions{
+		QueueSize: defaultAsyncQueueSize,
+		Workers:   defaultAsyncWorkers,
+	}
+	for _, fn := range args {
+		fn(&o)
+	}
+
+	if o.QueueSize < 0 {
+		o.QueueSize = 0
+	}
+
+	if o.Workers < 1 {
+		o.Workers = 1
+	}
+
+	// Init printer if nil
+	if printer == nil {
+		printer = NewStdLogPrinter(nil, 0)
+	}
+
+	p := AsyncPrinter{
+		printer: printer,
+		options: o,
+		queue:   make(chan asyncEntry, o.QueueSize),
+	}
+	p.cond = sync.NewCond(&p.mu)
+
+	// Start workers
+	p.wg.Add(o.Workers)
+	for i := 0; i < o.Workers; i++ {
+		go p.work()
+	}
+
+	return &p
+}
+
+func (p *AsyncPrinter) Print(namespace string, lv level.LogLevel, msg string, options *logkOption.Options) {
+	// Retain a copy, as options are reused by logger once Print returns. Stamp time on call, as entry is printed
+	// later
+	options = options.Clone()
+	stampTime(options)
+	e := asyncEntry{namespace: namespace, level: lv, msg: msg, options: options}
+
+	p.mu.Lock()
+	if p.closed {
+		p.mu.Unlock()
+		p.dropped.Add(1)
+		return
+	}
+	p.pending++
+	p.mu.Unlock()
+
+	// Try to queue without blocking
+	select {
+	case p.queue <- e:
+		return
+	default:
+	}
+
+	if p.options.OnOverflow != nil {
+		p.options.OnOverflow()
+	}
+
+	if p.options.Overflow == OverflowBlock {
+		p.queue <- e
+		return
+	}
+
+	p.dropped.Add(1)
+	p.done()
+}
+
+// QueueLen returns number of entries waiting to be printed. It reads without lock, so it reflects an instantaneous
+// view that may be stale
+func (p *AsyncPrinter) QueueLen() int {
+	return len(p.queue)
+}
+
+// QueueCap returns maximum number of entries waiting to be printed
+func (p *AsyncPrinter) QueueCap() int {
+	return cap(p.queue)
+}
+
+// Dropped returns number of entries that are discarded because queue is full or printer is closed
+func (p *AsyncPrinter) Dropped() uint64 {
+	return p.dropped.Load()
+}
+
+// Flush waits until queued entries are printed, then flushes underlying printer
+func (p *AsyncPrinter) Flush() error {
+	p.wait()
+	if f, ok := p.printer.(Flusher); ok {
+		return f.Flush()
+	}
+	return nil
+}
+
+// Close prints remaining entries, stops background goroutines and closes underlying printer.
+// Entries printed afterwards are dropped
+func (p *AsyncPrinter) Close() error {
+	p.mu.Lock()
+	if p.closed {
+		p.mu.Unlock()
+		return nil
+	}
+	p.closed = true
+	p.mu.Unlock()
+
+	p.wait()
+	close(p.queue)
+	p.wg.Wait()
+
+	var err error
+	if f, ok := p.printer.(Flusher); ok {
+		err = f.Flush()
+	}
+
+	if c, ok := p.printer.(io.Closer); ok {
+		if cErr := c.Close(); cErr != nil {
+			err = cErr
+		}
+	}
+	return err
+}
+
+func (p *AsyncPrinter) work() {
+	defer p.wg.Done()
+	for e := range p.queue {
+		p.printer.Print(e.namespace, e.level, e.msg, e.options)
+		p.done()
+	}
+}
+
+func (p *AsyncPrinter) done() {
+	p.mu.Lock()
+	p.pending--
+	p.cond.Broadcast()
+	p.mu.Unlock()
+}
+
+// wait blocks until there are no pending entries
+func (p *AsyncPrinter) wait() {
+	p.mu.Lock()
+	for p.pending > 0 {
+		p.cond.Wait()
+	}
+	p.mu.Unlock()
+}
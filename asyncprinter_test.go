@@ -0,0 +1,183 @@
+package logk
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/go-konsultin/logk/level"
+	logkOption "github.com/go-konsultin/logk/option"
+)
+
+// syncBuffer is a bytes.Buffer that can be written by concurrent printers
+type syncBuffer struct {
+	mu  sync.Mutex
+	buf bytes.Buffer
+}
+
+func (b *syncBuffer) Write(p []byte) (int, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.Write(p)
+}
+
+func (b *syncBuffer) Bytes() []byte {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return append([]byte(nil), b.buf.Bytes()...)
+}
+
+func TestAsyncPrinterRetainsPooledOptions(t *testing.T) {
+	const goroutines, entries = 8, 200
+
+	var buf syncBuffer
+	p := NewAsyncPrinter(NewJSONPrinter(&buf), WithAsyncQueueSize(goroutines*entries),
+		WithAsyncOverflow(OverflowBlock))
+	logger := NewStdLogger(p, logkOption.Level(level.Info))
+
+	var wg sync.WaitGroup
+	for g := 0; g < goroutines; g++ {
+		wg.Add(1)
+		go func(g int) {
+			defer wg.Done()
+			for i := 0; i < entries; i++ {
+				id := fmt.Sprintf("%d-%d", g, i)
+				logger.Info(id, logkOption.AddMetadata("id", id))
+				logger.Infof("%s", id)
+			}
+		}(g)
+	}
+	wg.Wait()
+	if err := logger.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	// Every entry keeps its own metadata and formatting arguments, although options are reused after Print
+	seen := make(map[string]int)
+	dec := json.NewDecoder(bytes.NewReader(buf.Bytes()))
+	for dec.More() {
+		var line map[string]interface{}
+		if err := dec.Decode(&line); err != nil {
+			t.Fatal(err)
+		}
+		msg, _ := line[logkOption.MessageKey].(string)
+		if metadata, ok := line[logkOption.MetadataKey].(map[string]interface{}); ok && metadata["id"] != msg {
+			t.Fatalf("entry %q has id %v", msg, metadata["id"])
+		}
+		seen[msg]++
+	}
+
+	if len(seen) != goroutines*entries {
+		t.Fatalf("distinct entries = %d, want %d", len(seen), goroutines*entries)
+	}
+	for msg, n := range seen {
+		if n != 2 {
+			t.Errorf("entry %q written %d times, want 2", msg, n)
+		}
+	}
+}
+
+// gatePrinter blocks printing until gate is closed, and reports start of the first print
+type gatePrinter struct {
+	gate    chan struct{}
+	started chan struct{}
+	once    sync.Once
+	printed atomic.Int32
+}
+
+func newGatePrinter() *gatePrinter {
+	return &gatePrinter{gate: make(chan struct{}), started: make(chan struct{})}
+}
+
+func (p *gatePrinter) Print(string, level.LogLevel, string, *logkOption.Options) {
+	p.once.Do(func() { close(p.started) })
+	<-p.gate
+	p.printed.Add(1)
+}
+
+func TestAsyncPrinterQueueAtCapacity(t *testing.T) {
+	const size = 4
+
+	gate := newGatePrinter()
+	var overflows atomic.Int32
+	p := NewAsyncPrinter(gate, WithAsyncQueueSize(size), WithAsyncWorkers(1),
+		WithAsyncOnOverflow(func() { overflows.Add(1) }))
+	options := logkOption.NewOptions()
+
+	// Worker holds the first entry, so the following ones stay in queue
+	p.Print("", level.Info, "held", options)
+	<-gate.started
+	for i := 0; i < size; i++ {
+		p.Print("", level.Info, "queued", options)
+	}
+
+	if p.QueueLen() != size || p.QueueCap() != size {
+		t.Fatalf("queue = %d/%d, want %d/%d", p.QueueLen(), p.QueueCap(), size, size)
+	}
+	if overflows.Load() != 0 || p.Dropped() != 0 {
+		t.Fatalf("overflows = %d, dropped = %d before queue is full", overflows.Load(), p.Dropped())
+	}
+
+	// Entries printed on full queue overflow and are dropped
+	p.Print("", level.Info, "dropped", options)
+	p.Print("", level.Info, "dropped", options)
+	if overflows.Load() != 2 || p.Dropped() != 2 {
+		t.Errorf("overflows = %d, dropped = %d, want 2", overflows.Load(), p.Dropped())
+	}
+
+	close(gate.gate)
+	if err := p.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if got := gate.printed.Load(); got != size+1 {
+		t.Errorf("printed = %d, want %d", got, size+1)
+	}
+	if p.QueueLen() != 0 {
+		t.Errorf("queue len = %d after close, want 0", p.QueueLen())
+	}
+}
+
+func TestAsyncPrinterQueueAtCapacityBlocks(t *testing.T) {
+	const size = 2
+
+	gate := newGatePrinter()
+	var overflows atomic.Int32
+	p := NewAsyncPrinter(gate, WithAsyncQueueSize(size), WithAsyncWorkers(1), WithAsyncOverflow(OverflowBlock),
+		WithAsyncOnOverflow(func() { overflows.Add(1) }))
+	options := logkOption.NewOptions()
+
+	p.Print("", level.Info, "held", options)
+	<-gate.started
+	for i := 0; i < size; i++ {
+		p.Print("", level.Info, "queued", options)
+	}
+
+	// Entry printed on full queue waits for free space
+	done := make(chan struct{})
+	go func() {
+		p.Print("", level.Info, "blocked", options)
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		t.Fatal("print on full queue returned before queue has free space")
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	close(gate.gate)
+	<-done
+	if err := p.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if overflows.Load() != 1 || p.Dropped() != 0 {
+		t.Errorf("overflows = %d, dropped = %d, want 1 and 0", overflows.Load(), p.Dropped())
+	}
+	if got := gate.printed.Load(); got != size+2 {
+		t.Errorf("printed = %d, want %d", got, size+2)
+	}
+}
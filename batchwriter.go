@@ -0,0 +1,203 @@
+package logk
+
+import (
+	"io"
+	"os"
+	"sync"
+	"time"
+)
+
+// Default batch writer options
+const (
+	defaultBatchMaxEntries = 256
+	defaultBatchMaxBytes   = 64 << 10
+	defaultBatchInterval   = time.Second
+)
+
+type BatchOptions struct {
+	// MaxEntries is number of buffered writes that are written out together
+	MaxEntries int
+	// MaxBytes is size of buffer, which is written out when next write doesn't fit in it. Larger writes are written
+	// directly
+	MaxBytes int
+	// Interval is maximum time a write is buffered. Zero keeps writes buffered until buffer is full or synced
+	Interval time.Duration
+}
+
+type BatchOption = func(*BatchOptions)
+
+func WithBatchMaxEntries(n int) BatchOption {
+	return func(o *BatchOptions) {
+		o.MaxEntries = n
+	}
+}
+
+func WithBatchMaxBytes(n int) BatchOption {
+	return func(o *BatchOptions) {
+		o.MaxBytes = n
+	}
+}
+
+func WithBatchInterval(d time.Duration) BatchOption {
+	return func(o *BatchOptions) {
+		o.Interval = d
+	}
+}
+
+// BatchWriter buffers encoded entries and writes them to underlying writer in a single call when MaxEntries are
+// buffered, MaxBytes is reached or Interval elapses since the first buffered entry, whichever comes first. It cuts
+// number of syscalls of high-volume services, at the cost of losing buffered entries on crash. Sync writes buffer
+// out, so entries are written on Flush and Close of logger
+type BatchWriter struct {
+	out     WriteSyncer
+	options BatchOptions
+
+	// mu guards fields below
+	mu      sync.Mutex
+	buf     []byte
+	entries int
+	timer   *time.Timer
+	// err is error of write from timer, which is returned by the next Write or Sync
+	err error
+}
+
+// NewBatchWriter creates BatchWriter that writes to w. If w is nil, entries are written to Stdout
+func NewBatchWriter(w io.Writer, args ...BatchOption) *BatchWriter {
+	o := BatchOptions{
+		MaxEntries: defaultBatchMaxEntries,
+		MaxBytes:   defaultBatchMaxBytes,
+		Interval:   defaultBatchInterval,
+	}
+	for _, fn := range args {
+		fn(&o)
+	}
+
+	if o.MaxEntries < 1 {
+		o.MaxEntries = 1
+	}
+
+	if o.MaxBytes < 1 {
+		o.MaxBytes = defaultBatchMaxBytes
+	}
+
+	if w == nil {
+		w = os.Stdout
+	}
+
+	return &BatchWriter{out: AddSync(w), options: o, buf: make([]byte, 0, o.MaxBytes)}
+}
+
+func (w *BatchWriter) Write(p []byte) (int, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	err := w.err
+	w.err = nil
+
+	// Write out buffer first if p doesn't fit in it
+	if len(w.buf) > 0 && len(w.buf)+len(p) > w.options.MaxBytes {
+		if fErr := w.flushLocked(); fErr != nil && err == nil {
+			err = fErr
+		}
+	}
+
+	if len(p) >= w.options.MaxBytes {
+		n, wErr := w.out.Write(p)
+		if wErr != nil {
+			err = wErr
+		}
+		return n, err
+	}
+
+	w.buf = append(w.buf, p...)
+	w.entries++
+
+	if w.entries >= w.options.MaxEntries || len(w.buf) >= w.options.MaxBytes {
+		if fErr := w.flushLocked(); fErr != nil && err == nil {
+			err = fErr
+		}
+	} else if w.timer == nil && w.options.Interval > 0 {
+		w.timer = time.AfterFunc(w.options.Interval, w.flushTimer)
+	}
+	return len(p), err
+}
+
+// Buffered returns size of entries that are not written out yet
+func (w *BatchWriter) Buffered() int {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return len(w.buf)
+}
+
+// Sync writes buffered entries out and syncs underlying writer
+func (w *BatchWriter) Sync() error {
+	w.mu.Lock()
+	err := w.err
+	w.err = nil
+	if fErr := w.flushLocked(); fErr != nil && err == nil {
+		err = fErr
+	}
+	w.mu.Unlock()
+
+	if sErr := w.out.Sync(); sErr != nil && err == nil {
+		err = sErr
+	}
+	return err
+}
+
+// Flush is Sync, so BatchWriter is a Flusher
+func (w *BatchWriter) Flush() error {
+	return w.Sync()
+}
+
+// Close writes buffered entries out and closes underlying writer if it implements io.Closer
+func (w *BatchWriter) Close() error {
+	err := w.Sync()
+	if c, ok := w.out.(io.Closer); ok {
+		if cErr := c.Close(); cErr != nil && err == nil {
+			err = cErr
+		}
+	}
+	return err
+}
+
+func (w *BatchWriter) flushTimer() {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if err := w.flushLocked(); err != nil {
+		w.err = err
+	}
+}
+
+func (w *BatchWriter) flushLocked() error {
+	if w.timer != nil {
+		w.timer.Stop()
+		w.timer = nil
+	}
+
+	if len(w.buf) == 0 {
+		return nil
+	}
+
+	_, err := w.out.Write(w.buf)
+	w.buf = w.buf[:0]
+	w.entries = 0
+	return err
+}
+
+// batchPrinter writes out BatchWriter of printer on Flush, as printers that write text don't sync their output
+type batchPrinter struct {
+	Printer
+	batch *BatchWriter
+}
+
+func (p *batchPrinter) Flush() error {
+	var err error
+	if f, ok := p.Printer.(Flusher); ok {
+		err = f.Flush()
+	}
+	if sErr := p.batch.Sync(); sErr != nil && err == nil {
+		err = sErr
+	}
+	return err
+}
@@ -0,0 +1,247 @@
+package logk
+
+import (
+	"io"
+	stdLog "log"
+	"maps"
+	"os"
+
+	"github.com/go-konsultin/logk/level"
+	logkOption "github.com/go-konsultin/logk/option"
+)
+
+// Builder composes a logger step by step, e.g.
+//
+//	logger := logk.New().
+//		Level(level.Info).
+//		Namespace("api").
+//		JSON().
+//		Output(os.Stderr).
+//		Sampling(level.Debug, 100).
+//		Caller().
+//		Build()
+//
+// Entries are written as text to Stdout unless format or output is set. Printers added with Printer receive entries
+// too, and replace the default text printer if neither format nor output is set
+type Builder struct {
+	level     level.LogLevel
+	namespace string
+
+	// format creates the primary printer, which writes to out
+	format         func(out io.Writer, args ...PrinterOption) Printer
+	out            io.Writer
+	printerOptions []PrinterOption
+	printers       []Printer
+
+	async        bool
+	asyncOptions []AsyncOption
+
+	batch        bool
+	batchOptions []BatchOption
+
+	tail        bool
+	tailOptions []TailOption
+
+	sampling map[level.LogLevel]int
+
+	caller          bool
+	callerSkip      int
+	stackTrace      bool
+	stackTraceLevel level.LogLevel
+
+	options []logkOption.SetterFunc
+}
+
+// New creates a builder of a logger in level.Default
+func New() *Builder {
+	return &Builder{level: level.Default}
+}
+
+// Level sets level of logger
+func (b *Builder) Level(lv level.LogLevel) *Builder {
+	b.level = lv
+	return b
+}
+
+// Namespace sets namespace of logger
+func (b *Builder) Namespace(namespace string) *Builder {
+	b.namespace = namespace
+	return b
+}
+
+// Text writes entries in standard log format, which is the default
+func (b *Builder) Text() *Builder {
+	b.format = func(out io.Writer, args ...PrinterOption) Printer {
+		return NewStdLogPrinter(out, stdLog.LstdFlags, args...)
+	}
+	return b
+}
+
+// JSON writes entries as JSON lines, see NewJSONPrinter
+func (b *Builder) JSON() *Builder {
+	return b.Encoder(NewJSONEncoder())
+}
+
+// Logfmt writes entries as logfmt lines, see NewLogfmtPrinter
+func (b *Builder) Logfmt() *Builder {
+	return b.Encoder(NewLogfmtEncoder())
+}
+
+// Pretty writes entries in human-readable format for terminals, see NewPrettyPrinter
+func (b *Builder) Pretty() *Builder {
+	b.format = func(out io.Writer, args ...PrinterOption) Printer {
+		return NewPrettyPrinter(out, args...)
+	}
+	return b
+}
+
+// GCP writes entries in Google Cloud Logging format, see NewGCPPrinter
+func (b *Builder) GCP(projectId string) *Builder {
+	return b.Encoder(NewGCPEncoder(projectId))
+}
+
+// ECS writes entries in Elastic Common Schema, see NewECSPrinter
+func (b *Builder) ECS() *Builder {
+	return b.Encoder(NewECSEncoder())
+}
+
+// Encoder writes entries encoded by enc
+func (b *Builder) Encoder(enc Encoder) *Builder {
+	b.format = func(out io.Writer, args ...PrinterOption) Printer {
+		return NewEncoderPrinter(enc, AddSync(out), args...)
+	}
+	return b
+}
+
+// Output sets writer of entries, default is Stdout
+func (b *Builder) Output(w io.Writer) *Builder {
+	b.out = w
+	return b
+}
+
+// PrinterOptions sets options of the primary printer, e.g. WithTimeFormat
+func (b *Builder) PrinterOptions(args ...PrinterOption) *Builder {
+	b.printerOptions = append(b.printerOptions, args...)
+	return b
+}
+
+// Printer adds a printer that receives every entry, e.g. a sink
+func (b *Builder) Printer(p Printer) *Builder {
+	if p != nil {
+		b.printers = append(b.printers, p)
+	}
+	return b
+}
+
+// Async writes entries from background goroutine, see NewAsyncPrinter
+func (b *Builder) Async(args ...AsyncOption) *Builder {
+	b.async = true
+	b.asyncOptions = args
+	return b
+}
+
+// Batch buffers writes of the primary printer and writes them out together, see NewBatchWriter
+func (b *Builder) Batch(args ...BatchOption) *Builder {
+	b.batch = true
+	b.batchOptions = args
+	return b
+}
+
+// Tail buffers entries that are less severe than tail level in their tail context and writes them only before an
+// error, see NewTailPrinter. Logger level must enable buffered levels
+func (b *Builder) Tail(args ...TailOption) *Builder {
+	b.tail = true
+	b.tailOptions = args
+	return b
+}
+
+// Sampling writes 1 in n entries in level, see NewSamplingLogger
+func (b *Builder) Sampling(lv level.LogLevel, n int) *Builder {
+	if b.sampling == nil {
+		b.sampling = make(map[level.LogLevel]int)
+	}
+	b.sampling[lv] = n
+	return b
+}
+
+// Caller captures call site of every entry, skipping additional frames of wrappers
+func (b *Builder) Caller(skip ...int) *Builder {
+	b.caller = true
+	b.callerSkip = 0
+	for _, s := range skip {
+		b.callerSkip += s
+	}
+	return b
+}
+
+// StackTrace captures stack trace of entries in lv and more severe levels
+func (b *Builder) StackTrace(lv level.LogLevel) *Builder {
+	b.stackTrace = true
+	b.stackTraceLevel = lv
+	return b
+}
+
+// Options adds options that are passed to NewStdLogger, for settings that builder doesn't cover
+func (b *Builder) Options(args ...logkOption.SetterFunc) *Builder {
+	b.options = append(b.options, args...)
+	return b
+}
+
+// Build creates the logger. Builder can be reused, but printers added with Printer are shared by built loggers
+func (b *Builder) Build() Logger {
+	var printers []Printer
+	if b.format != nil || b.out != nil || len(b.printers) == 0 {
+		out := b.out
+		if out == nil {
+			out = os.Stdout
+		}
+		var batch *BatchWriter
+		if b.batch {
+			batch = NewBatchWriter(out, b.batchOptions...)
+			out = batch
+		}
+
+		var primary Printer
+		if b.format != nil {
+			primary = b.format(out, b.printerOptions...)
+		} else {
+			primary = NewStdLogPrinter(out, stdLog.LstdFlags, b.printerOptions...)
+		}
+		if batch != nil {
+			primary = &batchPrinter{Printer: primary, batch: batch}
+		}
+		printers = append(printers, primary)
+	}
+	printers = append(printers, b.printers...)
+
+	var printer Printer = MultiPrinter(printers...)
+	if len(printers) == 1 {
+		printer = printers[0]
+	}
+	if b.tail {
+		printer = NewTailPrinter(printer, b.tailOptions...)
+	}
+	if b.async {
+		printer = NewAsyncPrinter(printer, b.asyncOptions...)
+	}
+
+	args := []logkOption.SetterFunc{logkOption.Level(b.level), logkOption.WithNamespace(b.namespace)}
+	if b.caller {
+		skip := b.callerSkip
+		// Skip frame of sampling logger that wraps std logger
+		if len(b.sampling) > 0 {
+			skip++
+		}
+		args = append(args, logkOption.EnableCaller(skip))
+	}
+	if b.stackTrace {
+		args = append(args, logkOption.EnableStackTrace(b.stackTraceLevel))
+	}
+	args = append(args, b.options...)
+
+	var logger Logger = NewStdLogger(printer, args...)
+	if len(b.sampling) > 0 {
+		logger = NewSamplingLogger(logger, maps.Clone(b.sampling))
+	}
+	return logger
+}
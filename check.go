@@ -0,0 +1,77 @@
+package logk
+
+import (
+	"github.com/go-konsultin/logk/level"
+	logkOption "github.com/go-konsultin/logk/option"
+)
+
+// EnabledLogger is implemented by loggers that can tell whether an entry in level would be written, so callers
+// can skip building expensive metadata of filtered entries
+type EnabledLogger interface {
+	Logger
+
+	// Enabled must return true if entry in lv passes level of logger. It must be cheap and must not allocate
+	Enabled(lv level.LogLevel) bool
+}
+
+// IsEnabled returns true if entry in lv would be written by logger. Loggers that don't implement EnabledLogger are
+// treated as enabled in all levels
+func IsEnabled(logger Logger, lv level.LogLevel) bool {
+	if el, ok := logger.(EnabledLogger); ok {
+		return el.Enabled(lv)
+	}
+	return true
+}
+
+// Enabled returns true if entry in lv would be written by registered logger
+func Enabled(lv level.LogLevel) bool {
+	return IsEnabled(Get(), lv)
+}
+
+// CheckedEntry is an entry that passed level check of its logger, returned by Check
+type CheckedEntry struct {
+	logger Logger
+	level  level.LogLevel
+	msg    string
+}
+
+// Check returns an entry that is written with Write if lv is enabled on logger, or nil if it isn't, e.g.
+//
+//	if ce := logk.Check(logger, level.Debug, "cache state"); ce != nil {
+//		ce.Write(logkOption.AddMetadata("entries", cache.Dump()))
+//	}
+func Check(logger Logger, lv level.LogLevel, msg string) *CheckedEntry {
+	if !IsEnabled(logger, lv) {
+		return nil
+	}
+	return &CheckedEntry{logger: logger, level: lv, msg: msg}
+}
+
+// Write writes entry with args in checked level. Entries in FATAL level follow fatal behavior of logger. It does
+// nothing on nil entry
+func (ce *CheckedEntry) Write(args ...logkOption.SetterFunc) {
+	if ce == nil {
+		return
+	}
+
+	// Std logger is called directly, so call site is not shifted by frame of Write
+	if l, ok := ce.logger.(*StdLogger); ok {
+		l.logAt(ce.level, ce.msg, logkOption.Evaluate(args), 1)
+		return
+	}
+
+	switch ce.level {
+	case level.Fatal:
+		ce.logger.Fatal(ce.msg, args...)
+	case level.Error:
+		ce.logger.Error(ce.msg, args...)
+	case level.Warn:
+		ce.logger.Warn(ce.msg, args...)
+	case level.Info:
+		ce.logger.Info(ce.msg, args...)
+	case level.Debug:
+		ce.logger.Debug(ce.msg, args...)
+	default:
+		ce.logger.Trace(ce.msg, args...)
+	}
+}
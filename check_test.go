@@ -0,0 +1,24 @@
+package logk
+
+import (
+	"bytes"
+	"fmt"
+	"runtime"
+	"testing"
+
+	"github.com/go-konsultin/logk/level"
+	logkOption "github.com/go-konsultin/logk/option"
+)
+
+func TestCheckedEntryWriteCaller(t *testing.T) {
+	var buf bytes.Buffer
+	logger := NewStdLogger(NewJSONPrinter(&buf), logkOption.Level(level.Info), logkOption.EnableCaller(0))
+
+	_, _, line, _ := runtime.Caller(0)
+	Check(logger, level.Info, "checked").Write()
+
+	want := fmt.Sprintf("check_test.go:%d", line+1)
+	if got := decodeLine(t, buf.Bytes())[logkOption.CallerKey]; got != want {
+		t.Errorf("caller = %v, want %s", got, want)
+	}
+}
@@ -0,0 +1,55 @@
+package logk
+
+import (
+	"sync/atomic"
+	"time"
+)
+
+// Clock provides the time entries are stamped with. Replace it with SetClock to get deterministic timestamps in
+// tests, e.g. with logkTest.NewClock
+type Clock interface {
+	Now() time.Time
+}
+
+// SystemClock is the default Clock that returns current time
+var SystemClock Clock = systemClock{}
+
+type systemClock struct{}
+
+func (systemClock) Now() time.Time {
+	return time.Now()
+}
+
+// clockState holds current clock with its start time, that uptime is measured from
+type clockState struct {
+	clock Clock
+	start time.Time
+}
+
+var currentClock atomic.Pointer[clockState]
+
+// SetClock replaces clock that loggers and printers stamp entries with. Uptime is measured from the time clock
+// is set. If c is nil, SystemClock is restored
+func SetClock(c Clock) {
+	if c == nil {
+		currentClock.Store(nil)
+		return
+	}
+	currentClock.Store(&clockState{clock: c, start: c.Now()})
+}
+
+// Now returns current time of clock set with SetClock
+func Now() time.Time {
+	if s := currentClock.Load(); s != nil {
+		return s.clock.Now()
+	}
+	return time.Now()
+}
+
+// uptime returns duration since process start, or since clock is set
+func uptime() time.Duration {
+	if s := currentClock.Load(); s != nil {
+		return s.clock.Now().Sub(s.start)
+	}
+	return time.Since(processStart)
+}
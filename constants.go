@@ -6,4 +6,18 @@ const pkgName = "logk"
 const (
 	EnvLogLevel     = "LOG_LEVEL"
 	EnvLogNamespace = "LOG_NAMESPACE"
+	// EnvLogFormat selects printer of FromEnv: text, json, logfmt, pretty, gcp or ecs
+	EnvLogFormat = "LOG_FORMAT"
+	// EnvLogOutput selects output of FromEnv: stdout, stderr or path of a file that entries are appended to
+	EnvLogOutput = "LOG_OUTPUT"
+	// EnvLogColor sets color of pretty format: auto, always or never
+	EnvLogColor = "LOG_COLOR"
+	// EnvLogCaller enables capturing call site of every entry when set to a true boolean value
+	EnvLogCaller = "LOG_CALLER"
+	// EnvLogSampling sets per-level sample rates as comma separated pairs, e.g. info=100,debug=1000
+	EnvLogSampling = "LOG_SAMPLING"
+	// EnvLogLevels sets per-namespace levels as comma separated pairs, e.g. db=debug,http=warn
+	EnvLogLevels = "LOG_LEVELS"
+	// EnvNoColor disables colorized output when set, see https://no-color.org
+	EnvNoColor = "NO_COLOR"
 )
@@ -6,6 +6,13 @@ type ContextKey string
 
 const (
 	RequestIdKey ContextKey = "requestId"
+	BaggageKey   ContextKey = "baggage"
+	TraceIdKey   ContextKey = "traceId"
+	SpanIdKey    ContextKey = "spanId"
+	// LoggerKey holds logger that is stored with logk.NewContext
+	LoggerKey ContextKey = "logger"
+	// TailKey holds buffer of entries that is stored with logk.NewTailContext
+	TailKey ContextKey = "tail"
 )
 
 // SetRequestId is helper function to set request id value to context
@@ -31,3 +38,30 @@ func GetRequestId(ctx context.Context) string {
 		return ""
 	}
 }
+
+// SetBaggage is helper function to set correlation baggage to context. Baggage is merged with existing baggage
+// in context, with given values taking precedence
+func SetBaggage(ctx context.Context, baggage map[string]string) context.Context {
+	if ctx == nil || len(baggage) == 0 {
+		return ctx
+	}
+
+	merged := make(map[string]string)
+	for k, v := range GetBaggage(ctx) {
+		merged[k] = v
+	}
+	for k, v := range baggage {
+		merged[k] = v
+	}
+	return context.WithValue(ctx, BaggageKey, merged)
+}
+
+// GetBaggage is helper function to retrieve correlation baggage in context. The returned map must not be modified
+func GetBaggage(ctx context.Context) map[string]string {
+	if ctx == nil {
+		return nil
+	}
+
+	b, _ := ctx.Value(BaggageKey).(map[string]string)
+	return b
+}
@@ -0,0 +1,130 @@
+package logkContext
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"sync/atomic"
+)
+
+// TraceExtractor returns trace and span id of the active span in context, e.g. an OpenTelemetry span:
+//
+//	logkContext.SetTraceExtractor(func(ctx context.Context) (string, string) {
+//		sc := trace.SpanContextFromContext(ctx)
+//		if !sc.IsValid() {
+//			return "", ""
+//		}
+//		return sc.TraceID().String(), sc.SpanID().String()
+//	})
+type TraceExtractor = func(ctx context.Context) (traceId string, spanId string)
+
+var traceExtractor atomic.Pointer[TraceExtractor]
+
+// SetTraceExtractor registers function to retrieve trace and span id from context, so tracing library doesn't
+// have to be a dependency. Set nil to remove it
+func SetTraceExtractor(fn TraceExtractor) {
+	if fn == nil {
+		traceExtractor.Store(nil)
+		return
+	}
+	traceExtractor.Store(&fn)
+}
+
+// SetTrace is helper function to set trace and span id values to context, e.g. parsed from traceparent header
+func SetTrace(ctx context.Context, traceId string, spanId string) context.Context {
+	if ctx == nil || traceId == "" {
+		return ctx
+	}
+
+	ctx = context.WithValue(ctx, TraceIdKey, traceId)
+	if spanId != "" {
+		ctx = context.WithValue(ctx, SpanIdKey, spanId)
+	}
+	return ctx
+}
+
+// GetTrace is helper function to retrieve trace and span id in context. Values that are set with SetTrace take
+// precedence over registered TraceExtractor
+func GetTrace(ctx context.Context) (traceId string, spanId string) {
+	if ctx == nil {
+		return "", ""
+	}
+
+	if traceId, _ = ctx.Value(TraceIdKey).(string); traceId != "" {
+		spanId, _ = ctx.Value(SpanIdKey).(string)
+		return traceId, spanId
+	}
+
+	if fn := traceExtractor.Load(); fn != nil {
+		return (*fn)(ctx)
+	}
+	return "", ""
+}
+
+// Headers that propagate trace context
+const (
+	TraceparentHeader       = "traceparent"
+	CloudTraceContextHeader = "X-Cloud-Trace-Context"
+)
+
+// ParseTraceparent parses W3C Trace Context traceparent header, e.g.
+// "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01", and returns trace and span id
+func ParseTraceparent(header string) (traceId string, spanId string, ok bool) {
+	parts := strings.Split(strings.TrimSpace(header), "-")
+	if len(parts) < 4 || len(parts[0]) != 2 || parts[0] == "ff" {
+		return "", "", false
+	}
+
+	traceId, spanId = parts[1], parts[2]
+	if !isLowerHex(traceId, 32) || !isLowerHex(spanId, 16) {
+		return "", "", false
+	}
+
+	// All zero ids are invalid
+	if strings.Trim(traceId, "0") == "" || strings.Trim(spanId, "0") == "" {
+		return "", "", false
+	}
+	return traceId, spanId, true
+}
+
+// ParseCloudTraceContext parses X-Cloud-Trace-Context header of Google Cloud, e.g.
+// "105445aa7843bc8bf206b12000100000/1;o=1", and returns trace id and span id. Span id is decimal in header and is
+// returned as 16 hex digits, like span ids of traceparent. Span id is empty if header has none
+func ParseCloudTraceContext(header string) (traceId string, spanId string, ok bool) {
+	header = strings.TrimSpace(header)
+	if i := strings.IndexByte(header, ';'); i >= 0 {
+		header = header[:i]
+	}
+
+	traceId, span, _ := strings.Cut(header, "/")
+	traceId = strings.ToLower(traceId)
+	if !isLowerHex(traceId, 32) || strings.Trim(traceId, "0") == "" {
+		return "", "", false
+	}
+
+	if span != "" {
+		id, err := strconv.ParseUint(span, 10, 64)
+		if err != nil {
+			return "", "", false
+		}
+		if id != 0 {
+			spanId = fmt.Sprintf("%016x", id)
+		}
+	}
+	return traceId, spanId, true
+}
+
+func isLowerHex(s string, n int) bool {
+	if len(s) != n {
+		return false
+	}
+
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		if (c < '0' || c > '9') && (c < 'a' || c > 'f') {
+			return false
+		}
+	}
+	return true
+}
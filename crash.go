@@ -0,0 +1,144 @@
+package logk
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"sync"
+
+	logkOption "github.com/go-konsultin/logk/option"
+)
+
+// PanicKey is metadata key of panic value in entries written by HandlePanic and RecoverAndLog
+const PanicKey = "panic"
+
+type PanicOptions struct {
+	// Logger writes panic entry, default is registered logger
+	Logger Logger
+	// Repanic panics again with recovered value once entry is written and printers are flushed
+	Repanic bool
+	// OnPanic is called with recovered value after entry is written, e.g. to report it to an error tracker
+	OnPanic func(v interface{})
+}
+
+type PanicOption = func(*PanicOptions)
+
+func WithPanicLogger(logger Logger) PanicOption {
+	return func(o *PanicOptions) {
+		o.Logger = logger
+	}
+}
+
+func WithRepanic(enabled bool) PanicOption {
+	return func(o *PanicOptions) {
+		o.Repanic = enabled
+	}
+}
+
+func WithOnPanic(fn func(v interface{})) PanicOption {
+	return func(o *PanicOptions) {
+		o.OnPanic = fn
+	}
+}
+
+var (
+	crashMu   sync.Mutex
+	crashRing *RingPrinter
+	crashOut  io.Writer
+)
+
+// SetCrashDump registers ring printer which entries are written to w when process crashes, i.e. on panic handled by
+// HandlePanic or RecoverAndLog and on FATAL entry, so recent entries of levels that are not persisted are kept with
+// the crash. If w is nil, entries are written to Stderr. Set nil ring to remove it
+func SetCrashDump(ring *RingPrinter, w io.Writer) {
+	if w == nil {
+		w = os.Stderr
+	}
+
+	crashMu.Lock()
+	defer crashMu.Unlock()
+	crashRing = ring
+	crashOut = w
+}
+
+// dumpCrash writes entries of registered crash ring printer
+func dumpCrash() {
+	crashMu.Lock()
+	defer crashMu.Unlock()
+	if crashRing == nil || crashRing.Len() == 0 {
+		return
+	}
+
+	_, _ = fmt.Fprintf(crashOut, "%s: last %d entries before crash:\n", pkgName, crashRing.Len())
+	_, _ = crashRing.DumpTo(crashOut)
+}
+
+// HandlePanic is a crash handler that must be deferred at the top of main and goroutines:
+//
+//	defer logk.HandlePanic()
+//
+// On panic, it writes FATAL entry with panic value and stack trace, flushes printers, dumps crash ring printer
+// registered with SetCrashDump, and panics again with the same value, so process crashes as it would without
+// handler. Fatal behavior of logger is not applied, as handler decides how to continue
+func HandlePanic(args ...PanicOption) {
+	// recover must be called directly by deferred function
+	if v := recover(); v != nil {
+		handlePanic(v, append([]PanicOption{WithRepanic(true)}, args...))
+	}
+}
+
+// RecoverAndLog is HandlePanic that doesn't panic again, so goroutine returns normally after panic is logged,
+// e.g. a worker that must not crash the process:
+//
+//	defer logk.RecoverAndLog(logk.WithPanicLogger(logger))
+func RecoverAndLog(args ...PanicOption) {
+	if v := recover(); v != nil {
+		handlePanic(v, args)
+	}
+}
+
+func handlePanic(v interface{}, args []PanicOption) {
+	var o PanicOptions
+	for _, fn := range args {
+		fn(&o)
+	}
+
+	logger := o.Logger
+	if logger == nil {
+		logger = Get()
+	}
+
+	// Write entry without exiting, panicking or flushing in logger
+	logger.With(logkOption.WithFatalBehavior(logkOption.FatalNoop)).Fatal("panic recovered",
+		logkOption.AddMetadata(PanicKey, fmt.Sprint(v)),
+		logkOption.WithStackTrace())
+
+	if o.OnPanic != nil {
+		o.OnPanic(v)
+	}
+
+	flushAll(logger)
+	dumpCrash()
+
+	if o.Repanic {
+		panic(v)
+	}
+}
+
+// flushAll flushes logger and registered logger, if it's another logger. Errors are ignored, as process is
+// crashing
+func flushAll(logger Logger) {
+	if f, ok := logger.(Flusher); ok {
+		_ = f.Flush()
+	}
+
+	logMutex.RLock()
+	registered := log
+	logMutex.RUnlock()
+
+	if registered != nil && registered != logger {
+		if f, ok := registered.(Flusher); ok {
+			_ = f.Flush()
+		}
+	}
+}
@@ -0,0 +1,184 @@
+package logk
+
+import (
+	"encoding/json"
+	"fmt"
+	"hash/fnv"
+	"io"
+	"sync"
+	"time"
+
+	"github.com/go-konsultin/logk/level"
+	logkOption "github.com/go-konsultin/logk/option"
+)
+
+// RepeatedKey is metadata key of number of collapsed entries in repeat summary entry
+const RepeatedKey = "repeated"
+
+// DedupPrinter collapses identical consecutive entries within window into the first entry and a summary entry,
+// e.g. "message repeated 12 times: [connection refused]". Entries are identical if they have the same level,
+// namespace, formatted message, error and metadata
+type DedupPrinter struct {
+	printer     Printer
+	window      time.Duration
+	fingerprint bool
+
+	// mu guards last and timer. Entries are printed with lock held, so summary is always written in order
+	mu    sync.Mutex
+	last  *dedupEntry
+	timer *time.Timer
+}
+
+type dedupEntry struct {
+	hash        uint64
+	namespace   string
+	level       level.LogLevel
+	msg         string
+	fingerprint string
+	firstAt     time.Time
+	repeats     uint64
+}
+
+func NewDedupPrinter(printer Printer, window time.Duration) *DedupPrinter {
+	// Init printer if nil
+	if printer == nil {
+		printer = NewStdLogPrinter(nil, 0)
+	}
+	return &DedupPrinter{printer: printer, window: window}
+}
+
+// ByFingerprint treats ERROR and FATAL entries with the same namespace and fingerprint as identical, so the same
+// failure is collapsed even if its message differs, e.g. by id. Summary entry has message of the first entry
+func (p *DedupPrinter) ByFingerprint() *DedupPrinter {
+	p.fingerprint = true
+	return p
+}
+
+func (p *DedupPrinter) Print(namespace string, lv level.LogLevel, msg string, options *logkOption.Options) {
+	// Fingerprint is computed from unformatted message. Summary entry keeps it, so it's grouped with its entries
+	var fingerprint string
+	if lv <= level.Error {
+		fingerprint = Fingerprint(lv, msg, options)
+	}
+	if len(options.FmtArgs) > 0 {
+		msg = fmt.Sprintf(msg, options.FmtArgs...)
+	}
+
+	var hash uint64
+	if p.fingerprint && fingerprint != "" {
+		hash = dedupHash(namespace, lv, fingerprint, nil)
+	} else {
+		hash = dedupHash(namespace, lv, msg, options)
+	}
+	now := time.Now()
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	// Collapse identical entry within window
+	if last := p.last; last != nil && last.hash == hash && now.Sub(last.firstAt) < p.window {
+		last.repeats++
+		if p.timer == nil {
+			p.timer = time.AfterFunc(p.window-now.Sub(last.firstAt), p.expire)
+		}
+		return
+	}
+
+	// Only fields of entry are kept for summary, as options are reused by logger once Print returns
+	p.writeRepeats()
+	p.last = &dedupEntry{hash: hash, namespace: namespace, level: lv, msg: msg, fingerprint: fingerprint, firstAt: now}
+	p.printer.Print(namespace, lv, msg, withoutFmtArgs(options))
+}
+
+// Flush writes summary of collapsed entries, then flushes underlying printer
+func (p *DedupPrinter) Flush() error {
+	p.mu.Lock()
+	p.writeRepeats()
+	p.mu.Unlock()
+
+	if f, ok := p.printer.(Flusher); ok {
+		return f.Flush()
+	}
+	return nil
+}
+
+// Close writes summary of collapsed entries and closes underlying printer
+func (p *DedupPrinter) Close() error {
+	err := p.Flush()
+	if c, ok := p.printer.(io.Closer); ok {
+		if cErr := c.Close(); cErr != nil && err == nil {
+			err = cErr
+		}
+	}
+	return err
+}
+
+// expire writes summary when window of the last entry has elapsed, so the next identical entry is written again
+func (p *DedupPrinter) expire() {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.timer = nil
+	p.writeRepeats()
+	p.last = nil
+}
+
+// writeRepeats writes summary of entries collapsed into the last entry. It must be called with lock held
+func (p *DedupPrinter) writeRepeats() {
+	if p.timer != nil {
+		p.timer.Stop()
+		p.timer = nil
+	}
+
+	last := p.last
+	if last == nil || last.repeats == 0 {
+		return
+	}
+
+	options := logkOption.NewOptions()
+	options.Level = last.level
+	options.Metadata = map[string]interface{}{RepeatedKey: last.repeats}
+	if last.fingerprint != "" {
+		options.Values[logkOption.FingerprintKey] = last.fingerprint
+	}
+
+	msg := fmt.Sprintf("message repeated %d times: [%s]", last.repeats, last.msg)
+	p.printer.Print(last.namespace, last.level, msg, options)
+	last.repeats = 0
+}
+
+// withoutFmtArgs returns copy of options without formatting arguments, as message is already formatted
+func withoutFmtArgs(options *logkOption.Options) *logkOption.Options {
+	if len(options.FmtArgs) == 0 {
+		return options
+	}
+	o := *options
+	o.FmtArgs = nil
+	return &o
+}
+
+func dedupHash(namespace string, lv level.LogLevel, msg string, options *logkOption.Options) uint64 {
+	h := fnv.New64a()
+	_, _ = h.Write([]byte(namespace))
+	_, _ = h.Write([]byte{0, byte(lv), 0})
+	_, _ = h.Write([]byte(msg))
+	if options == nil {
+		return h.Sum64()
+	}
+
+	if err := options.Error(); err != nil {
+		_, _ = h.Write([]byte{0})
+		_, _ = h.Write([]byte(err.Error()))
+	}
+
+	// Maps are serialized with sorted keys, so equal metadata has equal hash
+	if len(options.Metadata) > 0 {
+		_, _ = h.Write([]byte{0})
+		if b, err := json.Marshal(options.Metadata); err == nil {
+			_, _ = h.Write(b)
+		} else {
+			_, _ = h.Write([]byte(fmt.Sprintf("%+v", options.Metadata)))
+		}
+	}
+
+	return h.Sum64()
+}
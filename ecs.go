@@ -0,0 +1,166 @@
+package logk
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/go-konsultin/logk/level"
+)
+
+// ecsVersion is version of Elastic Common Schema that entries conform to
+const ecsVersion = "8.11.0"
+
+// Elastic Common Schema field names
+const (
+	ecsTimestampKey   = "@timestamp"
+	ecsLevelKey       = "log.level"
+	ecsLoggerKey      = "log.logger"
+	ecsMessageKey     = "message"
+	ecsVersionKey     = "ecs.version"
+	ecsFileKey        = "log.origin.file.name"
+	ecsLineKey        = "log.origin.file.line"
+	ecsFunctionKey    = "log.origin.function"
+	ecsErrorKey       = "error.message"
+	ecsErrorTypeKey   = "error.type"
+	ecsStackKey       = "error.stack_trace"
+	ecsFingerprintKey = "error.fingerprint"
+	ecsTraceIdKey     = "trace.id"
+	ecsSpanIdKey      = "span.id"
+	ecsRequestIdKey   = "http.request.id"
+	ecsSequenceKey    = "event.sequence"
+	ecsUptimeKey      = "process.uptime"
+	ecsLabelsKey      = "labels"
+	ecsSampleRateKey  = "sampleRate"
+)
+
+// NewECSPrinter creates a printer that writes entries as single-line JSON in Elastic Common Schema, so they can be
+// indexed by Elasticsearch without ingest pipeline, see NewECSEncoder
+func NewECSPrinter(out io.Writer, args ...PrinterOption) *encoderPrinter {
+	// If writer is nil, set default writer to Stdout
+	if out == nil {
+		out = os.Stdout
+	}
+
+	return NewEncoderPrinter(NewECSEncoder(), AddSync(out), args...)
+}
+
+// NewECSEncoder creates an encoder that formats entries in Elastic Common Schema: namespace is written as
+// log.logger, error as error.message and error.type, stack trace as error.stack_trace, fingerprint as
+// error.fingerprint and request id as http.request.id. Metadata and baggage are written as labels, with nested keys
+// joined by underscore and values that are not scalars serialized as JSON
+func NewECSEncoder() Encoder {
+	return ecsEncoder{}
+}
+
+type ecsEncoder struct{}
+
+func (ecsEncoder) Encode(entry *Entry) ([]byte, error) {
+	t := entry.Time
+	if entry.timeLocation != nil {
+		t = t.In(entry.timeLocation)
+	}
+
+	line := map[string]interface{}{
+		ecsTimestampKey: t.Format(time.RFC3339Nano),
+		ecsLevelKey:     strings.ToLower(level.String(entry.Level)),
+		ecsMessageKey:   entry.Message,
+		ecsVersionKey:   ecsVersion,
+	}
+
+	if entry.Namespace != "" {
+		line[ecsLoggerKey] = entry.Namespace
+	}
+
+	if entry.Caller.File != "" {
+		line[ecsFileKey] = entry.Caller.File
+		line[ecsLineKey] = entry.Caller.Line
+		if entry.Caller.Function != "" {
+			line[ecsFunctionKey] = entry.Caller.Function
+		}
+	}
+
+	if entry.Error != nil {
+		line[ecsErrorKey] = entry.Error.Error()
+		line[ecsErrorTypeKey] = fmt.Sprintf("%T", entry.Error)
+	}
+
+	if entry.StackTrace != "" {
+		line[ecsStackKey] = entry.StackTrace
+	}
+
+	if entry.Fingerprint != "" {
+		line[ecsFingerprintKey] = entry.Fingerprint
+	}
+
+	if entry.TraceId != "" {
+		line[ecsTraceIdKey] = entry.TraceId
+	}
+
+	if entry.SpanId != "" {
+		line[ecsSpanIdKey] = entry.SpanId
+	}
+
+	if entry.RequestId != "" {
+		line[ecsRequestIdKey] = entry.RequestId
+	}
+
+	if entry.Sequence > 0 {
+		line[ecsSequenceKey] = entry.Sequence
+	}
+
+	if entry.Uptime > 0 {
+		line[ecsUptimeKey] = int64(entry.Uptime / time.Second)
+	}
+
+	labels := make(map[string]interface{})
+	for k, v := range entry.Baggage {
+		labels[ecsLabelName(k)] = v
+	}
+	ecsLabels(labels, "", entry.Metadata)
+	if entry.SampleRate > 0 {
+		labels[ecsSampleRateKey] = entry.SampleRate
+	}
+
+	if len(labels) > 0 {
+		line[ecsLabelsKey] = labels
+	}
+
+	b, err := json.Marshal(line)
+	if err != nil {
+		return nil, err
+	}
+	return append(b, '\n'), nil
+}
+
+// ecsLabels flattens metadata into labels. Labels are keywords, so values that are not scalars are serialized
+func ecsLabels(dst map[string]interface{}, prefix string, src map[string]interface{}) {
+	for k, v := range src {
+		name := prefix + ecsLabelName(k)
+		switch val := v.(type) {
+		case nil:
+		case map[string]interface{}:
+			ecsLabels(dst, name+"_", val)
+		case string, bool, int, int8, int16, int32, int64, uint, uint8, uint16, uint32, uint64, float32, float64:
+			dst[name] = val
+		case error:
+			dst[name] = val.Error()
+		case fmt.Stringer:
+			dst[name] = val.String()
+		default:
+			if b, err := json.Marshal(val); err == nil {
+				dst[name] = string(b)
+			} else {
+				dst[name] = fmt.Sprintf("%+v", val)
+			}
+		}
+	}
+}
+
+// ecsLabelName replaces dots, which are not allowed in label names
+func ecsLabelName(key string) string {
+	return strings.ReplaceAll(key, ".", "_")
+}
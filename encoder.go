@@ -0,0 +1,127 @@
+package logk
+
+import (
+	"bytes"
+	"io"
+	"os"
+	"sync"
+
+	"github.com/go-konsultin/logk/level"
+	logkOption "github.com/go-konsultin/logk/option"
+)
+
+// Encoder formats an entry into bytes, including line terminator if format has one. Encoders are combined with a
+// WriteSyncer by NewEncoderPrinter, so any format can be written to any destination. Entry must not be retained
+// after Encode returns, as printers reuse it
+type Encoder interface {
+	Encode(entry *Entry) ([]byte, error)
+}
+
+// BufferEncoder is implemented by encoders that can write entry into a buffer. Printers use it instead of Encode
+// to reuse pooled buffers. Buffer content is undefined if error is returned
+type BufferEncoder interface {
+	EncodeTo(buf *bytes.Buffer, entry *Entry) error
+}
+
+// EncoderFunc is an adapter to use a function as Encoder
+type EncoderFunc func(entry *Entry) ([]byte, error)
+
+func (fn EncoderFunc) Encode(entry *Entry) ([]byte, error) {
+	return fn(entry)
+}
+
+// WriteSyncer is destination of encoded entries. Sync writes out data that is buffered by destination
+type WriteSyncer interface {
+	io.Writer
+	Sync() error
+}
+
+// AddSync converts w into WriteSyncer. If w implements WriteSyncer it's returned as is, otherwise Sync calls
+// Flush of w if it implements Flusher, and does nothing if it doesn't. Files are only synced if they are regular
+// files, as syncing terminals and pipes fails
+func AddSync(w io.Writer) WriteSyncer {
+	if f, ok := w.(*os.File); ok {
+		return fileSyncer{File: f}
+	}
+
+	if ws, ok := w.(WriteSyncer); ok {
+		return ws
+	}
+	return writerSyncer{Writer: w}
+}
+
+type writerSyncer struct {
+	io.Writer
+}
+
+func (w writerSyncer) Sync() error {
+	if f, ok := w.Writer.(Flusher); ok {
+		return f.Flush()
+	}
+	return nil
+}
+
+type fileSyncer struct {
+	*os.File
+}
+
+func (f fileSyncer) Sync() error {
+	info, err := f.Stat()
+	if err != nil || !info.Mode().IsRegular() {
+		return nil
+	}
+	return f.File.Sync()
+}
+
+// NewEncoderPrinter creates a printer that encodes entries with enc and writes them to out. If out is nil, entries
+// are written to Stdout. Entries that fail to encode are discarded
+func NewEncoderPrinter(enc Encoder, out WriteSyncer, args ...PrinterOption) *encoderPrinter {
+	// If writer is nil, set default writer to Stdout
+	if out == nil {
+		out = AddSync(os.Stdout)
+	}
+
+	return &encoderPrinter{enc: enc, out: out, options: evaluatePrinterOptions(args)}
+}
+
+type encoderPrinter struct {
+	enc     Encoder
+	out     WriteSyncer
+	options PrinterOptions
+	mu      sync.Mutex
+}
+
+func (p *encoderPrinter) Print(namespace string, lv level.LogLevel, msg string, options *logkOption.Options) {
+	entry := acquireEntry(namespace, lv, msg, options, &p.options)
+	defer releaseEntry(entry)
+
+	if be, ok := p.enc.(BufferEncoder); ok {
+		buf := getBuffer()
+		defer putBuffer(buf)
+
+		if err := be.EncodeTo(buf, entry); err != nil {
+			return
+		}
+		p.write(buf.Bytes())
+		return
+	}
+
+	b, err := p.enc.Encode(entry)
+	if err != nil {
+		return
+	}
+	p.write(b)
+}
+
+func (p *encoderPrinter) write(b []byte) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	_, _ = p.out.Write(b)
+}
+
+// Flush syncs destination
+func (p *encoderPrinter) Flush() error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.out.Sync()
+}
@@ -0,0 +1,411 @@
+package logk
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strings"
+	"time"
+
+	logkContext "github.com/go-konsultin/logk/context"
+	"github.com/go-konsultin/logk/level"
+	logkOption "github.com/go-konsultin/logk/option"
+)
+
+// Placeholders for structures truncated by PrinterOptions.MaxDepth
+const (
+	truncatedObject = "{...}"
+	truncatedArray  = "[...]"
+)
+
+// Entry is a single log record resolved from logger state and call options, ready to be serialized by printers
+type Entry struct {
+	Time      time.Time
+	Level     level.LogLevel
+	Namespace string
+	Message   string
+	Sequence  uint64
+	Uptime    time.Duration
+	// SampleRate is N of 1 in N sample rate applied to entry. Zero means entry is not sampled
+	SampleRate uint64
+	// Caller is call site of entry. File is empty if caller is not captured
+	Caller    logkOption.Caller
+	RequestId string
+	// TraceId and SpanId identify the active span in entry context
+	TraceId string
+	SpanId  string
+	Baggage map[string]string
+	Error   error
+	// StackTrace is captured stack trace of entry, empty if it is not captured
+	StackTrace string
+	// Fingerprint is grouping key of ERROR and FATAL entries, see Fingerprint
+	Fingerprint string
+	Metadata    map[string]interface{}
+
+	metadataFallback MetadataFallback
+	explicitNulls    bool
+	timeFormat       string
+	timeLocation     *time.Location
+}
+
+// NewEntry resolves print arguments into an Entry. Custom printers should use it to share serialization behavior
+// with built-in printers
+func NewEntry(namespace string, lv level.LogLevel, msg string, options *logkOption.Options, args ...PrinterOption) *Entry {
+	po := evaluatePrinterOptions(args)
+	return newEntry(namespace, lv, msg, options, &po)
+}
+
+func newEntry(namespace string, lv level.LogLevel, msg string, options *logkOption.Options, po *PrinterOptions) *Entry {
+	e := new(Entry)
+	initEntry(e, namespace, lv, msg, options, po)
+	return e
+}
+
+// initEntry resolves print arguments into e, overwriting all of its fields, so pooled entries can be reused
+func initEntry(e *Entry, namespace string, lv level.LogLevel, msg string, options *logkOption.Options,
+	po *PrinterOptions) {
+	*e = Entry{
+		Time:             Now(),
+		Level:            lv,
+		Namespace:        namespace,
+		Message:          msg,
+		metadataFallback: po.MetadataFallback,
+		explicitNulls:    po.ExplicitNulls,
+		timeFormat:       po.TimeFormat,
+		timeLocation:     po.TimeLocation,
+	}
+
+	// Use timestamp that is stamped by logger, so all printers agree on it
+	if t, ok := options.Timestamp(); ok {
+		e.Time = t
+	}
+
+	// If formatted arguments is available, then format message
+	if len(options.FmtArgs) > 0 {
+		e.Message = fmt.Sprintf(msg, options.FmtArgs...)
+	}
+
+	e.Sequence = options.Sequence()
+	e.Uptime = options.Uptime()
+	e.SampleRate = options.SampleRate()
+	e.Caller, _ = options.Caller()
+	e.RequestId = options.RequestId()
+	e.TraceId, e.SpanId = options.Trace()
+	e.Baggage = resolveBaggage(options)
+	e.Error = options.Error()
+	e.StackTrace = options.StackTrace()
+	if lv <= level.Error {
+		e.Fingerprint = Fingerprint(lv, msg, options)
+	}
+
+	// Merge fields extracted from context, metadata that is set on call takes precedence
+	e.Metadata = logkOption.MergeMetadata(extractContext(options.Context), options.Metadata)
+
+	// Mask sensitive values
+	e.Metadata = maskSensitive(e.Metadata)
+
+	// Reveal secrets in unsafe debug mode
+	if unsafeDebug.Load() && len(e.Metadata) > 0 {
+		e.Metadata = revealSecrets(e.Metadata)
+	}
+
+	// Limit metadata depth
+	if po.MaxDepth > 0 && len(e.Metadata) > 0 {
+		e.Metadata = limitMetadataDepth(e.Metadata, po.MaxDepth)
+	}
+}
+
+// Fields returns entry as a flat map keyed with logkOption key constants, to be serialized by structured printers.
+// Empty fields are omitted, unless printer is created with WithExplicitNulls
+func (e *Entry) Fields() map[string]interface{} {
+	fields := map[string]interface{}{
+		logkOption.TimeKey:    e.formatTime(time.RFC3339Nano),
+		logkOption.LevelKey:   strings.ToLower(level.String(e.Level)),
+		logkOption.MessageKey: e.Message,
+	}
+
+	if e.Namespace != "" || e.explicitNulls {
+		fields[logkOption.NamespaceKey] = e.Namespace
+	}
+
+	if e.Sequence > 0 {
+		fields[logkOption.SequenceKey] = e.Sequence
+	}
+
+	if e.Uptime > 0 {
+		fields[logkOption.UptimeKey] = e.Uptime.String()
+	}
+
+	if e.SampleRate > 0 {
+		fields[logkOption.SampledKey] = true
+		fields[logkOption.SampleRateKey] = e.SampleRate
+	}
+
+	if e.Caller.File != "" {
+		fields[logkOption.CallerKey] = e.Caller.String()
+	}
+
+	if e.RequestId != "" || e.explicitNulls {
+		fields[logkOption.RequestIdKey] = e.RequestId
+	}
+
+	if e.TraceId != "" {
+		fields[logkOption.TraceIdKey] = e.TraceId
+	}
+
+	if e.SpanId != "" {
+		fields[logkOption.SpanIdKey] = e.SpanId
+	}
+
+	if len(e.Baggage) > 0 || e.explicitNulls {
+		fields[logkOption.BaggageKey] = e.Baggage
+	}
+
+	if e.Error != nil {
+		fields[logkOption.ErrorKey] = e.Error.Error()
+	} else if e.explicitNulls {
+		fields[logkOption.ErrorKey] = nil
+	}
+
+	if e.StackTrace != "" {
+		fields[logkOption.StackTraceKey] = e.StackTrace
+	}
+
+	if e.Fingerprint != "" {
+		fields[logkOption.FingerprintKey] = e.Fingerprint
+	}
+
+	if len(e.Metadata) > 0 || e.explicitNulls {
+		fields[logkOption.MetadataKey] = e.Metadata
+	}
+
+	return fields
+}
+
+// formatTime formats entry timestamp with time options of printer. If format is not set, layout is used.
+// Unix formats are returned as int64, so structured printers write them as numbers
+func (e *Entry) formatTime(layout string) interface{} {
+	t := e.Time
+	if e.timeLocation != nil {
+		t = t.In(e.timeLocation)
+	}
+
+	switch e.timeFormat {
+	case TimeFormatUnix:
+		return t.Unix()
+	case TimeFormatUnixMilli:
+		return t.UnixMilli()
+	case "":
+		return t.Format(layout)
+	default:
+		return t.Format(e.timeFormat)
+	}
+}
+
+// MarshalJSON serializes entry fields. If metadata can't be serialized, it is rendered by MetadataFallback
+func (e *Entry) MarshalJSON() ([]byte, error) {
+	return marshalFields(e.Fields(), e.metadataFallback)
+}
+
+// MetadataString returns metadata serialized as JSON. If metadata can't be serialized, it is rendered by
+// MetadataFallback and false is returned when it is dropped
+func (e *Entry) MetadataString() (string, bool) {
+	b, err := json.Marshal(e.Metadata)
+	if err == nil {
+		return string(b), true
+	}
+
+	internalWarn("failed to serialize metadata: %s", err)
+	if e.metadataFallback == MetadataDrop {
+		return "", false
+	}
+	return fmt.Sprintf("%+v", e.Metadata), true
+}
+
+// marshalFields serializes fields as JSON. If metadata can't be serialized, it is rendered by fallback
+func marshalFields(fields map[string]interface{}, fallback MetadataFallback) ([]byte, error) {
+	b, err := json.Marshal(fields)
+	if err == nil {
+		return b, nil
+	}
+
+	meta, ok := fields[logkOption.MetadataKey]
+	if !ok {
+		return nil, err
+	}
+
+	internalWarn("failed to serialize metadata: %s", err)
+	if fallback == MetadataDrop {
+		delete(fields, logkOption.MetadataKey)
+	} else {
+		fields[logkOption.MetadataKey] = fmt.Sprintf("%+v", meta)
+	}
+	return json.Marshal(fields)
+}
+
+// resolveBaggage merges baggage propagated in context with baggage that is set on options, the latter takes precedence
+func resolveBaggage(options *logkOption.Options) map[string]string {
+	ctxBaggage := logkContext.GetBaggage(options.Context)
+	baggage, _ := logkOption.GetStringMap(options, logkOption.BaggageKey)
+	if len(ctxBaggage) == 0 {
+		return baggage
+	}
+	if len(baggage) == 0 {
+		return ctxBaggage
+	}
+
+	merged := make(map[string]string, len(ctxBaggage)+len(baggage))
+	for k, v := range ctxBaggage {
+		merged[k] = v
+	}
+	for k, v := range baggage {
+		merged[k] = v
+	}
+	return merged
+}
+
+// revealSecrets returns copy of metadata with secret values replaced by their actual value
+func revealSecrets(meta map[string]interface{}) map[string]interface{} {
+	result := make(map[string]interface{}, len(meta))
+	for k, v := range meta {
+		switch val := v.(type) {
+		case logkOption.SecretValue:
+			result[k] = val.Reveal()
+		case map[string]interface{}:
+			result[k] = revealSecrets(val)
+		default:
+			result[k] = v
+		}
+	}
+	return result
+}
+
+// maskSensitive returns metadata with values of sensitive keys masked. Metadata is copied only if a key is masked
+func maskSensitive(meta map[string]interface{}) map[string]interface{} {
+	patterns := SensitiveKeys()
+	if len(patterns) == 0 {
+		return meta
+	}
+
+	var result map[string]interface{}
+	for k := range meta {
+		if !isSensitiveKey(patterns, k) {
+			continue
+		}
+
+		if result == nil {
+			result = make(map[string]interface{}, len(meta))
+			for ck, cv := range meta {
+				result[ck] = cv
+			}
+		}
+		result[k] = sensitiveMask
+	}
+
+	if result == nil {
+		return meta
+	}
+	return result
+}
+
+// limitMetadataDepth returns a copy of metadata where structures nested deeper than maxDepth are replaced
+// with a placeholder. Values that are within limit are kept as is, with their types
+func limitMetadataDepth(meta map[string]interface{}, maxDepth int) map[string]interface{} {
+	result := make(map[string]interface{}, len(meta))
+	for k, v := range meta {
+		result[k], _ = limitDepth(v, 2, maxDepth)
+	}
+	return result
+}
+
+// limitDepth returns v with maps and slices at depth deeper than maxDepth replaced with a placeholder, and true if
+// anything is replaced. Containers are rebuilt only if something in them is replaced
+func limitDepth(v interface{}, depth int, maxDepth int) (interface{}, bool) {
+	if v == nil {
+		return v, false
+	}
+
+	// Values with own JSON encoding, e.g. time.Time, are truncated the way they are serialized
+	if _, ok := v.(json.Marshaler); ok {
+		return limitEncoded(v, depth, maxDepth)
+	}
+
+	rv := reflect.ValueOf(v)
+	switch rv.Kind() {
+	case reflect.Pointer, reflect.Interface:
+		if rv.IsNil() {
+			return v, false
+		}
+		if elem, changed := limitDepth(rv.Elem().Interface(), depth, maxDepth); changed {
+			return elem, true
+		}
+		return v, false
+	case reflect.Map:
+		if rv.IsNil() {
+			return v, false
+		}
+		if depth > maxDepth {
+			return truncatedObject, true
+		}
+
+		changed := false
+		m := make(map[string]interface{}, rv.Len())
+		iter := rv.MapRange()
+		for iter.Next() {
+			child, childChanged := limitDepth(iter.Value().Interface(), depth+1, maxDepth)
+			changed = changed || childChanged
+			m[fmt.Sprint(iter.Key().Interface())] = child
+		}
+		if !changed {
+			return v, false
+		}
+		return m, true
+	case reflect.Slice, reflect.Array:
+		// Bytes are serialized as base64 string
+		if rv.Type().Elem().Kind() == reflect.Uint8 || (rv.Kind() == reflect.Slice && rv.IsNil()) {
+			return v, false
+		}
+		if depth > maxDepth {
+			return truncatedArray, true
+		}
+
+		changed := false
+		a := make([]interface{}, rv.Len())
+		for i := range a {
+			var childChanged bool
+			a[i], childChanged = limitDepth(rv.Index(i).Interface(), depth+1, maxDepth)
+			changed = changed || childChanged
+		}
+		if !changed {
+			return v, false
+		}
+		return a, true
+	case reflect.Struct:
+		return limitEncoded(v, depth, maxDepth)
+	default:
+		return v, false
+	}
+}
+
+// limitEncoded truncates v as it's serialized to JSON. Numbers are decoded as json.Number, so they keep precision.
+// If nothing is truncated, v is returned as is
+func limitEncoded(v interface{}, depth int, maxDepth int) (interface{}, bool) {
+	b, err := json.Marshal(v)
+	if err != nil {
+		// Keep as is, let serializer handle the error
+		return v, false
+	}
+
+	dec := json.NewDecoder(bytes.NewReader(b))
+	dec.UseNumber()
+	var normalized interface{}
+	if err = dec.Decode(&normalized); err != nil {
+		return v, false
+	}
+
+	if truncated, changed := limitDepth(normalized, depth, maxDepth); changed {
+		return truncated, true
+	}
+	return v, false
+}
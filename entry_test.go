@@ -0,0 +1,348 @@
+package logk
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/go-konsultin/logk/level"
+	logkOption "github.com/go-konsultin/logk/option"
+)
+
+// decodeLine decodes a single JSON entry, keeping numbers as written
+func decodeLine(t *testing.T, b []byte) map[string]interface{} {
+	t.Helper()
+	dec := json.NewDecoder(bytes.NewReader(b))
+	dec.UseNumber()
+	var line map[string]interface{}
+	if err := dec.Decode(&line); err != nil {
+		t.Fatalf("invalid JSON %q: %s", b, err)
+	}
+	return line
+}
+
+func TestLimitMetadataDepthTruncatesNestedMaps(t *testing.T) {
+	var buf bytes.Buffer
+	logger := NewStdLogger(NewJSONPrinter(&buf, WithMaxDepth(3)), logkOption.Level(level.Info))
+
+	logger.Info("nested", logkOption.AddMetadata("a", map[string]interface{}{
+		"b": map[string]interface{}{
+			"c":    map[string]interface{}{"d": 1},
+			"list": []interface{}{1, 2},
+			"leaf": "kept",
+		},
+		"shallow": []int{1, 2},
+	}))
+
+	meta := decodeLine(t, buf.Bytes())["metadata"].(map[string]interface{})
+	a := meta["a"].(map[string]interface{})
+	b := a["b"].(map[string]interface{})
+
+	if b["c"] != truncatedObject {
+		t.Errorf("a.b.c = %v, want %s", b["c"], truncatedObject)
+	}
+	if b["list"] != truncatedArray {
+		t.Errorf("a.b.list = %v, want %s", b["list"], truncatedArray)
+	}
+	if b["leaf"] != "kept" {
+		t.Errorf("a.b.leaf = %v, want kept", b["leaf"])
+	}
+	if got := a["shallow"]; !reflect.DeepEqual(got, []interface{}{json.Number("1"), json.Number("2")}) {
+		t.Errorf("a.shallow = %v, want [1 2]", got)
+	}
+}
+
+func TestLimitMetadataDepthTruncatesStructs(t *testing.T) {
+	type inner struct {
+		Values map[string]int `json:"values"`
+	}
+	type outer struct {
+		Inner inner `json:"inner"`
+	}
+
+	got := limitMetadataDepth(map[string]interface{}{"o": outer{Inner: inner{Values: map[string]int{"x": 1}}}}, 3)
+	want := map[string]interface{}{"o": map[string]interface{}{"inner": map[string]interface{}{"values": truncatedObject}}}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("got %#v, want %#v", got, want)
+	}
+}
+
+func TestLimitMetadataDepthKeepsValuesWithinLimit(t *testing.T) {
+	type point struct{ X, Y int }
+	meta := map[string]interface{}{
+		"int64":  int64(9007199254740993),
+		"uint":   uint(7),
+		"struct": point{X: 1, Y: 2},
+		"map":    map[string]int{"a": 1},
+		"nil":    nil,
+	}
+
+	got := limitMetadataDepth(meta, 3)
+	if !reflect.DeepEqual(got, meta) {
+		t.Errorf("got %#v, want %#v", got, meta)
+	}
+}
+
+func TestLimitMetadataDepthKeepsPrecision(t *testing.T) {
+	const big = int64(9007199254740993)
+
+	var jsonBuf bytes.Buffer
+	NewStdLogger(NewJSONPrinter(&jsonBuf, WithMaxDepth(3)), logkOption.Level(level.Info)).
+		Info("big", logkOption.AddMetadata("n", big), logkOption.AddMetadata("deep", map[string]interface{}{
+			"n": big,
+		}))
+
+	meta := decodeLine(t, jsonBuf.Bytes())["metadata"].(map[string]interface{})
+	if meta["n"] != json.Number("9007199254740993") {
+		t.Errorf("json n = %v, want 9007199254740993", meta["n"])
+	}
+	if deep := meta["deep"].(map[string]interface{}); deep["n"] != json.Number("9007199254740993") {
+		t.Errorf("json deep.n = %v, want 9007199254740993", deep["n"])
+	}
+
+	var stdBuf bytes.Buffer
+	NewStdLogger(NewStdLogPrinter(&stdBuf, 0, WithMaxDepth(3)), logkOption.Level(level.Info)).
+		Info("big", logkOption.AddMetadata("n", big))
+	if !strings.Contains(stdBuf.String(), "9007199254740993") {
+		t.Errorf("std output %q doesn't contain 9007199254740993", stdBuf.String())
+	}
+}
+
+// checkNoDuplicateKeys fails if an object in JSON entry has the same key more than once, which json.Unmarshal
+// would silently accept
+func checkNoDuplicateKeys(t *testing.T, b []byte) {
+	t.Helper()
+
+	dec := json.NewDecoder(bytes.NewReader(b))
+	var walk func(path string) error
+	walk = func(path string) error {
+		tok, err := dec.Token()
+		if err != nil {
+			return err
+		}
+
+		switch tok {
+		case json.Delim('{'):
+			keys := make(map[string]bool)
+			for dec.More() {
+				tok, err = dec.Token()
+				if err != nil {
+					return err
+				}
+				k := tok.(string)
+				if keys[k] {
+					t.Errorf("duplicate key %s in %s", path+"."+k, b)
+				}
+				keys[k] = true
+				if err = walk(path + "." + k); err != nil {
+					return err
+				}
+			}
+			_, err = dec.Token()
+		case json.Delim('['):
+			for dec.More() {
+				if err = walk(path + "[]"); err != nil {
+					return err
+				}
+			}
+			_, err = dec.Token()
+		}
+		return err
+	}
+
+	if err := walk(""); err != nil {
+		t.Fatalf("invalid JSON %q: %s", b, err)
+	}
+}
+
+func TestEntryHasNoDuplicateKeys(t *testing.T) {
+	AddExtractor(func(context.Context) map[string]interface{} {
+		return map[string]interface{}{
+			"user":   "context",
+			"tenant": "context",
+			"group":  map[string]interface{}{"a": "context", "b": "context"},
+		}
+	})
+	t.Cleanup(ClearExtractors)
+
+	printers := map[string]func(io.Writer) Printer{
+		"json": func(w io.Writer) Printer { return NewJSONPrinter(w) },
+		"ecs":  func(w io.Writer) Printer { return NewECSPrinter(w) },
+		"gcp":  func(w io.Writer) Printer { return NewGCPPrinter(w, "project") },
+	}
+	for name, newPrinter := range printers {
+		t.Run(name, func(t *testing.T) {
+			var buf bytes.Buffer
+			logger := NewStdLogger(newPrinter(&buf), logkOption.Level(level.Info)).With(
+				logkOption.AddMetadata("user", "field"),
+				logkOption.AddMetadata("group", map[string]interface{}{"b": "field", "c": "field"}),
+			)
+
+			logger.Info("entry",
+				logkOption.Context(context.Background()),
+				logkOption.AddMetadata("user", "call"),
+				logkOption.AddMetadata("group", map[string]interface{}{"c": "call"}),
+				logkOption.AddMetadata(logkOption.MessageKey, "call"),
+			)
+			checkNoDuplicateKeys(t, buf.Bytes())
+		})
+	}
+
+	// Call fields take precedence over persistent fields, which take precedence over context fields
+	var buf bytes.Buffer
+	logger := NewStdLogger(NewJSONPrinter(&buf), logkOption.Level(level.Info)).With(
+		logkOption.AddMetadata("user", "field"),
+		logkOption.AddMetadata("group", map[string]interface{}{"b": "field", "c": "field"}),
+	)
+	logger.Info("entry",
+		logkOption.Context(context.Background()),
+		logkOption.AddMetadata("user", "call"),
+		logkOption.AddMetadata("group", map[string]interface{}{"c": "call"}),
+	)
+
+	want := map[string]interface{}{
+		"user":   "call",
+		"tenant": "context",
+		"group":  map[string]interface{}{"a": "context", "b": "field", "c": "call"},
+	}
+	if got := decodeLine(t, buf.Bytes())[logkOption.MetadataKey]; !reflect.DeepEqual(got, want) {
+		t.Errorf("metadata = %v, want %v", got, want)
+	}
+}
+
+func TestMetadataPrecedence(t *testing.T) {
+	AddExtractor(func(context.Context) map[string]interface{} {
+		return map[string]interface{}{
+			"context": "context", "persistent": "context", "call": "context", "all": "context",
+		}
+	})
+	t.Cleanup(ClearExtractors)
+
+	var buf bytes.Buffer
+	logger := NewStdLogger(NewJSONPrinter(&buf), logkOption.Level(level.Info)).With(
+		logkOption.AddMetadata("persistent", "persistent"),
+		logkOption.AddMetadata("all", "persistent"),
+	)
+	logger.Info("entry",
+		logkOption.Context(context.Background()),
+		logkOption.AddMetadata("call", "call"),
+		logkOption.AddMetadata("all", "call"),
+	)
+
+	// Each key holds value of the layer with the highest precedence that sets it: context, then persistent, then call
+	want := map[string]interface{}{"context": "context", "persistent": "persistent", "call": "call", "all": "call"}
+	if got := decodeLine(t, buf.Bytes())[logkOption.MetadataKey]; !reflect.DeepEqual(got, want) {
+		t.Errorf("metadata = %v, want %v", got, want)
+	}
+}
+
+func TestMetadataFallback(t *testing.T) {
+	// Channel can't be serialized to JSON
+	unmarshalable := logkOption.AddMetadata("events", make(chan int))
+
+	tests := []struct {
+		name     string
+		fallback MetadataFallback
+		want     func(metadata interface{}, ok bool) bool
+	}{
+		{"best effort", MetadataBestEffort, func(metadata interface{}, ok bool) bool {
+			s, _ := metadata.(string)
+			return ok && strings.Contains(s, "events:0x") && strings.Contains(s, "user:42")
+		}},
+		{"drop", MetadataDrop, func(_ interface{}, ok bool) bool {
+			return !ok
+		}},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			logger := NewStdLogger(NewJSONPrinter(&buf, WithMetadataFallback(tc.fallback)),
+				logkOption.Level(level.Info))
+			logger.Info("entry", unmarshalable, logkOption.AddMetadata("user", 42))
+
+			// Entry is written without metadata that can't be serialized
+			line := decodeLine(t, buf.Bytes())
+			if line[logkOption.MessageKey] != "entry" || line[logkOption.LevelKey] != "info" {
+				t.Errorf("entry = %v, want info entry", line)
+			}
+			if metadata, ok := line[logkOption.MetadataKey]; !tc.want(metadata, ok) {
+				t.Errorf("metadata = %v, %v", metadata, ok)
+			}
+
+			options := logkOption.Evaluate([]logkOption.SetterFunc{unmarshalable})
+			s, ok := NewEntry("", level.Info, "entry", options, WithMetadataFallback(tc.fallback)).MetadataString()
+			if ok != (tc.fallback == MetadataBestEffort) || (ok && !strings.Contains(s, "events:0x")) {
+				t.Errorf("metadata string = %q, %v", s, ok)
+			}
+		})
+	}
+}
+
+func TestExplicitNulls(t *testing.T) {
+	var nilPointer *struct{ Name string }
+	metadata := []logkOption.SetterFunc{
+		logkOption.AddMetadata("nilInterface", nil),
+		logkOption.AddMetadata("nilPointer", nilPointer),
+		logkOption.AddMetadata("emptySlice", []string{}),
+		logkOption.AddMetadata("emptyString", ""),
+	}
+	wantMetadata := map[string]interface{}{
+		"nilInterface": nil,
+		"nilPointer":   nil,
+		"emptySlice":   []interface{}{},
+		"emptyString":  "",
+	}
+	unset := []string{
+		logkOption.NamespaceKey, logkOption.RequestIdKey, logkOption.BaggageKey, logkOption.ErrorKey,
+	}
+
+	t.Run("explicit", func(t *testing.T) {
+		var buf bytes.Buffer
+		logger := NewStdLogger(NewJSONPrinter(&buf, WithExplicitNulls()), logkOption.Level(level.Info))
+
+		logger.Info("entry", metadata...)
+		line := decodeLine(t, buf.Bytes())
+		if got := line[logkOption.MetadataKey]; !reflect.DeepEqual(got, wantMetadata) {
+			t.Errorf("metadata = %#v, want %#v", got, wantMetadata)
+		}
+		for _, k := range unset {
+			if _, ok := line[k]; !ok {
+				t.Errorf("%s is omitted", k)
+			}
+		}
+
+		// Metadata key is present without metadata
+		buf.Reset()
+		logger.Info("entry")
+		if v, ok := decodeLine(t, buf.Bytes())[logkOption.MetadataKey]; !ok || v != nil {
+			t.Errorf("metadata = %v, %v, want null", v, ok)
+		}
+	})
+
+	t.Run("default", func(t *testing.T) {
+		var buf bytes.Buffer
+		logger := NewStdLogger(NewJSONPrinter(&buf), logkOption.Level(level.Info))
+
+		// Metadata is rendered as set, entry fields that are not set are omitted
+		logger.Info("entry", metadata...)
+		line := decodeLine(t, buf.Bytes())
+		if got := line[logkOption.MetadataKey]; !reflect.DeepEqual(got, wantMetadata) {
+			t.Errorf("metadata = %#v, want %#v", got, wantMetadata)
+		}
+		for _, k := range unset {
+			if v, ok := line[k]; ok {
+				t.Errorf("%s = %v, want omitted", k, v)
+			}
+		}
+
+		buf.Reset()
+		logger.Info("entry")
+		if v, ok := decodeLine(t, buf.Bytes())[logkOption.MetadataKey]; ok {
+			t.Errorf("metadata = %v, want omitted", v)
+		}
+	})
+}
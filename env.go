@@ -0,0 +1,159 @@
+package logk
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/go-konsultin/logk/level"
+)
+
+// FromEnv creates a logger configured entirely from environment variables:
+//
+//   - LOG_LEVEL and LOG_NAMESPACE set level and namespace
+//   - LOG_FORMAT selects text (default), json, logfmt, pretty, gcp or ecs printer
+//   - LOG_OUTPUT selects stdout (default), stderr or path of a file that entries are appended to
+//   - LOG_COLOR sets color of pretty format to auto (default), always or never
+//   - LOG_CALLER captures call site of every entry
+//   - LOG_SAMPLING wraps logger with per-level sample rates, e.g. info=100,debug=1000
+//   - LOG_LEVELS sets per-namespace levels with SetNamespaceLevel, e.g. db=debug,http=warn
+//
+// Invalid values are ignored and reported in returned error, while logger is always usable. Get uses FromEnv when
+// no logger is registered. File output is kept open for the lifetime of the process
+func FromEnv() (Logger, error) {
+	var errs []error
+
+	lv := level.Default
+	if s, ok := os.LookupEnv(EnvLogLevel); ok && s != "" {
+		parsed, err := level.ParseStrict(s)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", EnvLogLevel, err))
+		} else {
+			lv = parsed
+		}
+	}
+
+	b := New().Level(lv).Namespace(os.Getenv(EnvLogNamespace))
+
+	out, err := envOutput()
+	if err != nil {
+		errs = append(errs, err)
+	}
+	b.Output(out)
+
+	if err := envFormat(b); err != nil {
+		errs = append(errs, err)
+	}
+
+	rates, err := parseEnvPairs(EnvLogSampling)
+	if err != nil {
+		errs = append(errs, err)
+	}
+
+	for name, value := range rates {
+		sampledLevel, err := level.ParseStrict(name)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", EnvLogSampling, err))
+			continue
+		}
+
+		rate, err := strconv.Atoi(value)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", EnvLogSampling, err))
+			continue
+		}
+		b.Sampling(sampledLevel, rate)
+	}
+
+	if s, ok := os.LookupEnv(EnvLogCaller); ok && s != "" {
+		enabled, err := strconv.ParseBool(s)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", EnvLogCaller, err))
+		} else if enabled {
+			b.Caller()
+		}
+	}
+
+	levels, err := parseEnvPairs(EnvLogLevels)
+	if err != nil {
+		errs = append(errs, err)
+	}
+	for ns, value := range levels {
+		nsLevel, err := level.ParseStrict(value)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", EnvLogLevels, err))
+			continue
+		}
+		SetNamespaceLevel(ns, nsLevel)
+	}
+
+	return b.Build(), errors.Join(errs...)
+}
+
+func envOutput() (io.Writer, error) {
+	switch s := os.Getenv(EnvLogOutput); strings.ToLower(s) {
+	case "", "stdout":
+		return os.Stdout, nil
+	case "stderr":
+		return os.Stderr, nil
+	default:
+		f, err := os.OpenFile(s, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o644)
+		if err != nil {
+			return os.Stdout, fmt.Errorf("%s: %w", EnvLogOutput, err)
+		}
+		return f, nil
+	}
+}
+
+// envFormat sets format of b from LOG_FORMAT and LOG_COLOR, and leaves the default text format on invalid values
+func envFormat(b *Builder) error {
+	format := strings.ToLower(os.Getenv(EnvLogFormat))
+	switch format {
+	case "", "text":
+		b.Text()
+	case "json":
+		b.JSON()
+	case "logfmt":
+		b.Logfmt()
+	case "gcp":
+		b.GCP("")
+	case "ecs":
+		b.ECS()
+	case "pretty":
+		switch color := strings.ToLower(os.Getenv(EnvLogColor)); color {
+		case "", "auto":
+			b.Pretty()
+		case "always", "never":
+			b.Encoder(NewPrettyEncoder(color == "always"))
+		default:
+			b.Pretty()
+			return fmt.Errorf("%s: unknown color %q", EnvLogColor, color)
+		}
+	default:
+		return fmt.Errorf("%s: unknown format %q", EnvLogFormat, format)
+	}
+	return nil
+}
+
+// parseEnvPairs parses comma separated name=value pairs of environment variable key
+func parseEnvPairs(key string) (map[string]string, error) {
+	s := os.Getenv(key)
+	if s == "" {
+		return nil, nil
+	}
+
+	var errs []error
+	result := make(map[string]string)
+	for _, pair := range strings.Split(s, ",") {
+		name, value, ok := strings.Cut(strings.TrimSpace(pair), "=")
+		if !ok || name == "" {
+			errs = append(errs, fmt.Errorf("%s: invalid pair %q", key, pair))
+			continue
+		}
+		result[name] = value
+	}
+	return result, errors.Join(errs...)
+}
@@ -0,0 +1,53 @@
+package logk
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	logkOption "github.com/go-konsultin/logk/option"
+)
+
+func TestFromEnvCallerWithSampling(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "app.log")
+	t.Setenv(EnvLogOutput, path)
+	t.Setenv(EnvLogFormat, "json")
+	t.Setenv(EnvLogLevel, "info")
+	t.Setenv(EnvLogNamespace, "api")
+	t.Setenv(EnvLogSampling, "info=2")
+	t.Setenv(EnvLogCaller, "true")
+
+	logger, err := FromEnv()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Caller skips frame of sampling logger, as on logger built with Builder
+	line := nextLine()
+	logger.Info("started")
+
+	b, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	entry := decodeLine(t, b)
+	if want := fmt.Sprintf("env_test.go:%d", line); entry[logkOption.CallerKey] != want {
+		t.Errorf("caller = %v, want %s", entry[logkOption.CallerKey], want)
+	}
+	if entry[logkOption.NamespaceKey] != "api" {
+		t.Errorf("namespace = %v, want api", entry[logkOption.NamespaceKey])
+	}
+}
+
+func TestFromEnvInvalidFormat(t *testing.T) {
+	t.Setenv(EnvLogFormat, "xml")
+
+	logger, err := FromEnv()
+	if err == nil {
+		t.Error("unknown format is not reported")
+	}
+	if logger == nil {
+		t.Fatal("logger is nil on invalid config")
+	}
+}
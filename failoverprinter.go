@@ -0,0 +1,263 @@
+package logk
+
+import (
+	"fmt"
+	"io"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/go-konsultin/logk/level"
+	logkOption "github.com/go-konsultin/logk/option"
+)
+
+// Default failover printer options
+const (
+	defaultFailoverThreshold     = 5
+	defaultFailoverWindow        = 10 * time.Second
+	defaultFailoverProbeInterval = 30 * time.Second
+	defaultFailoverProbeWindow   = 5 * time.Second
+)
+
+type failoverState int8
+
+const (
+	// failoverClosed writes entries to primary
+	failoverClosed failoverState = iota
+	// failoverOpen writes entries to fallback
+	failoverOpen
+	// failoverProbing writes entries to both, until primary is considered recovered or fails again
+	failoverProbing
+)
+
+type FailoverOptions struct {
+	// Threshold is number of primary errors in Window that trips breaker
+	Threshold int
+	Window    time.Duration
+	// ProbeInterval is time entries are written to fallback before primary is probed again
+	ProbeInterval time.Duration
+	// ProbeWindow is time primary must be free of errors while probed, before entries are switched back to it
+	ProbeWindow time.Duration
+	// OnSwitch is called when entries are switched to fallback, with error that tripped breaker, and when they are
+	// switched back to primary, with nil error
+	OnSwitch func(fallback bool, err error)
+}
+
+type FailoverOption = func(*FailoverOptions)
+
+func WithFailoverThreshold(n int, window time.Duration) FailoverOption {
+	return func(o *FailoverOptions) {
+		o.Threshold = n
+		o.Window = window
+	}
+}
+
+func WithFailoverProbe(interval, window time.Duration) FailoverOption {
+	return func(o *FailoverOptions) {
+		o.ProbeInterval = interval
+		o.ProbeWindow = window
+	}
+}
+
+func WithFailoverOnSwitch(fn func(fallback bool, err error)) FailoverOption {
+	return func(o *FailoverOptions) {
+		o.OnSwitch = fn
+	}
+}
+
+// FailoverPrinter writes entries to primary printer, and switches to fallback printer, e.g. a local file, when
+// primary fails repeatedly. Errors of primary are panics of its Print, and errors reported with RecordError, which
+// is meant to be set as OnError callback of sink printers:
+//
+//	var failover *logk.FailoverPrinter
+//	primary := logkSink.NewHTTPPrinter(url, logkSink.WithOnError(func(err error) { failover.RecordError(err) }))
+//	failover = logk.NewFailoverPrinter(primary, fallback)
+//
+// After ProbeInterval, entries are written to both printers for ProbeWindow, as errors of sinks are reported
+// asynchronously. If primary doesn't fail in that time, entries are switched back to it. Switches are written to
+// Stderr as internal notices
+type FailoverPrinter struct {
+	primary   Printer
+	fallback  Printer
+	options   FailoverOptions
+	failovers atomic.Uint64
+
+	// mu guards fields below
+	mu         sync.Mutex
+	state      failoverState
+	failures   []time.Time
+	lastErr    error
+	switchedAt time.Time
+}
+
+func NewFailoverPrinter(primary, fallback Printer, args ...FailoverOption) *FailoverPrinter {
+	o := FailoverOptions{
+		Threshold:     defaultFailoverThreshold,
+		Window:        defaultFailoverWindow,
+		ProbeInterval: defaultFailoverProbeInterval,
+		ProbeWindow:   defaultFailoverProbeWindow,
+	}
+	for _, fn := range args {
+		fn(&o)
+	}
+
+	if o.Threshold < 1 {
+		o.Threshold = 1
+	}
+
+	// Init printers if nil
+	if primary == nil {
+		primary = NewStdLogPrinter(nil, 0)
+	}
+
+	if fallback == nil {
+		fallback = NewStdLogPrinter(nil, 0)
+	}
+
+	return &FailoverPrinter{primary: primary, fallback: fallback, options: o}
+}
+
+func (p *FailoverPrinter) Print(namespace string, lv level.LogLevel, msg string, options *logkOption.Options) {
+	state := p.advance(time.Now())
+
+	if state != failoverClosed {
+		p.fallback.Print(namespace, lv, msg, options)
+	}
+	if state == failoverOpen {
+		return
+	}
+
+	if err := printPanic(p.primary, namespace, lv, msg, options); err != nil {
+		p.RecordError(err)
+		// Entry is kept in fallback, unless it's already written there while probing
+		if state == failoverClosed {
+			p.fallback.Print(namespace, lv, msg, options)
+		}
+	}
+}
+
+// RecordError records failure of primary printer. It's safe to call from background goroutines of sinks
+func (p *FailoverPrinter) RecordError(err error) {
+	if err == nil {
+		return
+	}
+	now := time.Now()
+
+	p.mu.Lock()
+	p.lastErr = err
+	switch p.state {
+	case failoverClosed:
+		// Keep failures that are in window, the oldest first
+		i := 0
+		for i < len(p.failures) && now.Sub(p.failures[i]) >= p.options.Window {
+			i++
+		}
+		p.failures = append(p.failures[i:], now)
+		if len(p.failures) < p.options.Threshold {
+			p.mu.Unlock()
+			return
+		}
+		p.failures = p.failures[:0]
+	case failoverProbing:
+	default:
+		// Errors of entries that were written before breaker tripped
+		p.mu.Unlock()
+		return
+	}
+
+	probing := p.state == failoverProbing
+	p.state = failoverOpen
+	p.switchedAt = now
+	p.failovers.Add(1)
+	p.mu.Unlock()
+
+	if probing {
+		internalNotice("primary printer %T failed again while probed, writing to fallback %T: %s", p.primary,
+			p.fallback, err)
+	} else {
+		internalNotice("primary printer %T failed %d times in %s, switching to fallback %T: %s", p.primary,
+			p.options.Threshold, p.options.Window, p.fallback, err)
+	}
+	if p.options.OnSwitch != nil {
+		p.options.OnSwitch(true, err)
+	}
+}
+
+// advance moves breaker to probing when ProbeInterval elapses, and back to primary when ProbeWindow elapses
+// without errors. It returns the current state
+func (p *FailoverPrinter) advance(now time.Time) failoverState {
+	p.mu.Lock()
+	switch {
+	case p.state == failoverOpen && now.Sub(p.switchedAt) >= p.options.ProbeInterval:
+		p.state = failoverProbing
+		p.switchedAt = now
+	case p.state == failoverProbing && now.Sub(p.switchedAt) >= p.options.ProbeWindow:
+		p.state = failoverClosed
+		p.mu.Unlock()
+
+		internalNotice("primary printer %T recovered, switching back from fallback %T", p.primary, p.fallback)
+		if p.options.OnSwitch != nil {
+			p.options.OnSwitch(false, nil)
+		}
+		return failoverClosed
+	}
+	state := p.state
+	p.mu.Unlock()
+	return state
+}
+
+// Failed returns true while entries are written to fallback
+func (p *FailoverPrinter) Failed() bool {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.state != failoverClosed
+}
+
+// Failovers returns number of times entries are switched to fallback
+func (p *FailoverPrinter) Failovers() uint64 {
+	return p.failovers.Load()
+}
+
+// LastError returns the last error of primary, or nil if there is none, so printer can be a stats source
+func (p *FailoverPrinter) LastError() error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.lastErr
+}
+
+// Flush flushes both printers if they implement Flusher and returns the first error
+func (p *FailoverPrinter) Flush() error {
+	var err error
+	for _, child := range []Printer{p.primary, p.fallback} {
+		if f, ok := child.(Flusher); ok {
+			if fErr := f.Flush(); fErr != nil && err == nil {
+				err = fErr
+			}
+		}
+	}
+	return err
+}
+
+// Close closes both printers if they implement io.Closer and returns the first error
+func (p *FailoverPrinter) Close() error {
+	var err error
+	for _, child := range []Printer{p.primary, p.fallback} {
+		if c, ok := child.(io.Closer); ok {
+			if cErr := c.Close(); cErr != nil && err == nil {
+				err = cErr
+			}
+		}
+	}
+	return err
+}
+
+// printPanic prints entry and returns panic of printer as error
+func printPanic(p Printer, namespace string, lv level.LogLevel, msg string, options *logkOption.Options) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("printer %T panicked: %v", p, r)
+		}
+	}()
+	p.Print(namespace, lv, msg, options)
+	return nil
+}
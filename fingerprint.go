@@ -0,0 +1,134 @@
+package logk
+
+import (
+	"errors"
+	"fmt"
+	"hash/fnv"
+	"strings"
+	"unicode"
+
+	"github.com/go-konsultin/logk/level"
+	logkOption "github.com/go-konsultin/logk/option"
+)
+
+// fingerprintFrames is number of top stack frames that identify an error
+const fingerprintFrames = 3
+
+// Fingerprint returns stable grouping key of entry, so occurrences of the same failure can be grouped downstream,
+// e.g. by Sentry or Kibana, and by DedupPrinter and SamplingPrinter. It's a hash of type of root cause of entry
+// error, message template, and top frames of captured stack trace or call site. Entries without them are identified
+// by error message instead. Message and error message are normalized by replacing words with digits, e.g. ids and
+// addresses, so they don't split groups. Fingerprint that is set with logkOption.WithFingerprint is returned as is. Printers
+// attach it to ERROR and FATAL entries as "error.fingerprint"
+func Fingerprint(lv level.LogLevel, msg string, options *logkOption.Options) string {
+	if fp := options.Fingerprint(); fp != "" {
+		return fp
+	}
+
+	h := fnv.New64a()
+	write := func(s string) {
+		_, _ = h.Write([]byte(s))
+		_, _ = h.Write([]byte{0})
+	}
+
+	err := rootCause(options.Error())
+	if err != nil {
+		write(fmt.Sprintf("%T", err))
+	}
+
+	// Formatted message is identified by its format, other messages are normalized. Template is hashed along with
+	// location, so different messages of the same function are not grouped together
+	if len(options.FmtArgs) == 0 {
+		msg = normalizeFingerprint(msg)
+	}
+	write(msg)
+
+	if frames := topFrames(options.StackTrace(), fingerprintFrames); len(frames) > 0 {
+		for _, f := range frames {
+			write(f)
+		}
+	} else if c, ok := options.Caller(); ok && c.Function != "" {
+		write(c.Function)
+	} else if err != nil {
+		write(normalizeFingerprint(err.Error()))
+	}
+
+	return fmt.Sprintf("%016x", h.Sum64())
+}
+
+// FingerprintSamplingKey is SamplingKeyFunc that counts ERROR and FATAL entries by fingerprint, so the same
+// failure is sampled together regardless of its message. Other entries are counted by the default key
+func FingerprintSamplingKey(namespace string, lv level.LogLevel, msg string, options *logkOption.Options) string {
+	if lv <= level.Error {
+		return Fingerprint(lv, msg, options)
+	}
+	return defaultSamplingKey(namespace, lv, msg, options)
+}
+
+// rootCause returns the innermost error of err chain. Errors that wrap multiple errors are the root cause
+func rootCause(err error) error {
+	for err != nil {
+		next := errors.Unwrap(err)
+		if next == nil {
+			return err
+		}
+		err = next
+	}
+	return nil
+}
+
+// topFrames returns function names of the first n frames of stack trace, which holds function name and indented
+// file:line of each frame. Lines are left out, so fingerprint survives unrelated edits of the file
+func topFrames(stack string, n int) []string {
+	if stack == "" {
+		return nil
+	}
+
+	var frames []string
+	for _, line := range strings.Split(stack, "\n") {
+		if line == "" || strings.HasPrefix(line, "\t") {
+			continue
+		}
+		frames = append(frames, line)
+		if len(frames) == n {
+			break
+		}
+	}
+	return frames
+}
+
+// normalizeFingerprint replaces words that contain digits with #, e.g. "user 42 not found" with "user # not found"
+func normalizeFingerprint(s string) string {
+	var sb strings.Builder
+	sb.Grow(len(s))
+
+	isWord := func(r rune) bool {
+		return unicode.IsLetter(r) || unicode.IsDigit(r)
+	}
+
+	for len(s) > 0 {
+		i := strings.IndexFunc(s, func(r rune) bool { return !isWord(r) })
+		if i == 0 {
+			// Copy separators as is
+			j := strings.IndexFunc(s, isWord)
+			if j < 0 {
+				j = len(s)
+			}
+			sb.WriteString(s[:j])
+			s = s[j:]
+			continue
+		}
+		if i < 0 {
+			i = len(s)
+		}
+
+		word := s[:i]
+		if strings.IndexFunc(word, unicode.IsDigit) >= 0 {
+			sb.WriteByte('#')
+		} else {
+			sb.WriteString(word)
+		}
+		s = s[i:]
+	}
+	return sb.String()
+}
@@ -0,0 +1,54 @@
+package logk
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/go-konsultin/logk/level"
+	logkOption "github.com/go-konsultin/logk/option"
+)
+
+func TestFingerprintHashesTemplateWithLocation(t *testing.T) {
+	withCaller := func() *logkOption.Options {
+		o := logkOption.Evaluate([]logkOption.SetterFunc{logkOption.Error(errors.New("boom"))})
+		o.Values[logkOption.CallerKey] = logkOption.Caller{File: "handler.go", Line: 42, Function: "app.Handle"}
+		return o
+	}
+	withStack := func() *logkOption.Options {
+		o := logkOption.Evaluate([]logkOption.SetterFunc{logkOption.Error(errors.New("boom"))})
+		o.Values[logkOption.StackTraceKey] = "app.Handle\n\thandler.go:42\nmain.main\n\tmain.go:10\n"
+		return o
+	}
+
+	for name, newOptions := range map[string]func() *logkOption.Options{"caller": withCaller, "stack": withStack} {
+		t.Run(name, func(t *testing.T) {
+			a := Fingerprint(level.Error, "failed to load user 42", newOptions())
+			b := Fingerprint(level.Error, "failed to load user 7", newOptions())
+			c := Fingerprint(level.Error, "failed to save order 42", newOptions())
+
+			if a != b {
+				t.Errorf("messages of the same template have fingerprints %s and %s", a, b)
+			}
+			if a == c {
+				t.Errorf("messages of different templates in the same location have fingerprint %s", a)
+			}
+		})
+	}
+}
+
+func TestFingerprintWithoutLocation(t *testing.T) {
+	newOptions := func(err string) *logkOption.Options {
+		return logkOption.Evaluate([]logkOption.SetterFunc{logkOption.Error(errors.New(err))})
+	}
+
+	a := Fingerprint(level.Error, "request failed", newOptions("timeout after 30s"))
+	b := Fingerprint(level.Error, "request failed", newOptions("timeout after 10s"))
+	c := Fingerprint(level.Error, "request failed", newOptions("connection refused"))
+
+	if a != b {
+		t.Errorf("normalized errors have fingerprints %s and %s", a, b)
+	}
+	if a == c {
+		t.Errorf("different errors have fingerprint %s", a)
+	}
+}
@@ -0,0 +1,157 @@
+package logk
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"maps"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/go-konsultin/logk/level"
+	logkOption "github.com/go-konsultin/logk/option"
+)
+
+// Google Cloud Logging field names
+const (
+	gcpSeverityKey       = "severity"
+	gcpTraceKey          = "logging.googleapis.com/trace"
+	gcpSpanIdKey         = "logging.googleapis.com/spanId"
+	gcpSourceLocationKey = "logging.googleapis.com/sourceLocation"
+)
+
+// GCPHTTPRequestKey is metadata key of GCPHTTPRequest, which NewGCPEncoder writes as httpRequest field, e.g.
+//
+//	logger.Info("request finished", logkOption.AddMetadata(logk.GCPHTTPRequestKey, logk.GCPHTTPRequest{
+//		RequestMethod: r.Method,
+//		RequestUrl:    r.URL.String(),
+//		Status:        status,
+//		Latency:       time.Since(start),
+//	}))
+const GCPHTTPRequestKey = "httpRequest"
+
+// GCPHTTPRequest is HTTP request of entry, rendered by Cloud Logging in request log view
+type GCPHTTPRequest struct {
+	RequestMethod string        `json:"requestMethod,omitempty"`
+	RequestUrl    string        `json:"requestUrl,omitempty"`
+	RequestSize   int64         `json:"requestSize,omitempty,string"`
+	Status        int           `json:"status,omitempty"`
+	ResponseSize  int64         `json:"responseSize,omitempty,string"`
+	UserAgent     string        `json:"userAgent,omitempty"`
+	RemoteIp      string        `json:"remoteIp,omitempty"`
+	ServerIp      string        `json:"serverIp,omitempty"`
+	Referer       string        `json:"referer,omitempty"`
+	Protocol      string        `json:"protocol,omitempty"`
+	Latency       time.Duration `json:"-"`
+}
+
+// MarshalJSON writes latency in seconds with unit suffix, as Cloud Logging expects, e.g. "0.25s"
+func (r GCPHTTPRequest) MarshalJSON() ([]byte, error) {
+	type request GCPHTTPRequest
+	v := struct {
+		request
+		Latency string `json:"latency,omitempty"`
+	}{request: request(r)}
+
+	if r.Latency > 0 {
+		v.Latency = strconv.FormatFloat(r.Latency.Seconds(), 'f', -1, 64) + "s"
+	}
+	return json.Marshal(v)
+}
+
+// gcpSourceLocation is call site of entry
+type gcpSourceLocation struct {
+	File     string `json:"file"`
+	Line     string `json:"line"`
+	Function string `json:"function,omitempty"`
+}
+
+var gcpSeverity = map[level.LogLevel]string{
+	level.Fatal: "CRITICAL",
+	level.Error: "ERROR",
+	level.Warn:  "WARNING",
+	level.Info:  "INFO",
+	level.Debug: "DEBUG",
+	level.Trace: "DEBUG",
+}
+
+// NewGCPPrinter creates a printer that writes entries as single-line JSON recognized by Google Cloud Logging, as
+// expected by structured logging of Cloud Run and GKE. Trace id, or request id when there is no active span, is
+// written as trace. If projectId is set, it is written as trace resource name "projects/{projectId}/traces/{traceId}".
+// Caller is written as sourceLocation, and metadata in GCPHTTPRequestKey as httpRequest
+func NewGCPPrinter(out io.Writer, projectId string, args ...PrinterOption) *encoderPrinter {
+	// If writer is nil, set default writer to Stdout
+	if out == nil {
+		out = os.Stdout
+	}
+
+	return NewEncoderPrinter(NewGCPEncoder(projectId), AddSync(out), args...)
+}
+
+// NewGCPEncoder creates an encoder that formats entries as single-line JSON recognized by Google Cloud Logging,
+// as written by NewGCPPrinter
+func NewGCPEncoder(projectId string) Encoder {
+	return &gcpEncoder{projectId: projectId}
+}
+
+type gcpEncoder struct {
+	projectId string
+}
+
+func (e *gcpEncoder) Encode(entry *Entry) ([]byte, error) {
+	severity, ok := gcpSeverity[entry.Level]
+	if !ok {
+		severity = "DEFAULT"
+	}
+
+	// Replace level with severity, other fields are kept as is in jsonPayload
+	line := entry.Fields()
+	delete(line, logkOption.LevelKey)
+	line[gcpSeverityKey] = severity
+
+	traceId := entry.TraceId
+	if traceId == "" {
+		traceId = entry.RequestId
+	}
+
+	if traceId != "" {
+		if e.projectId != "" {
+			line[gcpTraceKey] = fmt.Sprintf("projects/%s/traces/%s", e.projectId, traceId)
+		} else {
+			line[gcpTraceKey] = traceId
+		}
+	}
+
+	if entry.SpanId != "" {
+		line[gcpSpanIdKey] = entry.SpanId
+	}
+
+	if entry.Caller.File != "" {
+		delete(line, logkOption.CallerKey)
+		line[gcpSourceLocationKey] = gcpSourceLocation{
+			File:     entry.Caller.File,
+			Line:     strconv.Itoa(entry.Caller.Line),
+			Function: entry.Caller.Function,
+		}
+	}
+
+	// Move request out of metadata, without mutating metadata of entry
+	if req, ok := entry.Metadata[GCPHTTPRequestKey]; ok {
+		line[GCPHTTPRequestKey] = req
+
+		metadata := maps.Clone(entry.Metadata)
+		delete(metadata, GCPHTTPRequestKey)
+		if len(metadata) > 0 {
+			line[logkOption.MetadataKey] = metadata
+		} else {
+			delete(line, logkOption.MetadataKey)
+		}
+	}
+
+	b, err := marshalFields(line, entry.metadataFallback)
+	if err != nil {
+		return nil, err
+	}
+	return append(b, '\n'), nil
+}
@@ -0,0 +1,77 @@
+package logk
+
+import (
+	"fmt"
+
+	"github.com/go-konsultin/logk/level"
+	logkOption "github.com/go-konsultin/logk/option"
+)
+
+// go-kit conventional keys
+const (
+	goKitLevelKey   = "level"
+	goKitMessageKey = "msg"
+	goKitErrorKey   = "err"
+	goKitMissing    = "(MISSING)"
+)
+
+// GoKitLogger adapts Logger to go-kit log.Logger interface without importing go-kit
+type GoKitLogger struct {
+	logger Logger
+	level  level.LogLevel
+}
+
+// NewGoKitLogger creates go-kit compatible logger that writes to logger in lvl. Key values are converted to
+// metadata, "msg" is used as message, "err" is attached as error and "level" overrides output level
+func NewGoKitLogger(logger Logger, lvl level.LogLevel) *GoKitLogger {
+	if logger == nil {
+		logger = Get()
+	}
+	return &GoKitLogger{logger: logger, level: lvl}
+}
+
+// Log implements go-kit log.Logger
+func (g *GoKitLogger) Log(keyvals ...interface{}) error {
+	// Pad odd key values
+	if len(keyvals)%2 != 0 {
+		keyvals = append(keyvals, goKitMissing)
+	}
+
+	lv := g.level
+	msg := ""
+	var args []logkOption.SetterFunc
+	for i := 0; i < len(keyvals); i += 2 {
+		k, v := fmt.Sprint(keyvals[i]), keyvals[i+1]
+		switch k {
+		case goKitLevelKey:
+			lv = level.Parse(fmt.Sprint(v))
+		case goKitMessageKey:
+			msg = fmt.Sprint(v)
+		case goKitErrorKey:
+			if err, ok := v.(error); ok {
+				args = append(args, logkOption.Error(err))
+			} else {
+				args = append(args, logkOption.AddMetadata(k, v))
+			}
+		default:
+			args = append(args, logkOption.AddMetadata(k, v))
+		}
+	}
+
+	switch lv {
+	case level.Fatal:
+		// go-kit never exits, so fatal entry is only written
+		g.logger.NewChild(logkOption.WithFatalBehavior(logkOption.FatalNoop)).Fatal(msg, args...)
+	case level.Error:
+		g.logger.Error(msg, args...)
+	case level.Warn:
+		g.logger.Warn(msg, args...)
+	case level.Info:
+		g.logger.Info(msg, args...)
+	case level.Debug:
+		g.logger.Debug(msg, args...)
+	default:
+		g.logger.Trace(msg, args...)
+	}
+	return nil
+}
@@ -0,0 +1,31 @@
+package logk
+
+import (
+	"bytes"
+	"os"
+	"testing"
+
+	"github.com/go-konsultin/logk/level"
+	logkOption "github.com/go-konsultin/logk/option"
+)
+
+func TestGoKitLoggerFatalDoesNotExit(t *testing.T) {
+	exited := false
+	exit = func(int) { exited = true }
+	t.Cleanup(func() { exit = os.Exit })
+
+	var buf bytes.Buffer
+	logger := NewStdLogger(NewJSONPrinter(&buf), logkOption.Level(level.Info))
+
+	if err := NewGoKitLogger(logger, level.Info).Log("level", "fatal", "msg", "fatal entry"); err != nil {
+		t.Fatal(err)
+	}
+
+	if exited {
+		t.Error("go-kit fatal entry exited the process")
+	}
+	line := decodeLine(t, buf.Bytes())
+	if line[logkOption.LevelKey] != "fatal" || line[logkOption.MessageKey] != "fatal entry" {
+		t.Errorf("entry = %v, want fatal entry", line)
+	}
+}
@@ -0,0 +1,164 @@
+package logkHttp
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/http"
+	"time"
+
+	"github.com/go-konsultin/logk"
+	logkContext "github.com/go-konsultin/logk/context"
+	logkOption "github.com/go-konsultin/logk/option"
+)
+
+// AccessLog holds the request logging logic of Middleware, so adapters of other server frameworks write the same
+// entries. Call Begin when request starts and Finish or Panic when it ends
+type AccessLog struct {
+	logger  logk.Logger
+	options MiddlewareOptions
+}
+
+// NewAccessLog creates access log that writes to logger. If logger is nil, registered logger is used
+func NewAccessLog(logger logk.Logger, args ...MiddlewareOption) *AccessLog {
+	o := MiddlewareOptions{
+		RequestIdHeader:    DefaultRequestIdHeader,
+		RequestIdGenerator: newRequestId,
+		LevelFunc:          DefaultLevel,
+	}
+	for _, fn := range args {
+		fn(&o)
+	}
+	return &AccessLog{logger: logger, options: o}
+}
+
+// RequestIdHeader returns header that carries request id, to be written to response
+func (a *AccessLog) RequestIdHeader() string {
+	return a.options.RequestIdHeader
+}
+
+// Request is a request in progress
+type Request struct {
+	// Context is request context bound with request id and Logger
+	Context context.Context
+	// Logger is child logger carrying request fields
+	Logger logk.Logger
+	// RequestId is propagated or generated request id
+	RequestId string
+
+	options *MiddlewareOptions
+	start   time.Time
+	request *http.Request
+}
+
+// Begin reads or generates request id of r and creates its child logger. Trace of caller is read from traceparent
+// or X-Cloud-Trace-Context header, unless context already carries a trace. With WithTailLog, request context gets
+// a tail buffer, so DEBUG entries of request are written only if it fails
+func (a *AccessLog) Begin(r *http.Request) *Request {
+	req := Request{options: &a.options, start: time.Now(), request: r}
+
+	ctx := r.Context()
+	req.RequestId = logkContext.GetRequestId(ctx)
+	if req.RequestId == "" {
+		req.RequestId = r.Header.Get(a.options.RequestIdHeader)
+	}
+	if req.RequestId == "" {
+		req.RequestId = a.options.RequestIdGenerator()
+	}
+	ctx = logkContext.SetRequestId(ctx, req.RequestId)
+
+	if traceId, _ := logkContext.GetTrace(ctx); traceId == "" {
+		if traceId, spanId, ok := parseTrace(r.Header); ok {
+			ctx = logkContext.SetTrace(ctx, traceId, spanId)
+		}
+	}
+
+	if a.options.TailSize > 0 {
+		ctx = logk.NewTailContext(ctx, a.options.TailSize)
+	}
+
+	logger := a.logger
+	if logger == nil {
+		logger = logk.Get()
+	}
+	req.Logger = logger.With(WithRequest(r, a.options.Request)).NewChildCtx(ctx)
+	req.Context = logk.NewContext(ctx, req.Logger)
+
+	if a.options.LogStart {
+		req.Logger.Debug("request started")
+	}
+
+	return &req
+}
+
+// Finish writes finished request entry with level determined by status. Zero status is written as 200, as
+// net/http does when handler writes nothing. Error returned by handler is attached as entry error
+func (r *Request) Finish(status int, bytes int64, err error) {
+	if status == 0 {
+		status = http.StatusOK
+	}
+
+	args := []logkOption.SetterFunc{
+		logkOption.AddMetadata(StatusKey, status),
+		logkOption.Field.Duration(DurationKey, time.Since(r.start)),
+		logkOption.AddMetadata(BytesKey, bytes),
+	}
+	if err != nil {
+		args = append(args, logkOption.Error(err))
+	}
+	if r.options.GCPHTTPRequest {
+		args = append(args, logkOption.AddMetadata(logk.GCPHTTPRequestKey, r.gcpHTTPRequest(status, bytes)))
+	}
+
+	logAt(r.Logger, r.options.LevelFunc(status), "request finished", args...)
+}
+
+// Panic writes ERROR entry for request which handler panicked with v, with stack trace of the panic. It must be
+// called from deferred function that recovered v
+func (r *Request) Panic(v interface{}, bytes int64) {
+	r.Logger.Error("request panicked",
+		logkOption.AddMetadata(PanicKey, fmt.Sprint(v)),
+		logkOption.AddMetadata(StatusKey, http.StatusInternalServerError),
+		logkOption.Field.Duration(DurationKey, time.Since(r.start)),
+		logkOption.AddMetadata(BytesKey, bytes),
+		logkOption.WithStackTrace())
+}
+
+func (r *Request) gcpHTTPRequest(status int, bytes int64) logk.GCPHTTPRequest {
+	req := logk.GCPHTTPRequest{
+		RequestMethod: r.request.Method,
+		Status:        status,
+		ResponseSize:  bytes,
+		UserAgent:     r.request.UserAgent(),
+		Referer:       r.request.Referer(),
+		Protocol:      r.request.Proto,
+		Latency:       time.Since(r.start),
+	}
+
+	if r.request.URL != nil {
+		req.RequestUrl = r.request.URL.String()
+	}
+	if r.request.ContentLength > 0 {
+		req.RequestSize = r.request.ContentLength
+	}
+
+	req.RemoteIp = r.request.RemoteAddr
+	if host, _, err := net.SplitHostPort(r.request.RemoteAddr); err == nil {
+		req.RemoteIp = host
+	}
+	return req
+}
+
+// parseTrace returns trace of caller propagated in traceparent or X-Cloud-Trace-Context header
+func parseTrace(header http.Header) (traceId string, spanId string, ok bool) {
+	if v := header.Get(logkContext.TraceparentHeader); v != "" {
+		if traceId, spanId, ok = logkContext.ParseTraceparent(v); ok {
+			return traceId, spanId, true
+		}
+	}
+
+	if v := header.Get(logkContext.CloudTraceContextHeader); v != "" {
+		return logkContext.ParseCloudTraceContext(v)
+	}
+	return "", "", false
+}
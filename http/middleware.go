@@ -0,0 +1,182 @@
+package logkHttp
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"net/http"
+
+	"github.com/go-konsultin/logk"
+	"github.com/go-konsultin/logk/level"
+	logkOption "github.com/go-konsultin/logk/option"
+)
+
+// DefaultRequestIdHeader is header that carries request id between services
+const DefaultRequestIdHeader = "X-Request-Id"
+
+// Metadata keys of finished request entry
+const (
+	StatusKey   = "status"
+	DurationKey = "duration"
+	BytesKey    = "bytes"
+	PanicKey    = "panic"
+)
+
+type MiddlewareOptions struct {
+	// RequestIdHeader is read to propagate request id and written to response
+	RequestIdHeader string
+	// RequestIdGenerator creates request id when request has none
+	RequestIdGenerator func() string
+	// LevelFunc determines level of finished request entry by response status
+	LevelFunc func(status int) level.LogLevel
+	// Request configures which parts of request are attached to child logger
+	Request RequestLogOptions
+	// LogStart writes a DEBUG entry when request starts
+	LogStart bool
+	// GCPHTTPRequest attaches finished request as logk.GCPHTTPRequest, which GCP printer writes as httpRequest
+	GCPHTTPRequest bool
+	// TailSize enables tail context of requests with buffer of given size, see logk.NewTailPrinter
+	TailSize int
+}
+
+type MiddlewareOption = func(*MiddlewareOptions)
+
+func WithRequestIdHeader(header string) MiddlewareOption {
+	return func(o *MiddlewareOptions) {
+		o.RequestIdHeader = header
+	}
+}
+
+func WithRequestIdGenerator(fn func() string) MiddlewareOption {
+	return func(o *MiddlewareOptions) {
+		o.RequestIdGenerator = fn
+	}
+}
+
+func WithLevelFunc(fn func(status int) level.LogLevel) MiddlewareOption {
+	return func(o *MiddlewareOptions) {
+		o.LevelFunc = fn
+	}
+}
+
+func WithRequestLogOptions(opts RequestLogOptions) MiddlewareOption {
+	return func(o *MiddlewareOptions) {
+		o.Request = opts
+	}
+}
+
+func WithLogStart(enabled bool) MiddlewareOption {
+	return func(o *MiddlewareOptions) {
+		o.LogStart = enabled
+	}
+}
+
+func WithGCPHTTPRequest(enabled bool) MiddlewareOption {
+	return func(o *MiddlewareOptions) {
+		o.GCPHTTPRequest = enabled
+	}
+}
+
+func WithTailLog(size int) MiddlewareOption {
+	return func(o *MiddlewareOptions) {
+		o.TailSize = size
+	}
+}
+
+// DefaultLevel writes server errors as ERROR, client errors as WARN and other responses as INFO
+func DefaultLevel(status int) level.LogLevel {
+	switch {
+	case status >= 500:
+		return level.Error
+	case status >= 400:
+		return level.Warn
+	default:
+		return level.Info
+	}
+}
+
+// Middleware logs every request when it is finished, with response status, duration and written bytes.
+// Request id is read from request header or generated, bound to request context and written to response header.
+// Each request gets a child logger carrying method, path and remote address, that handlers retrieve with
+// logk.FromContext. Panics are logged with stack trace and propagated. If logger is nil, registered logger is used
+func Middleware(logger logk.Logger, args ...MiddlewareOption) func(http.Handler) http.Handler {
+	access := NewAccessLog(logger, args...)
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			req := access.Begin(r)
+			w.Header().Set(access.RequestIdHeader(), req.RequestId)
+
+			rw := &responseWriter{ResponseWriter: w}
+			defer func() {
+				// Connection aborted on purpose is not a failure
+				if v := recover(); v != nil {
+					if v != http.ErrAbortHandler {
+						req.Panic(v, rw.bytes)
+					}
+					panic(v)
+				}
+			}()
+
+			next.ServeHTTP(rw, r.WithContext(req.Context))
+			req.Finish(rw.status, rw.bytes, nil)
+		})
+	}
+}
+
+// logAt writes entry in lv. FATAL is written as ERROR, so a request can't exit the process
+func logAt(logger logk.Logger, lv level.LogLevel, msg string, args ...logkOption.SetterFunc) {
+	switch lv {
+	case level.Fatal, level.Error:
+		logger.Error(msg, args...)
+	case level.Warn:
+		logger.Warn(msg, args...)
+	case level.Info:
+		logger.Info(msg, args...)
+	case level.Debug:
+		logger.Debug(msg, args...)
+	default:
+		logger.Trace(msg, args...)
+	}
+}
+
+// newRequestId returns 16 random bytes in hex
+func newRequestId() string {
+	b := make([]byte, 16)
+	_, _ = rand.Read(b)
+	return hex.EncodeToString(b)
+}
+
+// responseWriter records status and size of response
+type responseWriter struct {
+	http.ResponseWriter
+	status int
+	bytes  int64
+}
+
+func (w *responseWriter) WriteHeader(status int) {
+	// Informational responses are followed by the final one
+	if w.status == 0 && status >= 200 {
+		w.status = status
+	}
+	w.ResponseWriter.WriteHeader(status)
+}
+
+func (w *responseWriter) Write(p []byte) (int, error) {
+	if w.status == 0 {
+		w.status = http.StatusOK
+	}
+	n, err := w.ResponseWriter.Write(p)
+	w.bytes += int64(n)
+	return n, err
+}
+
+func (w *responseWriter) Flush() {
+	if f, ok := w.ResponseWriter.(http.Flusher); ok {
+		f.Flush()
+	}
+}
+
+// Unwrap returns underlying writer, so http.ResponseController can reach its optional interfaces
+func (w *responseWriter) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
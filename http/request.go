@@ -0,0 +1,104 @@
+package logkHttp
+
+import (
+	"net/http"
+	"strings"
+
+	logkOption "github.com/go-konsultin/logk/option"
+)
+
+// RequestKey is metadata key of attached request
+const RequestKey = "request"
+
+// Attached request field names
+const (
+	methodKey     = "method"
+	pathKey       = "path"
+	remoteAddrKey = "remoteAddr"
+	headersKey    = "headers"
+	queryKey      = "query"
+)
+
+// AllowAll allows every header or query parameter when set in allowlist
+const AllowAll = "*"
+
+const redactedValue = "***"
+
+// DefaultRedactHeaders are headers which values are redacted when RequestLogOptions.RedactHeaders is nil
+var DefaultRedactHeaders = []string{"Authorization", "Proxy-Authorization", "Cookie", "Set-Cookie"}
+
+// RequestLogOptions configure which parts of request are attached
+type RequestLogOptions struct {
+	// Headers is allowlist of header names to attach. Use AllowAll to attach every header
+	Headers []string
+	// QueryParams is allowlist of query parameter names to attach. Use AllowAll to attach every parameter
+	QueryParams []string
+	// RedactHeaders are header names which values are replaced with "***". If nil, DefaultRedactHeaders is used,
+	// set to an empty slice to disable redaction
+	RedactHeaders []string
+}
+
+// WithRequest attaches method, path, remote address and allowed headers and query parameters of r as metadata
+func WithRequest(r *http.Request, opts RequestLogOptions) logkOption.SetterFunc {
+	return logkOption.AddMetadata(RequestKey, requestFields(r, opts))
+}
+
+func requestFields(r *http.Request, opts RequestLogOptions) map[string]interface{} {
+	if r == nil {
+		return nil
+	}
+
+	fields := map[string]interface{}{
+		methodKey:     r.Method,
+		remoteAddrKey: r.RemoteAddr,
+	}
+
+	if r.URL != nil {
+		fields[pathKey] = r.URL.Path
+
+		if query := filterValues(r.URL.Query(), opts.QueryParams, nil); len(query) > 0 {
+			fields[queryKey] = query
+		}
+	}
+
+	redact := opts.RedactHeaders
+	if redact == nil {
+		redact = DefaultRedactHeaders
+	}
+
+	if headers := filterValues(r.Header, opts.Headers, redact); len(headers) > 0 {
+		fields[headersKey] = headers
+	}
+
+	return fields
+}
+
+// filterValues returns allowed values joined by comma. Keys are matched case-insensitively
+func filterValues(values map[string][]string, allow []string, redact []string) map[string]string {
+	if len(allow) == 0 || len(values) == 0 {
+		return nil
+	}
+
+	result := make(map[string]string)
+	for k, v := range values {
+		if !containsFold(allow, k) && !containsFold(allow, AllowAll) {
+			continue
+		}
+
+		if containsFold(redact, k) {
+			result[k] = redactedValue
+		} else {
+			result[k] = strings.Join(v, ", ")
+		}
+	}
+	return result
+}
+
+func containsFold(list []string, s string) bool {
+	for _, v := range list {
+		if strings.EqualFold(v, s) {
+			return true
+		}
+	}
+	return false
+}
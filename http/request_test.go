@@ -0,0 +1,97 @@
+package logkHttp
+
+import (
+	"net/http/httptest"
+	"reflect"
+	"testing"
+
+	logkOption "github.com/go-konsultin/logk/option"
+)
+
+func TestWithRequestRedactsHeaders(t *testing.T) {
+	r := httptest.NewRequest("GET", "/users/42?page=2&token=secret", nil)
+	r.RemoteAddr = "10.0.0.1:5123"
+	r.Header.Set("Authorization", "Bearer secret")
+	r.Header.Set("Cookie", "session=secret")
+	r.Header.Set("X-Api-Key", "secret")
+	r.Header.Set("User-Agent", "test")
+
+	tests := []struct {
+		name string
+		opts RequestLogOptions
+		want map[string]string
+	}{
+		{
+			name: "default redaction",
+			opts: RequestLogOptions{Headers: []string{AllowAll}},
+			want: map[string]string{
+				"Authorization": redactedValue,
+				"Cookie":        redactedValue,
+				"X-Api-Key":     "secret",
+				"User-Agent":    "test",
+			},
+		},
+		{
+			name: "allowlist matched case-insensitively",
+			opts: RequestLogOptions{Headers: []string{"authorization", "user-agent"}},
+			want: map[string]string{
+				"Authorization": redactedValue,
+				"User-Agent":    "test",
+			},
+		},
+		{
+			name: "custom redaction",
+			opts: RequestLogOptions{Headers: []string{AllowAll}, RedactHeaders: []string{"x-api-key"}},
+			want: map[string]string{
+				"Authorization": "Bearer secret",
+				"Cookie":        "session=secret",
+				"X-Api-Key":     redactedValue,
+				"User-Agent":    "test",
+			},
+		},
+		{
+			name: "redaction disabled",
+			opts: RequestLogOptions{Headers: []string{"Cookie"}, RedactHeaders: []string{}},
+			want: map[string]string{
+				"Cookie": "session=secret",
+			},
+		},
+		{
+			name: "no allowlist",
+			opts: RequestLogOptions{},
+			want: nil,
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			options := logkOption.Evaluate([]logkOption.SetterFunc{WithRequest(r, tc.opts)})
+			fields, ok := options.Metadata[RequestKey].(map[string]interface{})
+			if !ok {
+				t.Fatalf("request metadata = %v", options.Metadata[RequestKey])
+			}
+
+			if fields[methodKey] != "GET" || fields[pathKey] != "/users/42" || fields[remoteAddrKey] != "10.0.0.1:5123" {
+				t.Errorf("request fields = %v", fields)
+			}
+
+			headers, _ := fields[headersKey].(map[string]string)
+			if !reflect.DeepEqual(headers, tc.want) {
+				t.Errorf("headers = %v, want %v", headers, tc.want)
+			}
+		})
+	}
+}
+
+func TestWithRequestQueryAllowlist(t *testing.T) {
+	r := httptest.NewRequest("GET", "/search?q=logs&page=2&page=3&token=secret", nil)
+
+	fields := requestFields(r, RequestLogOptions{QueryParams: []string{"q", "PAGE"}})
+	want := map[string]string{"q": "logs", "page": "2, 3"}
+	if got, _ := fields[queryKey].(map[string]string); !reflect.DeepEqual(got, want) {
+		t.Errorf("query = %v, want %v", got, want)
+	}
+
+	if _, ok := requestFields(r, RequestLogOptions{})[queryKey]; ok {
+		t.Error("query is attached without allowlist")
+	}
+}
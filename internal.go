@@ -0,0 +1,34 @@
+package logk
+
+import (
+	"fmt"
+	"os"
+	"sync/atomic"
+	"time"
+)
+
+// internalWarnInterval limits how often internal warnings are written
+const internalWarnInterval = time.Minute
+
+var lastInternalWarn atomic.Int64
+
+// internalWarn writes a warning about logk itself to Stderr, at most once in internalWarnInterval
+func internalWarn(format string, args ...interface{}) {
+	now := time.Now().UnixNano()
+	last := lastInternalWarn.Load()
+	if last != 0 && now-last < int64(internalWarnInterval) {
+		return
+	}
+
+	if !lastInternalWarn.CompareAndSwap(last, now) {
+		return
+	}
+
+	_, _ = fmt.Fprintf(os.Stderr, "%s: %s\n", pkgName, fmt.Sprintf(format, args...))
+}
+
+// internalNotice writes a notice about logk itself to Stderr. Unlike internalWarn it's not rate limited, so it's only
+// used for rare events, e.g. failover of a printer
+func internalNotice(format string, args ...interface{}) {
+	_, _ = fmt.Fprintf(os.Stderr, "%s: %s\n", pkgName, fmt.Sprintf(format, args...))
+}
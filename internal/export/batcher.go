@@ -0,0 +1,102 @@
+package logkExport
+
+import (
+	"sync"
+	"time"
+)
+
+// Batcher groups encoded entries and submits each group as a single payload to dispatcher, when it reaches
+// maxSize entries or maxBytes, or when interval elapses since the first entry of the group
+type Batcher struct {
+	maxSize    int
+	maxBytes   int
+	interval   time.Duration
+	encode     func(items [][]byte) []byte
+	dispatcher *Dispatcher
+
+	// mu guards items, size and timer
+	mu    sync.Mutex
+	items [][]byte
+	size  int
+	timer *time.Timer
+}
+
+// NewBatcher creates a batcher that encodes groups of entries with encode and submits them to d. maxBytes and
+// interval are not applied if they are zero
+func NewBatcher(maxSize int, maxBytes int, interval time.Duration, encode func([][]byte) []byte,
+	d *Dispatcher) *Batcher {
+	if maxSize < 1 {
+		maxSize = 1
+	}
+
+	return &Batcher{
+		maxSize:    maxSize,
+		maxBytes:   maxBytes,
+		interval:   interval,
+		encode:     encode,
+		dispatcher: d,
+	}
+}
+
+// Add appends encoded entry to current group
+func (b *Batcher) Add(item []byte) {
+	b.mu.Lock()
+
+	// Submit current batch first if item doesn't fit in it
+	if b.maxBytes > 0 && len(b.items) > 0 && b.size+len(item) > b.maxBytes {
+		b.submitLocked()
+	}
+
+	b.items = append(b.items, item)
+	b.size += len(item)
+
+	if len(b.items) >= b.maxSize || (b.maxBytes > 0 && b.size >= b.maxBytes) {
+		b.submitLocked()
+	} else if b.timer == nil && b.interval > 0 {
+		b.timer = time.AfterFunc(b.interval, b.flushBuffer)
+	}
+	b.mu.Unlock()
+}
+
+// flushBuffer submits pending entries without waiting for delivery
+func (b *Batcher) flushBuffer() {
+	b.mu.Lock()
+	b.submitLocked()
+	b.mu.Unlock()
+}
+
+// Flush submits pending entries and waits until all batches are delivered
+func (b *Batcher) Flush() {
+	b.flushBuffer()
+	b.dispatcher.Flush()
+}
+
+// Close submits pending entries, waits until all batches are delivered and stops dispatcher
+func (b *Batcher) Close() {
+	b.flushBuffer()
+	b.dispatcher.Close()
+}
+
+func (b *Batcher) submitLocked() {
+	if b.timer != nil {
+		b.timer.Stop()
+		b.timer = nil
+	}
+
+	if len(b.items) == 0 {
+		return
+	}
+
+	payload := b.encode(b.items)
+	b.items = nil
+	b.size = 0
+
+	// Submit is done with lock held, so batches are queued in order. With blocking overflow policy, logging
+	// goroutines wait for the batch to be queued, which is the intended backpressure
+	b.dispatcher.Submit(payload)
+}
+
+// Dropped returns number of batches that are discarded by overflow policy of dispatcher
+func (b *Batcher) Dropped() uint64 {
+	return b.dispatcher.Dropped()
+}
@@ -0,0 +1,187 @@
+// Package logkExport delivers encoded entries to remote destinations in batches, with bounded concurrency, bounded
+// queue and retry. It is shared by network printers of sink package and exporters in nested modules
+package logkExport
+
+import (
+	"sync"
+	"sync/atomic"
+
+	"github.com/go-konsultin/logk"
+)
+
+// Dispatcher delivers payloads with bounded concurrency, bounded queue and bounded in-flight bytes.
+// It is shared by network printers, so bursts can't exhaust sockets or memory
+type Dispatcher struct {
+	send     func(payload []byte)
+	queue    chan []byte
+	maxBytes int64
+	overflow logk.OverflowPolicy
+	// onOverflow is called when queue or in-flight bytes limit is exceeded, before overflow policy is applied
+	onOverflow func()
+	dropped    atomic.Uint64
+	wg         sync.WaitGroup
+
+	// mu guards in-flight accounting below. inFlight is only written with lock held, but can be read without it
+	mu       sync.Mutex
+	cond     *sync.Cond
+	inFlight atomic.Int64
+	pending  int
+	closed   bool
+}
+
+// NewDispatcher creates a dispatcher that delivers payloads with send from concurrency workers. onOverflow is called
+// when queue or in-flight bytes limit is exceeded, before overflow policy is applied
+func NewDispatcher(concurrency int, queueSize int, maxBytes int64, overflow logk.OverflowPolicy, onOverflow func(),
+	send func([]byte)) *Dispatcher {
+	if concurrency < 1 {
+		concurrency = 1
+	}
+
+	if queueSize < 0 {
+		queueSize = 0
+	}
+
+	d := Dispatcher{
+		send:       send,
+		queue:      make(chan []byte, queueSize),
+		maxBytes:   maxBytes,
+		overflow:   overflow,
+		onOverflow: onOverflow,
+	}
+	d.cond = sync.NewCond(&d.mu)
+
+	// Start workers
+	d.wg.Add(concurrency)
+	for i := 0; i < concurrency; i++ {
+		go d.work()
+	}
+
+	return &d
+}
+
+func (d *Dispatcher) work() {
+	defer d.wg.Done()
+	for payload := range d.queue {
+		d.send(payload)
+		d.release(len(payload))
+	}
+}
+
+// Submit queues payload for delivery. Overflow policy is applied if queue or in-flight bytes limit is exceeded
+func (d *Dispatcher) Submit(payload []byte) {
+	size := int64(len(payload))
+
+	d.mu.Lock()
+	if d.closed {
+		d.mu.Unlock()
+		d.dropped.Add(1)
+		return
+	}
+
+	// Reserve in-flight bytes. A single payload larger than limit is allowed when nothing is in flight
+	notified := false
+	for d.maxBytes > 0 && d.inFlight.Load() > 0 && d.inFlight.Load()+size > d.maxBytes {
+		if !notified {
+			d.notifyOverflow()
+			notified = true
+		}
+
+		if d.overflow == logk.OverflowDrop {
+			d.mu.Unlock()
+			d.dropped.Add(1)
+			return
+		}
+		d.cond.Wait()
+
+		// Dispatcher may be closed while waiting, queue must not be written then
+		if d.closed {
+			d.mu.Unlock()
+			d.dropped.Add(1)
+			return
+		}
+	}
+	d.inFlight.Add(size)
+	d.pending++
+	d.mu.Unlock()
+
+	// Try to queue without blocking
+	select {
+	case d.queue <- payload:
+		return
+	default:
+	}
+
+	if !notified {
+		d.notifyOverflow()
+	}
+
+	if d.overflow == logk.OverflowBlock {
+		d.queue <- payload
+		return
+	}
+
+	d.dropped.Add(1)
+	d.release(len(payload))
+}
+
+func (d *Dispatcher) notifyOverflow() {
+	if d.onOverflow != nil {
+		d.onOverflow()
+	}
+}
+
+func (d *Dispatcher) release(size int) {
+	d.mu.Lock()
+	d.inFlight.Add(-int64(size))
+	d.pending--
+	d.cond.Broadcast()
+	d.mu.Unlock()
+}
+
+// Flush waits until all submitted payloads are delivered
+func (d *Dispatcher) Flush() {
+	d.mu.Lock()
+	for d.pending > 0 {
+		d.cond.Wait()
+	}
+	d.mu.Unlock()
+}
+
+// Close delivers remaining payloads and stops workers. Payloads submitted afterwards are dropped
+func (d *Dispatcher) Close() {
+	d.mu.Lock()
+	if d.closed {
+		d.mu.Unlock()
+		return
+	}
+	d.closed = true
+	// Wake up submitters waiting for in-flight bytes, so they leave
+	d.cond.Broadcast()
+	for d.pending > 0 {
+		d.cond.Wait()
+	}
+	d.mu.Unlock()
+
+	close(d.queue)
+	d.wg.Wait()
+}
+
+// QueueLen returns number of payloads waiting for a worker
+func (d *Dispatcher) QueueLen() int {
+	return len(d.queue)
+}
+
+// QueueCap returns capacity of queue
+func (d *Dispatcher) QueueCap() int {
+	return cap(d.queue)
+}
+
+// InFlight returns number of bytes that are queued or being delivered
+func (d *Dispatcher) InFlight() int64 {
+	return d.inFlight.Load()
+}
+
+// Dropped returns number of payloads that are discarded by overflow policy or submitted after close
+func (d *Dispatcher) Dropped() uint64 {
+	return d.dropped.Load()
+}
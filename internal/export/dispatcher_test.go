@@ -0,0 +1,50 @@
+package logkExport
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/go-konsultin/logk"
+)
+
+func TestDispatcherCloseWakesBlockedSubmitters(t *testing.T) {
+	release := make(chan struct{})
+	d := NewDispatcher(1, 16, 10, logk.OverflowBlock, nil, func([]byte) {
+		<-release
+	})
+
+	// The first payload fills in-flight bytes, the rest wait for them
+	d.Submit(make([]byte, 10))
+
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			d.Submit(make([]byte, 5))
+		}()
+	}
+
+	// Let submitters park on in-flight bytes
+	time.Sleep(50 * time.Millisecond)
+
+	closed := make(chan struct{})
+	go func() {
+		d.Close()
+		close(closed)
+	}()
+	time.Sleep(20 * time.Millisecond)
+	close(release)
+
+	select {
+	case <-closed:
+	case <-time.After(5 * time.Second):
+		t.Fatal("close did not return")
+	}
+	wg.Wait()
+
+	if got := d.Dropped(); got != 8 {
+		t.Errorf("dropped = %d, want 8", got)
+	}
+}
@@ -0,0 +1,54 @@
+package logkExport
+
+import (
+	"github.com/go-konsultin/logk"
+	"github.com/go-konsultin/logk/level"
+	logkOption "github.com/go-konsultin/logk/option"
+)
+
+// OTLPScopeName is instrumentation scope of exported log records
+const OTLPScopeName = "github.com/go-konsultin/logk"
+
+// OpenTelemetry semantic convention attribute keys
+const (
+	OTLPServiceNameKey      = "service.name"
+	otlpExceptionMessageKey = "exception.message"
+	otlpExceptionStackKey   = "exception.stacktrace"
+)
+
+// OTLPSeverity maps level to OpenTelemetry severity number
+var OTLPSeverity = map[level.LogLevel]int{
+	level.Fatal: 21,
+	level.Error: 17,
+	level.Warn:  13,
+	level.Info:  9,
+	level.Debug: 5,
+	level.Trace: 1,
+}
+
+// OTLPAttributes returns fields of entry other than those mapped to log record as attributes, with error and stack
+// trace named by semantic conventions. Metadata is merged into attributes, fields take precedence
+func OTLPAttributes(entry *logk.Entry) map[string]interface{} {
+	fields := entry.Fields()
+	for _, k := range []string{logkOption.TimeKey, logkOption.LevelKey, logkOption.MessageKey,
+		logkOption.TraceIdKey, logkOption.SpanIdKey, logkOption.MetadataKey} {
+		delete(fields, k)
+	}
+
+	if v, ok := fields[logkOption.ErrorKey]; ok {
+		delete(fields, logkOption.ErrorKey)
+		fields[otlpExceptionMessageKey] = v
+	}
+
+	if v, ok := fields[logkOption.StackTraceKey]; ok {
+		delete(fields, logkOption.StackTraceKey)
+		fields[otlpExceptionStackKey] = v
+	}
+
+	for k, v := range entry.Metadata {
+		if _, ok := fields[k]; !ok {
+			fields[k] = v
+		}
+	}
+	return fields
+}
@@ -0,0 +1,46 @@
+package logkExport
+
+import (
+	"errors"
+	"time"
+)
+
+// MaxRetryBackoff limits delay between retries
+const MaxRetryBackoff = 30 * time.Second
+
+// retryableError marks failures that may succeed when retried, e.g. network errors or 503 response
+type retryableError struct {
+	err error
+}
+
+func (e *retryableError) Error() string {
+	return e.err.Error()
+}
+
+func (e *retryableError) Unwrap() error {
+	return e.err
+}
+
+// Retryable marks err as a failure that may succeed when retried by Retry
+func Retryable(err error) error {
+	return &retryableError{err: err}
+}
+
+// Retry calls fn until it succeeds, returns an error that isn't marked with Retryable or maxRetries is reached.
+// Backoff is doubled on each attempt
+func Retry(maxRetries int, backoff time.Duration, fn func() error) error {
+	for attempt := 0; ; attempt++ {
+		err := fn()
+		if err == nil {
+			return nil
+		}
+
+		var retryable *retryableError
+		if !errors.As(err, &retryable) || attempt >= maxRetries {
+			return err
+		}
+
+		time.Sleep(backoff)
+		backoff = min(backoff*2, MaxRetryBackoff)
+	}
+}
@@ -0,0 +1,275 @@
+// Package logkIngest converts JSON lines written by other logging libraries into logk entries. It is shared by
+// bridges of libraries that can only be integrated through their writer
+package logkIngest
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"io"
+	"math"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/go-konsultin/logk"
+	"github.com/go-konsultin/logk/level"
+	logkOption "github.com/go-konsultin/logk/option"
+)
+
+// maxLineSize limits buffered partial line, so a writer that never terminates lines can't grow memory unbounded
+const maxLineSize = 1 << 20
+
+// timeLayouts are tried in order to parse string timestamps
+var timeLayouts = []string{
+	time.RFC3339Nano,
+	"2006-01-02T15:04:05.000Z0700",
+	"2006-01-02T15:04:05Z0700",
+	"2006-01-02 15:04:05.000Z0700",
+	"2006-01-02 15:04:05",
+}
+
+// Keys is the set of JSON keys of a library encoder that map to logk entry fields. Each field accepts several
+// candidates, the first key that is present wins. Keys that are not listed are written as metadata
+type Keys struct {
+	Time       []string
+	Level      []string
+	Message    []string
+	Namespace  []string
+	Caller     []string
+	Function   []string
+	File       []string
+	Error      []string
+	StackTrace []string
+}
+
+// LevelFunc converts level name of a library to logk level. It returns false if name is unknown
+type LevelFunc = func(name string) (level.LogLevel, bool)
+
+// Writer is an io.Writer that parses each line as a JSON object and prints it with printer. Lines that are not JSON
+// are printed as INFO message as is
+type Writer struct {
+	printer    logk.Printer
+	keys       Keys
+	parseLevel LevelFunc
+
+	mu  sync.Mutex
+	buf []byte
+}
+
+func NewWriter(printer logk.Printer, keys Keys, parseLevel LevelFunc) *Writer {
+	// Init printer if nil
+	if printer == nil {
+		printer = logk.NewStdLogPrinter(nil, 0)
+	}
+
+	return &Writer{printer: printer, keys: keys, parseLevel: parseLevel}
+}
+
+// Write prints every complete line in p. A trailing partial line is buffered until it is terminated
+func (w *Writer) Write(p []byte) (int, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	w.buf = append(w.buf, p...)
+	for {
+		i := bytes.IndexByte(w.buf, '\n')
+		if i < 0 {
+			break
+		}
+		w.printLine(w.buf[:i])
+		w.buf = w.buf[i+1:]
+	}
+
+	if len(w.buf) > maxLineSize {
+		w.printLine(w.buf)
+		w.buf = nil
+	}
+
+	// Release consumed buffer
+	if len(w.buf) == 0 {
+		w.buf = nil
+	}
+
+	return len(p), nil
+}
+
+// Sync prints buffered partial line and flushes printer
+func (w *Writer) Sync() error {
+	w.mu.Lock()
+	if len(w.buf) > 0 {
+		w.printLine(w.buf)
+		w.buf = nil
+	}
+	w.mu.Unlock()
+
+	if f, ok := w.printer.(logk.Flusher); ok {
+		return f.Flush()
+	}
+	return nil
+}
+
+// Close prints buffered partial line, then flushes and closes printer
+func (w *Writer) Close() error {
+	err := w.Sync()
+	if c, ok := w.printer.(io.Closer); ok {
+		if cErr := c.Close(); cErr != nil {
+			err = cErr
+		}
+	}
+	return err
+}
+
+func (w *Writer) printLine(line []byte) {
+	line = bytes.TrimSpace(line)
+	if len(line) == 0 {
+		return
+	}
+
+	options := logkOption.NewOptions()
+
+	var fields map[string]interface{}
+	dec := json.NewDecoder(bytes.NewReader(line))
+	dec.UseNumber()
+	if err := dec.Decode(&fields); err != nil {
+		options.Values[logkOption.TimeKey] = logk.Now()
+		w.printer.Print("", level.Info, string(line), options)
+		return
+	}
+
+	lv := level.Info
+	if name, ok := take(fields, w.keys.Level).(string); ok {
+		if parsed, ok := w.parseLevel(name); ok {
+			lv = parsed
+		}
+	}
+
+	msg, _ := take(fields, w.keys.Message).(string)
+	namespace, _ := take(fields, w.keys.Namespace).(string)
+
+	t, ok := parseTime(take(fields, w.keys.Time))
+	if !ok {
+		t = logk.Now()
+	}
+	options.Values[logkOption.TimeKey] = t
+
+	if c, ok := w.caller(fields); ok {
+		options.Values[logkOption.CallerKey] = c
+	}
+
+	if v := take(fields, w.keys.Error); v != nil {
+		options.Values[logkOption.ErrorKey] = errors.New(toString(v))
+	}
+
+	if s, ok := take(fields, w.keys.StackTrace).(string); ok && s != "" {
+		options.Values[logkOption.StackTraceKey] = s
+	}
+
+	if len(fields) > 0 {
+		options.Metadata = make(map[string]interface{}, len(fields))
+		for k, v := range fields {
+			options.Metadata[k] = normalize(v)
+		}
+	}
+
+	options.Level = lv
+	w.printer.Print(namespace, lv, msg, options)
+}
+
+// caller reads caller as "file:line" or as separated file and function keys
+func (w *Writer) caller(fields map[string]interface{}) (logkOption.Caller, bool) {
+	var c logkOption.Caller
+	if s, ok := take(fields, w.keys.Caller).(string); ok {
+		c = ParseCaller(s)
+	}
+
+	if s, ok := take(fields, w.keys.File).(string); ok {
+		fc := ParseCaller(s)
+		c.File, c.Line = fc.File, fc.Line
+	}
+
+	if s, ok := take(fields, w.keys.Function).(string); ok {
+		c.Function = s
+	}
+
+	return c, c.File != "" || c.Function != ""
+}
+
+// ParseCaller parses call site formatted as "file:line". Line is zero if it's absent
+func ParseCaller(s string) logkOption.Caller {
+	i := strings.LastIndexByte(s, ':')
+	if i < 0 {
+		return logkOption.Caller{File: s}
+	}
+
+	line, err := strconv.Atoi(s[i+1:])
+	if err != nil {
+		return logkOption.Caller{File: s}
+	}
+	return logkOption.Caller{File: s[:i], Line: line}
+}
+
+// take removes and returns value of the first present key
+func take(fields map[string]interface{}, keys []string) interface{} {
+	for _, k := range keys {
+		if v, ok := fields[k]; ok {
+			delete(fields, k)
+			return v
+		}
+	}
+	return nil
+}
+
+// parseTime parses epoch seconds, e.g. 1700000000.123, or formatted string
+func parseTime(v interface{}) (time.Time, bool) {
+	switch val := v.(type) {
+	case json.Number:
+		f, err := val.Float64()
+		if err != nil {
+			return time.Time{}, false
+		}
+		sec, frac := math.Modf(f)
+		return time.Unix(int64(sec), int64(frac*1e9)), true
+	case string:
+		for _, layout := range timeLayouts {
+			if t, err := time.Parse(layout, val); err == nil {
+				return t, true
+			}
+		}
+	}
+	return time.Time{}, false
+}
+
+// normalize converts json.Number to int64 or float64, so metadata has the same types as entries logged with logk
+func normalize(v interface{}) interface{} {
+	switch val := v.(type) {
+	case json.Number:
+		if i, err := val.Int64(); err == nil {
+			return i
+		}
+		f, _ := val.Float64()
+		return f
+	case map[string]interface{}:
+		for k, item := range val {
+			val[k] = normalize(item)
+		}
+	case []interface{}:
+		for i, item := range val {
+			val[i] = normalize(item)
+		}
+	}
+	return v
+}
+
+func toString(v interface{}) string {
+	if s, ok := v.(string); ok {
+		return s
+	}
+
+	b, err := json.Marshal(normalize(v))
+	if err != nil {
+		return ""
+	}
+	return string(b)
+}
@@ -0,0 +1,81 @@
+package logkIngest
+
+import (
+	"io"
+
+	"github.com/go-konsultin/logk"
+	"github.com/go-konsultin/logk/level"
+	logkOption "github.com/go-konsultin/logk/option"
+)
+
+// LoggerPrinter is a Printer that writes ingested entries with a logk Logger, so they go through level, hooks and
+// redaction of the logger. Time and caller of ingested entries are replaced by logger's own.
+// FATAL entries are written with FatalNoop behavior, as the library that produced them exits or panics by itself
+type LoggerPrinter struct {
+	logger logk.Logger
+}
+
+// NewLoggerPrinter creates a printer that writes to logger. If logger is nil, registered logger is used
+func NewLoggerPrinter(logger logk.Logger) *LoggerPrinter {
+	return &LoggerPrinter{logger: logger}
+}
+
+func (p *LoggerPrinter) Print(namespace string, lv level.LogLevel, msg string, options *logkOption.Options) {
+	logger := p.target()
+
+	var childArgs []logkOption.SetterFunc
+	if namespace != "" {
+		childArgs = append(childArgs, logkOption.WithNamespace(namespace))
+	}
+	if lv == level.Fatal {
+		childArgs = append(childArgs, logkOption.WithFatalBehavior(logkOption.FatalNoop))
+	}
+	if len(childArgs) > 0 {
+		logger = logger.NewChild(childArgs...)
+	}
+
+	args := make([]logkOption.SetterFunc, 0, 2)
+	if len(options.Metadata) > 0 {
+		args = append(args, logkOption.Metadata(options.Metadata))
+	}
+	if err := options.Error(); err != nil {
+		args = append(args, logkOption.Error(err))
+	}
+
+	switch lv {
+	case level.Fatal:
+		logger.Fatal(msg, args...)
+	case level.Error:
+		logger.Error(msg, args...)
+	case level.Warn:
+		logger.Warn(msg, args...)
+	case level.Info:
+		logger.Info(msg, args...)
+	case level.Debug:
+		logger.Debug(msg, args...)
+	default:
+		logger.Trace(msg, args...)
+	}
+}
+
+func (p *LoggerPrinter) Flush() error {
+	if f, ok := p.target().(logk.Flusher); ok {
+		return f.Flush()
+	}
+	return nil
+}
+
+// Close closes logger if it was set explicitly. Registered logger is left open, as it is shared
+func (p *LoggerPrinter) Close() error {
+	if c, ok := p.logger.(io.Closer); ok {
+		return c.Close()
+	}
+	return nil
+}
+
+func (p *LoggerPrinter) target() logk.Logger {
+	if p.logger != nil {
+		return p.logger
+	}
+	return logk.Get()
+}
@@ -0,0 +1,50 @@
+package logk
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"os"
+)
+
+// NewJSONPrinter creates a printer that writes each entry as a single-line JSON object, suitable for log
+// aggregation pipelines
+func NewJSONPrinter(out io.Writer, args ...PrinterOption) *encoderPrinter {
+	// If writer is nil, set default writer to Stdout
+	if out == nil {
+		out = os.Stdout
+	}
+
+	return NewEncoderPrinter(NewJSONEncoder(), AddSync(out), args...)
+}
+
+// NewJSONEncoder creates an encoder that formats each entry as a single-line JSON object
+func NewJSONEncoder() Encoder {
+	return jsonEncoder{}
+}
+
+type jsonEncoder struct{}
+
+func (jsonEncoder) Encode(entry *Entry) ([]byte, error) {
+	b, err := entry.MarshalJSON()
+	if err != nil {
+		return nil, err
+	}
+	return append(b, '\n'), nil
+}
+
+func (jsonEncoder) EncodeTo(buf *bytes.Buffer, entry *Entry) error {
+	fields := entry.Fields()
+	if err := json.NewEncoder(buf).Encode(fields); err == nil {
+		return nil
+	}
+
+	// Render metadata with fallback. Encoder doesn't write anything on error, so buffer is still empty
+	b, err := marshalFields(fields, entry.metadataFallback)
+	if err != nil {
+		return err
+	}
+	buf.Write(b)
+	buf.WriteByte('\n')
+	return nil
+}
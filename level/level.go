@@ -1,6 +1,9 @@
 package level
 
-import "strings"
+import (
+	"fmt"
+	"strings"
+)
 
 type LogLevel = int8
 
@@ -22,19 +25,30 @@ const (
 
 // Parse parse string value to level.LogLevel
 func Parse(level string) LogLevel {
+	l, err := ParseStrict(level)
+	if err != nil {
+		return Default
+	}
+	return l
+}
+
+// ParseStrict parse string value to level.LogLevel and returns error if value is unknown
+func ParseStrict(level string) (LogLevel, error) {
 	switch strings.ToLower(level) {
 	case "panic", "0", "fatal", "1":
-		return Fatal
+		return Fatal, nil
+	case "error", "3":
+		return Error, nil
 	case "warn", "4":
-		return Warn
+		return Warn, nil
 	case "info", "6":
-		return Info
+		return Info, nil
 	case "debug", "7":
-		return Debug
+		return Debug, nil
 	case "trace", "8":
-		return Trace
+		return Trace, nil
 	default:
-		return Default
+		return Default, fmt.Errorf("unknown log level %q", level)
 	}
 }
 
@@ -0,0 +1,123 @@
+package logk
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/go-konsultin/logk/level"
+)
+
+// maxLevelRequestSize limits body of level update request
+const maxLevelRequestSize = 64 << 10
+
+// levelPayload is the JSON body of LevelHandler. In update request, an empty namespace level removes the override
+type levelPayload struct {
+	Level      string            `json:"level,omitempty"`
+	Namespaces map[string]string `json:"namespaces"`
+}
+
+type levelErrorPayload struct {
+	Error string `json:"error"`
+}
+
+// LevelHandler returns an http.Handler to read and change levels at runtime.
+//
+// GET responds with level of the registered logger and namespace overrides, e.g.
+//
+//	{"level":"info","namespaces":{"db":"debug"}}
+//
+// PUT accepts the same body. Level is applied to the registered logger, which must implement LevelLogger, and each
+// namespace is applied with SetNamespaceLevel. An empty namespace level removes its override. Fields that are
+// absent are left unchanged
+func LevelHandler() http.Handler {
+	return http.HandlerFunc(serveLevel)
+}
+
+func serveLevel(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodGet:
+	case http.MethodPut:
+		if code, err := updateLevel(w, r); err != nil {
+			writeLevelResponse(w, code, levelErrorPayload{Error: err.Error()})
+			return
+		}
+	default:
+		w.Header().Set("Allow", "GET, PUT")
+		writeLevelResponse(w, http.StatusMethodNotAllowed,
+			levelErrorPayload{Error: fmt.Sprintf("method %s is not allowed", r.Method)})
+		return
+	}
+
+	payload := levelPayload{Namespaces: make(map[string]string)}
+	if ll, ok := Get().(LevelLogger); ok {
+		payload.Level = strings.ToLower(level.String(ll.GetLevel()))
+	}
+	for ns, lv := range NamespaceLevels() {
+		payload.Namespaces[ns] = strings.ToLower(level.String(lv))
+	}
+
+	writeLevelResponse(w, http.StatusOK, payload)
+}
+
+// updateLevel validates the whole request before applying it, so invalid request changes nothing
+func updateLevel(w http.ResponseWriter, r *http.Request) (int, error) {
+	var payload levelPayload
+	if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, maxLevelRequestSize)).Decode(&payload); err != nil {
+		return http.StatusBadRequest, fmt.Errorf("invalid request body: %w", err)
+	}
+
+	var ll LevelLogger
+	var lv level.LogLevel
+	if payload.Level != "" {
+		var ok bool
+		if ll, ok = Get().(LevelLogger); !ok {
+			return http.StatusNotImplemented, errors.New("registered logger does not support changing level")
+		}
+
+		var err error
+		if lv, err = level.ParseStrict(payload.Level); err != nil {
+			return http.StatusBadRequest, err
+		}
+	}
+
+	namespaces := make(map[string]*level.LogLevel, len(payload.Namespaces))
+	for ns, s := range payload.Namespaces {
+		if ns == "" {
+			return http.StatusBadRequest, errors.New("namespace must not be empty")
+		}
+
+		if s == "" {
+			namespaces[ns] = nil
+			continue
+		}
+
+		nsLevel, err := level.ParseStrict(s)
+		if err != nil {
+			return http.StatusBadRequest, fmt.Errorf("namespace %s: %w", ns, err)
+		}
+		namespaces[ns] = &nsLevel
+	}
+
+	if ll != nil {
+		ll.SetLevel(lv)
+	}
+
+	for ns, nsLevel := range namespaces {
+		if nsLevel == nil {
+			RemoveNamespaceLevel(ns)
+			continue
+		}
+		SetNamespaceLevel(ns, *nsLevel)
+	}
+
+	return http.StatusOK, nil
+}
+
+func writeLevelResponse(w http.ResponseWriter, code int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(code)
+	_ = json.NewEncoder(w).Encode(v)
+}
@@ -0,0 +1,95 @@
+package logk
+
+import (
+	"github.com/go-konsultin/logk/level"
+	logkOption "github.com/go-konsultin/logk/option"
+)
+
+// LevelPrinter creates a printer that selects the underlying printer by entry level, e.g. compact text for Info
+// and JSON for Error. Levels that are not mapped are written to fallback, or discarded if fallback is nil.
+// Entry timestamp is stamped before printing, so all printers agree on it
+func LevelPrinter(printers map[level.LogLevel]Printer, fallback Printer) *levelPrinter {
+	p := levelPrinter{
+		printers: make(map[level.LogLevel]Printer, len(printers)),
+		fallback: fallback,
+	}
+	for lv, child := range printers {
+		if child != nil {
+			p.printers[lv] = child
+		}
+	}
+	return &p
+}
+
+type levelPrinter struct {
+	printers map[level.LogLevel]Printer
+	fallback Printer
+}
+
+// Route maps every level between from and to, inclusive, to printer. It overrides previous mapping of those levels.
+// Use MultiPrinter to send a range to multiple printers, e.g.
+//
+//	LevelPrinter(nil, stdout).Route(level.Fatal, level.Error, MultiPrinter(stderr, pager))
+func (p *levelPrinter) Route(from, to level.LogLevel, printer Printer) *levelPrinter {
+	if from > to {
+		from, to = to, from
+	}
+
+	for lv := int(from); lv <= int(to); lv++ {
+		if printer == nil {
+			delete(p.printers, level.LogLevel(lv))
+			continue
+		}
+		p.printers[level.LogLevel(lv)] = printer
+	}
+	return p
+}
+
+func (p *levelPrinter) Print(namespace string, lv level.LogLevel, msg string, options *logkOption.Options) {
+	child, ok := p.printers[lv]
+	if !ok {
+		child = p.fallback
+	}
+
+	if child == nil {
+		return
+	}
+
+	stampTime(options)
+	child.Print(namespace, lv, msg, options)
+}
+
+// Flush flushes all printers that implement Flusher and returns the first error
+func (p *levelPrinter) Flush() error {
+	return MultiPrinter(p.all()...).Flush()
+}
+
+// Close closes all printers that implement io.Closer and returns the first error
+func (p *levelPrinter) Close() error {
+	return MultiPrinter(p.all()...).Close()
+}
+
+// all returns distinct printers, as a printer can be mapped to multiple levels
+func (p *levelPrinter) all() []Printer {
+	seen := make(map[Printer]bool)
+	var result []Printer
+	add := func(child Printer) {
+		if child != nil && !seen[child] {
+			seen[child] = true
+			result = append(result, child)
+		}
+	}
+
+	for _, child := range p.printers {
+		add(child)
+	}
+	add(p.fallback)
+	return result
+}
+
+// stampTime sets entry timestamp on options if it's not set yet
+func stampTime(options *logkOption.Options) {
+	if _, ok := options.Timestamp(); !ok {
+		options.Values[logkOption.TimeKey] = Now()
+	}
+}
@@ -0,0 +1,171 @@
+package logk
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode"
+
+	logkOption "github.com/go-konsultin/logk/option"
+)
+
+// Key prefixes of flattened fields in logfmt output
+const (
+	logfmtMetadataPrefix = "meta."
+	logfmtBaggagePrefix  = "baggage."
+)
+
+// logfmtLeadingKeys are written first and in order, other fields are sorted by key
+var logfmtLeadingKeys = []string{
+	logkOption.TimeKey,
+	logkOption.LevelKey,
+	logkOption.NamespaceKey,
+	logkOption.MessageKey,
+}
+
+// NewLogfmtPrinter creates a printer that writes each entry as key=value pairs on a single line. Metadata is
+// flattened into meta.<key> and baggage into baggage.<key>
+func NewLogfmtPrinter(out io.Writer, args ...PrinterOption) *encoderPrinter {
+	// If writer is nil, set default writer to Stdout
+	if out == nil {
+		out = os.Stdout
+	}
+
+	return NewEncoderPrinter(NewLogfmtEncoder(), AddSync(out), args...)
+}
+
+// NewLogfmtEncoder creates an encoder that formats each entry as key=value pairs on a single line
+func NewLogfmtEncoder() Encoder {
+	return logfmtEncoder{}
+}
+
+type logfmtEncoder struct{}
+
+func (e logfmtEncoder) Encode(entry *Entry) ([]byte, error) {
+	var buf bytes.Buffer
+	err := e.EncodeTo(&buf, entry)
+	return buf.Bytes(), err
+}
+
+func (logfmtEncoder) EncodeTo(sb *bytes.Buffer, entry *Entry) error {
+	fields := entry.Fields()
+	delete(fields, logkOption.MetadataKey)
+	delete(fields, logkOption.BaggageKey)
+
+	// Write leading keys
+	for _, k := range logfmtLeadingKeys {
+		if v, ok := fields[k]; ok {
+			writeLogfmtPair(sb, k, v)
+			delete(fields, k)
+		}
+	}
+
+	// Write other fields
+	for _, k := range sortedKeys(fields) {
+		writeLogfmtPair(sb, k, fields[k])
+	}
+
+	// Write flattened baggage and metadata
+	for _, k := range sortedKeys(entry.Baggage) {
+		writeLogfmtPair(sb, logfmtBaggagePrefix+k, entry.Baggage[k])
+	}
+
+	flat := make(map[string]interface{})
+	flattenLogfmt(flat, logfmtMetadataPrefix, entry.Metadata)
+	for _, k := range sortedKeys(flat) {
+		writeLogfmtPair(sb, k, flat[k])
+	}
+
+	sb.WriteByte('\n')
+	return nil
+}
+
+// flattenLogfmt flattens nested metadata maps into dst with dot separated keys
+func flattenLogfmt(dst map[string]interface{}, prefix string, src map[string]interface{}) {
+	for k, v := range src {
+		if nested, ok := v.(map[string]interface{}); ok && len(nested) > 0 {
+			flattenLogfmt(dst, prefix+k+".", nested)
+			continue
+		}
+		dst[prefix+k] = v
+	}
+}
+
+func writeLogfmtPair(sb *bytes.Buffer, k string, v interface{}) {
+	if sb.Len() > 0 {
+		sb.WriteByte(' ')
+	}
+	sb.WriteString(logfmtKey(k))
+	sb.WriteByte('=')
+	sb.WriteString(logfmtValue(v))
+}
+
+// logfmtKey replaces characters that are not allowed in logfmt keys with underscore
+func logfmtKey(k string) string {
+	if k == "" {
+		return "_"
+	}
+
+	return strings.Map(func(r rune) rune {
+		if r <= ' ' || r == '=' || r == '"' || r == unicode.ReplacementChar {
+			return '_'
+		}
+		return r
+	}, k)
+}
+
+// logfmtValue formats value, quoting and escaping it when needed
+func logfmtValue(v interface{}) string {
+	var s string
+	switch val := v.(type) {
+	case nil:
+		return "null"
+	case string:
+		s = val
+	case error:
+		s = val.Error()
+	case fmt.Stringer:
+		s = val.String()
+	case bool, int, int8, int16, int32, int64, uint, uint8, uint16, uint32, uint64, float32, float64:
+		return fmt.Sprint(val)
+	default:
+		b, err := json.Marshal(val)
+		if err != nil {
+			s = fmt.Sprintf("%+v", val)
+		} else {
+			s = string(b)
+		}
+	}
+
+	if needsLogfmtQuote(s) {
+		return strconv.Quote(s)
+	}
+	return s
+}
+
+func needsLogfmtQuote(s string) bool {
+	if s == "" {
+		return true
+	}
+
+	for _, r := range s {
+		if r <= ' ' || r == '=' || r == '"' || r == '\\' || !unicode.IsPrint(r) {
+			return true
+		}
+	}
+	return false
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -1,11 +1,12 @@
 package logk
 
 import (
+	"context"
 	"fmt"
-	stdLog "log"
-	"os"
+	"io"
 	"sync"
 
+	logkContext "github.com/go-konsultin/logk/context"
 	"github.com/go-konsultin/logk/level"
 	logkOption "github.com/go-konsultin/logk/option"
 )
@@ -50,31 +51,56 @@ type Logger interface {
 	// NewChild must create a child logger and inherit level, writer and other flags
 	// only option such as namespace could be overridden
 	NewChild(args ...logkOption.SetterFunc) Logger
+
+	// NewChildCtx must create a child logger like NewChild that is bound to ctx, so values in context such as
+	// request id are written on all child entries
+	NewChildCtx(ctx context.Context, args ...logkOption.SetterFunc) Logger
+
+	// With must create a logger like NewChild that merges metadata set in args into every subsequent entry
+	With(args ...logkOption.SetterFunc) Logger
+}
+
+// LevelLogger is implemented by loggers which level can be changed at runtime
+type LevelLogger interface {
+	Logger
+
+	// SetLevel must change level of logger safely while it is in use
+	SetLevel(lv level.LogLevel)
+
+	// GetLevel must return the current effective level
+	GetLevel() level.LogLevel
 }
 
 var log Logger
 var logMutex sync.RWMutex
 
-// Get retrieve logger instance and will fallback to StdLogger if no logger registered
+// Get retrieve logger instance and will fallback to logger configured with FromEnv if no logger registered
 func Get() Logger {
-	// If log is nil, initiate standard logger
-	if log == nil {
-		// Get logger from env
-		logLevelStr, _ := os.LookupEnv(EnvLogLevel)
-		logLevel := level.Parse(logLevelStr)
-
-		// Get logger prefix
-		namespace, _ := os.LookupEnv(EnvLogNamespace)
-
-		// Init standard logger
-		p := NewStdLogPrinter(os.Stdout, stdLog.LstdFlags)
-		l := NewStdLogger(p, logkOption.Level(logLevel), logkOption.WithNamespace(namespace))
-
-		// Register logger
-		Register(l)
-		log.Trace("No logger found. StdLogger initiated")
+	logMutex.RLock()
+	l := log
+	logMutex.RUnlock()
+
+	if l != nil {
+		return l
+	}
+
+	// Initiate logger from env, unless another goroutine has registered one in the meantime
+	logMutex.Lock()
+	initiated := log == nil
+	if initiated {
+		envLogger, err := FromEnv()
+		if err != nil {
+			internalWarn("invalid logger configuration in environment: %s", err)
+		}
+		log = envLogger
 	}
-	return log
+	l = log
+	logMutex.Unlock()
+
+	if initiated {
+		l.Trace("No logger found. Logger initiated from environment")
+	}
+	return l
 }
 
 func NewChild(args ...logkOption.SetterFunc) Logger {
@@ -83,17 +109,97 @@ func NewChild(args ...logkOption.SetterFunc) Logger {
 	return logger.NewChild(args...)
 }
 
-// Register a logger implementation instance
+func NewChildCtx(ctx context.Context, args ...logkOption.SetterFunc) Logger {
+	// Get parent logger
+	logger := Get()
+	return logger.NewChildCtx(ctx, args...)
+}
+
+// NewContext returns a copy of ctx that carries logger, e.g. a request-scoped child logger set by middleware
+func NewContext(ctx context.Context, logger Logger) context.Context {
+	if ctx == nil || logger == nil {
+		return ctx
+	}
+	return context.WithValue(ctx, logkContext.LoggerKey, logger)
+}
+
+// FromContext retrieves logger stored with NewContext and will fallback to registered logger if none is stored
+func FromContext(ctx context.Context) Logger {
+	if ctx != nil {
+		if l, ok := ctx.Value(logkContext.LoggerKey).(Logger); ok {
+			return l
+		}
+	}
+	return Get()
+}
+
+// Register a logger implementation instance. If the previous logger implements Flusher or io.Closer,
+// it will be flushed and closed after the new logger is installed. Children created from the previous logger share
+// its printers, use RegisterNoClose while they are still in use
 func Register(l Logger) {
+	if prev := swap(l); prev != nil && prev != l {
+		closeLogger(prev)
+	}
+}
+
+// RegisterNoClose a logger implementation instance without flushing and closing the previous logger.
+// Use it when the previous logger is still in use elsewhere
+func RegisterNoClose(l Logger) {
+	swap(l)
+}
+
+// swap installs l and returns previous logger. Previous logger is closed by caller after lock is released, so printers that log while flushing don't deadlock
+func swap(l Logger) Logger {
 	// If logger is nil, return error
 	if l == nil {
 		panic(fmt.Errorf("%s: logger to be registered is nil", pkgName))
 	}
 
-	// Set logger
 	logMutex.Lock()
 	defer logMutex.Unlock()
+
+	prev := log
 	log = l
+	return prev
+}
+
+// Flush writes out buffered entries of registered logger, if it implements Flusher. Call it before the process
+// exits, so entries queued in async and network printers are not lost
+func Flush() error {
+	logMutex.RLock()
+	l := log
+	logMutex.RUnlock()
+
+	if f, ok := l.(Flusher); ok {
+		return f.Flush()
+	}
+	return nil
+}
+
+// Close flushes registered logger and closes it, if it implements io.Closer. Logger stays registered, but entries
+// written afterwards may be dropped by closed printers
+func Close() error {
+	logMutex.RLock()
+	l := log
+	logMutex.RUnlock()
+
+	err := Flush()
+	if c, ok := l.(io.Closer); ok {
+		if cErr := c.Close(); cErr != nil && err == nil {
+			err = cErr
+		}
+	}
+	return err
+}
+
+func closeLogger(l Logger) {
+	// Errors are ignored, as there is no logger left to report them
+	if f, ok := l.(Flusher); ok {
+		_ = f.Flush()
+	}
+	if c, ok := l.(io.Closer); ok {
+		_ = c.Close()
+	}
 }
 
 // Clear logger implementation instance
@@ -0,0 +1,103 @@
+package logk
+
+import (
+	"sync"
+	"testing"
+
+	"github.com/go-konsultin/logk/level"
+	logkOption "github.com/go-konsultin/logk/option"
+)
+
+// closingPrinter counts flushes and closes
+type closingPrinter struct {
+	mu      sync.Mutex
+	printed int
+	flushed int
+	closed  int
+}
+
+func (p *closingPrinter) Print(string, level.LogLevel, string, *logkOption.Options) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.printed++
+}
+
+func (p *closingPrinter) Flush() error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.flushed++
+	return nil
+}
+
+func (p *closingPrinter) Close() error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.closed++
+	return nil
+}
+
+func restoreLogger(t *testing.T) {
+	logMutex.RLock()
+	prev := log
+	logMutex.RUnlock()
+
+	t.Cleanup(func() {
+		logMutex.Lock()
+		log = prev
+		logMutex.Unlock()
+	})
+}
+
+func TestRegisterFlushesAndClosesPrevious(t *testing.T) {
+	restoreLogger(t)
+
+	old := &closingPrinter{}
+	Register(NewStdLogger(old, logkOption.Level(level.Info)))
+
+	Register(NewStdLogger(&closingPrinter{}))
+	if old.flushed == 0 || old.closed != 1 {
+		t.Errorf("flushed = %d, closed = %d, want flushed and closed once", old.flushed, old.closed)
+	}
+}
+
+func TestRegisterNoCloseKeepsPrevious(t *testing.T) {
+	restoreLogger(t)
+
+	old := &closingPrinter{}
+	oldLogger := NewStdLogger(old, logkOption.Level(level.Info))
+	Register(oldLogger)
+	child := oldLogger.NewChild(logkOption.WithNamespace("child"))
+
+	RegisterNoClose(NewStdLogger(&closingPrinter{}))
+	if old.flushed != 0 || old.closed != 0 {
+		t.Errorf("flushed = %d, closed = %d, want neither", old.flushed, old.closed)
+	}
+
+	// Child of previous logger keeps writing to its printer
+	child.Info("still open")
+	if old.printed != 1 {
+		t.Errorf("printed = %d, want 1", old.printed)
+	}
+}
+
+func TestGetConcurrentInit(t *testing.T) {
+	restoreLogger(t)
+	Clear()
+
+	loggers := make([]Logger, 16)
+	var wg sync.WaitGroup
+	for i := range loggers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			loggers[i] = Get()
+		}()
+	}
+	wg.Wait()
+
+	for _, l := range loggers {
+		if l == nil || l != loggers[0] {
+			t.Fatal("concurrent Get initiated more than one logger")
+		}
+	}
+}
@@ -0,0 +1,336 @@
+package logkConfig
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	stdLog "log"
+	"os"
+	"regexp"
+	"time"
+
+	"github.com/go-konsultin/logk"
+	"github.com/go-konsultin/logk/level"
+	logkSink "github.com/go-konsultin/logk/sink"
+)
+
+// timeFormats maps names of TimeFormat to layouts, other values are used as layout
+var timeFormats = map[string]string{
+	"rfc3339":     time.RFC3339,
+	"rfc3339nano": time.RFC3339Nano,
+	"unix":        logk.TimeFormatUnix,
+	"unixmilli":   logk.TimeFormatUnixMilli,
+}
+
+// Validate checks config without side effects. All problems are returned joined, each as FieldError
+func (c *Config) Validate() error {
+	var errs []error
+	checkLevel := func(key string, s string) {
+		if s == "" {
+			return
+		}
+		if _, err := level.ParseStrict(s); err != nil {
+			errs = append(errs, &FieldError{Key: key, Err: err})
+		}
+	}
+
+	checkLevel("level", c.Level)
+	checkLevel("stackTraceLevel", c.StackTraceLevel)
+	for ns, lv := range c.NamespaceLevels {
+		checkLevel("namespaceLevels."+ns, lv)
+	}
+
+	for name, rate := range c.Sampling {
+		checkLevel("sampling."+name, name)
+		if rate < 1 {
+			errs = append(errs, fieldError("sampling."+name, "rate must be positive, got %d", rate))
+		}
+	}
+
+	for i, p := range c.Redact.Patterns {
+		if _, err := regexp.Compile(p); err != nil {
+			errs = append(errs, &FieldError{Key: fmt.Sprintf("redact.patterns[%d]", i), Err: err})
+		}
+	}
+
+	for i, p := range c.Printers {
+		checkLevel(printerKey(i, "level"), p.Level)
+		errs = append(errs, p.validate(i)...)
+	}
+
+	return errors.Join(errs...)
+}
+
+func (p *PrinterConfig) validate(i int) []error {
+	var errs []error
+	switch normalize(p.Type) {
+	case TypeText, TypeJSON, TypeLogfmt, TypePretty, TypeGCP, TypeECS:
+		if p.MaxSize < 0 || p.MaxAge < 0 || p.MaxBackups < 0 {
+			errs = append(errs, fieldError(printerKey(i, "output"), "rotation limits must not be negative"))
+		}
+	case TypeHTTP:
+		if p.URL == "" {
+			errs = append(errs, fieldError(printerKey(i, "url"), "is required"))
+		}
+		switch normalize(p.Format) {
+		case "", TypeJSON, TypeLogfmt:
+		default:
+			errs = append(errs, fieldError(printerKey(i, "format"), "unknown format %q", p.Format))
+		}
+	case TypeSyslog:
+		switch normalize(p.SyslogFormat) {
+		case "", "rfc5424", "rfc3164":
+		default:
+			errs = append(errs, fieldError(printerKey(i, "syslogFormat"), "unknown format %q", p.SyslogFormat))
+		}
+		switch normalize(p.SyslogFraming) {
+		case "", "newline", "octet-counting":
+		default:
+			errs = append(errs, fieldError(printerKey(i, "syslogFraming"), "unknown framing %q", p.SyslogFraming))
+		}
+		if (p.CertFile == "") != (p.KeyFile == "") {
+			errs = append(errs, fieldError(printerKey(i, "certFile"), "certFile and keyFile must be set together"))
+		}
+	case TypeJournald:
+	case "":
+		errs = append(errs, fieldError(printerKey(i, "type"), "is required"))
+	default:
+		errs = append(errs, fieldError(printerKey(i, "type"), "unknown type %q", p.Type))
+	}
+	return errs
+}
+
+// Build validates config and creates logger from it. Namespace levels and redaction rules are registered globally.
+// Closing logger closes outputs that are opened by Build
+func (c *Config) Build() (logk.Logger, error) {
+	if err := c.Validate(); err != nil {
+		return nil, err
+	}
+
+	printer, err := c.newPrinter()
+	if err != nil {
+		return nil, err
+	}
+
+	// Register global rules
+	for ns, lv := range c.NamespaceLevels {
+		logk.SetNamespaceLevel(ns, level.Parse(lv))
+	}
+	for _, r := range c.redactors() {
+		logk.AddRedactor(r)
+	}
+	if len(c.Redact.SensitiveKeys) > 0 {
+		logk.AddSensitiveKeys(c.Redact.SensitiveKeys...)
+	}
+
+	return c.newLogger(printer), nil
+}
+
+// newPrinter creates configured printers, and closes those already created if one fails
+func (c *Config) newPrinter() (logk.Printer, error) {
+	var printers []logk.Printer
+	for i := range c.Printers {
+		p, err := c.Printers[i].build()
+		if err != nil {
+			_ = logk.MultiPrinter(printers...).Close()
+			return nil, &FieldError{Key: fmt.Sprintf("printers[%d]", i), Err: err}
+		}
+		printers = append(printers, p)
+	}
+
+	switch len(printers) {
+	case 0:
+		return logk.NewStdLogPrinter(os.Stdout, stdLog.LstdFlags), nil
+	case 1:
+		return printers[0], nil
+	default:
+		return logk.MultiPrinter(printers...).ContinueOnError(), nil
+	}
+}
+
+// newLogger creates logger that writes to printer
+func (c *Config) newLogger(printer logk.Printer) logk.Logger {
+	b := logk.New().Level(parseLevel(c.Level)).Namespace(c.Namespace).Printer(printer)
+	for name, rate := range c.Sampling {
+		b.Sampling(level.Parse(name), rate)
+	}
+	if c.Caller {
+		b.Caller()
+	}
+	if c.StackTraceLevel != "" {
+		b.StackTrace(level.Parse(c.StackTraceLevel))
+	}
+	return b.Build()
+}
+
+// redactors creates redactors of redaction rules
+func (c *Config) redactors() []logk.Redactor {
+	var result []logk.Redactor
+	if len(c.Redact.Keys) > 0 {
+		result = append(result, logk.RedactKeys(c.Redact.Keys...))
+	}
+	for _, p := range c.Redact.Patterns {
+		result = append(result, logk.RedactPattern(regexp.MustCompile(p)))
+	}
+	return result
+}
+
+func (p *PrinterConfig) build() (logk.Printer, error) {
+	var po []logk.PrinterOption
+	if p.TimeFormat != "" {
+		layout, ok := timeFormats[normalize(p.TimeFormat)]
+		if !ok {
+			layout = p.TimeFormat
+		}
+		po = append(po, logk.WithTimeFormat(layout))
+	}
+	if p.UTC {
+		po = append(po, logk.WithUTC())
+	}
+
+	var printer logk.Printer
+	switch typ := normalize(p.Type); typ {
+	case TypeText, TypeJSON, TypeLogfmt, TypePretty, TypeGCP, TypeECS:
+		out, err := p.output()
+		if err != nil {
+			return nil, err
+		}
+
+		printer = newFormatPrinter(typ, out, p.ProjectId, po)
+		if c, ok := out.(io.Closer); ok && out != os.Stdout && out != os.Stderr {
+			printer = &ownedPrinter{Printer: printer, out: c}
+		}
+	case TypeHTTP:
+		args := []logkSink.HTTPOption{logkSink.WithPrinterOptions(po...)}
+		for k, v := range p.Headers {
+			args = append(args, logkSink.WithHeader(k, v))
+		}
+		if normalize(p.Format) == TypeLogfmt {
+			args = append(args, logkSink.WithEncoder(logk.NewLogfmtEncoder()),
+				logkSink.WithHeader("Content-Type", "text/plain"))
+		}
+		printer = logkSink.NewHTTPPrinter(p.URL, args...)
+	case TypeSyslog:
+		args := []logkSink.SyslogOption{logkSink.WithSyslogPrinterOptions(po...)}
+		if normalize(p.SyslogFormat) == "rfc3164" {
+			args = append(args, logkSink.WithSyslogFormat(logkSink.RFC3164))
+		}
+		if p.Facility != 0 {
+			args = append(args, logkSink.WithFacility(p.Facility))
+		}
+		if p.AppName != "" {
+			args = append(args, logkSink.WithAppName(p.AppName))
+		}
+
+		switch normalize(p.SyslogFraming) {
+		case "newline":
+			args = append(args, logkSink.WithSyslogFraming(logkSink.SyslogFramingNewline))
+		case "octet-counting":
+			args = append(args, logkSink.WithSyslogFraming(logkSink.SyslogFramingOctetCounting))
+		}
+		if p.CAFile != "" || p.CertFile != "" {
+			tlsConfig, err := logkSink.NewTLSConfig(p.CAFile, p.CertFile, p.KeyFile)
+			if err != nil {
+				return nil, err
+			}
+			args = append(args, logkSink.WithSyslogTLS(tlsConfig))
+		}
+
+		sp, err := logkSink.NewSyslogPrinter(p.Network, p.Address, args...)
+		if err != nil {
+			return nil, err
+		}
+		printer = sp
+	case TypeJournald:
+		args := []logkSink.JournaldOption{logkSink.WithJournaldPrinterOptions(po...)}
+		if p.AppName != "" {
+			args = append(args, logkSink.WithJournaldIdentifier(p.AppName))
+		}
+
+		jp, err := logkSink.NewJournaldPrinter(args...)
+		if err != nil {
+			return nil, err
+		}
+		printer = jp
+	}
+
+	if p.Async {
+		printer = logk.NewAsyncPrinter(printer)
+	}
+
+	// Only write levels from the most severe down to printer level
+	if p.Level != "" {
+		printer = logk.LevelPrinter(nil, nil).Route(level.Fatal, level.Parse(p.Level), printer)
+	}
+	return printer, nil
+}
+
+// output opens output of format printers
+func (p *PrinterConfig) output() (io.Writer, error) {
+	switch p.Output {
+	case "", OutputStdout:
+		return os.Stdout, nil
+	case OutputStderr:
+		return os.Stderr, nil
+	}
+
+	var args []logkSink.FileOption
+	if p.MaxSize > 0 {
+		args = append(args, logkSink.WithMaxSize(p.MaxSize))
+	}
+	if p.MaxAge > 0 {
+		args = append(args, logkSink.WithMaxAge(time.Duration(p.MaxAge)))
+	}
+	if p.MaxBackups > 0 {
+		args = append(args, logkSink.WithMaxBackups(p.MaxBackups))
+	}
+	if p.Compress {
+		args = append(args, logkSink.WithCompress(true))
+	}
+	return logkSink.NewFile(p.Output, args...)
+}
+
+func newFormatPrinter(typ string, out io.Writer, projectId string, po []logk.PrinterOption) logk.Printer {
+	switch typ {
+	case TypeJSON:
+		return logk.NewJSONPrinter(out, po...)
+	case TypeLogfmt:
+		return logk.NewLogfmtPrinter(out, po...)
+	case TypePretty:
+		return logk.NewPrettyPrinter(out, po...)
+	case TypeGCP:
+		return logk.NewGCPPrinter(out, projectId, po...)
+	case TypeECS:
+		return logk.NewECSPrinter(out, po...)
+	default:
+		return logk.NewStdLogPrinter(out, stdLog.LstdFlags, po...)
+	}
+}
+
+func parseLevel(s string) level.LogLevel {
+	if s == "" {
+		return level.Default
+	}
+	return level.Parse(s)
+}
+
+// ownedPrinter closes output that is opened for printer
+type ownedPrinter struct {
+	logk.Printer
+	out io.Closer
+}
+
+func (p *ownedPrinter) Flush() error {
+	if f, ok := p.Printer.(logk.Flusher); ok {
+		return f.Flush()
+	}
+	return nil
+}
+
+func (p *ownedPrinter) Close() error {
+	err := p.Flush()
+	if cErr := p.out.Close(); cErr != nil && err == nil {
+		err = cErr
+	}
+	return err
+}
@@ -0,0 +1,175 @@
+// Package logkConfig builds a fully wired logger from a declarative config file in JSON, YAML or TOML, e.g.
+//
+//	logger, err := logkConfig.Load("logging.yaml")
+//	if err != nil {
+//		panic(err)
+//	}
+//	logk.Register(logger)
+//
+// Example of YAML config, keys are the same in JSON and TOML:
+//
+//	level: info
+//	namespace: billing
+//	caller: true
+//	stackTraceLevel: error
+//	namespaceLevels:
+//	  db: debug
+//	sampling:
+//	  debug: 100
+//	redact:
+//	  keys: [password, "*token"]
+//	  patterns: ['\d{16}']
+//	printers:
+//	  - type: pretty
+//	  - type: json
+//	    output: /var/log/billing.log
+//	    level: warn
+//	    maxSize: 104857600
+//	    maxAge: 24h
+//	  - type: http
+//	    url: https://logs.example.com/ingest
+//	    async: true
+package logkConfig
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+const pkgName = "logk/logkconfig"
+
+// Printer types
+const (
+	TypeText     = "text"
+	TypeJSON     = "json"
+	TypeLogfmt   = "logfmt"
+	TypePretty   = "pretty"
+	TypeGCP      = "gcp"
+	TypeECS      = "ecs"
+	TypeHTTP     = "http"
+	TypeSyslog   = "syslog"
+	TypeJournald = "journald"
+)
+
+// Outputs of text, json, logfmt, pretty, gcp and ecs printers. Other values are paths of files
+const (
+	OutputStdout = "stdout"
+	OutputStderr = "stderr"
+)
+
+// Config is declarative configuration of a logger. Levels are names accepted by level.ParseStrict
+type Config struct {
+	// Level of logger, default is level.Default
+	Level     string `json:"level" yaml:"level" toml:"level"`
+	Namespace string `json:"namespace" yaml:"namespace" toml:"namespace"`
+	// Caller captures call site of every entry
+	Caller bool `json:"caller" yaml:"caller" toml:"caller"`
+	// StackTraceLevel captures stack trace of entries in level and more severe levels
+	StackTraceLevel string `json:"stackTraceLevel" yaml:"stackTraceLevel" toml:"stackTraceLevel"`
+	// NamespaceLevels overrides level of namespaces with logk.SetNamespaceLevel
+	NamespaceLevels map[string]string `json:"namespaceLevels" yaml:"namespaceLevels" toml:"namespaceLevels"`
+	// Sampling writes 1 in N entries of level, keyed by level name
+	Sampling map[string]int `json:"sampling" yaml:"sampling" toml:"sampling"`
+	Redact   RedactConfig   `json:"redact" yaml:"redact" toml:"redact"`
+	// Printers receive every entry in order. If it's empty, entries are written as text to Stdout
+	Printers []PrinterConfig `json:"printers" yaml:"printers" toml:"printers"`
+}
+
+// RedactConfig holds redaction rules that are registered globally
+type RedactConfig struct {
+	// Keys are glob patterns of metadata keys which values are redacted, see logk.RedactKeys
+	Keys []string `json:"keys" yaml:"keys" toml:"keys"`
+	// Patterns are regular expressions of values that are redacted, see logk.RedactPattern
+	Patterns []string `json:"patterns" yaml:"patterns" toml:"patterns"`
+	// SensitiveKeys are masked by printers, see logk.AddSensitiveKeys
+	SensitiveKeys []string `json:"sensitiveKeys" yaml:"sensitiveKeys" toml:"sensitiveKeys"`
+}
+
+// PrinterConfig configures a printer. Fields that don't apply to Type are ignored
+type PrinterConfig struct {
+	// Type is one of text, json, logfmt, pretty, gcp, ecs, http, syslog and journald
+	Type string `json:"type" yaml:"type" toml:"type"`
+	// Level is the least severe level written by printer. Default is all levels that pass logger level
+	Level string `json:"level" yaml:"level" toml:"level"`
+	// Async writes entries from background goroutine with logk.NewAsyncPrinter
+	Async bool `json:"async" yaml:"async" toml:"async"`
+	// TimeFormat is time layout or one of rfc3339, rfc3339nano, unix and unixmilli
+	TimeFormat string `json:"timeFormat" yaml:"timeFormat" toml:"timeFormat"`
+	// UTC writes timestamps in UTC
+	UTC bool `json:"utc" yaml:"utc" toml:"utc"`
+
+	// Output is stdout, stderr or path of a file, for text, json, logfmt, pretty, gcp and ecs. Default is stdout
+	Output string `json:"output" yaml:"output" toml:"output"`
+	// MaxSize, MaxAge, MaxBackups and Compress configure rotation of file output
+	MaxSize    int64    `json:"maxSize" yaml:"maxSize" toml:"maxSize"`
+	MaxAge     Duration `json:"maxAge" yaml:"maxAge" toml:"maxAge"`
+	MaxBackups int      `json:"maxBackups" yaml:"maxBackups" toml:"maxBackups"`
+	Compress   bool     `json:"compress" yaml:"compress" toml:"compress"`
+	// ProjectId is Google Cloud project of gcp trace resource names
+	ProjectId string `json:"projectId" yaml:"projectId" toml:"projectId"`
+
+	// URL and Headers configure http printer. Format is json or logfmt, default is json
+	URL     string            `json:"url" yaml:"url" toml:"url"`
+	Headers map[string]string `json:"headers" yaml:"headers" toml:"headers"`
+	Format  string            `json:"format" yaml:"format" toml:"format"`
+
+	// Network and Address of syslog server, network is udp, tcp or tls. If both are empty, local syslog is used
+	Network string `json:"network" yaml:"network" toml:"network"`
+	Address string `json:"address" yaml:"address" toml:"address"`
+	// SyslogFormat is rfc5424 or rfc3164, default is rfc5424
+	SyslogFormat string `json:"syslogFormat" yaml:"syslogFormat" toml:"syslogFormat"`
+	Facility     int    `json:"facility" yaml:"facility" toml:"facility"`
+	// SyslogFraming is newline or octet-counting. Default is octet counting over TLS and newline otherwise
+	SyslogFraming string `json:"syslogFraming" yaml:"syslogFraming" toml:"syslogFraming"`
+	// CAFile, CertFile and KeyFile are PEM files of syslog TLS transport. CAFile verifies server, default is system
+	// roots, and CertFile and KeyFile are client certificate
+	CAFile   string `json:"caFile" yaml:"caFile" toml:"caFile"`
+	CertFile string `json:"certFile" yaml:"certFile" toml:"certFile"`
+	KeyFile  string `json:"keyFile" yaml:"keyFile" toml:"keyFile"`
+	// AppName is syslog app name, or journald identifier
+	AppName string `json:"appName" yaml:"appName" toml:"appName"`
+}
+
+// Duration is time.Duration that is written as string in config files, e.g. 24h
+type Duration time.Duration
+
+func (d *Duration) UnmarshalText(text []byte) error {
+	parsed, err := time.ParseDuration(string(text))
+	if err != nil {
+		return err
+	}
+	*d = Duration(parsed)
+	return nil
+}
+
+func (d Duration) MarshalText() ([]byte, error) {
+	return []byte(time.Duration(d).String()), nil
+}
+
+// FieldError is a config error that points at the offending key, e.g. printers[1].type
+type FieldError struct {
+	Key string
+	Err error
+}
+
+func (e *FieldError) Error() string {
+	return fmt.Sprintf("%s: %s", e.Key, e.Err)
+}
+
+func (e *FieldError) Unwrap() error {
+	return e.Err
+}
+
+func fieldError(key string, format string, args ...interface{}) *FieldError {
+	return &FieldError{Key: key, Err: fmt.Errorf(format, args...)}
+}
+
+// printerKey returns key of printer field
+func printerKey(i int, field string) string {
+	return fmt.Sprintf("printers[%d].%s", i, field)
+}
+
+func normalize(s string) string {
+	return strings.ToLower(strings.TrimSpace(s))
+}
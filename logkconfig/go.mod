@@ -0,0 +1,11 @@
+module github.com/go-konsultin/logk/logkconfig
+
+go 1.23.0
+
+require (
+	github.com/BurntSushi/toml v1.4.0
+	github.com/go-konsultin/logk v0.0.0
+	gopkg.in/yaml.v3 v3.0.1
+)
+
+replace github.com/go-konsultin/logk => ../
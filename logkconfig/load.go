@@ -0,0 +1,100 @@
+package logkConfig
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+
+	"github.com/BurntSushi/toml"
+	"github.com/go-konsultin/logk"
+	"gopkg.in/yaml.v3"
+)
+
+// FileFormat is syntax of config file
+type FileFormat string
+
+const (
+	FormatJSON FileFormat = "json"
+	FormatYAML FileFormat = "yaml"
+	FormatTOML FileFormat = "toml"
+)
+
+// Load reads config file at path and builds logger from it. Syntax is detected by file extension
+func Load(path string) (logk.Logger, error) {
+	c, err := LoadConfig(path)
+	if err != nil {
+		return nil, err
+	}
+	return c.Build()
+}
+
+// LoadConfig reads config file at path without building logger. Syntax is detected by file extension: .json,
+// .yaml, .yml or .toml
+func LoadConfig(path string) (*Config, error) {
+	var format FileFormat
+	switch ext := filepath.Ext(path); ext {
+	case ".json":
+		format = FormatJSON
+	case ".yaml", ".yml":
+		format = FormatYAML
+	case ".toml":
+		format = FormatTOML
+	default:
+		return nil, fmt.Errorf("%s: unknown config file extension %q", pkgName, ext)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	c, err := Parse(data, format)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return c, nil
+}
+
+// Parse decodes config in format and validates it. Unknown keys are rejected, so typos don't go unnoticed
+func Parse(data []byte, format FileFormat) (*Config, error) {
+	var c Config
+	switch format {
+	case FormatJSON:
+		dec := json.NewDecoder(bytes.NewReader(data))
+		dec.DisallowUnknownFields()
+		if err := dec.Decode(&c); err != nil {
+			return nil, err
+		}
+	case FormatYAML:
+		dec := yaml.NewDecoder(bytes.NewReader(data))
+		dec.KnownFields(true)
+		// Empty document is a valid config with defaults
+		if err := dec.Decode(&c); err != nil && !errors.Is(err, io.EOF) {
+			return nil, err
+		}
+	case FormatTOML:
+		md, err := toml.Decode(string(data), &c)
+		if err != nil {
+			return nil, err
+		}
+
+		var errs []error
+		for _, key := range md.Undecoded() {
+			errs = append(errs, fieldError(key.String(), "unknown key"))
+		}
+		if len(errs) > 0 {
+			return nil, errors.Join(errs...)
+		}
+	default:
+		return nil, fmt.Errorf("%s: unknown config format %q", pkgName, format)
+	}
+
+	if err := c.Validate(); err != nil {
+		return nil, err
+	}
+	return &c, nil
+}
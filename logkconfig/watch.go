@@ -0,0 +1,302 @@
+package logkConfig
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"slices"
+	"sync"
+	"sync/atomic"
+	"syscall"
+	"time"
+
+	"github.com/go-konsultin/logk"
+	"github.com/go-konsultin/logk/level"
+)
+
+// defaultWatchInterval is how often config file is checked for changes
+const defaultWatchInterval = 2 * time.Second
+
+type WatchOptions struct {
+	// Interval of checking modification time and size of config file. Zero disables polling, so config is only
+	// reloaded on signals or Reload
+	Interval time.Duration
+	// Signals trigger reload, default is SIGHUP
+	Signals []os.Signal
+	// OnReload is called after each reload attempt with the new config, or with error if config was kept. Default
+	// writes errors to Stderr
+	OnReload func(c *Config, err error)
+}
+
+type WatchOption = func(*WatchOptions)
+
+func WithInterval(d time.Duration) WatchOption {
+	return func(o *WatchOptions) {
+		o.Interval = d
+	}
+}
+
+func WithSignals(signals ...os.Signal) WatchOption {
+	return func(o *WatchOptions) {
+		o.Signals = signals
+	}
+}
+
+func WithOnReload(fn func(c *Config, err error)) WatchOption {
+	return func(o *WatchOptions) {
+		o.OnReload = fn
+	}
+}
+
+// Watcher keeps registered global logger in sync with config file. On change, it swaps printers, levels and
+// redaction rules without dropping entries that are being written: the previous printers are flushed and closed
+// only after in-flight entries are written.
+//
+// Printers are shared by the registered logger and all its children, so they follow the new config. Level,
+// namespace, caller, stack trace and sampling apply to the new registered logger, and level is also set on the
+// previous ones, which reaches children that follow their level.
+type Watcher struct {
+	path    string
+	options WatchOptions
+	printer *logk.SwapPrinter
+
+	// mu serializes reloads and guards applied config
+	mu     sync.Mutex
+	config *Config
+	logger logk.Logger
+	stat   os.FileInfo
+
+	// previous are loggers replaced on reload, which level follows config, as their children may be in use
+	previous []logk.LevelLogger
+
+	// redactors of current config, read by the redactor that is registered once
+	redactors atomic.Pointer[[]logk.Redactor]
+
+	signals chan os.Signal
+	stop    chan struct{}
+	done    chan struct{}
+	once    sync.Once
+}
+
+// Watch loads config file at path, registers logger built from it as global logger and reloads it on changes of the
+// file or on SIGHUP, e.g.
+//
+//	w, err := logkConfig.Watch("/etc/billing/logging.yaml")
+//	if err != nil {
+//		panic(err)
+//	}
+//	defer w.Close()
+//
+// Invalid config is reported with OnReload and the previous config stays in effect
+func Watch(path string, args ...WatchOption) (*Watcher, error) {
+	w := &Watcher{
+		path: path,
+		options: WatchOptions{
+			Interval: defaultWatchInterval,
+			Signals:  []os.Signal{syscall.SIGHUP},
+			OnReload: func(_ *Config, err error) {
+				if err != nil {
+					_, _ = fmt.Fprintf(os.Stderr, "%s: config is not reloaded: %s\n", pkgName, err)
+				}
+			},
+		},
+		stop: make(chan struct{}),
+		done: make(chan struct{}),
+	}
+	for _, fn := range args {
+		fn(&w.options)
+	}
+
+	c, stat, err := w.load()
+	if err != nil {
+		return nil, err
+	}
+
+	printer, err := c.newPrinter()
+	if err != nil {
+		return nil, err
+	}
+	w.printer = logk.NewSwapPrinter(printer)
+
+	// Redactors are registered once and delegate to rules of current config
+	w.redactors.Store(&[]logk.Redactor{})
+	logk.AddRedactor(func(key string, value interface{}) interface{} {
+		for _, r := range *w.redactors.Load() {
+			value = r(key, value)
+		}
+		return value
+	})
+	w.apply(c, stat)
+
+	if len(w.options.Signals) > 0 {
+		w.signals = make(chan os.Signal, 1)
+		signal.Notify(w.signals, w.options.Signals...)
+	}
+	go w.watch()
+	return w, nil
+}
+
+// Reload loads config file and applies it. If config is invalid, the previous config stays in effect
+func (w *Watcher) Reload() error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	c, err := w.reload()
+	if w.options.OnReload != nil {
+		w.options.OnReload(c, err)
+	}
+	return err
+}
+
+// Config returns config that is in effect
+func (w *Watcher) Config() *Config {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return w.config
+}
+
+// Logger returns logger that is registered by the last reload
+func (w *Watcher) Logger() logk.Logger {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return w.logger
+}
+
+// Close stops watching. Registered logger stays in effect, close it with logk.Close
+func (w *Watcher) Close() error {
+	w.once.Do(func() {
+		if w.signals != nil {
+			signal.Stop(w.signals)
+		}
+		close(w.stop)
+	})
+	<-w.done
+	return nil
+}
+
+func (w *Watcher) watch() {
+	defer close(w.done)
+
+	var tick <-chan time.Time
+	if w.options.Interval > 0 {
+		ticker := time.NewTicker(w.options.Interval)
+		defer ticker.Stop()
+		tick = ticker.C
+	}
+
+	for {
+		select {
+		case <-w.stop:
+			return
+		case <-w.signals:
+			_ = w.Reload()
+		case <-tick:
+			if w.changed() {
+				_ = w.Reload()
+			}
+		}
+	}
+}
+
+// changed returns true if modification time or size of config file differ from the applied one. Errors are left to
+// Reload, so they're reported once the file is reloaded
+func (w *Watcher) changed() bool {
+	stat, err := os.Stat(w.path)
+	if err != nil {
+		return false
+	}
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return !stat.ModTime().Equal(w.stat.ModTime()) || stat.Size() != w.stat.Size()
+}
+
+func (w *Watcher) load() (*Config, os.FileInfo, error) {
+	// Stat before reading, so a write in between is detected by the next check
+	stat, err := os.Stat(w.path)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	c, err := LoadConfig(w.path)
+	if err != nil {
+		return nil, nil, err
+	}
+	return c, stat, nil
+}
+
+func (w *Watcher) reload() (*Config, error) {
+	c, stat, err := w.load()
+	if err != nil {
+		// Don't retry invalid file until it changes again
+		if stat, sErr := os.Stat(w.path); sErr == nil {
+			w.stat = stat
+		}
+		return nil, err
+	}
+
+	printer, err := c.newPrinter()
+	if err != nil {
+		w.stat = stat
+		return nil, fmt.Errorf("%s: %w", w.path, err)
+	}
+
+	// Printers are flushed and closed once in-flight entries are written
+	closePrinter(w.printer.Swap(printer))
+	w.apply(c, stat)
+	return c, nil
+}
+
+// apply registers global rules and logger of c, replacing those of the previous config
+func (w *Watcher) apply(c *Config, stat os.FileInfo) {
+	prev := w.config
+	if prev == nil {
+		prev = &Config{}
+	}
+
+	for ns := range prev.NamespaceLevels {
+		if _, ok := c.NamespaceLevels[ns]; !ok {
+			logk.RemoveNamespaceLevel(ns)
+		}
+	}
+	for ns, lv := range c.NamespaceLevels {
+		logk.SetNamespaceLevel(ns, level.Parse(lv))
+	}
+
+	redactors := c.redactors()
+	w.redactors.Store(&redactors)
+
+	if !slices.Equal(prev.Redact.SensitiveKeys, c.Redact.SensitiveKeys) {
+		logk.AddSensitiveKeys(c.Redact.SensitiveKeys...)
+		logk.RemoveSensitiveKeys(prev.Redact.SensitiveKeys...)
+	}
+
+	// Previous logger of watcher shares printer, so it must not be closed
+	logger := c.newLogger(w.printer)
+	if w.logger == nil {
+		logk.Register(logger)
+	} else {
+		if l, ok := w.logger.(logk.LevelLogger); ok {
+			w.previous = append(w.previous, l)
+		}
+		for _, l := range w.previous {
+			l.SetLevel(parseLevel(c.Level))
+		}
+		logk.RegisterNoClose(logger)
+	}
+
+	w.config = c
+	w.logger = logger
+	w.stat = stat
+}
+
+func closePrinter(p logk.Printer) {
+	// Errors are ignored, as printer is no longer in use
+	if f, ok := p.(logk.Flusher); ok {
+		_ = f.Flush()
+	}
+	if c, ok := p.(io.Closer); ok {
+		_ = c.Close()
+	}
+}
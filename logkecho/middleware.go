@@ -0,0 +1,40 @@
+// Package logkEcho provides Echo middleware that writes the same access log as logkHttp.Middleware
+package logkEcho
+
+import (
+	"github.com/go-konsultin/logk"
+	logkHttp "github.com/go-konsultin/logk/http"
+	"github.com/labstack/echo/v4"
+)
+
+// Middleware logs every request when it is finished, with the same entries as logkHttp.Middleware. Child logger of
+// request is bound to request context, so handlers retrieve it with logk.FromContext(c.Request().Context()).
+// Error returned by handler is passed to Echo error handler first, so entry has the status that is actually
+// written. Panics are logged with stack trace and propagated to middleware.Recover, which must be registered
+// before this middleware. If logger is nil, registered logger is used
+func Middleware(logger logk.Logger, args ...logkHttp.MiddlewareOption) echo.MiddlewareFunc {
+	access := logkHttp.NewAccessLog(logger, args...)
+
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			req := access.Begin(c.Request())
+			c.SetRequest(c.Request().WithContext(req.Context))
+			c.Response().Header().Set(access.RequestIdHeader(), req.RequestId)
+
+			defer func() {
+				if v := recover(); v != nil {
+					req.Panic(v, c.Response().Size)
+					panic(v)
+				}
+			}()
+
+			err := next(c)
+			if err != nil {
+				c.Error(err)
+			}
+
+			req.Finish(c.Response().Status, c.Response().Size, err)
+			return err
+		}
+	}
+}
@@ -0,0 +1,47 @@
+// Package logkFiber provides Fiber middleware that writes the same access log as logkHttp.Middleware
+package logkFiber
+
+import (
+	"github.com/go-konsultin/logk"
+	logkHttp "github.com/go-konsultin/logk/http"
+	"github.com/gofiber/fiber/v2"
+	"github.com/gofiber/fiber/v2/middleware/adaptor"
+)
+
+// Middleware logs every request when it is finished, with the same entries as logkHttp.Middleware. Child logger of
+// request is bound to user context, so handlers retrieve it with logk.FromContext(c.UserContext()).
+// Error returned by handler is passed to the app error handler first, so entry has the status that is actually
+// written. Panics are logged with stack trace and propagated to recover middleware, which must be registered
+// before this middleware. If logger is nil, registered logger is used
+func Middleware(logger logk.Logger, args ...logkHttp.MiddlewareOption) fiber.Handler {
+	access := logkHttp.NewAccessLog(logger, args...)
+
+	return func(c *fiber.Ctx) error {
+		// Convert request to net/http, as access log reads method, path, headers and remote address from it
+		r, err := adaptor.ConvertRequest(c, true)
+		if err != nil {
+			return err
+		}
+
+		req := access.Begin(r.WithContext(c.UserContext()))
+		c.SetUserContext(req.Context)
+		c.Set(access.RequestIdHeader(), req.RequestId)
+
+		defer func() {
+			if v := recover(); v != nil {
+				req.Panic(v, int64(len(c.Response().Body())))
+				panic(v)
+			}
+		}()
+
+		chainErr := c.Next()
+		if chainErr != nil {
+			if err := c.App().ErrorHandler(c, chainErr); err != nil {
+				_ = c.SendStatus(fiber.StatusInternalServerError)
+			}
+		}
+
+		req.Finish(c.Response().StatusCode(), int64(len(c.Response().Body())), chainErr)
+		return nil
+	}
+}
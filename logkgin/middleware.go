@@ -0,0 +1,46 @@
+// Package logkGin provides Gin middleware that writes the same access log as logkHttp.Middleware
+package logkGin
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/go-konsultin/logk"
+	logkHttp "github.com/go-konsultin/logk/http"
+)
+
+// Middleware logs every request when it is finished, with the same entries as logkHttp.Middleware. Child logger of
+// request is bound to c.Request context, so handlers retrieve it with logk.FromContext(c.Request.Context()).
+// The last error added with c.Error is attached to entry. Panics are logged with stack trace and propagated, so
+// gin.Recovery must be registered before this middleware to write the response. If logger is nil, registered
+// logger is used
+func Middleware(logger logk.Logger, args ...logkHttp.MiddlewareOption) gin.HandlerFunc {
+	access := logkHttp.NewAccessLog(logger, args...)
+
+	return func(c *gin.Context) {
+		req := access.Begin(c.Request)
+		c.Request = c.Request.WithContext(req.Context)
+		c.Header(access.RequestIdHeader(), req.RequestId)
+
+		defer func() {
+			if v := recover(); v != nil {
+				req.Panic(v, written(c))
+				panic(v)
+			}
+		}()
+
+		c.Next()
+
+		var err error
+		if last := c.Errors.Last(); last != nil {
+			err = last.Err
+		}
+		req.Finish(c.Writer.Status(), written(c), err)
+	}
+}
+
+// written returns response size, which gin reports as -1 when nothing is written
+func written(c *gin.Context) int64 {
+	if size := c.Writer.Size(); size > 0 {
+		return int64(size)
+	}
+	return 0
+}
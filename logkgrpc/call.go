@@ -0,0 +1,46 @@
+package logkGrpc
+
+import (
+	"strings"
+	"time"
+
+	"github.com/go-konsultin/logk"
+	"github.com/go-konsultin/logk/level"
+	logkOption "github.com/go-konsultin/logk/option"
+	"google.golang.org/grpc/status"
+)
+
+// logCall writes call entry with status code and duration. FATAL returned by LevelFunc is written as ERROR, so a
+// call can't exit the process
+func logCall(logger logk.Logger, o *Options, msg string, err error, start time.Time) {
+	code := status.Code(err)
+	args := []logkOption.SetterFunc{
+		logkOption.AddMetadata(CodeKey, code.String()),
+		logkOption.Field.Duration(DurationKey, time.Since(start)),
+	}
+	if err != nil {
+		args = append(args, logkOption.Error(err))
+	}
+
+	switch o.LevelFunc(code) {
+	case level.Fatal, level.Error:
+		logger.Error(msg, args...)
+	case level.Warn:
+		logger.Warn(msg, args...)
+	case level.Info:
+		logger.Info(msg, args...)
+	case level.Debug:
+		logger.Debug(msg, args...)
+	default:
+		logger.Trace(msg, args...)
+	}
+}
+
+// splitMethod splits full method name, e.g. /package.Service/Method, into service and method
+func splitMethod(fullMethod string) (string, string) {
+	fullMethod = strings.TrimPrefix(fullMethod, "/")
+	if i := strings.LastIndexByte(fullMethod, '/'); i >= 0 {
+		return fullMethod[:i], fullMethod[i+1:]
+	}
+	return "", fullMethod
+}
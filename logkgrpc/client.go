@@ -0,0 +1,98 @@
+package logkGrpc
+
+import (
+	"context"
+	"errors"
+	"io"
+	"sync"
+	"time"
+
+	"github.com/go-konsultin/logk"
+	logkContext "github.com/go-konsultin/logk/context"
+	logkOption "github.com/go-konsultin/logk/option"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+)
+
+// UnaryClientInterceptor logs every outgoing unary call when it is finished, with target of connection as peer.
+// Request id in context is sent in outgoing metadata. If logger is nil, logger in context is used
+func UnaryClientInterceptor(logger logk.Logger, args ...Option) grpc.UnaryClientInterceptor {
+	o := evaluateOptions(args)
+	return func(ctx context.Context, method string, req, reply interface{}, cc *grpc.ClientConn,
+		invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+		start := time.Now()
+		ctx, callLogger := newClientCall(ctx, logger, &o, method, cc)
+
+		err := invoker(ctx, method, req, reply, cc, opts...)
+		logCall(callLogger, &o, "finished unary call", err, start)
+		return err
+	}
+}
+
+// StreamClientInterceptor logs every outgoing streaming call when it is finished, that is when receiving a message
+// fails or reaches end of stream. Calls which stream is abandoned without being drained are not logged
+func StreamClientInterceptor(logger logk.Logger, args ...Option) grpc.StreamClientInterceptor {
+	o := evaluateOptions(args)
+	return func(ctx context.Context, desc *grpc.StreamDesc, cc *grpc.ClientConn, method string,
+		streamer grpc.Streamer, opts ...grpc.CallOption) (grpc.ClientStream, error) {
+		start := time.Now()
+		ctx, callLogger := newClientCall(ctx, logger, &o, method, cc)
+
+		cs, err := streamer(ctx, desc, cc, method, opts...)
+		if err != nil {
+			logCall(callLogger, &o, "finished streaming call", err, start)
+			return nil, err
+		}
+
+		return &clientStream{ClientStream: cs, logger: callLogger, options: &o, start: start}, nil
+	}
+}
+
+// clientStream logs call once receiving fails
+type clientStream struct {
+	grpc.ClientStream
+	logger  logk.Logger
+	options *Options
+	start   time.Time
+	once    sync.Once
+}
+
+func (s *clientStream) RecvMsg(m interface{}) error {
+	err := s.ClientStream.RecvMsg(m)
+	if err == nil {
+		return nil
+	}
+
+	s.once.Do(func() {
+		callErr := err
+		if errors.Is(err, io.EOF) {
+			callErr = nil
+		}
+		logCall(s.logger, s.options, "finished streaming call", callErr, s.start)
+	})
+	return err
+}
+
+// newClientCall sends request id in outgoing metadata and creates call logger
+func newClientCall(ctx context.Context, logger logk.Logger, o *Options, fullMethod string,
+	cc *grpc.ClientConn) (context.Context, logk.Logger) {
+	if logger == nil {
+		logger = logk.FromContext(ctx)
+	}
+
+	if reqId := logkContext.GetRequestId(ctx); reqId != "" {
+		ctx = metadata.AppendToOutgoingContext(ctx, o.RequestIdHeader, reqId)
+	}
+
+	service, method := splitMethod(fullMethod)
+	args := []logkOption.SetterFunc{
+		logkOption.AddMetadata(ServiceKey, service),
+		logkOption.AddMetadata(MethodKey, method),
+	}
+
+	if cc != nil {
+		args = append(args, logkOption.AddMetadata(PeerKey, cc.Target()))
+	}
+
+	return ctx, logger.With(args...).NewChildCtx(ctx)
+}
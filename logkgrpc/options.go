@@ -0,0 +1,66 @@
+// Package logkGrpc provides gRPC interceptors that log every call with a logk Logger
+package logkGrpc
+
+import (
+	"github.com/go-konsultin/logk/level"
+	"google.golang.org/grpc/codes"
+)
+
+// Metadata keys of call entries
+const (
+	ServiceKey  = "grpcService"
+	MethodKey   = "grpcMethod"
+	CodeKey     = "grpcCode"
+	PeerKey     = "peer"
+	DurationKey = "duration"
+)
+
+// DefaultRequestIdHeader is metadata key that carries request id between services
+const DefaultRequestIdHeader = "x-request-id"
+
+type Options struct {
+	// LevelFunc determines level of call entry by status code
+	LevelFunc func(code codes.Code) level.LogLevel
+	// RequestIdHeader is metadata key that carries request id. Server reads it from incoming metadata, client
+	// writes request id in context to outgoing metadata
+	RequestIdHeader string
+}
+
+type Option = func(*Options)
+
+func WithLevelFunc(fn func(code codes.Code) level.LogLevel) Option {
+	return func(o *Options) {
+		o.LevelFunc = fn
+	}
+}
+
+func WithRequestIdHeader(header string) Option {
+	return func(o *Options) {
+		o.RequestIdHeader = header
+	}
+}
+
+// DefaultLevel writes successful calls as INFO, calls that failed because of client as WARN and other failures as
+// ERROR
+func DefaultLevel(code codes.Code) level.LogLevel {
+	switch code {
+	case codes.OK:
+		return level.Info
+	case codes.Canceled, codes.InvalidArgument, codes.NotFound, codes.AlreadyExists, codes.PermissionDenied,
+		codes.Unauthenticated, codes.FailedPrecondition, codes.OutOfRange, codes.ResourceExhausted, codes.Aborted:
+		return level.Warn
+	default:
+		return level.Error
+	}
+}
+
+func evaluateOptions(args []Option) Options {
+	o := Options{
+		LevelFunc:       DefaultLevel,
+		RequestIdHeader: DefaultRequestIdHeader,
+	}
+	for _, fn := range args {
+		fn(&o)
+	}
+	return o
+}
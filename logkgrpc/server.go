@@ -0,0 +1,81 @@
+package logkGrpc
+
+import (
+	"context"
+	"time"
+
+	"github.com/go-konsultin/logk"
+	logkContext "github.com/go-konsultin/logk/context"
+	logkOption "github.com/go-konsultin/logk/option"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+)
+
+// UnaryServerInterceptor logs every unary call when it is finished. Each call gets a child logger carrying service,
+// method and peer, that handlers retrieve with logk.FromContext. Request id is read from incoming metadata.
+// If logger is nil, registered logger is used
+func UnaryServerInterceptor(logger logk.Logger, args ...Option) grpc.UnaryServerInterceptor {
+	o := evaluateOptions(args)
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo,
+		handler grpc.UnaryHandler) (interface{}, error) {
+		start := time.Now()
+		ctx, callLogger := newServerCall(ctx, logger, &o, info.FullMethod)
+
+		resp, err := handler(ctx, req)
+		logCall(callLogger, &o, "finished unary call", err, start)
+		return resp, err
+	}
+}
+
+// StreamServerInterceptor logs every streaming call when it is finished, same as UnaryServerInterceptor
+func StreamServerInterceptor(logger logk.Logger, args ...Option) grpc.StreamServerInterceptor {
+	o := evaluateOptions(args)
+	return func(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		start := time.Now()
+		ctx, callLogger := newServerCall(ss.Context(), logger, &o, info.FullMethod)
+
+		err := handler(srv, &serverStream{ServerStream: ss, ctx: ctx})
+		logCall(callLogger, &o, "finished streaming call", err, start)
+		return err
+	}
+}
+
+// serverStream overrides context of stream with context that carries call logger
+type serverStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (s *serverStream) Context() context.Context {
+	return s.ctx
+}
+
+// newServerCall binds request id and call logger to ctx
+func newServerCall(ctx context.Context, logger logk.Logger, o *Options,
+	fullMethod string) (context.Context, logk.Logger) {
+	if logger == nil {
+		logger = logk.Get()
+	}
+
+	if logkContext.GetRequestId(ctx) == "" {
+		if md, ok := metadata.FromIncomingContext(ctx); ok {
+			if values := md.Get(o.RequestIdHeader); len(values) > 0 {
+				ctx = logkContext.SetRequestId(ctx, values[0])
+			}
+		}
+	}
+
+	service, method := splitMethod(fullMethod)
+	args := []logkOption.SetterFunc{
+		logkOption.AddMetadata(ServiceKey, service),
+		logkOption.AddMetadata(MethodKey, method),
+	}
+
+	if p, ok := peer.FromContext(ctx); ok && p.Addr != nil {
+		args = append(args, logkOption.AddMetadata(PeerKey, p.Addr.String()))
+	}
+
+	callLogger := logger.With(args...).NewChildCtx(ctx)
+	return logk.NewContext(ctx, callLogger), callLogger
+}
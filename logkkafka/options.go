@@ -0,0 +1,182 @@
+// Package logkKafka provides a printer that publishes logk entries to a Kafka topic
+package logkKafka
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/go-konsultin/logk"
+	"github.com/segmentio/kafka-go"
+)
+
+// Default printer options
+const (
+	defaultBufferSize   = 4096
+	defaultBatchSize    = 100
+	defaultBatchTimeout = 100 * time.Millisecond
+	defaultMaxAttempts  = 3
+	defaultWriteTimeout = 10 * time.Second
+)
+
+type Options struct {
+	// Key returns message key of entry, which selects partition with Hash balancer. Default is no key, so entries are
+	// spread over partitions
+	Key func(entry *logk.Entry) []byte
+	// Balancer is partitioner of messages, default is kafka.Hash, which falls back to round-robin for messages
+	// without key
+	Balancer kafka.Balancer
+	// RequiredAcks is number of acknowledgements required from replicas, default is kafka.RequireOne
+	RequiredAcks kafka.RequiredAcks
+	Compression  kafka.Compression
+	// Transport configures connections to brokers, e.g. TLS and SASL
+	Transport kafka.RoundTripper
+	// BufferSize limits number of entries waiting to be published, so an unavailable broker can't exhaust memory
+	BufferSize int
+	// Overflow is applied when buffer is full
+	Overflow logk.OverflowPolicy
+	// BatchSize is maximum number of entries in a single produce request
+	BatchSize int
+	// BatchTimeout is maximum time an entry waits for batch to fill up
+	BatchTimeout time.Duration
+	// MaxAttempts is number of attempts to publish a batch before it is discarded
+	MaxAttempts  int
+	WriteTimeout time.Duration
+	// OnError is called from background goroutine when a batch can't be published
+	OnError func(err error)
+	// Encoder formats message values, default is JSON
+	Encoder        logk.Encoder
+	PrinterOptions []logk.PrinterOption
+}
+
+type Option = func(*Options)
+
+func WithKey(fn func(entry *logk.Entry) []byte) Option {
+	return func(o *Options) {
+		o.Key = fn
+	}
+}
+
+func WithBalancer(b kafka.Balancer) Option {
+	return func(o *Options) {
+		o.Balancer = b
+	}
+}
+
+func WithRequiredAcks(acks kafka.RequiredAcks) Option {
+	return func(o *Options) {
+		o.RequiredAcks = acks
+	}
+}
+
+func WithCompression(c kafka.Compression) Option {
+	return func(o *Options) {
+		o.Compression = c
+	}
+}
+
+func WithTransport(t kafka.RoundTripper) Option {
+	return func(o *Options) {
+		o.Transport = t
+	}
+}
+
+func WithBufferSize(n int) Option {
+	return func(o *Options) {
+		o.BufferSize = n
+	}
+}
+
+func WithOverflowPolicy(p logk.OverflowPolicy) Option {
+	return func(o *Options) {
+		o.Overflow = p
+	}
+}
+
+func WithBatch(size int, timeout time.Duration) Option {
+	return func(o *Options) {
+		o.BatchSize = size
+		o.BatchTimeout = timeout
+	}
+}
+
+func WithMaxAttempts(n int) Option {
+	return func(o *Options) {
+		o.MaxAttempts = n
+	}
+}
+
+func WithWriteTimeout(d time.Duration) Option {
+	return func(o *Options) {
+		o.WriteTimeout = d
+	}
+}
+
+func WithOnError(fn func(err error)) Option {
+	return func(o *Options) {
+		o.OnError = fn
+	}
+}
+
+func WithEncoder(enc logk.Encoder) Option {
+	return func(o *Options) {
+		o.Encoder = enc
+	}
+}
+
+func WithPrinterOptions(args ...logk.PrinterOption) Option {
+	return func(o *Options) {
+		o.PrinterOptions = append(o.PrinterOptions, args...)
+	}
+}
+
+// KeyRequestId keys messages by request id, so entries of a request are kept in order on the same partition
+func KeyRequestId(entry *logk.Entry) []byte {
+	if entry.RequestId == "" {
+		return nil
+	}
+	return []byte(entry.RequestId)
+}
+
+// KeyNamespace keys messages by namespace
+func KeyNamespace(entry *logk.Entry) []byte {
+	if entry.Namespace == "" {
+		return nil
+	}
+	return []byte(entry.Namespace)
+}
+
+// KeyMetadata returns key function that keys messages by value of metadata key, e.g. tenant id
+func KeyMetadata(key string) func(entry *logk.Entry) []byte {
+	return func(entry *logk.Entry) []byte {
+		v, ok := entry.Metadata[key]
+		if !ok || v == nil {
+			return nil
+		}
+		return []byte(fmt.Sprint(v))
+	}
+}
+
+func evaluateOptions(args []Option) Options {
+	o := Options{
+		Balancer:     &kafka.Hash{},
+		RequiredAcks: kafka.RequireOne,
+		BufferSize:   defaultBufferSize,
+		BatchSize:    defaultBatchSize,
+		BatchTimeout: defaultBatchTimeout,
+		MaxAttempts:  defaultMaxAttempts,
+		WriteTimeout: defaultWriteTimeout,
+		Encoder:      logk.NewJSONEncoder(),
+	}
+	for _, fn := range args {
+		fn(&o)
+	}
+
+	if o.BufferSize < 0 {
+		o.BufferSize = 0
+	}
+
+	if o.BatchSize < 1 {
+		o.BatchSize = 1
+	}
+	return o
+}
@@ -0,0 +1,165 @@
+package logkKafka
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"sync/atomic"
+
+	"github.com/go-konsultin/logk"
+	"github.com/go-konsultin/logk/level"
+	logkOption "github.com/go-konsultin/logk/option"
+	"github.com/segmentio/kafka-go"
+)
+
+const pkgName = "logk/logkkafka"
+
+// Printer publishes entries to a Kafka topic. Entries are encoded on the logging goroutine and queued into a bounded
+// buffer, which is published in batches from a background goroutine. While broker is unavailable, buffer fills up
+// and overflow policy is applied, so logging doesn't block or grow memory unless OverflowBlock is set
+type Printer struct {
+	writer  *kafka.Writer
+	options Options
+	buffer  chan kafka.Message
+	dropped atomic.Uint64
+	wg      sync.WaitGroup
+
+	// mu guards pending and closed
+	mu      sync.Mutex
+	cond    *sync.Cond
+	pending int
+	closed  bool
+}
+
+// NewPrinter creates printer that publishes entries to topic, connecting to brokers lazily on first batch
+func NewPrinter(brokers []string, topic string, args ...Option) *Printer {
+	if len(brokers) == 0 || topic == "" {
+		panic(fmt.Errorf("%s: brokers and topic are required", pkgName))
+	}
+
+	o := evaluateOptions(args)
+	p := Printer{
+		writer: &kafka.Writer{
+			Addr:         kafka.TCP(brokers...),
+			Topic:        topic,
+			Balancer:     o.Balancer,
+			MaxAttempts:  o.MaxAttempts,
+			BatchSize:    o.BatchSize,
+			BatchTimeout: o.BatchTimeout,
+			WriteTimeout: o.WriteTimeout,
+			RequiredAcks: o.RequiredAcks,
+			Compression:  o.Compression,
+			Transport:    o.Transport,
+		},
+		options: o,
+		buffer:  make(chan kafka.Message, o.BufferSize),
+	}
+	p.cond = sync.NewCond(&p.mu)
+
+	p.wg.Add(1)
+	go p.work()
+
+	return &p
+}
+
+func (p *Printer) Print(namespace string, lv level.LogLevel, msg string, options *logkOption.Options) {
+	entry := logk.NewEntry(namespace, lv, msg, options, p.options.PrinterOptions...)
+	value, err := p.options.Encoder.Encode(entry)
+	if err != nil {
+		return
+	}
+
+	m := kafka.Message{Value: value, Time: entry.Time}
+	if p.options.Key != nil {
+		m.Key = p.options.Key(entry)
+	}
+
+	p.mu.Lock()
+	if p.closed {
+		p.mu.Unlock()
+		p.dropped.Add(1)
+		return
+	}
+	p.pending++
+	p.mu.Unlock()
+
+	// Try to queue without blocking
+	select {
+	case p.buffer <- m:
+		return
+	default:
+	}
+
+	if p.options.Overflow == logk.OverflowBlock {
+		p.buffer <- m
+		return
+	}
+
+	p.dropped.Add(1)
+	p.done(1)
+}
+
+// Dropped returns number of entries that are discarded because buffer is full or printer is closed
+func (p *Printer) Dropped() uint64 {
+	return p.dropped.Load()
+}
+
+// Flush waits until buffered entries are published
+func (p *Printer) Flush() error {
+	p.mu.Lock()
+	for p.pending > 0 {
+		p.cond.Wait()
+	}
+	p.mu.Unlock()
+	return nil
+}
+
+// Close publishes remaining entries and closes connections to brokers. Entries printed afterwards are dropped
+func (p *Printer) Close() error {
+	p.mu.Lock()
+	if p.closed {
+		p.mu.Unlock()
+		return nil
+	}
+	p.closed = true
+	p.mu.Unlock()
+
+	_ = p.Flush()
+	close(p.buffer)
+	p.wg.Wait()
+	return p.writer.Close()
+}
+
+func (p *Printer) work() {
+	defer p.wg.Done()
+
+	batch := make([]kafka.Message, 0, p.options.BatchSize)
+	for m := range p.buffer {
+		// Take entries that are already buffered without waiting, so they are published in a single call
+		batch = append(batch[:0], m)
+	drain:
+		for len(batch) < p.options.BatchSize {
+			select {
+			case m, ok := <-p.buffer:
+				if !ok {
+					break drain
+				}
+				batch = append(batch, m)
+			default:
+				break drain
+			}
+		}
+
+		if err := p.writer.WriteMessages(context.Background(), batch...); err != nil && p.options.OnError != nil {
+			p.options.OnError(fmt.Errorf("%s: publish %d entries: %w", pkgName, len(batch), err))
+		}
+		p.done(len(batch))
+	}
+}
+
+func (p *Printer) done(n int) {
+	p.mu.Lock()
+	p.pending -= n
+	p.cond.Broadcast()
+	p.mu.Unlock()
+}
@@ -0,0 +1,171 @@
+// Package logkNats provides a printer that publishes logk entries to NATS subjects, optionally persisted by JetStream
+package logkNats
+
+import (
+	"strings"
+	"time"
+
+	"github.com/go-konsultin/logk"
+	"github.com/go-konsultin/logk/level"
+	"github.com/nats-io/nats.go/jetstream"
+)
+
+// Default printer options
+const (
+	defaultBufferSize = 4096
+	defaultBatchSize  = 256
+	defaultTimeout    = 5 * time.Second
+)
+
+// Placeholders of subject template
+const (
+	NamespacePlaceholder = "{namespace}"
+	LevelPlaceholder     = "{level}"
+)
+
+type Options struct {
+	// Subject returns subject of entry. Default is expanded from subject template of NewPrinter
+	Subject func(entry *logk.Entry) string
+	// JetStream publishes entries to a stream and waits for acknowledgements, so entries are persisted. Subjects
+	// must be bound to a stream, see Stream
+	JetStream bool
+	// Stream is created, or updated, when printer is created, so subjects of printer are bound to it
+	Stream *jetstream.StreamConfig
+	// PublishOptions are applied to JetStream publishes, e.g. jetstream.WithExpectStream
+	PublishOptions []jetstream.PublishOpt
+	// BufferSize limits number of entries waiting to be published, so a disconnected client can't exhaust memory
+	BufferSize int
+	// Overflow is applied when buffer is full
+	Overflow logk.OverflowPolicy
+	// BatchSize is maximum number of JetStream publishes waiting for acknowledgement
+	BatchSize int
+	// Timeout limits waiting for JetStream acknowledgements and for server on Flush
+	Timeout time.Duration
+	// OnError is called from background goroutine when entries can't be published
+	OnError func(err error)
+	// Encoder formats message data, default is JSON
+	Encoder        logk.Encoder
+	PrinterOptions []logk.PrinterOption
+}
+
+type Option = func(*Options)
+
+func WithSubjectFunc(fn func(entry *logk.Entry) string) Option {
+	return func(o *Options) {
+		o.Subject = fn
+	}
+}
+
+func WithJetStream(args ...jetstream.PublishOpt) Option {
+	return func(o *Options) {
+		o.JetStream = true
+		o.PublishOptions = append(o.PublishOptions, args...)
+	}
+}
+
+// WithStream enables JetStream and creates or updates stream with config when printer is created
+func WithStream(config jetstream.StreamConfig) Option {
+	return func(o *Options) {
+		o.JetStream = true
+		o.Stream = &config
+	}
+}
+
+func WithBufferSize(n int) Option {
+	return func(o *Options) {
+		o.BufferSize = n
+	}
+}
+
+func WithOverflowPolicy(p logk.OverflowPolicy) Option {
+	return func(o *Options) {
+		o.Overflow = p
+	}
+}
+
+func WithBatchSize(n int) Option {
+	return func(o *Options) {
+		o.BatchSize = n
+	}
+}
+
+func WithTimeout(d time.Duration) Option {
+	return func(o *Options) {
+		o.Timeout = d
+	}
+}
+
+func WithOnError(fn func(err error)) Option {
+	return func(o *Options) {
+		o.OnError = fn
+	}
+}
+
+func WithEncoder(enc logk.Encoder) Option {
+	return func(o *Options) {
+		o.Encoder = enc
+	}
+}
+
+func WithPrinterOptions(args ...logk.PrinterOption) Option {
+	return func(o *Options) {
+		o.PrinterOptions = append(o.PrinterOptions, args...)
+	}
+}
+
+// SubjectTemplate returns subject function that replaces {namespace} and {level} in template, e.g.
+// "logs.{namespace}.{level}" gives "logs.api.users.error" for namespace api/users. Characters of namespace that are
+// not allowed in subject tokens are replaced by dot, and empty tokens are removed, so entries without namespace go to
+// "logs.error"
+func SubjectTemplate(template string) func(entry *logk.Entry) string {
+	hasNamespace := strings.Contains(template, NamespacePlaceholder)
+	hasLevel := strings.Contains(template, LevelPlaceholder)
+	if !hasNamespace && !hasLevel {
+		return func(*logk.Entry) string {
+			return template
+		}
+	}
+
+	return func(entry *logk.Entry) string {
+		subject := template
+		if hasNamespace {
+			subject = strings.ReplaceAll(subject, NamespacePlaceholder, subjectTokens(entry.Namespace))
+		}
+		if hasLevel {
+			subject = strings.ReplaceAll(subject, LevelPlaceholder, strings.ToLower(level.String(entry.Level)))
+		}
+		return strings.Join(strings.FieldsFunc(subject, func(r rune) bool { return r == '.' }), ".")
+	}
+}
+
+// subjectTokens maps s to dot-separated subject tokens, replacing whitespace, wildcards and other separators
+func subjectTokens(s string) string {
+	return strings.Map(func(r rune) rune {
+		if r == '-' || r == '_' || r >= '0' && r <= '9' || r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' {
+			return r
+		}
+		return '.'
+	}, s)
+}
+
+func evaluateOptions(subject string, args []Option) Options {
+	o := Options{
+		Subject:    SubjectTemplate(subject),
+		BufferSize: defaultBufferSize,
+		BatchSize:  defaultBatchSize,
+		Timeout:    defaultTimeout,
+		Encoder:    logk.NewJSONEncoder(),
+	}
+	for _, fn := range args {
+		fn(&o)
+	}
+
+	if o.BufferSize < 0 {
+		o.BufferSize = 0
+	}
+
+	if o.BatchSize < 1 {
+		o.BatchSize = 1
+	}
+	return o
+}
@@ -0,0 +1,236 @@
+package logkNats
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/go-konsultin/logk"
+	"github.com/go-konsultin/logk/level"
+	logkOption "github.com/go-konsultin/logk/option"
+	"github.com/nats-io/nats.go"
+	"github.com/nats-io/nats.go/jetstream"
+)
+
+const pkgName = "logk/logknats"
+
+// Printer publishes entries to NATS subjects. Entries are encoded on the logging goroutine and queued into a bounded
+// buffer, which is published from a background goroutine. With JetStream, publishes are asynchronous and each batch
+// waits for acknowledgements, so OnError reports entries that are not persisted
+type Printer struct {
+	conn    *nats.Conn
+	js      jetstream.JetStream
+	options Options
+	buffer  chan *nats.Msg
+	dropped atomic.Uint64
+	wg      sync.WaitGroup
+
+	// mu guards pending and closed
+	mu      sync.Mutex
+	cond    *sync.Cond
+	pending int
+	closed  bool
+}
+
+// NewPrinter creates printer that publishes entries over conn to subject, which is a template, see SubjectTemplate.
+// Connection is owned by caller, who closes it after printer
+func NewPrinter(conn *nats.Conn, subject string, args ...Option) (*Printer, error) {
+	if conn == nil || subject == "" {
+		return nil, fmt.Errorf("%s: connection and subject are required", pkgName)
+	}
+
+	o := evaluateOptions(subject, args)
+	p := Printer{
+		conn:    conn,
+		options: o,
+		buffer:  make(chan *nats.Msg, o.BufferSize),
+	}
+	p.cond = sync.NewCond(&p.mu)
+
+	if o.JetStream {
+		js, err := jetstream.New(conn, jetstream.WithPublishAsyncMaxPending(o.BatchSize))
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", pkgName, err)
+		}
+		p.js = js
+
+		if o.Stream != nil {
+			ctx, cancel := context.WithTimeout(context.Background(), o.Timeout)
+			_, err = js.CreateOrUpdateStream(ctx, *o.Stream)
+			cancel()
+			if err != nil {
+				return nil, fmt.Errorf("%s: create stream %s: %w", pkgName, o.Stream.Name, err)
+			}
+		}
+	}
+
+	p.wg.Add(1)
+	go p.work()
+
+	return &p, nil
+}
+
+func (p *Printer) Print(namespace string, lv level.LogLevel, msg string, options *logkOption.Options) {
+	entry := logk.NewEntry(namespace, lv, msg, options, p.options.PrinterOptions...)
+	data, err := p.options.Encoder.Encode(entry)
+	if err != nil {
+		return
+	}
+	m := &nats.Msg{Subject: p.options.Subject(entry), Data: data}
+
+	p.mu.Lock()
+	if p.closed {
+		p.mu.Unlock()
+		p.dropped.Add(1)
+		return
+	}
+	p.pending++
+	p.mu.Unlock()
+
+	// Try to queue without blocking
+	select {
+	case p.buffer <- m:
+		return
+	default:
+	}
+
+	if p.options.Overflow == logk.OverflowBlock {
+		p.buffer <- m
+		return
+	}
+
+	p.dropped.Add(1)
+	p.done(1)
+}
+
+// Dropped returns number of entries that are discarded because buffer is full or printer is closed
+func (p *Printer) Dropped() uint64 {
+	return p.dropped.Load()
+}
+
+// Flush waits until buffered entries are published, and until server has received them
+func (p *Printer) Flush() error {
+	p.mu.Lock()
+	for p.pending > 0 {
+		p.cond.Wait()
+	}
+	p.mu.Unlock()
+
+	if err := p.conn.FlushTimeout(p.options.Timeout); err != nil {
+		return fmt.Errorf("%s: %w", pkgName, err)
+	}
+	return nil
+}
+
+// Close publishes remaining entries and stops background goroutine. Entries printed afterwards are dropped
+func (p *Printer) Close() error {
+	p.mu.Lock()
+	if p.closed {
+		p.mu.Unlock()
+		return nil
+	}
+	p.closed = true
+	p.mu.Unlock()
+
+	err := p.Flush()
+	close(p.buffer)
+	p.wg.Wait()
+	return err
+}
+
+func (p *Printer) work() {
+	defer p.wg.Done()
+
+	batch := make([]*nats.Msg, 0, p.options.BatchSize)
+	for m := range p.buffer {
+		// Take entries that are already buffered without waiting, so JetStream acknowledgements are awaited together
+		batch = append(batch[:0], m)
+	drain:
+		for len(batch) < p.options.BatchSize {
+			select {
+			case m, ok := <-p.buffer:
+				if !ok {
+					break drain
+				}
+				batch = append(batch, m)
+			default:
+				break drain
+			}
+		}
+
+		var failed int
+		var err error
+		if p.js != nil {
+			failed, err = p.publishJetStream(batch)
+		} else {
+			failed, err = p.publish(batch)
+		}
+		if failed > 0 && p.options.OnError != nil {
+			p.options.OnError(fmt.Errorf("%s: publish %d of %d entries: %w", pkgName, failed, len(batch), err))
+		}
+		p.done(len(batch))
+	}
+}
+
+// publish sends messages to core NATS, and returns number of failed messages and the first error. While client is
+// reconnecting, messages are kept in its reconnect buffer
+func (p *Printer) publish(batch []*nats.Msg) (int, error) {
+	var failed int
+	var first error
+	for _, m := range batch {
+		if err := p.conn.PublishMsg(m); err != nil {
+			failed++
+			first = firstError(first, err)
+		}
+	}
+	return failed, first
+}
+
+// publishJetStream publishes messages asynchronously and waits for their acknowledgements
+func (p *Printer) publishJetStream(batch []*nats.Msg) (int, error) {
+	var failed int
+	var first error
+	futures := make([]jetstream.PubAckFuture, 0, len(batch))
+	for _, m := range batch {
+		f, err := p.js.PublishMsgAsync(m, p.options.PublishOptions...)
+		if err != nil {
+			failed++
+			first = firstError(first, err)
+			continue
+		}
+		futures = append(futures, f)
+	}
+
+	timeout := time.NewTimer(p.options.Timeout)
+	defer timeout.Stop()
+	for i, f := range futures {
+		select {
+		case <-f.Ok():
+		case err := <-f.Err():
+			failed++
+			first = firstError(first, err)
+		case <-timeout.C:
+			failed += len(futures) - i
+			return failed, firstError(first, errors.New("acknowledgement timed out"))
+		}
+	}
+	return failed, first
+}
+
+func (p *Printer) done(n int) {
+	p.mu.Lock()
+	p.pending -= n
+	p.cond.Broadcast()
+	p.mu.Unlock()
+}
+
+// firstError returns first, or err if there is none yet
+func firstError(first, err error) error {
+	if first != nil {
+		return first
+	}
+	return err
+}
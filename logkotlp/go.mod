@@ -0,0 +1,21 @@
+module github.com/go-konsultin/logk/logkotlp
+
+go 1.23.0
+
+require (
+	github.com/go-konsultin/logk v0.0.0
+	go.opentelemetry.io/proto/otlp v1.5.0
+	google.golang.org/grpc v1.70.0
+	google.golang.org/protobuf v1.36.1
+)
+
+require (
+	github.com/grpc-ecosystem/grpc-gateway/v2 v2.25.1 // indirect
+	golang.org/x/net v0.33.0 // indirect
+	golang.org/x/sys v0.29.0 // indirect
+	golang.org/x/text v0.21.0 // indirect
+	google.golang.org/genproto/googleapis/api v0.0.0-20250102185135-69823020774d // indirect
+	google.golang.org/genproto/googleapis/rpc v0.0.0-20250102185135-69823020774d // indirect
+)
+
+replace github.com/go-konsultin/logk => ../
@@ -0,0 +1,123 @@
+// Package logkOtlp exports logk entries to an OpenTelemetry collector over OTLP/gRPC
+package logkOtlp
+
+import (
+	"time"
+
+	"github.com/go-konsultin/logk"
+	logkExport "github.com/go-konsultin/logk/internal/export"
+)
+
+// Default printer options
+const (
+	defaultBatchSize     = 512
+	defaultBatchInterval = time.Second
+	defaultQueueSize     = 64
+	defaultMaxRetries    = 3
+	defaultRetryBackoff  = 500 * time.Millisecond
+	defaultTimeout       = 10 * time.Second
+)
+
+type Options struct {
+	// Header holds gRPC metadata sent with every export request, e.g. authorization
+	Header map[string]string
+	// Resource holds attributes of the resource producing entries, e.g. service.name
+	Resource map[string]string
+	// BatchSize is maximum number of entries in a single export request
+	BatchSize int
+	// BatchInterval is maximum time an entry waits before it is exported
+	BatchInterval time.Duration
+	// QueueSize limits number of batches waiting to be exported
+	QueueSize int
+	// MaxRetries is number of retries of an export that failed with a retryable status, e.g. UNAVAILABLE
+	MaxRetries int
+	// RetryBackoff is delay before the first retry, it is doubled on each retry
+	RetryBackoff time.Duration
+	// Timeout limits a single export request
+	Timeout time.Duration
+	// Overflow is applied when queue is full
+	Overflow logk.OverflowPolicy
+	// OnError is called from background goroutine when a batch can't be exported, or when collector rejects part
+	// of it
+	OnError        func(err error)
+	PrinterOptions []logk.PrinterOption
+}
+
+type Option = func(*Options)
+
+func WithHeader(key, value string) Option {
+	return func(o *Options) {
+		o.Header[key] = value
+	}
+}
+
+func WithResource(key, value string) Option {
+	return func(o *Options) {
+		o.Resource[key] = value
+	}
+}
+
+func WithServiceName(name string) Option {
+	return WithResource(logkExport.OTLPServiceNameKey, name)
+}
+
+func WithBatch(size int, interval time.Duration) Option {
+	return func(o *Options) {
+		o.BatchSize = size
+		o.BatchInterval = interval
+	}
+}
+
+func WithQueueSize(n int) Option {
+	return func(o *Options) {
+		o.QueueSize = n
+	}
+}
+
+func WithRetry(maxRetries int, backoff time.Duration) Option {
+	return func(o *Options) {
+		o.MaxRetries = maxRetries
+		o.RetryBackoff = backoff
+	}
+}
+
+func WithTimeout(d time.Duration) Option {
+	return func(o *Options) {
+		o.Timeout = d
+	}
+}
+
+func WithOverflowPolicy(p logk.OverflowPolicy) Option {
+	return func(o *Options) {
+		o.Overflow = p
+	}
+}
+
+func WithOnError(fn func(err error)) Option {
+	return func(o *Options) {
+		o.OnError = fn
+	}
+}
+
+func WithPrinterOptions(args ...logk.PrinterOption) Option {
+	return func(o *Options) {
+		o.PrinterOptions = append(o.PrinterOptions, args...)
+	}
+}
+
+func evaluateOptions(args []Option) Options {
+	o := Options{
+		Header:        make(map[string]string),
+		Resource:      make(map[string]string),
+		BatchSize:     defaultBatchSize,
+		BatchInterval: defaultBatchInterval,
+		QueueSize:     defaultQueueSize,
+		MaxRetries:    defaultMaxRetries,
+		RetryBackoff:  defaultRetryBackoff,
+		Timeout:       defaultTimeout,
+	}
+	for _, fn := range args {
+		fn(&o)
+	}
+	return o
+}
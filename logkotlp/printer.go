@@ -0,0 +1,313 @@
+package logkOtlp
+
+import (
+	"bytes"
+	"context"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/go-konsultin/logk"
+	logkExport "github.com/go-konsultin/logk/internal/export"
+	"github.com/go-konsultin/logk/level"
+	logkOption "github.com/go-konsultin/logk/option"
+	collogspb "go.opentelemetry.io/proto/otlp/collector/logs/v1"
+	commonpb "go.opentelemetry.io/proto/otlp/common/v1"
+	logspb "go.opentelemetry.io/proto/otlp/logs/v1"
+	resourcepb "go.opentelemetry.io/proto/otlp/resource/v1"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/encoding/protowire"
+	"google.golang.org/protobuf/proto"
+)
+
+const pkgName = "logk/logkotlp"
+
+// logRecordsField is number of log_records field of ScopeLogs, which encoded records are appended to
+var logRecordsField = (&logspb.ScopeLogs{}).ProtoReflect().Descriptor().Fields().ByName("log_records").Number()
+
+// Printer exports entries in batches to an OpenTelemetry collector with OTLP/gRPC, e.g. to localhost:4317. Levels are
+// mapped to OpenTelemetry severity numbers, and metadata, namespace and other fields are written as log record
+// attributes, same as logkSink.OTLPPrinter does over OTLP/HTTP. Exports that fail with a retryable status, e.g.
+// UNAVAILABLE, are retried with backoff
+type Printer struct {
+	client   collogspb.LogsServiceClient
+	options  Options
+	resource *resourcepb.Resource
+	header   metadata.MD
+	scope    []byte
+	batcher  *logkExport.Batcher
+}
+
+// NewPrinter creates printer that exports entries over conn. Connection is owned by caller, who closes it after
+// printer
+func NewPrinter(conn grpc.ClientConnInterface, args ...Option) (*Printer, error) {
+	if conn == nil {
+		return nil, fmt.Errorf("%s: connection is required", pkgName)
+	}
+
+	o := evaluateOptions(args)
+	p := Printer{
+		client:   collogspb.NewLogsServiceClient(conn),
+		options:  o,
+		resource: &resourcepb.Resource{},
+		header:   metadata.New(o.Header),
+	}
+
+	for _, k := range sortedKeys(o.Resource) {
+		p.resource.Attributes = append(p.resource.Attributes, keyValue(k, o.Resource[k]))
+	}
+
+	scope, err := proto.Marshal(&logspb.ScopeLogs{
+		Scope: &commonpb.InstrumentationScope{Name: logkExport.OTLPScopeName},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", pkgName, err)
+	}
+	p.scope = scope
+
+	d := logkExport.NewDispatcher(1, o.QueueSize, 0, o.Overflow, nil, p.send)
+	p.batcher = logkExport.NewBatcher(o.BatchSize, 0, o.BatchInterval, p.encode, d)
+
+	return &p, nil
+}
+
+func (p *Printer) Print(namespace string, lv level.LogLevel, msg string, options *logkOption.Options) {
+	entry := logk.NewEntry(namespace, lv, msg, options, p.options.PrinterOptions...)
+	record, err := proto.Marshal(newLogRecord(entry))
+	if err != nil {
+		return
+	}
+	p.batcher.Add(record)
+}
+
+// Dropped returns number of batches that are discarded by overflow policy
+func (p *Printer) Dropped() uint64 {
+	return p.batcher.Dropped()
+}
+
+// Flush exports pending entries and waits until all batches are exported
+func (p *Printer) Flush() error {
+	p.batcher.Flush()
+	return nil
+}
+
+// Close exports remaining entries and stops background worker
+func (p *Printer) Close() error {
+	p.batcher.Close()
+	return nil
+}
+
+// encode appends encoded log records to ScopeLogs, as repeated fields of protobuf messages can be concatenated
+func (p *Printer) encode(records [][]byte) []byte {
+	size := len(p.scope)
+	for _, r := range records {
+		size += protowire.SizeTag(logRecordsField) + protowire.SizeBytes(len(r))
+	}
+
+	b := make([]byte, 0, size)
+	b = append(b, p.scope...)
+	for _, r := range records {
+		b = protowire.AppendTag(b, logRecordsField, protowire.BytesType)
+		b = protowire.AppendBytes(b, r)
+	}
+	return b
+}
+
+func (p *Printer) send(payload []byte) {
+	var scope logspb.ScopeLogs
+	if err := proto.Unmarshal(payload, &scope); err != nil {
+		p.onError(fmt.Errorf("%s: %w", pkgName, err))
+		return
+	}
+
+	req := collogspb.ExportLogsServiceRequest{
+		ResourceLogs: []*logspb.ResourceLogs{{
+			Resource:  p.resource,
+			ScopeLogs: []*logspb.ScopeLogs{&scope},
+		}},
+	}
+
+	var resp *collogspb.ExportLogsServiceResponse
+	err := logkExport.Retry(p.options.MaxRetries, p.options.RetryBackoff, func() error {
+		ctx, cancel := context.WithTimeout(context.Background(), p.options.Timeout)
+		defer cancel()
+
+		if len(p.header) > 0 {
+			ctx = metadata.NewOutgoingContext(ctx, p.header)
+		}
+
+		var err error
+		resp, err = p.client.Export(ctx, &req)
+		if err != nil && isRetryable(err) {
+			return logkExport.Retryable(err)
+		}
+		return err
+	})
+	if err != nil {
+		p.onError(fmt.Errorf("%s: export failed: %w", pkgName, err))
+		return
+	}
+
+	if ps := resp.GetPartialSuccess(); ps.GetRejectedLogRecords() > 0 {
+		p.onError(fmt.Errorf("%s: collector rejected %d log records: %s", pkgName, ps.GetRejectedLogRecords(),
+			ps.GetErrorMessage()))
+	}
+}
+
+func (p *Printer) onError(err error) {
+	if p.options.OnError != nil {
+		p.options.OnError(err)
+	}
+}
+
+// isRetryable reports whether export may succeed when retried, by status codes that OTLP specifies as retryable
+func isRetryable(err error) bool {
+	switch status.Code(err) {
+	case codes.Canceled, codes.DeadlineExceeded, codes.ResourceExhausted, codes.Aborted, codes.OutOfRange,
+		codes.Unavailable, codes.DataLoss:
+		return true
+	default:
+		return false
+	}
+}
+
+func newLogRecord(entry *logk.Entry) *logspb.LogRecord {
+	r := logspb.LogRecord{
+		TimeUnixNano:         uint64(entry.Time.UnixNano()),
+		ObservedTimeUnixNano: uint64(logk.Now().UnixNano()),
+		SeverityNumber:       logspb.SeverityNumber(logkExport.OTLPSeverity[entry.Level]),
+		SeverityText:         strings.ToUpper(level.String(entry.Level)),
+		Body:                 anyValue(entry.Message),
+		TraceId:              decodeId(entry.TraceId, 16),
+		SpanId:               decodeId(entry.SpanId, 8),
+	}
+
+	attributes := logkExport.OTLPAttributes(entry)
+	for _, k := range sortedKeys(attributes) {
+		r.Attributes = append(r.Attributes, keyValue(k, attributes[k]))
+	}
+
+	return &r
+}
+
+// decodeId decodes hex trace or span id. Ids that are not n bytes long are omitted, as collector rejects them
+func decodeId(id string, n int) []byte {
+	if len(id) != n*2 {
+		return nil
+	}
+
+	b, err := hex.DecodeString(id)
+	if err != nil {
+		return nil
+	}
+	return b
+}
+
+func keyValue(k string, v interface{}) *commonpb.KeyValue {
+	return &commonpb.KeyValue{Key: k, Value: anyValue(v)}
+}
+
+// anyValue converts v to OTLP AnyValue. Values of unknown types are converted through their JSON form
+func anyValue(v interface{}) *commonpb.AnyValue {
+	switch val := v.(type) {
+	case nil:
+		return &commonpb.AnyValue{}
+	case string:
+		return &commonpb.AnyValue{Value: &commonpb.AnyValue_StringValue{StringValue: val}}
+	case bool:
+		return &commonpb.AnyValue{Value: &commonpb.AnyValue_BoolValue{BoolValue: val}}
+	case int:
+		return intValue(int64(val))
+	case int8:
+		return intValue(int64(val))
+	case int16:
+		return intValue(int64(val))
+	case int32:
+		return intValue(int64(val))
+	case int64:
+		return intValue(val)
+	case uint:
+		return intValue(int64(val))
+	case uint8:
+		return intValue(int64(val))
+	case uint16:
+		return intValue(int64(val))
+	case uint32:
+		return intValue(int64(val))
+	case uint64:
+		return intValue(int64(val))
+	case float32:
+		return &commonpb.AnyValue{Value: &commonpb.AnyValue_DoubleValue{DoubleValue: float64(val)}}
+	case float64:
+		return &commonpb.AnyValue{Value: &commonpb.AnyValue_DoubleValue{DoubleValue: val}}
+	case json.Number:
+		if i, err := val.Int64(); err == nil {
+			return intValue(i)
+		}
+		f, _ := val.Float64()
+		return &commonpb.AnyValue{Value: &commonpb.AnyValue_DoubleValue{DoubleValue: f}}
+	case []byte:
+		return &commonpb.AnyValue{Value: &commonpb.AnyValue_BytesValue{BytesValue: val}}
+	case error:
+		return anyValue(val.Error())
+	case fmt.Stringer:
+		return anyValue(val.String())
+	case map[string]string:
+		values := make([]*commonpb.KeyValue, 0, len(val))
+		for _, k := range sortedKeys(val) {
+			values = append(values, keyValue(k, val[k]))
+		}
+		return &commonpb.AnyValue{Value: &commonpb.AnyValue_KvlistValue{
+			KvlistValue: &commonpb.KeyValueList{Values: values},
+		}}
+	case map[string]interface{}:
+		values := make([]*commonpb.KeyValue, 0, len(val))
+		for _, k := range sortedKeys(val) {
+			values = append(values, keyValue(k, val[k]))
+		}
+		return &commonpb.AnyValue{Value: &commonpb.AnyValue_KvlistValue{
+			KvlistValue: &commonpb.KeyValueList{Values: values},
+		}}
+	case []interface{}:
+		values := make([]*commonpb.AnyValue, 0, len(val))
+		for _, item := range val {
+			values = append(values, anyValue(item))
+		}
+		return &commonpb.AnyValue{Value: &commonpb.AnyValue_ArrayValue{
+			ArrayValue: &commonpb.ArrayValue{Values: values},
+		}}
+	}
+
+	// Convert through JSON, so structs and typed slices become maps and arrays
+	b, err := json.Marshal(v)
+	if err != nil {
+		return anyValue(fmt.Sprintf("%+v", v))
+	}
+
+	var generic interface{}
+	d := json.NewDecoder(bytes.NewReader(b))
+	d.UseNumber()
+	if err = d.Decode(&generic); err != nil {
+		return anyValue(string(b))
+	}
+
+	return anyValue(generic)
+}
+
+func intValue(i int64) *commonpb.AnyValue {
+	return &commonpb.AnyValue{Value: &commonpb.AnyValue_IntValue{IntValue: i}}
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
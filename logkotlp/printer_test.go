@@ -0,0 +1,207 @@
+package logkOtlp
+
+import (
+	"context"
+	"errors"
+	"net"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/go-konsultin/logk"
+	logkContext "github.com/go-konsultin/logk/context"
+	"github.com/go-konsultin/logk/level"
+	logkOption "github.com/go-konsultin/logk/option"
+	collogspb "go.opentelemetry.io/proto/otlp/collector/logs/v1"
+	commonpb "go.opentelemetry.io/proto/otlp/common/v1"
+	logspb "go.opentelemetry.io/proto/otlp/logs/v1"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+)
+
+// collector records export requests, failing the first failures of them with UNAVAILABLE
+type collector struct {
+	collogspb.UnimplementedLogsServiceServer
+
+	mu       sync.Mutex
+	requests []*collogspb.ExportLogsServiceRequest
+	headers  []metadata.MD
+	failures int
+}
+
+func (c *collector) Export(ctx context.Context, req *collogspb.ExportLogsServiceRequest) (
+	*collogspb.ExportLogsServiceResponse, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	md, _ := metadata.FromIncomingContext(ctx)
+	c.headers = append(c.headers, md)
+
+	if c.failures > 0 {
+		c.failures--
+		return nil, status.Error(codes.Unavailable, "unavailable")
+	}
+
+	c.requests = append(c.requests, req)
+	return &collogspb.ExportLogsServiceResponse{}, nil
+}
+
+func (c *collector) records() []*logspb.LogRecord {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	var records []*logspb.LogRecord
+	for _, req := range c.requests {
+		for _, rl := range req.ResourceLogs {
+			for _, sl := range rl.ScopeLogs {
+				records = append(records, sl.LogRecords...)
+			}
+		}
+	}
+	return records
+}
+
+func newCollector(t *testing.T, c *collector) *grpc.ClientConn {
+	t.Helper()
+
+	lis := bufconn.Listen(1 << 20)
+	srv := grpc.NewServer()
+	collogspb.RegisterLogsServiceServer(srv, c)
+	go func() {
+		_ = srv.Serve(lis)
+	}()
+
+	conn, err := grpc.NewClient("passthrough:///bufnet",
+		grpc.WithContextDialer(func(context.Context, string) (net.Conn, error) {
+			return lis.Dial()
+		}),
+		grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	t.Cleanup(func() {
+		_ = conn.Close()
+		srv.Stop()
+	})
+	return conn
+}
+
+func attributes(r *logspb.LogRecord) map[string]*commonpb.AnyValue {
+	m := make(map[string]*commonpb.AnyValue, len(r.Attributes))
+	for _, kv := range r.Attributes {
+		m[kv.Key] = kv.Value
+	}
+	return m
+}
+
+func TestPrinter(t *testing.T) {
+	c := &collector{}
+	p, err := NewPrinter(newCollector(t, c), WithServiceName("api"), WithHeader("authorization", "token"),
+		WithBatch(10, time.Hour))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	logger := logk.NewStdLogger(p, logkOption.Level(level.Trace))
+	ctx := logkContext.SetTrace(context.Background(), "0af7651916cd43dd8448eb211c80319c", "b7ad6b7169203331")
+	logger.NewChildCtx(ctx, logkOption.WithNamespace("svc")).Error("failed", logkOption.Error(errors.New("boom")),
+		logkOption.AddMetadata("attempt", 2), logkOption.AddMetadata("user", map[string]interface{}{"id": "u-1"}))
+	logger.Debug("debug")
+
+	if err = p.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	if len(c.requests) != 1 {
+		t.Fatalf("requests = %d, want one batch", len(c.requests))
+	}
+	if got := c.headers[0].Get("authorization"); len(got) != 1 || got[0] != "token" {
+		t.Errorf("authorization header = %v, want token", got)
+	}
+
+	rl := c.requests[0].ResourceLogs[0]
+	if kv := rl.Resource.Attributes[0]; kv.Key != "service.name" || kv.Value.GetStringValue() != "api" {
+		t.Errorf("resource attribute = %v, want service.name=api", kv)
+	}
+	if name := rl.ScopeLogs[0].Scope.GetName(); name != "github.com/go-konsultin/logk" {
+		t.Errorf("scope = %s", name)
+	}
+
+	records := c.records()
+	if len(records) != 2 {
+		t.Fatalf("records = %d, want 2", len(records))
+	}
+
+	r := records[0]
+	if r.SeverityNumber != logspb.SeverityNumber_SEVERITY_NUMBER_ERROR || r.SeverityText != "ERROR" {
+		t.Errorf("severity = %s %s, want ERROR", r.SeverityNumber, r.SeverityText)
+	}
+	if r.Body.GetStringValue() != "failed" {
+		t.Errorf("body = %v, want failed", r.Body)
+	}
+	if len(r.TraceId) != 16 || len(r.SpanId) != 8 {
+		t.Errorf("trace id = %x, span id = %x", r.TraceId, r.SpanId)
+	}
+
+	attrs := attributes(r)
+	if got := attrs["exception.message"].GetStringValue(); got != "boom" {
+		t.Errorf("exception.message = %q, want boom", got)
+	}
+	if got := attrs["attempt"].GetIntValue(); got != 2 {
+		t.Errorf("attempt = %d, want 2", got)
+	}
+	if got := attrs[logkOption.NamespaceKey].GetStringValue(); got != "svc" {
+		t.Errorf("namespace = %q, want svc", got)
+	}
+	if kv := attrs["user"].GetKvlistValue().GetValues(); len(kv) != 1 || kv[0].Value.GetStringValue() != "u-1" {
+		t.Errorf("user = %v, want id=u-1", attrs["user"])
+	}
+
+	if records[1].SeverityNumber != logspb.SeverityNumber_SEVERITY_NUMBER_DEBUG {
+		t.Errorf("severity = %s, want DEBUG", records[1].SeverityNumber)
+	}
+}
+
+func TestPrinterRetry(t *testing.T) {
+	c := &collector{failures: 2}
+
+	var errs []error
+	p, err := NewPrinter(newCollector(t, c), WithRetry(2, time.Millisecond), WithOnError(func(err error) {
+		errs = append(errs, err)
+	}))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	p.Print("", level.Info, "retried", logkOption.NewOptions())
+	if err = p.Flush(); err != nil {
+		t.Fatal(err)
+	}
+
+	if len(c.records()) != 1 || len(errs) != 0 {
+		t.Errorf("records = %d, errors = %v, want one record without errors", len(c.records()), errs)
+	}
+
+	// Retries are exhausted
+	c.mu.Lock()
+	c.failures = 3
+	c.mu.Unlock()
+
+	p.Print("", level.Info, "failed", logkOption.NewOptions())
+	_ = p.Close()
+
+	if len(errs) != 1 || status.Code(errors.Unwrap(errs[0])) != codes.Unavailable {
+		t.Errorf("errors = %v, want one UNAVAILABLE error", errs)
+	}
+}
+
+func TestNewPrinterRequiresConn(t *testing.T) {
+	if _, err := NewPrinter(nil); err == nil {
+		t.Error("printer is created without connection")
+	}
+}
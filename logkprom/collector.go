@@ -0,0 +1,145 @@
+// Package logkProm exposes Prometheus metrics of logk, so alerts can fire on error spikes and dropped entries
+// without parsing logs
+package logkProm
+
+import (
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/go-konsultin/logk"
+	"github.com/go-konsultin/logk/level"
+	logkOption "github.com/go-konsultin/logk/option"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// levels are observed with logk.OnLevel
+var levels = []level.LogLevel{level.Fatal, level.Error, level.Warn, level.Info, level.Debug, level.Trace}
+
+type Options struct {
+	// Namespace is prefix of metric names
+	Namespace string
+	// ConstLabels are added to every metric
+	ConstLabels prometheus.Labels
+}
+
+type Option = func(*Options)
+
+func WithNamespace(ns string) Option {
+	return func(o *Options) {
+		o.Namespace = ns
+	}
+}
+
+func WithConstLabels(labels prometheus.Labels) Option {
+	return func(o *Options) {
+		o.ConstLabels = labels
+	}
+}
+
+// Collector is a prometheus.Collector of logk metrics:
+//
+//   - logk_entries_total{level, namespace} counts entries written by StdLogger, after level filtering and hooks
+//   - logk_dropped_entries_total{source} reports drop counters added with TrackDropped
+//   - logk_sink_errors_total{sink} counts errors reported to callbacks created with OnError
+type Collector struct {
+	entries *prometheus.CounterVec
+	errors  *prometheus.CounterVec
+	dropped *prometheus.Desc
+
+	mu             sync.RWMutex
+	droppedSources map[string]func() uint64
+
+	observeOnce sync.Once
+}
+
+func NewCollector(args ...Option) *Collector {
+	o := Options{Namespace: "logk"}
+	for _, fn := range args {
+		fn(&o)
+	}
+
+	return &Collector{
+		entries: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace:   o.Namespace,
+			Name:        "entries_total",
+			Help:        "Number of log entries written, by level and namespace.",
+			ConstLabels: o.ConstLabels,
+		}, []string{"level", "namespace"}),
+		errors: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace:   o.Namespace,
+			Name:        "sink_errors_total",
+			Help:        "Number of errors writing log entries to sinks, by sink.",
+			ConstLabels: o.ConstLabels,
+		}, []string{"sink"}),
+		dropped: prometheus.NewDesc(prometheus.BuildFQName(o.Namespace, "", "dropped_entries_total"),
+			"Number of log entries dropped by buffered and sampling printers, by source.",
+			[]string{"source"}, o.ConstLabels),
+		droppedSources: make(map[string]func() uint64),
+	}
+}
+
+// Register registers collector on r and starts counting entries of StdLogger with logk.OnLevel
+func (c *Collector) Register(r prometheus.Registerer) error {
+	if err := r.Register(c); err != nil {
+		return err
+	}
+	c.observeOnce.Do(c.observe)
+	return nil
+}
+
+// MustRegister is like Register but panics on error
+func (c *Collector) MustRegister(r prometheus.Registerer) {
+	if err := c.Register(r); err != nil {
+		panic(err)
+	}
+}
+
+// TrackDropped reports fn as drop counter of source, e.g. c.TrackDropped("async", asyncPrinter.Dropped).
+// Adding the same source again replaces its counter
+func (c *Collector) TrackDropped(source string, fn func() uint64) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.droppedSources[source] = fn
+}
+
+// OnError returns callback that counts errors of sink, e.g. logkSink.WithOTLPOnError(c.OnError("otlp"))
+func (c *Collector) OnError(sink string) func(err error) {
+	counter := c.errors.WithLabelValues(sink)
+	return func(error) {
+		counter.Inc()
+	}
+}
+
+func (c *Collector) Describe(ch chan<- *prometheus.Desc) {
+	c.entries.Describe(ch)
+	c.errors.Describe(ch)
+	ch <- c.dropped
+}
+
+func (c *Collector) Collect(ch chan<- prometheus.Metric) {
+	c.entries.Collect(ch)
+	c.errors.Collect(ch)
+
+	c.mu.RLock()
+	sources := make([]string, 0, len(c.droppedSources))
+	for source := range c.droppedSources {
+		sources = append(sources, source)
+	}
+	sort.Strings(sources)
+
+	for _, source := range sources {
+		ch <- prometheus.MustNewConstMetric(c.dropped, prometheus.CounterValue,
+			float64(c.droppedSources[source]()), source)
+	}
+	c.mu.RUnlock()
+}
+
+func (c *Collector) observe() {
+	for _, lv := range levels {
+		name := strings.ToLower(level.String(lv))
+		logk.OnLevel(lv, func(namespace string, _ level.LogLevel, _ string, _ *logkOption.Options) {
+			c.entries.WithLabelValues(name, namespace).Inc()
+		})
+	}
+}
@@ -0,0 +1,15 @@
+module github.com/go-konsultin/logk/logksentry
+
+go 1.23.0
+
+require (
+	github.com/getsentry/sentry-go v0.42.0
+	github.com/go-konsultin/logk v0.0.0
+)
+
+require (
+	golang.org/x/sys v0.18.0 // indirect
+	golang.org/x/text v0.14.0 // indirect
+)
+
+replace github.com/go-konsultin/logk => ../
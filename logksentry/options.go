@@ -0,0 +1,123 @@
+// Package logkSentry reports logk entries to Sentry as events
+package logkSentry
+
+import (
+	"time"
+
+	"github.com/getsentry/sentry-go"
+	"github.com/go-konsultin/logk"
+	"github.com/go-konsultin/logk/level"
+)
+
+// Default printer options
+const (
+	defaultFlushTimeout  = 2 * time.Second
+	defaultMaxErrorDepth = 10
+)
+
+type Options struct {
+	// Hub captures events, default is sentry.CurrentHub, which is set up with sentry.Init
+	Hub *sentry.Hub
+	// Level is the lowest severity that is reported, default is ERROR
+	Level level.LogLevel
+	// Environment and Release override those of client options, e.g. when they are not known at sentry.Init
+	Environment string
+	Release     string
+	// Tags are added to all events
+	Tags map[string]string
+	// TagKeys are metadata keys whose values are written as tags, so events can be searched by them
+	TagKeys []string
+	// Fingerprint returns fingerprint of entry that groups events into issues. Default is nil, which leaves
+	// grouping to Sentry, see FingerprintByMessage
+	Fingerprint func(entry *logk.Entry) []string
+	// FlushTimeout limits waiting for events to be sent on Flush and Close
+	FlushTimeout time.Duration
+	// MaxErrorDepth is number of wrapped errors reported as exceptions
+	MaxErrorDepth  int
+	PrinterOptions []logk.PrinterOption
+}
+
+type Option = func(*Options)
+
+func WithHub(hub *sentry.Hub) Option {
+	return func(o *Options) {
+		o.Hub = hub
+	}
+}
+
+func WithLevel(lv level.LogLevel) Option {
+	return func(o *Options) {
+		o.Level = lv
+	}
+}
+
+func WithEnvironment(env string) Option {
+	return func(o *Options) {
+		o.Environment = env
+	}
+}
+
+func WithRelease(release string) Option {
+	return func(o *Options) {
+		o.Release = release
+	}
+}
+
+func WithTag(key, value string) Option {
+	return func(o *Options) {
+		o.Tags[key] = value
+	}
+}
+
+func WithTagKeys(keys ...string) Option {
+	return func(o *Options) {
+		o.TagKeys = append(o.TagKeys, keys...)
+	}
+}
+
+func WithFingerprint(fn func(entry *logk.Entry) []string) Option {
+	return func(o *Options) {
+		o.Fingerprint = fn
+	}
+}
+
+func WithFlushTimeout(d time.Duration) Option {
+	return func(o *Options) {
+		o.FlushTimeout = d
+	}
+}
+
+func WithMaxErrorDepth(n int) Option {
+	return func(o *Options) {
+		o.MaxErrorDepth = n
+	}
+}
+
+func WithPrinterOptions(args ...logk.PrinterOption) Option {
+	return func(o *Options) {
+		o.PrinterOptions = append(o.PrinterOptions, args...)
+	}
+}
+
+// FingerprintByMessage groups events by namespace and message, so entries logged at the same place with different
+// errors are reported as a single issue
+func FingerprintByMessage(entry *logk.Entry) []string {
+	return []string{entry.Namespace, entry.Message}
+}
+
+func evaluateOptions(args []Option) Options {
+	o := Options{
+		Level:         level.Error,
+		Tags:          make(map[string]string),
+		FlushTimeout:  defaultFlushTimeout,
+		MaxErrorDepth: defaultMaxErrorDepth,
+	}
+	for _, fn := range args {
+		fn(&o)
+	}
+
+	if o.Hub == nil {
+		o.Hub = sentry.CurrentHub()
+	}
+	return o
+}
@@ -0,0 +1,191 @@
+package logkSentry
+
+import (
+	"fmt"
+	"runtime"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/getsentry/sentry-go"
+	"github.com/go-konsultin/logk"
+	"github.com/go-konsultin/logk/level"
+	logkOption "github.com/go-konsultin/logk/option"
+)
+
+const pkgName = "logk/logksentry"
+
+// logkModule prefixes modules of logk frames, which are trimmed from stack traces captured on print
+const logkModule = "github.com/go-konsultin/logk"
+
+// Sentry event keys
+const (
+	requestIdTag = "request_id"
+	metadataKey  = "metadata"
+	baggageKey   = "baggage"
+	traceContext = "trace"
+	callerKey    = "caller"
+)
+
+var sentryLevels = map[level.LogLevel]sentry.Level{
+	level.Fatal: sentry.LevelFatal,
+	level.Error: sentry.LevelError,
+	level.Warn:  sentry.LevelWarning,
+	level.Info:  sentry.LevelInfo,
+	level.Debug: sentry.LevelDebug,
+	level.Trace: sentry.LevelDebug,
+}
+
+// Printer reports entries at or above level, ERROR by default, as Sentry events. Error of entry and errors it wraps
+// are reported as exceptions, with stack trace of entry when error doesn't carry one. Request id is written as tag,
+// metadata, baggage and caller as contexts, and trace id links event to trace.
+//
+// Printer is usually added next to the primary printer with logkOption.WithPrinter. It can also be registered as
+// hook, e.g. logk.OnLevel(level.Error, printer.Print), to report entries of all loggers
+type Printer struct {
+	options Options
+}
+
+// NewPrinter creates printer that captures events with hub, sentry.CurrentHub by default
+func NewPrinter(args ...Option) *Printer {
+	return &Printer{options: evaluateOptions(args)}
+}
+
+func (p *Printer) Print(namespace string, lv level.LogLevel, msg string, options *logkOption.Options) {
+	if lv > p.options.Level {
+		return
+	}
+
+	entry := logk.NewEntry(namespace, lv, msg, options, p.options.PrinterOptions...)
+	p.options.Hub.CaptureEvent(p.newEvent(entry))
+}
+
+// Flush waits until captured events are sent, up to flush timeout
+func (p *Printer) Flush() error {
+	if !p.options.Hub.Flush(p.options.FlushTimeout) {
+		return fmt.Errorf("%s: events were not sent in %s", pkgName, p.options.FlushTimeout)
+	}
+	return nil
+}
+
+// Close sends captured events, so they are not lost on shutdown. Client of hub is not closed, as it may be shared
+func (p *Printer) Close() error {
+	return p.Flush()
+}
+
+func (p *Printer) newEvent(entry *logk.Entry) *sentry.Event {
+	event := sentry.NewEvent()
+	event.Level = sentryLevels[entry.Level]
+	event.Message = entry.Message
+	event.Logger = entry.Namespace
+	event.Timestamp = entry.Time
+	event.Environment = p.options.Environment
+	event.Release = p.options.Release
+
+	if p.options.Fingerprint != nil {
+		event.Fingerprint = p.options.Fingerprint(entry)
+	}
+
+	for k, v := range p.options.Tags {
+		event.Tags[k] = v
+	}
+	for _, k := range p.options.TagKeys {
+		if v, ok := entry.Metadata[k]; ok && v != nil {
+			event.Tags[k] = fmt.Sprint(v)
+		}
+	}
+	if entry.RequestId != "" {
+		event.Tags[requestIdTag] = entry.RequestId
+	}
+
+	if len(entry.Metadata) > 0 {
+		event.Contexts[metadataKey] = entry.Metadata
+	}
+
+	if len(entry.Baggage) > 0 {
+		baggage := make(sentry.Context, len(entry.Baggage))
+		for k, v := range entry.Baggage {
+			baggage[k] = v
+		}
+		event.Contexts[baggageKey] = baggage
+	}
+
+	if entry.TraceId != "" {
+		event.Contexts[traceContext] = sentry.Context{"trace_id": entry.TraceId, "span_id": entry.SpanId}
+	}
+
+	if entry.Caller.File != "" {
+		event.Contexts[callerKey] = sentry.Context{
+			"file":     entry.Caller.File,
+			"line":     entry.Caller.Line,
+			"function": entry.Caller.Function,
+		}
+	}
+
+	// Group events by fingerprint of logk, so they are grouped like in other sinks
+	if entry.Fingerprint != "" {
+		event.Fingerprint = []string{entry.Fingerprint}
+	}
+
+	stack := parseStackTrace(entry.StackTrace)
+	if entry.Error != nil {
+		event.SetException(entry.Error, p.options.MaxErrorDepth)
+
+		// Outermost exception gets stack trace of print, unless error carries its own. Prefer stack trace of entry,
+		// which starts at call site of logger
+		if n := len(event.Exception); n > 0 && sentry.ExtractStacktrace(entry.Error) == nil {
+			if stack != nil {
+				event.Exception[n-1].Stacktrace = stack
+			} else {
+				event.Exception[n-1].Stacktrace = trimLogkFrames(event.Exception[n-1].Stacktrace)
+			}
+		}
+	} else if stack != nil {
+		event.Threads = []sentry.Thread{{Stacktrace: stack, Current: true}}
+	}
+
+	return event
+}
+
+// parseStackTrace parses stack trace of entry, which holds function name and indented file:line of each frame
+// starting with the innermost one. Frames are returned starting with the outermost one, as Sentry expects
+func parseStackTrace(stack string) *sentry.Stacktrace {
+	if stack == "" {
+		return nil
+	}
+
+	lines := strings.Split(strings.TrimSuffix(stack, "\n"), "\n")
+	var frames []sentry.Frame
+	for i := 0; i+1 < len(lines); i += 2 {
+		location := strings.TrimPrefix(lines[i+1], "\t")
+		sep := strings.LastIndexByte(location, ':')
+		if sep < 0 {
+			continue
+		}
+
+		line, _ := strconv.Atoi(location[sep+1:])
+		frames = append(frames, sentry.NewFrame(runtime.Frame{
+			Function: lines[i],
+			File:     location[:sep],
+			Line:     line,
+		}))
+	}
+
+	if len(frames) == 0 {
+		return nil
+	}
+	slices.Reverse(frames)
+	return &sentry.Stacktrace{Frames: frames}
+}
+
+// trimLogkFrames removes frames of logk, which are innermost frames of stack trace captured while printing
+func trimLogkFrames(stack *sentry.Stacktrace) *sentry.Stacktrace {
+	if stack == nil {
+		return nil
+	}
+
+	frames := slices.DeleteFunc(slices.Clone(stack.Frames), func(f sentry.Frame) bool {
+		return strings.HasPrefix(f.Module, logkModule)
+	})
+	return &sentry.Stacktrace{Frames: frames}
+}
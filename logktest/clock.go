@@ -0,0 +1,51 @@
+package logkTest
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/go-konsultin/logk"
+)
+
+// Clock is a logk.Clock that only moves when it is set or advanced, so printed timestamps are deterministic
+type Clock struct {
+	mu  sync.Mutex
+	now time.Time
+}
+
+// NewClock creates a clock that is stopped at t
+func NewClock(t time.Time) *Clock {
+	return &Clock{now: t}
+}
+
+// UseClock sets a clock that is stopped at t with logk.SetClock, and restores logk.SystemClock when test has
+// finished. Tests that use it must not run in parallel, as clock is global
+func UseClock(t testing.TB, now time.Time) *Clock {
+	c := NewClock(now)
+	logk.SetClock(c)
+	t.Cleanup(func() {
+		logk.SetClock(nil)
+	})
+	return c
+}
+
+func (c *Clock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.now
+}
+
+// Set stops clock at t
+func (c *Clock) Set(t time.Time) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.now = t
+}
+
+// Advance moves clock forward by d
+func (c *Clock) Advance(d time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.now = c.now.Add(d)
+}
@@ -0,0 +1,55 @@
+package logkTest
+
+import (
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/go-konsultin/logk"
+	"github.com/go-konsultin/logk/level"
+	logkOption "github.com/go-konsultin/logk/option"
+)
+
+const pkgName = "logk/logktest"
+
+// NewTestLogger creates a logger in TRACE level that records entries in returned Recorder, and mirrors them in
+// logfmt through t.Log, so output is shown with the failing test. Entries written after test has finished are
+// only recorded. FATAL entries are recorded with FatalNoop behavior, so code under test doesn't exit the test binary;
+// pass logkOption.WithFatalBehavior to override it
+func NewTestLogger(t testing.TB, args ...logkOption.SetterFunc) (*logk.StdLogger, *Recorder) {
+	r := NewRecorder()
+	w := &testWriter{t: t}
+	t.Cleanup(w.done)
+
+	args = append([]logkOption.SetterFunc{
+		logkOption.Level(level.Trace),
+		logkOption.WithFatalBehavior(logkOption.FatalNoop),
+	}, args...)
+	l := logk.NewStdLogger(r, append(args, logk.WithPrinter(logk.NewLogfmtPrinter(w)))...)
+	return l, r
+}
+
+// testWriter writes each line with t.Log
+type testWriter struct {
+	t        testing.TB
+	mu       sync.Mutex
+	finished bool
+}
+
+func (w *testWriter) Write(p []byte) (int, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	// t.Log panics after test has finished
+	if !w.finished {
+		w.t.Helper()
+		w.t.Log(strings.TrimSuffix(string(p), "\n"))
+	}
+	return len(p), nil
+}
+
+func (w *testWriter) done() {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.finished = true
+}
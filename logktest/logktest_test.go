@@ -0,0 +1,124 @@
+package logkTest
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/go-konsultin/logk/level"
+	logkOption "github.com/go-konsultin/logk/option"
+)
+
+// fakeT records failures of assertion helpers instead of failing the running test
+type fakeT struct {
+	testing.TB
+	failed bool
+}
+
+func (f *fakeT) Helper() {}
+
+func (f *fakeT) Errorf(string, ...interface{}) {
+	f.failed = true
+}
+
+func TestNewTestLoggerRecordsFatal(t *testing.T) {
+	l, r := NewTestLogger(t)
+
+	l.Fatal("fatal entry")
+	l.Info("after fatal")
+
+	if got := r.FilterLevel(level.Fatal); len(got) != 1 || got[0].Message != "fatal entry" {
+		t.Fatalf("fatal entries = %v, want one fatal entry", got)
+	}
+	if r.Len() != 2 {
+		t.Errorf("len = %d, want 2", r.Len())
+	}
+}
+
+func TestNewTestLoggerFatalBehaviorOverride(t *testing.T) {
+	l, r := NewTestLogger(t, logkOption.WithFatalBehavior(logkOption.FatalPanic))
+
+	defer func() {
+		if recover() == nil {
+			t.Error("fatal didn't panic")
+		}
+		if r.Len() != 1 {
+			t.Errorf("len = %d, want 1", r.Len())
+		}
+	}()
+	l.Fatal("fatal entry")
+}
+
+type userId int
+
+func (id userId) String() string {
+	return fmt.Sprintf("u-%d", int(id))
+}
+
+func TestAssertContainsField(t *testing.T) {
+	l, r := NewTestLogger(t)
+	l.Info("login",
+		logkOption.AddMetadata("user", map[string]interface{}{
+			"id":   userId(7),
+			"tags": []string{"a", "b"},
+		}),
+		logkOption.AddMetadata("attempts", int64(3)),
+	)
+
+	pass := []struct {
+		key   string
+		value interface{}
+	}{
+		{logkOption.MessageKey, "login"},
+		{logkOption.LevelKey, "info"},
+		{"attempts", int64(3)},
+		{"attempts", 3},
+		{"user.id", userId(7)},
+		{"user.id", "u-7"},
+		{"user.tags", []string{"a", "b"}},
+	}
+	for _, tc := range pass {
+		ft := &fakeT{TB: t}
+		if !r.AssertContainsField(ft, tc.key, tc.value) || ft.failed {
+			t.Errorf("%s=%v: assertion failed", tc.key, tc.value)
+		}
+	}
+
+	fail := []struct {
+		key   string
+		value interface{}
+	}{
+		{"attempts", 4},
+		{"user.id", "u-8"},
+		{"user.name", "x"},
+		{"user.id.value", 7},
+		{"missing", nil},
+	}
+	for _, tc := range fail {
+		ft := &fakeT{TB: t}
+		if r.AssertContainsField(ft, tc.key, tc.value) || !ft.failed {
+			t.Errorf("%s=%v: assertion passed", tc.key, tc.value)
+		}
+	}
+}
+
+func TestRecorderFilter(t *testing.T) {
+	l, r := NewTestLogger(t)
+	l.Info("first")
+	l.Warn("second")
+	l.Warn("third")
+
+	if got := r.FilterLevel(level.Warn); len(got) != 2 {
+		t.Errorf("warn entries = %d, want 2", len(got))
+	}
+	if got := r.FilterMessage("ir"); len(got) != 2 {
+		t.Errorf("entries matching ir = %d, want 2", len(got))
+	}
+	if got := r.LastEntry(); got == nil || got.Message != "third" {
+		t.Errorf("last entry = %v, want third", got)
+	}
+
+	r.Reset()
+	if r.Len() != 0 || r.LastEntry() != nil {
+		t.Errorf("len = %d after reset, want 0", r.Len())
+	}
+}
@@ -0,0 +1,129 @@
+package logkTest
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/go-konsultin/logk"
+	"github.com/go-konsultin/logk/level"
+	logkOption "github.com/go-konsultin/logk/option"
+)
+
+// Recorder is a printer that stores entries in memory, so tests can assert on them instead of scraping output
+type Recorder struct {
+	mu      sync.Mutex
+	entries []*logk.Entry
+}
+
+func NewRecorder() *Recorder {
+	return &Recorder{}
+}
+
+func (r *Recorder) Print(namespace string, lv level.LogLevel, msg string, options *logkOption.Options) {
+	entry := logk.NewEntry(namespace, lv, msg, options)
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.entries = append(r.entries, entry)
+}
+
+// Entries returns a copy of recorded entries in order
+func (r *Recorder) Entries() []*logk.Entry {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]*logk.Entry(nil), r.entries...)
+}
+
+// Len returns number of recorded entries
+func (r *Recorder) Len() int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return len(r.entries)
+}
+
+// LastEntry returns the most recent entry, or nil if nothing is recorded
+func (r *Recorder) LastEntry() *logk.Entry {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if len(r.entries) == 0 {
+		return nil
+	}
+	return r.entries[len(r.entries)-1]
+}
+
+// FilterLevel returns entries in level
+func (r *Recorder) FilterLevel(lv level.LogLevel) []*logk.Entry {
+	return r.filter(func(e *logk.Entry) bool {
+		return e.Level == lv
+	})
+}
+
+// FilterMessage returns entries which formatted message contains substr
+func (r *Recorder) FilterMessage(substr string) []*logk.Entry {
+	return r.filter(func(e *logk.Entry) bool {
+		return strings.Contains(e.Message, substr)
+	})
+}
+
+// Reset removes recorded entries
+func (r *Recorder) Reset() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.entries = nil
+}
+
+// AssertContainsField fails test if no entry has field or metadata key with value. Values are compared with
+// reflect.DeepEqual, then by their formatted form, so 1 matches an int64 field and a string matches its Stringer
+func (r *Recorder) AssertContainsField(t testing.TB, key string, value interface{}) bool {
+	t.Helper()
+
+	for _, e := range r.Entries() {
+		if v, ok := Field(e, key); ok && equal(v, value) {
+			return true
+		}
+	}
+
+	t.Errorf("%s: no entry contains field %s=%v", pkgName, key, value)
+	return false
+}
+
+// Field returns value of entry field or metadata by key. Nested metadata can be accessed with dot separated key,
+// e.g. "user.id". Fields take precedence over metadata
+func Field(e *logk.Entry, key string) (interface{}, bool) {
+	if v, ok := e.Fields()[key]; ok {
+		return v, true
+	}
+
+	var current interface{} = e.Metadata
+	for _, part := range strings.Split(key, ".") {
+		m, ok := current.(map[string]interface{})
+		if !ok {
+			return nil, false
+		}
+
+		if current, ok = m[part]; !ok {
+			return nil, false
+		}
+	}
+	return current, true
+}
+
+func (r *Recorder) filter(fn func(*logk.Entry) bool) []*logk.Entry {
+	var result []*logk.Entry
+	for _, e := range r.Entries() {
+		if fn(e) {
+			result = append(result, e)
+		}
+	}
+	return result
+}
+
+func equal(a, b interface{}) bool {
+	if reflect.DeepEqual(a, b) {
+		return true
+	}
+	return fmt.Sprint(a) == fmt.Sprint(b)
+}
@@ -0,0 +1,9 @@
+module github.com/go-konsultin/logk/logrbridge
+
+go 1.23.0
+
+require github.com/go-konsultin/logk v0.0.0
+
+require github.com/go-logr/logr v1.4.2
+
+replace github.com/go-konsultin/logk => ../
@@ -0,0 +1,122 @@
+package logkLogr
+
+import (
+	"fmt"
+
+	"github.com/go-konsultin/logk"
+	"github.com/go-konsultin/logk/level"
+	logkOption "github.com/go-konsultin/logk/option"
+	"github.com/go-logr/logr"
+)
+
+// missingValue is written when keysAndValues has odd length, same as logr funcr implementation
+const missingValue = "<no-value>"
+
+// Sink implements logr.LogSink on top of a logk Logger, so controller-runtime and client-go components log through
+// logk:
+//
+//	log := logkLogr.NewLogger(logger)
+//
+// V-level 0 is written as INFO, 1 as DEBUG and 2 and above as TRACE. Names set with WithName are joined with "/"
+// and written as namespace with NewChild
+type Sink struct {
+	logger logk.Logger
+	name   string
+}
+
+var _ logr.LogSink = (*Sink)(nil)
+
+// NewSink creates logr.LogSink backed by logger. If logger is nil, registered logger is used
+func NewSink(logger logk.Logger) *Sink {
+	if logger == nil {
+		logger = logk.Get()
+	}
+	return &Sink{logger: logger}
+}
+
+// NewLogger creates logr.Logger backed by logger. If logger is nil, registered logger is used
+func NewLogger(logger logk.Logger) logr.Logger {
+	return logr.New(NewSink(logger))
+}
+
+// Init does nothing, as caller is captured by logk logger
+func (s *Sink) Init(logr.RuntimeInfo) {}
+
+// Enabled reports whether V-level is enabled by logger, including namespace level of names set with WithName.
+// Logger that doesn't implement logk.EnabledLogger enables all levels
+func (s *Sink) Enabled(v int) bool {
+	return logk.IsEnabled(s.logger, toLevel(v))
+}
+
+func (s *Sink) Info(v int, msg string, keysAndValues ...interface{}) {
+	args := []logkOption.SetterFunc{logkOption.Metadata(toMetadata(keysAndValues))}
+
+	switch toLevel(v) {
+	case level.Info:
+		s.logger.Info(msg, args...)
+	case level.Debug:
+		s.logger.Debug(msg, args...)
+	default:
+		s.logger.Trace(msg, args...)
+	}
+}
+
+func (s *Sink) Error(err error, msg string, keysAndValues ...interface{}) {
+	s.logger.Error(msg, logkOption.Error(err), logkOption.Metadata(toMetadata(keysAndValues)))
+}
+
+// WithValues returns a sink which entries carry keysAndValues as metadata
+func (s *Sink) WithValues(keysAndValues ...interface{}) logr.LogSink {
+	c := Sink{
+		logger: s.logger.With(logkOption.Metadata(toMetadata(keysAndValues))),
+		name:   s.name,
+	}
+	return &c
+}
+
+// WithName returns a sink which namespace is name appended to current name with "/"
+func (s *Sink) WithName(name string) logr.LogSink {
+	if s.name != "" {
+		name = s.name + "/" + name
+	}
+
+	c := Sink{
+		logger: s.logger.NewChild(logkOption.WithNamespace(name)),
+		name:   name,
+	}
+	return &c
+}
+
+// toLevel converts logr V-level to logk level
+func toLevel(v int) level.LogLevel {
+	switch {
+	case v <= 0:
+		return level.Info
+	case v == 1:
+		return level.Debug
+	default:
+		return level.Trace
+	}
+}
+
+// toMetadata converts alternating keys and values to metadata. Keys that are not string are formatted with fmt
+func toMetadata(keysAndValues []interface{}) map[string]interface{} {
+	if len(keysAndValues) == 0 {
+		return nil
+	}
+
+	m := make(map[string]interface{}, (len(keysAndValues)+1)/2)
+	for i := 0; i < len(keysAndValues); i += 2 {
+		k, ok := keysAndValues[i].(string)
+		if !ok {
+			k = fmt.Sprint(keysAndValues[i])
+		}
+
+		if i+1 < len(keysAndValues) {
+			m[k] = keysAndValues[i+1]
+		} else {
+			m[k] = missingValue
+		}
+	}
+	return m
+}
@@ -0,0 +1,60 @@
+package logkLogr
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/go-konsultin/logk"
+	"github.com/go-konsultin/logk/level"
+	logkTest "github.com/go-konsultin/logk/logktest"
+	logkOption "github.com/go-konsultin/logk/option"
+)
+
+func TestSink(t *testing.T) {
+	logger, r := logkTest.NewTestLogger(t)
+	log := NewLogger(logger).WithName("ctrl").WithName("pod").WithValues("attempt", 2)
+
+	log.V(1).Info("reconciling", "pod", "web-0", "odd")
+	log.Error(errors.New("boom"), "failed")
+
+	entries := r.Entries()
+	if len(entries) != 2 {
+		t.Fatalf("len = %d, want 2", len(entries))
+	}
+
+	if e := entries[0]; e.Level != level.Debug || e.Namespace != "ctrl/pod" || e.Message != "reconciling" {
+		t.Errorf("entry = %s %q %q, want debug \"reconciling\" \"ctrl/pod\"", level.String(e.Level), e.Message,
+			e.Namespace)
+	}
+	if e := entries[1]; e.Level != level.Error || e.Error == nil || e.Error.Error() != "boom" {
+		t.Errorf("entry = %s %v, want error boom", level.String(e.Level), e.Error)
+	}
+
+	r.AssertContainsField(t, "attempt", 2)
+	r.AssertContainsField(t, "pod", "web-0")
+	r.AssertContainsField(t, "odd", missingValue)
+}
+
+func TestSinkEnabled(t *testing.T) {
+	logger, r := logkTest.NewTestLogger(t, logkOption.Level(level.Info))
+	logk.SetNamespaceLevel("verbose", level.Trace)
+	t.Cleanup(func() {
+		logk.RemoveNamespaceLevel("verbose")
+	})
+
+	log := NewLogger(logger)
+	if !log.V(0).Enabled() || log.V(1).Enabled() {
+		t.Errorf("enabled V(0)=%t V(1)=%t, want true false", log.V(0).Enabled(), log.V(1).Enabled())
+	}
+
+	verbose := log.WithName("verbose")
+	if !verbose.V(2).Enabled() {
+		t.Error("V(2) of namespace in TRACE level isn't enabled")
+	}
+
+	log.V(1).Info("skipped")
+	verbose.V(2).Info("written")
+	if r.Len() != 1 || r.LastEntry().Level != level.Trace {
+		t.Errorf("entries = %v, want one trace entry", r.Entries())
+	}
+}
@@ -0,0 +1,12 @@
+module github.com/go-konsultin/logk/logrusbridge
+
+go 1.23.0
+
+require (
+	github.com/go-konsultin/logk v0.0.0
+	github.com/sirupsen/logrus v1.9.3
+)
+
+require golang.org/x/sys v0.18.0 // indirect
+
+replace github.com/go-konsultin/logk => ../
@@ -0,0 +1,104 @@
+package logkLogrus
+
+import (
+	"github.com/go-konsultin/logk"
+	logkIngest "github.com/go-konsultin/logk/internal/ingest"
+	"github.com/go-konsultin/logk/level"
+	logkOption "github.com/go-konsultin/logk/option"
+	"github.com/sirupsen/logrus"
+)
+
+// Hook is a logrus.Hook that forwards logrus entries into a logk Logger, with logrus levels mapped to logk levels
+// and fields translated to metadata. "error" field becomes entry error and "namespace" field becomes namespace.
+// Discard logrus output, so entries are only written by logk:
+//
+//	log.SetOutput(io.Discard)
+//	log.AddHook(logkLogrus.NewHook(nil))
+//
+// Panic and fatal entries are written as FATAL without exiting, as logrus panics or exits by itself
+type Hook struct {
+	printer logk.Printer
+	levels  []logrus.Level
+}
+
+// NewHook creates a hook that forwards entries in levels to logger. If logger is nil, registered logger is used.
+// If levels is empty, entries in all levels are forwarded
+func NewHook(logger logk.Logger, levels ...logrus.Level) *Hook {
+	return NewPrinterHook(logkIngest.NewLoggerPrinter(logger), levels...)
+}
+
+// NewPrinterHook creates a hook that prints forwarded entries with printer, keeping logrus time and caller.
+// If printer is nil, std printer is used
+func NewPrinterHook(printer logk.Printer, levels ...logrus.Level) *Hook {
+	// Init printer if nil
+	if printer == nil {
+		printer = logk.NewStdLogPrinter(nil, 0)
+	}
+
+	if len(levels) == 0 {
+		levels = logrus.AllLevels
+	}
+
+	return &Hook{printer: printer, levels: levels}
+}
+
+func (h *Hook) Levels() []logrus.Level {
+	return h.levels
+}
+
+func (h *Hook) Fire(entry *logrus.Entry) error {
+	lv := toLogkLevel(entry.Level)
+	options := logkOption.NewOptions()
+	options.Level = lv
+	options.Values[logkOption.TimeKey] = entry.Time
+
+	if entry.Caller != nil {
+		options.Values[logkOption.CallerKey] = logkOption.Caller{
+			File:     entry.Caller.File,
+			Line:     entry.Caller.Line,
+			Function: entry.Caller.Function,
+		}
+	}
+
+	var namespace string
+	for k, v := range entry.Data {
+		switch val := v.(type) {
+		case error:
+			if k == logrus.ErrorKey {
+				options.Values[logkOption.ErrorKey] = val
+				continue
+			}
+		case string:
+			if k == logkOption.NamespaceKey {
+				namespace = val
+				continue
+			}
+		}
+
+		if options.Metadata == nil {
+			options.Metadata = make(map[string]interface{}, len(entry.Data))
+		}
+		options.Metadata[k] = v
+	}
+
+	h.printer.Print(namespace, lv, entry.Message, options)
+	return nil
+}
+
+// toLogkLevel converts logrus level to logk level
+func toLogkLevel(lv logrus.Level) level.LogLevel {
+	switch lv {
+	case logrus.PanicLevel, logrus.FatalLevel:
+		return level.Fatal
+	case logrus.ErrorLevel:
+		return level.Error
+	case logrus.WarnLevel:
+		return level.Warn
+	case logrus.InfoLevel:
+		return level.Info
+	case logrus.DebugLevel:
+		return level.Debug
+	default:
+		return level.Trace
+	}
+}
@@ -0,0 +1,73 @@
+package logkLogrus
+
+import (
+	"errors"
+	"io"
+	"testing"
+
+	"github.com/go-konsultin/logk/level"
+	logkTest "github.com/go-konsultin/logk/logktest"
+	logkOption "github.com/go-konsultin/logk/option"
+	"github.com/sirupsen/logrus"
+)
+
+func newLogrus(hook logrus.Hook) *logrus.Logger {
+	l := logrus.New()
+	l.SetOutput(io.Discard)
+	l.SetLevel(logrus.TraceLevel)
+	l.AddHook(hook)
+	return l
+}
+
+func TestHook(t *testing.T) {
+	logger, r := logkTest.NewTestLogger(t)
+	err := errors.New("boom")
+
+	newLogrus(NewHook(logger)).WithError(err).WithField(logkOption.NamespaceKey, "svc").
+		WithField("attempt", 2).Warn("retrying")
+
+	e := r.LastEntry()
+	if e == nil {
+		t.Fatal("no entry is recorded")
+	}
+	if e.Level != level.Warn || e.Message != "retrying" || e.Namespace != "svc" {
+		t.Errorf("entry = %s %q %q, want warn \"retrying\" \"svc\"", level.String(e.Level), e.Message, e.Namespace)
+	}
+	if !errors.Is(e.Error, err) {
+		t.Errorf("error = %v, want %v", e.Error, err)
+	}
+	r.AssertContainsField(t, "attempt", 2)
+}
+
+func TestHookLevels(t *testing.T) {
+	logger, r := logkTest.NewTestLogger(t)
+	l := newLogrus(NewHook(logger, logrus.ErrorLevel, logrus.FatalLevel))
+	l.ExitFunc = func(int) {}
+
+	l.Info("skipped")
+	l.Error("written")
+	l.Fatal("fatal")
+
+	if r.Len() != 2 {
+		t.Fatalf("len = %d, want 2", r.Len())
+	}
+	if got := r.FilterLevel(level.Fatal); len(got) != 1 {
+		t.Errorf("fatal entries = %d, want 1", len(got))
+	}
+}
+
+func TestHookPrinter(t *testing.T) {
+	r := logkTest.NewRecorder()
+	l := newLogrus(NewPrinterHook(r))
+	l.SetReportCaller(true)
+
+	l.Debug("debug")
+
+	e := r.LastEntry()
+	if e == nil || e.Level != level.Debug {
+		t.Fatalf("entry = %v, want debug entry", e)
+	}
+	if e.Caller.File == "" {
+		t.Error("caller is not set")
+	}
+}
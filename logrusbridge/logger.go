@@ -0,0 +1,186 @@
+package logkLogrus
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/go-konsultin/logk"
+	logkContext "github.com/go-konsultin/logk/context"
+	"github.com/go-konsultin/logk/level"
+	logkOption "github.com/go-konsultin/logk/option"
+	"github.com/sirupsen/logrus"
+)
+
+// Logger implements logk.Logger by writing to a logrus entry, for teams migrating gradually so code using logk writes
+// through existing logrus hooks and formatters. Metadata is written as logrus fields. FATAL entries are written with
+// logrus Fatal, which exits the process
+type Logger struct {
+	entry     *logrus.Entry
+	namespace string
+	ctx       context.Context
+}
+
+// NewLogger creates logk.Logger backed by logger
+func NewLogger(logger *logrus.Logger) *Logger {
+	return NewEntryLogger(logrus.NewEntry(logger))
+}
+
+// NewEntryLogger creates logk.Logger backed by entry, so fields of entry are written with every entry
+func NewEntryLogger(entry *logrus.Entry) *Logger {
+	return &Logger{entry: entry}
+}
+
+func (l *Logger) Fatal(msg string, args ...logkOption.SetterFunc) {
+	l.log(level.Fatal, msg, logkOption.Evaluate(args))
+}
+
+func (l *Logger) Fatalf(format string, args ...interface{}) {
+	l.log(level.Fatal, format, logkOption.NewFormatOptions(args...))
+}
+
+func (l *Logger) Error(msg string, args ...logkOption.SetterFunc) {
+	l.log(level.Error, msg, logkOption.Evaluate(args))
+}
+
+func (l *Logger) Errorf(format string, args ...interface{}) {
+	l.log(level.Error, format, logkOption.NewFormatOptions(args...))
+}
+
+func (l *Logger) Warn(msg string, args ...logkOption.SetterFunc) {
+	l.log(level.Warn, msg, logkOption.Evaluate(args))
+}
+
+func (l *Logger) Warnf(format string, args ...interface{}) {
+	l.log(level.Warn, format, logkOption.NewFormatOptions(args...))
+}
+
+func (l *Logger) Info(msg string, args ...logkOption.SetterFunc) {
+	l.log(level.Info, msg, logkOption.Evaluate(args))
+}
+
+func (l *Logger) Infof(format string, args ...interface{}) {
+	l.log(level.Info, format, logkOption.NewFormatOptions(args...))
+}
+
+func (l *Logger) Debug(msg string, args ...logkOption.SetterFunc) {
+	l.log(level.Debug, msg, logkOption.Evaluate(args))
+}
+
+func (l *Logger) Debugf(format string, args ...interface{}) {
+	l.log(level.Debug, format, logkOption.NewFormatOptions(args...))
+}
+
+func (l *Logger) Trace(msg string, args ...logkOption.SetterFunc) {
+	l.log(level.Trace, msg, logkOption.Evaluate(args))
+}
+
+func (l *Logger) Tracef(format string, args ...interface{}) {
+	l.log(level.Trace, format, logkOption.NewFormatOptions(args...))
+}
+
+// NewChild creates a child logger. Namespace is written as "namespace" field
+func (l *Logger) NewChild(args ...logkOption.SetterFunc) logk.Logger {
+	options := logkOption.Evaluate(args)
+
+	c := Logger{entry: l.entry, namespace: l.namespace, ctx: l.ctx}
+	if namespace := options.Namespace(); namespace != "" {
+		c.namespace = namespace
+	}
+
+	if options.Context != nil {
+		c.ctx = options.Context
+	}
+
+	return &c
+}
+
+func (l *Logger) NewChildCtx(ctx context.Context, args ...logkOption.SetterFunc) logk.Logger {
+	if ctx != nil {
+		args = append(args, logkOption.Context(ctx))
+	}
+	return l.NewChild(args...)
+}
+
+// With creates a child logger which logrus entry carries metadata set in args as fields
+func (l *Logger) With(args ...logkOption.SetterFunc) logk.Logger {
+	c := l.NewChild(args...).(*Logger)
+	if metadata := logkOption.Evaluate(args).Metadata; len(metadata) > 0 {
+		c.entry = c.entry.WithFields(metadata)
+	}
+	return c
+}
+
+func (l *Logger) log(lv level.LogLevel, msg string, options *logkOption.Options) {
+	if !l.entry.Logger.IsLevelEnabled(toLogrusLevel(lv)) {
+		return
+	}
+
+	ctx := options.Context
+	if ctx == nil {
+		ctx = l.ctx
+	}
+
+	msg = logkOption.ResolveLazy(options, msg)
+	if len(options.FmtArgs) > 0 {
+		msg = fmt.Sprintf(msg, options.FmtArgs...)
+	}
+
+	fields := make(logrus.Fields, len(options.Metadata)+4)
+	if l.namespace != "" {
+		fields[logkOption.NamespaceKey] = l.namespace
+	}
+
+	if reqId := logkContext.GetRequestId(ctx); reqId != "" {
+		fields[logkOption.RequestIdKey] = reqId
+	}
+
+	if traceId, spanId := logkContext.GetTrace(ctx); traceId != "" {
+		fields[logkOption.TraceIdKey] = traceId
+		if spanId != "" {
+			fields[logkOption.SpanIdKey] = spanId
+		}
+	}
+
+	// logrus renders error field as error message, same as logrus.Entry.WithError
+	if err := options.Error(); err != nil {
+		fields[logrus.ErrorKey] = err
+	}
+
+	for k, v := range options.Metadata {
+		fields[k] = v
+	}
+
+	e := l.entry.WithFields(fields)
+	switch lv {
+	case level.Fatal:
+		e.Fatal(msg)
+	case level.Error:
+		e.Error(msg)
+	case level.Warn:
+		e.Warn(msg)
+	case level.Info:
+		e.Info(msg)
+	case level.Debug:
+		e.Debug(msg)
+	default:
+		e.Trace(msg)
+	}
+}
+
+// toLogrusLevel converts logk level to logrus level
+func toLogrusLevel(lv level.LogLevel) logrus.Level {
+	switch lv {
+	case level.Fatal:
+		return logrus.FatalLevel
+	case level.Error:
+		return logrus.ErrorLevel
+	case level.Warn:
+		return logrus.WarnLevel
+	case level.Info:
+		return logrus.InfoLevel
+	case level.Debug:
+		return logrus.DebugLevel
+	default:
+		return logrus.TraceLevel
+	}
+}
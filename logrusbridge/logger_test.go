@@ -0,0 +1,76 @@
+package logkLogrus
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+
+	logkContext "github.com/go-konsultin/logk/context"
+	logkOption "github.com/go-konsultin/logk/option"
+	"github.com/sirupsen/logrus"
+)
+
+func newTestLogger(buf *bytes.Buffer, lv logrus.Level) *logrus.Logger {
+	l := logrus.New()
+	l.SetOutput(buf)
+	l.SetFormatter(&logrus.JSONFormatter{})
+	l.SetLevel(lv)
+	return l
+}
+
+func decode(t *testing.T, buf *bytes.Buffer) map[string]interface{} {
+	t.Helper()
+	var line map[string]interface{}
+	if err := json.Unmarshal(buf.Bytes(), &line); err != nil {
+		t.Fatalf("invalid JSON %q: %s", buf.Bytes(), err)
+	}
+	buf.Reset()
+	return line
+}
+
+func TestLogger(t *testing.T) {
+	var buf bytes.Buffer
+	logger := NewLogger(newTestLogger(&buf, logrus.TraceLevel))
+
+	ctx := logkContext.SetRequestId(context.Background(), "req-1")
+	child := logger.NewChildCtx(ctx, logkOption.WithNamespace("svc")).With(logkOption.AddMetadata("attempt", 2))
+	child.Error("failed", logkOption.Error(errors.New("boom")), logkOption.AddMetadata("user", "ann"))
+
+	got := decode(t, &buf)
+	want := map[string]interface{}{
+		"level":                 "error",
+		"msg":                   "failed",
+		"error":                 "boom",
+		"attempt":               float64(2),
+		"user":                  "ann",
+		logkOption.NamespaceKey: "svc",
+		logkOption.RequestIdKey: "req-1",
+	}
+	for k, v := range want {
+		if got[k] != v {
+			t.Errorf("%s = %v, want %v", k, got[k], v)
+		}
+	}
+
+	logger.Tracef("hello %s", "world")
+	if got := decode(t, &buf); got["msg"] != "hello world" || got["level"] != "trace" || got["attempt"] != nil {
+		t.Errorf("entry = %v, want trace hello world without attempt", got)
+	}
+}
+
+func TestLoggerLevel(t *testing.T) {
+	var buf bytes.Buffer
+	logger := NewEntryLogger(newTestLogger(&buf, logrus.WarnLevel).WithField("app", "api"))
+
+	logger.Info("skipped")
+	if buf.Len() > 0 {
+		t.Errorf("output = %q, want nothing", buf.String())
+	}
+
+	logger.Warn("written")
+	if got := decode(t, &buf); got["level"] != "warning" || got["app"] != "api" {
+		t.Errorf("entry = %v, want warning with app=api", got)
+	}
+}
@@ -0,0 +1,66 @@
+package logkLogrus
+
+import (
+	"strings"
+
+	"github.com/go-konsultin/logk"
+	logkIngest "github.com/go-konsultin/logk/internal/ingest"
+	"github.com/go-konsultin/logk/level"
+	logkOption "github.com/go-konsultin/logk/option"
+)
+
+// keys are default keys of logrus.JSONFormatter. Namespace is read from "namespace" field, as logrus loggers are
+// not named
+var keys = logkIngest.Keys{
+	Time:       []string{"time"},
+	Level:      []string{"level"},
+	Message:    []string{"msg"},
+	Namespace:  []string{logkOption.NamespaceKey},
+	Function:   []string{"func"},
+	File:       []string{"file"},
+	Error:      []string{"error"},
+	StackTrace: []string{"stacktrace"},
+}
+
+// Writer is an io.Writer that forwards entries formatted by logrus.JSONFormatter into a logk Logger, with logrus
+// levels mapped to logk levels and fields translated to metadata. Set it as output of logrus logger:
+//
+//	log.SetFormatter(&logrus.JSONFormatter{})
+//	log.SetOutput(logkLogrus.NewWriter(nil))
+//
+// Level filtering is done by both loggers, so logrus level should be at least as verbose as logk level.
+// Panic and fatal entries are written as FATAL without exiting, as logrus panics or exits by itself
+type Writer struct {
+	*logkIngest.Writer
+}
+
+// NewWriter creates a writer that forwards to logger. If logger is nil, registered logger is used
+func NewWriter(logger logk.Logger) *Writer {
+	return &Writer{Writer: logkIngest.NewWriter(logkIngest.NewLoggerPrinter(logger), keys, parseLevel)}
+}
+
+// NewPrinterWriter creates a writer that prints forwarded entries with printer, keeping logrus time and caller.
+// If printer is nil, std printer is used
+func NewPrinterWriter(printer logk.Printer) *Writer {
+	return &Writer{Writer: logkIngest.NewWriter(printer, keys, parseLevel)}
+}
+
+// parseLevel converts logrus level name to logk level
+func parseLevel(name string) (level.LogLevel, bool) {
+	switch strings.ToLower(name) {
+	case "panic", "fatal":
+		return level.Fatal, true
+	case "error":
+		return level.Error, true
+	case "warning", "warn":
+		return level.Warn, true
+	case "info":
+		return level.Info, true
+	case "debug":
+		return level.Debug, true
+	case "trace":
+		return level.Trace, true
+	default:
+		return 0, false
+	}
+}
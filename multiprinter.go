@@ -0,0 +1,86 @@
+package logk
+
+import (
+	"io"
+
+	"github.com/go-konsultin/logk/level"
+	logkOption "github.com/go-konsultin/logk/option"
+)
+
+// MultiPrinter creates a printer that forwards each entry to all printers, in the given order
+func MultiPrinter(printers ...Printer) *multiPrinter {
+	p := multiPrinter{}
+	for _, child := range printers {
+		if child != nil {
+			p.printers = append(p.printers, child)
+		}
+	}
+	return &p
+}
+
+type multiPrinter struct {
+	printers        []Printer
+	continueOnError bool
+}
+
+// ContinueOnError makes printer recover from a panic in one printer, so the entry is still forwarded to the
+// remaining printers. The panic is reported as internal warning on Stderr
+func (m *multiPrinter) ContinueOnError() *multiPrinter {
+	m.continueOnError = true
+	return m
+}
+
+func (m *multiPrinter) Print(namespace string, lv level.LogLevel, msg string, options *logkOption.Options) {
+	for _, p := range m.printers {
+		if m.continueOnError {
+			printRecover(p, namespace, lv, msg, options)
+			continue
+		}
+		p.Print(namespace, lv, msg, options)
+	}
+}
+
+// Flush flushes all printers that implement Flusher and returns the first error
+func (m *multiPrinter) Flush() error {
+	var err error
+	for _, p := range m.printers {
+		if f, ok := p.(Flusher); ok {
+			if fErr := f.Flush(); fErr != nil && err == nil {
+				err = fErr
+			}
+		}
+	}
+	return err
+}
+
+// Close closes all printers that implement io.Closer and returns the first error
+func (m *multiPrinter) Close() error {
+	var err error
+	for _, p := range m.printers {
+		if c, ok := p.(io.Closer); ok {
+			if cErr := c.Close(); cErr != nil && err == nil {
+				err = cErr
+			}
+		}
+	}
+	return err
+}
+
+// printRecover prints entry and recovers if printer panics
+func printRecover(p Printer, namespace string, lv level.LogLevel, msg string, options *logkOption.Options) {
+	defer func() {
+		if r := recover(); r != nil {
+			internalWarn("printer %T failed: %v", p, r)
+		}
+	}()
+	p.Print(namespace, lv, msg, options)
+}
+
+// WithPrinter adds a printer to logger constructed by NewStdLogger. It can be repeated, entries are written to
+// the printer passed to NewStdLogger first, then to added printers in order
+func WithPrinter(p Printer) logkOption.SetterFunc {
+	return func(o *logkOption.Options) {
+		printers, _ := o.Values[logkOption.PrintersKey].([]Printer)
+		o.Values[logkOption.PrintersKey] = append(printers, p)
+	}
+}
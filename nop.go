@@ -0,0 +1,66 @@
+package logk
+
+import (
+	"context"
+
+	"github.com/go-konsultin/logk/level"
+	logkOption "github.com/go-konsultin/logk/option"
+)
+
+// nop is shared, as nopLogger has no state
+var nop = &nopLogger{}
+
+// Nop returns a logger that discards everything without allocating, for benchmarks and libraries that accept a
+// Logger but run with logging disabled. Its children are itself
+func Nop() Logger {
+	return nop
+}
+
+type nopLogger struct{}
+
+func (*nopLogger) Enabled(level.LogLevel) bool {
+	return false
+}
+
+func (*nopLogger) Fatal(string, ...logkOption.SetterFunc) {}
+
+func (*nopLogger) Fatalf(string, ...interface{}) {}
+
+func (*nopLogger) Error(string, ...logkOption.SetterFunc) {}
+
+func (*nopLogger) Errorf(string, ...interface{}) {}
+
+func (*nopLogger) Warn(string, ...logkOption.SetterFunc) {}
+
+func (*nopLogger) Warnf(string, ...interface{}) {}
+
+func (*nopLogger) Info(string, ...logkOption.SetterFunc) {}
+
+func (*nopLogger) Infof(string, ...interface{}) {}
+
+func (*nopLogger) Debug(string, ...logkOption.SetterFunc) {}
+
+func (*nopLogger) Debugf(string, ...interface{}) {}
+
+func (*nopLogger) Trace(string, ...logkOption.SetterFunc) {}
+
+func (*nopLogger) Tracef(string, ...interface{}) {}
+
+func (n *nopLogger) NewChild(...logkOption.SetterFunc) Logger {
+	return n
+}
+
+func (n *nopLogger) NewChildCtx(context.Context, ...logkOption.SetterFunc) Logger {
+	return n
+}
+
+func (n *nopLogger) With(...logkOption.SetterFunc) Logger {
+	return n
+}
+
+func (*nopLogger) SetLevel(level.LogLevel) {}
+
+// GetLevel returns level lower than level.Fatal, as nothing is written
+func (*nopLogger) GetLevel() level.LogLevel {
+	return 0
+}
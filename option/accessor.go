@@ -0,0 +1,76 @@
+package logkOption
+
+import (
+	"time"
+
+	logkContext "github.com/go-konsultin/logk/context"
+)
+
+// Accessors of well-known keys, for custom printers and hooks. They return zero values when key is not set
+
+// Namespace returns namespace of entry
+func (o *Options) Namespace() string {
+	s, _ := GetString(o, NamespaceKey)
+	return s
+}
+
+// Error returns error set with Error
+func (o *Options) Error() error {
+	return GetError(o, ErrorKey)
+}
+
+// RequestId returns request id in context of entry
+func (o *Options) RequestId() string {
+	return logkContext.GetRequestId(o.Context)
+}
+
+// Trace returns trace and span id of active span in context of entry
+func (o *Options) Trace() (traceId string, spanId string) {
+	return logkContext.GetTrace(o.Context)
+}
+
+// Timestamp returns time entry is stamped with by logger, and false if it isn't stamped yet
+func (o *Options) Timestamp() (time.Time, bool) {
+	return GetTime(o, TimeKey)
+}
+
+// Sequence returns sequence number of entry
+func (o *Options) Sequence() uint64 {
+	i, _ := GetUint64(o, SequenceKey)
+	return i
+}
+
+// Uptime returns uptime stamped on entry
+func (o *Options) Uptime() time.Duration {
+	d, _ := GetDuration(o, UptimeKey)
+	return d
+}
+
+// SampleRate returns N of 1 in N sample rate applied to entry, zero if entry is not sampled
+func (o *Options) SampleRate() uint64 {
+	i, _ := GetUint64(o, SampleRateKey)
+	return i
+}
+
+// Caller returns captured call site of entry, and false if it isn't captured
+func (o *Options) Caller() (Caller, bool) {
+	return GetCaller(o, CallerKey)
+}
+
+// Fingerprint returns grouping key that is set on entry with WithFingerprint
+func (o *Options) Fingerprint() string {
+	s, _ := GetString(o, FingerprintKey)
+	return s
+}
+
+// StackTrace returns captured stack trace of entry
+func (o *Options) StackTrace() string {
+	s, _ := GetString(o, StackTraceKey)
+	return s
+}
+
+// Baggage returns baggage set on entry, without baggage in context
+func (o *Options) Baggage() map[string]string {
+	m, _ := GetStringMap(o, BaggageKey)
+	return m
+}
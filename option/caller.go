@@ -0,0 +1,18 @@
+package logkOption
+
+import (
+	"path/filepath"
+	"strconv"
+)
+
+// Caller is the call site of an entry
+type Caller struct {
+	File     string
+	Line     int
+	Function string
+}
+
+// String returns base name of file and line, e.g. main.go:42
+func (c Caller) String() string {
+	return filepath.Base(c.File) + ":" + strconv.Itoa(c.Line)
+}
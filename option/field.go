@@ -0,0 +1,52 @@
+package logkOption
+
+import "time"
+
+// Field is the namespace of typed metadata setters, e.g.
+//
+//	logger.Info("request served", logkOption.Field.String("path", path), logkOption.Field.Duration("took", took))
+var Field fieldSetters
+
+type fieldSetters struct{}
+
+func (fieldSetters) String(key string, val string) SetterFunc {
+	return AddMetadata(key, val)
+}
+
+func (fieldSetters) Int(key string, val int) SetterFunc {
+	return AddMetadata(key, val)
+}
+
+func (fieldSetters) Int64(key string, val int64) SetterFunc {
+	return AddMetadata(key, val)
+}
+
+func (fieldSetters) Float64(key string, val float64) SetterFunc {
+	return AddMetadata(key, val)
+}
+
+func (fieldSetters) Bool(key string, val bool) SetterFunc {
+	return AddMetadata(key, val)
+}
+
+// Duration sets duration in human-readable format, e.g. 1.5s
+func (fieldSetters) Duration(key string, val time.Duration) SetterFunc {
+	return AddMetadata(key, val.String())
+}
+
+// Time sets time that is serialized in RFC3339 format with nanoseconds
+func (fieldSetters) Time(key string, val time.Time) SetterFunc {
+	return AddMetadata(key, val)
+}
+
+// Err sets error message, or nil if err is nil. Use Error to set the error of entry
+func (fieldSetters) Err(key string, err error) SetterFunc {
+	if err == nil {
+		return AddMetadata(key, nil)
+	}
+	return AddMetadata(key, err.Error())
+}
+
+func (fieldSetters) Any(key string, val interface{}) SetterFunc {
+	return AddMetadata(key, val)
+}
@@ -1,6 +1,17 @@
 package logkOption
 
-import "time"
+import (
+	"math"
+	"time"
+
+	"github.com/go-konsultin/logk/level"
+)
+
+// Get is generic helper to retrieve value of type T in Values by key. Like other getters, value type must be exact
+func Get[T any](o *Options, k string) (T, bool) {
+	v, ok := o.Values[k].(T)
+	return v, ok
+}
 
 // GetString is helper to retrieve string value in Values by key
 // Value type must be exact, as it use casting instead of converting to target value
@@ -12,6 +23,14 @@ func GetString(o *Options, k string) (string, bool) {
 	return s, true
 }
 
+func GetBool(o *Options, k string) (bool, bool) {
+	b, ok := o.Values[k].(bool)
+	if !ok {
+		return false, false
+	}
+	return b, true
+}
+
 func GetInt64(o *Options, k string) (int64, bool) {
 	i, ok := o.Values[k].(int64)
 	if !ok {
@@ -20,6 +39,30 @@ func GetInt64(o *Options, k string) (int64, bool) {
 	return i, true
 }
 
+func GetUint64(o *Options, k string) (uint64, bool) {
+	i, ok := o.Values[k].(uint64)
+	if !ok {
+		return 0, false
+	}
+	return i, true
+}
+
+func GetStringMap(o *Options, k string) (map[string]string, bool) {
+	m, ok := o.Values[k].(map[string]string)
+	if !ok {
+		return nil, false
+	}
+	return m, true
+}
+
+func GetDuration(o *Options, k string) (time.Duration, bool) {
+	d, ok := o.Values[k].(time.Duration)
+	if !ok {
+		return 0, false
+	}
+	return d, true
+}
+
 func GetTime(o *Options, k string) (time.Time, bool) {
 	t, ok := o.Values[k].(time.Time)
 	if !ok {
@@ -35,3 +78,143 @@ func GetError(o *Options, k string) error {
 	}
 	return e
 }
+
+func GetRequestIdGenerator(o *Options, k string) func() string {
+	fn, ok := o.Values[k].(func() string)
+	if !ok {
+		return nil
+	}
+	return fn
+}
+
+func GetInt(o *Options, k string) (int, bool) {
+	i, ok := o.Values[k].(int)
+	if !ok {
+		return 0, false
+	}
+	return i, true
+}
+
+func GetCaller(o *Options, k string) (Caller, bool) {
+	c, ok := o.Values[k].(Caller)
+	if !ok {
+		return Caller{}, false
+	}
+	return c, true
+}
+
+func GetLevel(o *Options, k string) (level.LogLevel, bool) {
+	lv, ok := o.Values[k].(level.LogLevel)
+	if !ok {
+		return 0, false
+	}
+	return lv, true
+}
+
+func GetFloat64(o *Options, k string) (float64, bool) {
+	f, ok := o.Values[k].(float64)
+	if !ok {
+		return 0, false
+	}
+	return f, true
+}
+
+func GetStringSlice(o *Options, k string) ([]string, bool) {
+	s, ok := o.Values[k].([]string)
+	if !ok {
+		return nil, false
+	}
+	return s, true
+}
+
+func GetMap(o *Options, k string) (map[string]interface{}, bool) {
+	m, ok := o.Values[k].(map[string]interface{})
+	if !ok {
+		return nil, false
+	}
+	return m, true
+}
+
+// GetInt64Coerce retrieves integer value in Values by key, converting any integer type and floats without
+// fraction. Unlike GetInt64, values stored as int or uint32 are not missed. Values that overflow int64 are missed
+func GetInt64Coerce(o *Options, k string) (int64, bool) {
+	switch v := o.Values[k].(type) {
+	case int:
+		return int64(v), true
+	case int8:
+		return int64(v), true
+	case int16:
+		return int64(v), true
+	case int32:
+		return int64(v), true
+	case int64:
+		return v, true
+	case uint:
+		return uintToInt64(uint64(v))
+	case uint8:
+		return int64(v), true
+	case uint16:
+		return int64(v), true
+	case uint32:
+		return int64(v), true
+	case uint64:
+		return uintToInt64(v)
+	case float32:
+		return floatToInt64(float64(v))
+	case float64:
+		return floatToInt64(v)
+	case time.Duration:
+		return int64(v), true
+	}
+	return 0, false
+}
+
+// GetUint64Coerce retrieves integer value in Values by key like GetInt64Coerce. Negative values are missed
+func GetUint64Coerce(o *Options, k string) (uint64, bool) {
+	switch v := o.Values[k].(type) {
+	case uint:
+		return uint64(v), true
+	case uint64:
+		return v, true
+	}
+
+	i, ok := GetInt64Coerce(o, k)
+	if !ok || i < 0 {
+		return 0, false
+	}
+	return uint64(i), true
+}
+
+// GetFloat64Coerce retrieves numeric value in Values by key, converting any integer and float type
+func GetFloat64Coerce(o *Options, k string) (float64, bool) {
+	switch v := o.Values[k].(type) {
+	case float64:
+		return v, true
+	case float32:
+		return float64(v), true
+	case uint:
+		return float64(v), true
+	case uint64:
+		return float64(v), true
+	}
+
+	i, ok := GetInt64Coerce(o, k)
+	if !ok {
+		return 0, false
+	}
+	return float64(i), true
+}
+
+func uintToInt64(v uint64) (int64, bool) {
+	if v > math.MaxInt64 {
+		return 0, false
+	}
+	return int64(v), true
+}
+
+func floatToInt64(v float64) (int64, bool) {
+	if v != math.Trunc(v) || v < math.MinInt64 || v >= math.MaxInt64 {
+		return 0, false
+	}
+	return int64(v), true
+}
@@ -0,0 +1,60 @@
+package logkOption
+
+// Group sets metadata of fields as a nested object under key, e.g. Group("http", Field.Int("status", 200)) is
+// written as {"http":{"status":200}} in JSON and http.status=200 in logfmt. Groups with the same key are merged
+func Group(key string, fields ...SetterFunc) SetterFunc {
+	return func(o *Options) {
+		group := Evaluate(fields).Metadata
+		if group == nil {
+			group = make(map[string]interface{})
+		}
+
+		if o.Metadata == nil {
+			o.Metadata = make(map[string]interface{})
+		}
+
+		if existing, ok := o.Metadata[key].(map[string]interface{}); ok {
+			group = MergeMetadata(existing, group)
+		}
+		o.Metadata[key] = group
+	}
+}
+
+// MergeMetadata merges metadata layers into a single map. Layers are ordered from the lowest precedence, so
+// later layers override keys of earlier layers. Logger merges fields extracted from context, then persistent fields,
+// then call-site fields. Nested objects such as groups are merged key by key instead of being replaced. Layers are
+// never mutated, and if only one layer is not empty, it is returned as is without copying
+func MergeMetadata(layers ...map[string]interface{}) map[string]interface{} {
+	var result map[string]interface{}
+	copied := false
+	for _, layer := range layers {
+		if len(layer) == 0 {
+			continue
+		}
+
+		if result == nil {
+			result = layer
+			continue
+		}
+
+		// Copy before writing, so layers are never mutated
+		if !copied {
+			merged := make(map[string]interface{}, len(result)+len(layer))
+			for k, v := range result {
+				merged[k] = v
+			}
+			result = merged
+			copied = true
+		}
+
+		for k, v := range layer {
+			if nested, ok := v.(map[string]interface{}); ok {
+				if prev, ok := result[k].(map[string]interface{}); ok {
+					v = MergeMetadata(prev, nested)
+				}
+			}
+			result[k] = v
+		}
+	}
+	return result
+}
@@ -4,4 +4,63 @@ package logkOption
 const (
 	ErrorKey     = "error"
 	NamespaceKey = "namespace"
+	TeeKey       = "tee"
+	SequenceKey  = "sequence"
+	LevelKey     = "level"
+	TimeKey      = "time"
+	MessageKey   = "message"
+	RequestIdKey = "requestId"
+	TraceIdKey   = "traceId"
+	SpanIdKey    = "spanId"
+	MetadataKey  = "metadata"
+	BaggageKey   = "baggage"
+	PrintersKey  = "printers"
+	UptimeKey    = "uptime"
+	OnceKey      = "once"
+	SampledKey   = "sampled"
+	// SampleRateKey holds N of 1 in N sample rate that is applied to entry
+	SampleRateKey = "sampleRate"
+	// RequestIdGeneratorKey holds func() string that is set when constructing logger
+	RequestIdGeneratorKey = "requestIdGenerator"
+	// CallerKey holds Caller of entry. It is set to true by WithCaller to request capturing on a single entry
+	CallerKey = "caller"
+	// CallerSkipKey holds number of additional frames to skip when capturing caller, set by EnableCaller
+	CallerSkipKey = "callerSkip"
+	// StackTraceKey holds stack trace of entry. It is set to true by WithStackTrace to request capturing on a single
+	// entry
+	StackTraceKey = "stackTrace"
+	// StackTraceLevelKey holds level.LogLevel which entries and more severe ones capture stack trace, set by
+	// EnableStackTrace
+	StackTraceLevelKey = "stackTraceLevel"
+	// FatalBehaviorKey holds FatalBehavior value that is set when constructing logger
+	FatalBehaviorKey = "fatalBehavior"
+	// ExitCodeKey holds exit code of FatalExit behavior
+	ExitCodeKey = "exitCode"
+	// LazyMessageKey holds func() string that computes message, set by LazyMsg
+	LazyMessageKey = "lazyMessage"
+	// SequenceModeKey holds SequenceMode value that is set when constructing logger
+	SequenceModeKey = "sequenceMode"
+	// FingerprintKey holds grouping key of ERROR and FATAL entries. It's computed by printers, unless it's set with
+	// WithFingerprint
+	FingerprintKey = "error.fingerprint"
+)
+
+// SequenceMode determine which counter is used to stamp sequence number
+type SequenceMode = string
+
+const (
+	SequenceLogger SequenceMode = "logger"
+	SequenceGlobal SequenceMode = "global"
+)
+
+// FatalBehavior determine what logger does after writing a FATAL entry
+type FatalBehavior = string
+
+const (
+	// FatalExit flushes printers and exits the process. It is the default behavior
+	FatalExit FatalBehavior = "exit"
+	// FatalPanic flushes printers and panics with message
+	FatalPanic FatalBehavior = "panic"
+	// FatalNoop only writes the entry
+	FatalNoop FatalBehavior = "noop"
 )
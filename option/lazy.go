@@ -0,0 +1,67 @@
+package logkOption
+
+// LazyValue is metadata value that is computed only when entry passes level filter of logger, set with Lazy
+type LazyValue func() interface{}
+
+// Lazy sets metadata that is computed by fn only if entry is written, e.g. to dump a large structure at TRACE.
+// It is computed by logger after level filter, WithOnce and logger sampling, before hooks, redaction and
+// printers see the entry. Printers that drop or hold back entries, e.g. sampling, rate limit, dedup and tail
+// printers, don't skip computing it, so use logger sampling, see logk.NewSamplingLogger, when fn is expensive
+func Lazy(key string, fn func() interface{}) SetterFunc {
+	return AddMetadata(key, LazyValue(fn))
+}
+
+// LazyMsg sets message that is computed by fn only if entry is written. It replaces message passed to the logger.
+// Like Lazy, it is computed before printers see the entry
+func LazyMsg(fn func() string) SetterFunc {
+	return func(o *Options) {
+		o.Values[LazyMessageKey] = fn
+	}
+}
+
+// ResolveLazy computes lazy metadata and message of options, and returns message to be written. Loggers call it
+// once entry is known to be written. Metadata is copied if it or its groups have lazy values, as it may be shared
+// by caller
+func ResolveLazy(o *Options, msg string) string {
+	if fn, ok := o.Values[LazyMessageKey].(func() string); ok {
+		delete(o.Values, LazyMessageKey)
+		msg = fn()
+	}
+
+	if hasLazy(o.Metadata) {
+		o.Metadata = resolveLazy(o.Metadata)
+	}
+	return msg
+}
+
+// hasLazy returns true if m or its groups have lazy values
+func hasLazy(m map[string]interface{}) bool {
+	for _, v := range m {
+		switch val := v.(type) {
+		case LazyValue:
+			return true
+		case map[string]interface{}:
+			if hasLazy(val) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// resolveLazy returns copy of m with lazy values computed
+func resolveLazy(m map[string]interface{}) map[string]interface{} {
+	resolved := make(map[string]interface{}, len(m))
+	for k, v := range m {
+		switch val := v.(type) {
+		case LazyValue:
+			v = val()
+		case map[string]interface{}:
+			if hasLazy(val) {
+				v = resolveLazy(val)
+			}
+		}
+		resolved[k] = v
+	}
+	return resolved
+}
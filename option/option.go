@@ -2,7 +2,9 @@ package logkOption
 
 import (
 	"context"
+	"sync"
 
+	logkContext "github.com/go-konsultin/logk/context"
 	"github.com/go-konsultin/logk/level"
 )
 
@@ -24,19 +26,105 @@ func NewOptions() *Options {
 	}
 }
 
-// Evaluate initiate given option setter that is set in args parameter and returns Options
+// maxPooledValues is size of values map above which options are not returned to pool, so an entry with many
+// values doesn't keep a large map alive
+const maxPooledValues = 64
+
+var optionsPool = sync.Pool{
+	New: func() interface{} {
+		return &Options{Values: make(map[string]interface{})}
+	},
+}
+
+// Evaluate initiate given option setter that is set in args parameter and returns Options. Options are taken from a
+// pool, caller that owns them may return them with Release
 func Evaluate(args []SetterFunc) *Options {
-	optCopy := NewOptions()
+	optCopy := optionsPool.Get().(*Options)
+	optCopy.Level = level.Default
 	for _, fn := range args {
 		fn(optCopy)
 	}
 	return optCopy
 }
 
-// NewFormatOptions construct options for formatting
+// NewFormatOptions construct options for formatting. Options are taken from a pool, caller that owns them may
+// return them with Release
 func NewFormatOptions(args ...interface{}) *Options {
-	return &Options{
-		Values:  make(map[string]interface{}),
-		FmtArgs: args,
+	o := optionsPool.Get().(*Options)
+	o.FmtArgs = args
+	return o
+}
+
+// Release resets options and returns them to pool. Options must not be used after they are released, so printers
+// and hooks that keep options after they return, e.g. to write them asynchronously, retain a Clone instead.
+// Metadata map is not reused, as it may be owned by caller
+func Release(o *Options) {
+	if o == nil || len(o.Values) > maxPooledValues {
+		return
+	}
+
+	clear(o.Values)
+	o.Metadata = nil
+	o.FmtArgs = nil
+	o.Context = nil
+	o.Level = 0
+	optionsPool.Put(o)
+}
+
+// Clone returns a copy of options that can be modified or retained without affecting the original. Values,
+// metadata and formatting arguments are copied shallowly
+func (o *Options) Clone() *Options {
+	c := Options{
+		Values:  make(map[string]interface{}, len(o.Values)),
+		Context: o.Context,
+		Level:   o.Level,
+	}
+	for k, v := range o.Values {
+		c.Values[k] = v
+	}
+
+	if o.Metadata != nil {
+		c.Metadata = make(map[string]interface{}, len(o.Metadata))
+		for k, v := range o.Metadata {
+			c.Metadata[k] = v
+		}
 	}
+
+	if o.FmtArgs != nil {
+		c.FmtArgs = append([]interface{}(nil), o.FmtArgs...)
+	}
+	return &c
+}
+
+// Snapshot returns a copy of everything that was set on options as a plain map, so hooks, custom printers and tests
+// can read it without accessing internal maps. Request, trace and span id are resolved from context.
+// Metadata is copied shallowly, and the result is safe to retain
+func (o *Options) Snapshot() map[string]interface{} {
+	result := make(map[string]interface{}, len(o.Values)+5)
+	for k, v := range o.Values {
+		result[k] = v
+	}
+
+	result[LevelKey] = o.Level
+
+	if reqId := logkContext.GetRequestId(o.Context); reqId != "" {
+		result[RequestIdKey] = reqId
+	}
+
+	if traceId, spanId := logkContext.GetTrace(o.Context); traceId != "" {
+		result[TraceIdKey] = traceId
+		if spanId != "" {
+			result[SpanIdKey] = spanId
+		}
+	}
+
+	if len(o.Metadata) > 0 {
+		meta := make(map[string]interface{}, len(o.Metadata))
+		for k, v := range o.Metadata {
+			meta[k] = v
+		}
+		result[MetadataKey] = meta
+	}
+
+	return result
 }
@@ -0,0 +1,41 @@
+package logkOption
+
+import "fmt"
+
+// secretMask is rendered in place of secret values
+const secretMask = "***"
+
+// SecretValue is a sensitive value that is always rendered as "***" by printers, fmt and encoding/json, unless
+// unsafe debug mode is enabled with logk.SetUnsafeDebug
+type SecretValue string
+
+func (s SecretValue) String() string {
+	return secretMask
+}
+
+func (s SecretValue) GoString() string {
+	return secretMask
+}
+
+// Format renders mask for every verb, so it can't be revealed with %s, %v, %q or %x
+func (s SecretValue) Format(f fmt.State, verb rune) {
+	_, _ = f.Write([]byte(secretMask))
+}
+
+func (s SecretValue) MarshalJSON() ([]byte, error) {
+	return []byte(`"` + secretMask + `"`), nil
+}
+
+func (s SecretValue) MarshalText() ([]byte, error) {
+	return []byte(secretMask), nil
+}
+
+// Reveal returns the actual value
+func (s SecretValue) Reveal() string {
+	return string(s)
+}
+
+// Secret adds a sensitive metadata value that is rendered as "***"
+func Secret(key string, value string) SetterFunc {
+	return AddMetadata(key, SecretValue(value))
+}
@@ -33,12 +33,135 @@ func Error(err error) SetterFunc {
 	}
 }
 
+// WithFingerprint sets grouping key of a single entry, which replaces fingerprint computed from its error, e.g. to
+// group failures of an operation regardless of their cause
+func WithFingerprint(fp string) SetterFunc {
+	return func(o *Options) {
+		o.Values[FingerprintKey] = fp
+	}
+}
+
 func WithNamespace(n string) SetterFunc {
 	return func(o *Options) {
 		o.Values[NamespaceKey] = n
 	}
 }
 
+// WithTee toggles mirroring of log output to the standard library logger in addition to the configured printer
+func WithTee(enabled bool) SetterFunc {
+	return func(o *Options) {
+		o.Values[TeeKey] = enabled
+	}
+}
+
+// WithSequence stamps a monotonically increasing sequence number to each entry. The counter is shared by the
+// logger and its children
+func WithSequence() SetterFunc {
+	return func(o *Options) {
+		o.Values[SequenceModeKey] = SequenceLogger
+	}
+}
+
+// WithGlobalSequence stamps a monotonically increasing sequence number to each entry. The counter is shared by
+// all loggers in process
+func WithGlobalSequence() SetterFunc {
+	return func(o *Options) {
+		o.Values[SequenceModeKey] = SequenceGlobal
+	}
+}
+
+// WithRequestIdGenerator sets function to generate request id when none is found in context. The generated id is
+// reused for the lifetime of the logger, and children generate their own
+func WithRequestIdGenerator(fn func() string) SetterFunc {
+	return func(o *Options) {
+		o.Values[RequestIdGeneratorKey] = fn
+	}
+}
+
+// WithBaggage sets correlation baggage that is rendered on every entry. When set on logger, children inherit
+// and can extend it
+func WithBaggage(b map[string]string) SetterFunc {
+	return func(o *Options) {
+		merged := make(map[string]string)
+		if existing, ok := GetStringMap(o, BaggageKey); ok {
+			for k, v := range existing {
+				merged[k] = v
+			}
+		}
+		for k, v := range b {
+			merged[k] = v
+		}
+		o.Values[BaggageKey] = merged
+	}
+}
+
+// WithUptime renders process uptime on each entry. When set on logger, children inherit it
+func WithUptime() SetterFunc {
+	return func(o *Options) {
+		o.Values[UptimeKey] = true
+	}
+}
+
+// WithOnce makes entry to be written only the first time for key in process lifetime, e.g. for deprecation notices
+func WithOnce(key string) SetterFunc {
+	return func(o *Options) {
+		o.Values[OnceKey] = key
+	}
+}
+
+// WithCaller captures call site of a single entry
+func WithCaller() SetterFunc {
+	return func(o *Options) {
+		o.Values[CallerKey] = true
+	}
+}
+
+// EnableCaller captures call site of every entry when set on logger. Skip is number of additional frames to skip,
+// e.g. 1 when logger is called from a helper function that wraps it. Children inherit it
+func EnableCaller(skip int) SetterFunc {
+	return func(o *Options) {
+		o.Values[CallerKey] = true
+		o.Values[CallerSkipKey] = skip
+	}
+}
+
+// WithStackTrace captures stack trace of a single entry
+func WithStackTrace() SetterFunc {
+	return func(o *Options) {
+		o.Values[StackTraceKey] = true
+	}
+}
+
+// EnableStackTrace captures stack trace of entries in lv and more severe levels when set on logger, e.g. level.Error
+// captures on Error and Fatal. Children inherit it
+func EnableStackTrace(lv level.LogLevel) SetterFunc {
+	return func(o *Options) {
+		o.Values[StackTraceLevelKey] = lv
+	}
+}
+
+// WithFatalBehavior sets what logger does after writing a FATAL entry. Children inherit it
+func WithFatalBehavior(b FatalBehavior) SetterFunc {
+	return func(o *Options) {
+		o.Values[FatalBehaviorKey] = b
+	}
+}
+
+// WithExitCode sets exit code of FatalExit behavior. Default is 1. Children inherit it
+func WithExitCode(code int) SetterFunc {
+	return func(o *Options) {
+		o.Values[ExitCodeKey] = code
+	}
+}
+
+// Sampled marks entry as admitted by a sampler that writes 1 in rate entries, so consumers of the partial stream
+// can scale counts
+func Sampled(rate uint64) SetterFunc {
+	return func(o *Options) {
+		o.Values[SampleRateKey] = rate
+	}
+}
+
 func Context(ctx context.Context) SetterFunc {
 	return func(o *Options) {
 		o.Context = ctx
@@ -0,0 +1,44 @@
+package logk
+
+import (
+	"bytes"
+	"sync"
+
+	"github.com/go-konsultin/logk/level"
+	logkOption "github.com/go-konsultin/logk/option"
+)
+
+// maxPooledBufferSize prevents buffers of unusually large entries from being retained in pool
+const maxPooledBufferSize = 64 << 10
+
+var (
+	bufferPool = sync.Pool{New: func() interface{} { return new(bytes.Buffer) }}
+	entryPool  = sync.Pool{New: func() interface{} { return new(Entry) }}
+)
+
+func getBuffer() *bytes.Buffer {
+	return bufferPool.Get().(*bytes.Buffer)
+}
+
+func putBuffer(buf *bytes.Buffer) {
+	if buf.Cap() > maxPooledBufferSize {
+		return
+	}
+	buf.Reset()
+	bufferPool.Put(buf)
+}
+
+// acquireEntry returns a pooled entry resolved from print arguments. It must be released with releaseEntry once
+// printer is done with it, and must not be retained
+func acquireEntry(namespace string, lv level.LogLevel, msg string, options *logkOption.Options,
+	po *PrinterOptions) *Entry {
+	e := entryPool.Get().(*Entry)
+	initEntry(e, namespace, lv, msg, options, po)
+	return e
+}
+
+func releaseEntry(e *Entry) {
+	// Drop references, so pooled entry doesn't keep metadata alive
+	*e = Entry{}
+	entryPool.Put(e)
+}
@@ -0,0 +1,122 @@
+package logk
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/go-konsultin/logk/level"
+	logkOption "github.com/go-konsultin/logk/option"
+)
+
+// ANSI escape codes
+const (
+	colorReset  = "\x1b[0m"
+	colorRed    = "\x1b[31m"
+	colorBold   = "\x1b[1;31m"
+	colorYellow = "\x1b[33m"
+	colorBlue   = "\x1b[34m"
+	colorGray   = "\x1b[90m"
+)
+
+const prettyTimeLayout = "15:04:05.000"
+
+var prettyLevelColor = map[level.LogLevel]string{
+	level.Fatal: colorBold,
+	level.Error: colorRed,
+	level.Warn:  colorYellow,
+	level.Info:  colorBlue,
+	level.Debug: colorGray,
+	level.Trace: colorGray,
+}
+
+// NewPrettyPrinter creates a human-friendly printer for development. Levels are colorized and columns aligned,
+// with metadata rendered indented under the message. Color is disabled when out is not a terminal or NO_COLOR is set
+func NewPrettyPrinter(out io.Writer, args ...PrinterOption) *encoderPrinter {
+	// If writer is nil, set default writer to Stdout
+	if out == nil {
+		out = os.Stdout
+	}
+
+	_, noColor := os.LookupEnv(EnvNoColor)
+
+	return NewEncoderPrinter(NewPrettyEncoder(!noColor && isTerminal(out)), AddSync(out), args...)
+}
+
+// NewPrettyEncoder creates an encoder that formats entries as written by NewPrettyPrinter. Levels are colorized
+// with ANSI escape codes if color is true
+func NewPrettyEncoder(color bool) Encoder {
+	return &prettyEncoder{color: color}
+}
+
+type prettyEncoder struct {
+	color bool
+}
+
+func (p *prettyEncoder) Encode(entry *Entry) ([]byte, error) {
+	var buf bytes.Buffer
+	err := p.EncodeTo(&buf, entry)
+	return buf.Bytes(), err
+}
+
+func (p *prettyEncoder) EncodeTo(sb *bytes.Buffer, entry *Entry) error {
+	// Write time, level and namespace in aligned columns
+	sb.WriteString(p.paint(colorGray, fmt.Sprint(entry.formatTime(prettyTimeLayout))))
+	sb.WriteByte(' ')
+	sb.WriteString(p.paint(prettyLevelColor[entry.Level], fmt.Sprintf("%-5s", strings.ToUpper(level.String(entry.Level)))))
+	sb.WriteByte(' ')
+	if entry.Namespace != "" {
+		sb.WriteString(p.paint(colorGray, "("+entry.Namespace+")"))
+		sb.WriteByte(' ')
+	}
+	sb.WriteString(entry.Message)
+	sb.WriteByte('\n')
+
+	// Write fields indented under message
+	fields := entry.Fields()
+	for _, k := range []string{logkOption.TimeKey, logkOption.LevelKey, logkOption.NamespaceKey,
+		logkOption.MessageKey, logkOption.MetadataKey} {
+		delete(fields, k)
+	}
+
+	for _, k := range sortedKeys(fields) {
+		p.writeField(sb, k, fields[k])
+	}
+
+	for _, k := range sortedKeys(entry.Metadata) {
+		p.writeField(sb, k, entry.Metadata[k])
+	}
+
+	return nil
+}
+
+func (p *prettyEncoder) writeField(sb *bytes.Buffer, k string, v interface{}) {
+	sb.WriteString("    ")
+	sb.WriteString(p.paint(colorGray, k+":"))
+	sb.WriteByte(' ')
+	sb.WriteString(logfmtValue(v))
+	sb.WriteByte('\n')
+}
+
+func (p *prettyEncoder) paint(color string, s string) string {
+	if !p.color || color == "" {
+		return s
+	}
+	return color + s + colorReset
+}
+
+// isTerminal returns true if w is a character device such as terminal
+func isTerminal(w io.Writer) bool {
+	f, ok := w.(*os.File)
+	if !ok {
+		return false
+	}
+
+	info, err := f.Stat()
+	if err != nil {
+		return false
+	}
+	return info.Mode()&os.ModeCharDevice != 0
+}
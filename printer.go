@@ -1,10 +1,127 @@
 package logk
 
 import (
+	"time"
+
 	"github.com/go-konsultin/logk/level"
 	logkOption "github.com/go-konsultin/logk/option"
 )
 
+// Printer writes entries. Options passed to Print are owned by logger and are reused after Print returns, so a printer
+// that keeps an entry to write it later, e.g. AsyncPrinter, retains options.Clone()
 type Printer interface {
 	Print(namespace string, outLevel level.LogLevel, msg string, options *logkOption.Options)
 }
+
+// Flusher is implemented by printers and loggers that buffer output and need to write it out before being discarded
+type Flusher interface {
+	Flush() error
+}
+
+// OverflowPolicy determine what buffered printers do when their buffer is full
+type OverflowPolicy int8
+
+const (
+	// OverflowDrop discards new entries until buffer has free space
+	OverflowDrop OverflowPolicy = iota
+	// OverflowBlock waits until buffer has free space
+	OverflowBlock
+)
+
+// MetadataFallback determine how printers render metadata that can't be serialized to JSON
+type MetadataFallback int8
+
+const (
+	// MetadataBestEffort renders metadata with fmt verb %+v
+	MetadataBestEffort MetadataFallback = iota
+	// MetadataDrop omits metadata
+	MetadataDrop
+)
+
+// Timestamp formats that are not time layouts, to be used with WithTimeFormat
+const (
+	// TimeFormatUnix writes seconds since epoch as a number
+	TimeFormatUnix = "unix"
+	// TimeFormatUnixMilli writes milliseconds since epoch as a number
+	TimeFormatUnixMilli = "unixmilli"
+)
+
+// PrinterOptions holds configuration that is shared across printer implementations
+type PrinterOptions struct {
+	// MaxDepth limits nesting of serialized metadata. Zero means unlimited
+	MaxDepth int
+	// MetadataFallback is applied when metadata can't be serialized to JSON
+	MetadataFallback MetadataFallback
+	// ExplicitNulls renders entry fields that are not set as null or empty instead of omitting them
+	ExplicitNulls bool
+	// LevelPrefix overrides level prefixes of text printers. Levels that are absent fall back to default prefixes
+	LevelPrefix map[level.LogLevel]string
+	// TimeFormat is time layout or one of TimeFormatUnix and TimeFormatUnixMilli. Empty uses default format of
+	// printer
+	TimeFormat string
+	// TimeLocation converts timestamps before they are formatted. Nil keeps the location they are stamped with
+	TimeLocation *time.Location
+}
+
+type PrinterOption = func(*PrinterOptions)
+
+// WithMaxDepth truncates nested metadata structures deeper than n with a placeholder
+func WithMaxDepth(n int) PrinterOption {
+	return func(o *PrinterOptions) {
+		o.MaxDepth = n
+	}
+}
+
+// WithMetadataFallback sets how metadata that can't be serialized to JSON is rendered
+func WithMetadataFallback(f MetadataFallback) PrinterOption {
+	return func(o *PrinterOptions) {
+		o.MetadataFallback = f
+	}
+}
+
+// WithExplicitNulls renders entry fields that are not set, i.e. namespace, request id, baggage, error and metadata,
+// as null or empty instead of omitting them. It's useful when presence of a key is meaningful. Metadata values are
+// rendered as set in either case, including nil and empty ones
+func WithExplicitNulls() PrinterOption {
+	return func(o *PrinterOptions) {
+		o.ExplicitNulls = true
+	}
+}
+
+// WithLevelPrefix sets level prefixes of text printers, e.g. {level.Info: "I: "}
+func WithLevelPrefix(prefix map[level.LogLevel]string) PrinterOption {
+	return func(o *PrinterOptions) {
+		o.LevelPrefix = make(map[level.LogLevel]string, len(prefix))
+		for lv, p := range prefix {
+			o.LevelPrefix[lv] = p
+		}
+	}
+}
+
+// WithTimeFormat sets format of timestamps, e.g. time.RFC3339 or logk.TimeFormatUnixMilli. Std printer writes
+// timestamp itself instead of using log flags when it is set
+func WithTimeFormat(layout string) PrinterOption {
+	return func(o *PrinterOptions) {
+		o.TimeFormat = layout
+	}
+}
+
+// WithTimeLocation converts timestamps to loc before they are formatted
+func WithTimeLocation(loc *time.Location) PrinterOption {
+	return func(o *PrinterOptions) {
+		o.TimeLocation = loc
+	}
+}
+
+// WithUTC writes timestamps in UTC
+func WithUTC() PrinterOption {
+	return WithTimeLocation(time.UTC)
+}
+
+func evaluatePrinterOptions(args []PrinterOption) PrinterOptions {
+	o := PrinterOptions{}
+	for _, fn := range args {
+		fn(&o)
+	}
+	return o
+}
@@ -0,0 +1,220 @@
+package logkProtobuf
+
+import (
+	"bufio"
+	"encoding/base64"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"sync"
+
+	"github.com/go-konsultin/logk"
+	"github.com/go-konsultin/logk/level"
+	logkOption "github.com/go-konsultin/logk/option"
+)
+
+const pkgName = "logk/protobuf"
+
+// DefaultMaxFrameSize is default limit of size of message read by Decoder, same as default limit of protodelim
+const DefaultMaxFrameSize = 4 << 20
+
+// ErrFrameTooLarge is returned by Decoder when size of message exceeds MaxFrameSize
+var ErrFrameTooLarge = errors.New(pkgName + ": frame too large")
+
+// MarshalFunc maps an entry into serialized protobuf message. Typically, it fills a generated message type and
+// returns the result of proto.Marshal, which keeps protobuf runtime dependency in the caller module
+type MarshalFunc = func(e *logk.Entry) ([]byte, error)
+
+// Framing determine how serialized messages are delimited in stream
+type Framing int8
+
+const (
+	// LengthPrefixed writes each message prefixed with its size as uvarint, compatible with protodelim package
+	LengthPrefixed Framing = iota
+	// NewlineDelimited writes each message as base64 encoded line, as raw protobuf bytes may contain newline
+	NewlineDelimited
+)
+
+type Options struct {
+	// OnError is called when an entry can't be marshalled or written
+	OnError        func(err error)
+	PrinterOptions []logk.PrinterOption
+}
+
+type Option = func(*Options)
+
+func WithOnError(fn func(err error)) Option {
+	return func(o *Options) {
+		o.OnError = fn
+	}
+}
+
+func WithPrinterOptions(args ...logk.PrinterOption) Option {
+	return func(o *Options) {
+		o.PrinterOptions = append(o.PrinterOptions, args...)
+	}
+}
+
+// Printer writes each log entry as a protobuf message frame
+type Printer struct {
+	out     io.Writer
+	marshal MarshalFunc
+	framing Framing
+	options Options
+	mu      sync.Mutex
+}
+
+func NewPrinter(out io.Writer, marshal MarshalFunc, framing Framing, args ...Option) *Printer {
+	// If writer is nil, set default writer to Stdout
+	if out == nil {
+		out = os.Stdout
+	}
+
+	if marshal == nil {
+		panic(fmt.Errorf("%s: marshal function is nil", pkgName))
+	}
+
+	var o Options
+	for _, fn := range args {
+		fn(&o)
+	}
+
+	return &Printer{
+		out:     out,
+		marshal: marshal,
+		framing: framing,
+		options: o,
+	}
+}
+
+func (p *Printer) Print(namespace string, lv level.LogLevel, msg string, options *logkOption.Options) {
+	entry := logk.NewEntry(namespace, lv, msg, options, p.options.PrinterOptions...)
+
+	b, err := p.marshal(entry)
+	if err != nil {
+		p.reportError(fmt.Errorf("%s: marshal entry: %w", pkgName, err))
+		return
+	}
+
+	var frame []byte
+	switch p.framing {
+	case NewlineDelimited:
+		frame = make([]byte, base64.StdEncoding.EncodedLen(len(b))+1)
+		base64.StdEncoding.Encode(frame, b)
+		frame[len(frame)-1] = '\n'
+	default:
+		frame = binary.AppendUvarint(make([]byte, 0, len(b)+binary.MaxVarintLen64), uint64(len(b)))
+		frame = append(frame, b...)
+	}
+
+	p.mu.Lock()
+	_, err = p.out.Write(frame)
+	p.mu.Unlock()
+	if err != nil {
+		p.reportError(fmt.Errorf("%s: write frame: %w", pkgName, err))
+	}
+}
+
+func (p *Printer) reportError(err error) {
+	if p.options.OnError != nil {
+		p.options.OnError(err)
+	}
+}
+
+type DecoderOptions struct {
+	// MaxFrameSize limits size of a message, so corrupted or hostile stream can't make Decoder allocate unbounded
+	// memory. Default is DefaultMaxFrameSize
+	MaxFrameSize int
+}
+
+type DecoderOption = func(*DecoderOptions)
+
+func WithMaxFrameSize(n int) DecoderOption {
+	return func(o *DecoderOptions) {
+		o.MaxFrameSize = n
+	}
+}
+
+// Decoder reads serialized messages that are written by Printer, to be unmarshalled with proto.Unmarshal
+type Decoder struct {
+	r       *bufio.Reader
+	framing Framing
+	options DecoderOptions
+}
+
+func NewDecoder(r io.Reader, framing Framing, args ...DecoderOption) *Decoder {
+	o := DecoderOptions{MaxFrameSize: DefaultMaxFrameSize}
+	for _, fn := range args {
+		fn(&o)
+	}
+
+	if o.MaxFrameSize < 1 {
+		o.MaxFrameSize = DefaultMaxFrameSize
+	}
+
+	return &Decoder{r: bufio.NewReader(r), framing: framing, options: o}
+}
+
+// Decode returns the next serialized message. It returns io.EOF when there are no more messages, and
+// ErrFrameTooLarge when message exceeds MaxFrameSize, after which the stream can't be decoded further
+func (d *Decoder) Decode() ([]byte, error) {
+	switch d.framing {
+	case NewlineDelimited:
+		line, err := d.readLine()
+		if err != nil {
+			return nil, err
+		}
+		b := make([]byte, base64.StdEncoding.DecodedLen(len(line)))
+		n, err := base64.StdEncoding.Decode(b, line)
+		if err != nil {
+			return nil, fmt.Errorf("%s: invalid frame: %w", pkgName, err)
+		}
+		return b[:n], nil
+	default:
+		size, err := binary.ReadUvarint(d.r)
+		if err != nil {
+			return nil, err
+		}
+		if size > uint64(d.options.MaxFrameSize) {
+			return nil, fmt.Errorf("%w: %d bytes exceed limit of %d", ErrFrameTooLarge, size, d.options.MaxFrameSize)
+		}
+		b := make([]byte, size)
+		if _, err = io.ReadFull(d.r, b); err != nil {
+			if errors.Is(err, io.EOF) {
+				return nil, io.ErrUnexpectedEOF
+			}
+			return nil, err
+		}
+		return b, nil
+	}
+}
+
+// readLine returns the next line without newline. Line is read in chunks of buffer size, so it's bounded by encoded
+// size of MaxFrameSize before it's fully buffered
+func (d *Decoder) readLine() ([]byte, error) {
+	limit := base64.StdEncoding.EncodedLen(d.options.MaxFrameSize)
+
+	var line []byte
+	for {
+		chunk, err := d.r.ReadSlice('\n')
+		line = append(line, chunk...)
+		switch {
+		case err == nil:
+			line = line[:len(line)-1]
+			if len(line) > limit {
+				return nil, fmt.Errorf("%w: %d encoded bytes exceed limit of %d", ErrFrameTooLarge, len(line), limit)
+			}
+			return line, nil
+		case len(line) > limit:
+			return nil, fmt.Errorf("%w: more than %d encoded bytes", ErrFrameTooLarge, limit)
+		case errors.Is(err, bufio.ErrBufferFull):
+			continue
+		case errors.Is(err, io.EOF) && len(line) > 0:
+			return nil, io.ErrUnexpectedEOF
+		default:
+			return nil, err
+		}
+	}
+}
@@ -0,0 +1,187 @@
+package logkProtobuf
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"testing"
+
+	"github.com/go-konsultin/logk"
+	"github.com/go-konsultin/logk/level"
+	logkOption "github.com/go-konsultin/logk/option"
+)
+
+// Field numbers of test message, encoded by hand so the test doesn't depend on protobuf runtime
+const (
+	messageField   = 1
+	levelField     = 2
+	namespaceField = 3
+)
+
+type testEntry struct {
+	message   string
+	level     level.LogLevel
+	namespace string
+}
+
+// marshalEntry encodes entry as message { string message = 1; int32 level = 2; string namespace = 3; }
+func marshalEntry(e *logk.Entry) ([]byte, error) {
+	var b []byte
+	appendString := func(field int, s string) {
+		b = binary.AppendUvarint(b, uint64(field<<3|2))
+		b = binary.AppendUvarint(b, uint64(len(s)))
+		b = append(b, s...)
+	}
+
+	appendString(messageField, e.Message)
+	b = binary.AppendUvarint(b, uint64(levelField<<3))
+	b = binary.AppendUvarint(b, uint64(e.Level))
+	appendString(namespaceField, e.Namespace)
+	return b, nil
+}
+
+func unmarshalEntry(b []byte) (testEntry, error) {
+	var e testEntry
+	for len(b) > 0 {
+		tag, n := binary.Uvarint(b)
+		if n <= 0 {
+			return e, errors.New("invalid tag")
+		}
+		b = b[n:]
+
+		v, n := binary.Uvarint(b)
+		if n <= 0 {
+			return e, errors.New("invalid value")
+		}
+		b = b[n:]
+
+		switch int(tag >> 3) {
+		case levelField:
+			e.level = level.LogLevel(v)
+		case messageField, namespaceField:
+			if uint64(len(b)) < v {
+				return e, errors.New("truncated string")
+			}
+			s := string(b[:v])
+			b = b[v:]
+			if int(tag>>3) == messageField {
+				e.message = s
+			} else {
+				e.namespace = s
+			}
+		default:
+			return e, fmt.Errorf("unknown field %d", tag>>3)
+		}
+	}
+	return e, nil
+}
+
+func TestPrinterRoundTrip(t *testing.T) {
+	want := []testEntry{
+		{message: "started", level: level.Info, namespace: "api"},
+		// Message of 10 bytes is encoded with length byte '\n', which must survive newline delimited framing
+		{message: "0123456789", level: level.Error, namespace: ""},
+		{message: "", level: level.Debug, namespace: "worker"},
+	}
+
+	for name, framing := range map[string]Framing{"length prefixed": LengthPrefixed, "newline": NewlineDelimited} {
+		t.Run(name, func(t *testing.T) {
+			var buf bytes.Buffer
+			p := NewPrinter(&buf, marshalEntry, framing)
+			for _, e := range want {
+				options := logkOption.NewOptions()
+				p.Print(e.namespace, e.level, e.message, options)
+			}
+
+			d := NewDecoder(&buf, framing)
+			for i, w := range want {
+				b, err := d.Decode()
+				if err != nil {
+					t.Fatalf("frame %d: %s", i, err)
+				}
+				got, err := unmarshalEntry(b)
+				if err != nil {
+					t.Fatalf("frame %d: %s", i, err)
+				}
+				if got != w {
+					t.Errorf("frame %d = %+v, want %+v", i, got, w)
+				}
+			}
+
+			if _, err := d.Decode(); err != io.EOF {
+				t.Errorf("decode after last frame = %v, want EOF", err)
+			}
+		})
+	}
+}
+
+func TestDecoderTruncatedFrame(t *testing.T) {
+	for name, framing := range map[string]Framing{"length prefixed": LengthPrefixed, "newline": NewlineDelimited} {
+		t.Run(name, func(t *testing.T) {
+			var buf bytes.Buffer
+			NewPrinter(&buf, marshalEntry, framing).Print("api", level.Info, "started", logkOption.NewOptions())
+
+			d := NewDecoder(bytes.NewReader(buf.Bytes()[:buf.Len()-1]), framing)
+			if _, err := d.Decode(); !errors.Is(err, io.ErrUnexpectedEOF) {
+				t.Errorf("decode of truncated frame = %v, want unexpected EOF", err)
+			}
+		})
+	}
+}
+
+func TestDecoderMaxFrameSize(t *testing.T) {
+	for name, framing := range map[string]Framing{"length prefixed": LengthPrefixed, "newline": NewlineDelimited} {
+		t.Run(name, func(t *testing.T) {
+			var buf bytes.Buffer
+			p := NewPrinter(&buf, marshalEntry, framing)
+			p.Print("api", level.Info, "ok", logkOption.NewOptions())
+			p.Print("api", level.Info, string(bytes.Repeat([]byte("x"), 8<<10)), logkOption.NewOptions())
+
+			d := NewDecoder(&buf, framing, WithMaxFrameSize(1<<10))
+			if _, err := d.Decode(); err != nil {
+				t.Fatalf("frame within limit: %s", err)
+			}
+			if _, err := d.Decode(); !errors.Is(err, ErrFrameTooLarge) {
+				t.Errorf("decode of frame over limit = %v, want ErrFrameTooLarge", err)
+			}
+		})
+	}
+
+	// Size prefix of a corrupted stream is rejected before message is allocated
+	prefix := binary.AppendUvarint(nil, 1<<40)
+	if _, err := NewDecoder(bytes.NewReader(prefix), LengthPrefixed).Decode(); !errors.Is(err, ErrFrameTooLarge) {
+		t.Errorf("decode of huge size prefix = %v, want ErrFrameTooLarge", err)
+	}
+}
+
+// failingWriter fails every write
+type failingWriter struct{}
+
+func (failingWriter) Write([]byte) (int, error) {
+	return 0, errors.New("disk full")
+}
+
+func TestPrinterReportsErrors(t *testing.T) {
+	var errs []error
+	onError := WithOnError(func(err error) { errs = append(errs, err) })
+
+	marshalErr := errors.New("invalid entry")
+	failing := func(*logk.Entry) ([]byte, error) { return nil, marshalErr }
+	var buf bytes.Buffer
+	NewPrinter(&buf, failing, LengthPrefixed, onError).Print("api", level.Info, "started", logkOption.NewOptions())
+	if len(errs) != 1 || !errors.Is(errs[0], marshalErr) {
+		t.Errorf("errors = %v, want marshal error", errs)
+	}
+	if buf.Len() != 0 {
+		t.Errorf("entry that can't be marshalled is written as %q", buf.Bytes())
+	}
+
+	errs = nil
+	NewPrinter(failingWriter{}, marshalEntry, LengthPrefixed, onError).Print("api", level.Info, "started",
+		logkOption.NewOptions())
+	if len(errs) != 1 {
+		t.Errorf("errors = %v, want write error", errs)
+	}
+}
@@ -0,0 +1,251 @@
+package logk
+
+import (
+	"fmt"
+	"io"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/go-konsultin/logk/level"
+	logkOption "github.com/go-konsultin/logk/option"
+)
+
+// Default rate limit printer options
+const defaultSummaryInterval = 30 * time.Second
+
+// Metadata keys of suppression summary entry. Summary of ERROR and FATAL entries also carries their fingerprint,
+// so it's grouped with them downstream
+const (
+	SuppressedKey        = "suppressed"
+	SuppressedMessageKey = "suppressedMessage"
+)
+
+type RateLimitOptions struct {
+	// SummaryInterval is interval at which summary of suppressed entries is written
+	SummaryInterval time.Duration
+	// Key returns key of entry. Default is namespace and unformatted message
+	Key SamplingKeyFunc
+}
+
+type RateLimitOption = func(*RateLimitOptions)
+
+func WithSummaryInterval(d time.Duration) RateLimitOption {
+	return func(o *RateLimitOptions) {
+		o.SummaryInterval = d
+	}
+}
+
+func WithRateLimitKey(fn SamplingKeyFunc) RateLimitOption {
+	return func(o *RateLimitOptions) {
+		o.Key = fn
+	}
+}
+
+// RateLimitPrinter writes at most limit entries with the same key per window. Suppressed entries are counted and
+// a summary entry, e.g. "suppressed 4,312 identical messages in the last 30s", is written periodically with
+// the level and namespace of suppressed entries. While a key keeps exceeding limit, entries admitted in a window
+// are marked with logkOption.Sampled by rate of the previous window, so consumers can estimate actual counts
+type RateLimitPrinter struct {
+	printer Printer
+	limit   int
+	window  time.Duration
+	options RateLimitOptions
+
+	// mu guards keys and lastSummary
+	mu          sync.Mutex
+	keys        map[string]*rateLimitKey
+	lastSummary time.Time
+
+	stop     chan struct{}
+	stopOnce sync.Once
+	wg       sync.WaitGroup
+}
+
+type rateLimitKey struct {
+	namespace   string
+	level       level.LogLevel
+	msg         string
+	fingerprint string
+	windowStart time.Time
+	count       int
+	// rate is estimated number of entries each admitted entry stands for, by count of the previous window
+	rate       uint64
+	suppressed uint64
+	lastSeen   time.Time
+}
+
+// NewRateLimitPrinter creates a printer that writes at most limit entries with the same key per window, e.g.
+// NewRateLimitPrinter(p, 10, time.Second)
+func NewRateLimitPrinter(printer Printer, limit int, window time.Duration, args ...RateLimitOption) *RateLimitPrinter {
+	o := RateLimitOptions{
+		SummaryInterval: defaultSummaryInterval,
+		Key:             defaultSamplingKey,
+	}
+	for _, fn := range args {
+		fn(&o)
+	}
+
+	// Init printer if nil
+	if printer == nil {
+		printer = NewStdLogPrinter(nil, 0)
+	}
+
+	p := RateLimitPrinter{
+		printer:     printer,
+		limit:       limit,
+		window:      window,
+		options:     o,
+		keys:        make(map[string]*rateLimitKey),
+		lastSummary: time.Now(),
+		stop:        make(chan struct{}),
+	}
+
+	if o.SummaryInterval > 0 {
+		p.wg.Add(1)
+		go p.summarize()
+	}
+
+	return &p
+}
+
+func (p *RateLimitPrinter) Print(namespace string, lv level.LogLevel, msg string, options *logkOption.Options) {
+	key := p.options.Key(namespace, lv, msg, options)
+	now := time.Now()
+
+	p.mu.Lock()
+	k, ok := p.keys[key]
+	if !ok {
+		// Only fields of entry are kept for summary, as options are reused by logger once Print returns
+		k = &rateLimitKey{namespace: namespace, level: lv, msg: msg, windowStart: now}
+		if lv <= level.Error {
+			k.fingerprint = Fingerprint(lv, msg, options)
+		}
+		p.keys[key] = k
+	}
+
+	if elapsed := now.Sub(k.windowStart); elapsed >= p.window {
+		// Rate is only carried over from the window right before, as an idle key starts over
+		k.rate = 0
+		if elapsed < 2*p.window && p.limit > 0 && k.count > p.limit {
+			k.rate = uint64((k.count + p.limit - 1) / p.limit)
+		}
+		k.windowStart = now
+		k.count = 0
+	}
+	k.count++
+	k.lastSeen = now
+
+	allowed := k.count <= p.limit
+	if !allowed {
+		k.suppressed++
+	}
+	rate := k.rate
+	p.mu.Unlock()
+
+	if allowed {
+		p.printer.Print(namespace, lv, msg, markSampled(options, rate))
+	}
+}
+
+// Flush writes summary of suppressed entries, then flushes underlying printer
+func (p *RateLimitPrinter) Flush() error {
+	p.writeSummary()
+	if f, ok := p.printer.(Flusher); ok {
+		return f.Flush()
+	}
+	return nil
+}
+
+// Close writes summary of suppressed entries, stops background goroutine and closes underlying printer
+func (p *RateLimitPrinter) Close() error {
+	p.stopOnce.Do(func() {
+		close(p.stop)
+	})
+	p.wg.Wait()
+
+	err := p.Flush()
+	if c, ok := p.printer.(io.Closer); ok {
+		if cErr := c.Close(); cErr != nil && err == nil {
+			err = cErr
+		}
+	}
+	return err
+}
+
+func (p *RateLimitPrinter) summarize() {
+	defer p.wg.Done()
+
+	ticker := time.NewTicker(p.options.SummaryInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			p.writeSummary()
+		case <-p.stop:
+			return
+		}
+	}
+}
+
+// writeSummary writes a summary entry for each key with suppressed entries, and forgets idle keys
+func (p *RateLimitPrinter) writeSummary() {
+	now := time.Now()
+	var summaries []rateLimitKey
+
+	p.mu.Lock()
+	for key, k := range p.keys {
+		if k.suppressed > 0 {
+			summaries = append(summaries, *k)
+			k.suppressed = 0
+			continue
+		}
+
+		if now.Sub(k.lastSeen) >= p.window {
+			delete(p.keys, key)
+		}
+	}
+
+	interval := now.Sub(p.lastSummary)
+	p.lastSummary = now
+	p.mu.Unlock()
+
+	if interval >= time.Second {
+		interval = interval.Round(time.Second)
+	} else {
+		interval = interval.Round(time.Millisecond)
+	}
+
+	for _, k := range summaries {
+		options := logkOption.NewOptions()
+		options.Level = k.level
+		options.Metadata = map[string]interface{}{
+			SuppressedKey:        k.suppressed,
+			SuppressedMessageKey: k.msg,
+		}
+		if k.fingerprint != "" {
+			logkOption.WithFingerprint(k.fingerprint)(options)
+		}
+
+		msg := fmt.Sprintf("suppressed %s identical messages in the last %s", formatCount(k.suppressed), interval)
+		p.printer.Print(k.namespace, k.level, msg, options)
+	}
+}
+
+// formatCount formats n with thousands separator, e.g. 4,312
+func formatCount(n uint64) string {
+	s := strconv.FormatUint(n, 10)
+	if len(s) <= 3 {
+		return s
+	}
+
+	b := make([]byte, 0, len(s)+len(s)/3)
+	for i, c := range []byte(s) {
+		if i > 0 && (len(s)-i)%3 == 0 {
+			b = append(b, ',')
+		}
+		b = append(b, c)
+	}
+	return string(b)
+}
@@ -0,0 +1,96 @@
+package logk
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/go-konsultin/logk/level"
+	logkOption "github.com/go-konsultin/logk/option"
+)
+
+func TestRateLimitPrinterMarksSampled(t *testing.T) {
+	const window = 100 * time.Millisecond
+
+	rec := &recordPrinter{}
+	p := NewRateLimitPrinter(rec, 2, window, WithSummaryInterval(0))
+	t.Cleanup(func() {
+		_ = p.Close()
+	})
+
+	options := logkOption.NewOptions()
+	flood := func(n int) {
+		for i := 0; i < n; i++ {
+			p.Print("", level.Info, "flood", options)
+		}
+	}
+
+	// The first window isn't marked, as its rate isn't known yet
+	flood(6)
+	if got, want := rec.reset(), []uint64{0, 0}; !equalRates(got, want) {
+		t.Errorf("first window rates = %v, want %v", got, want)
+	}
+
+	// Admitted entries of the next window stand for 3 entries, by count of the first window
+	time.Sleep(window + window/5)
+	flood(6)
+	if got, want := rec.reset(), []uint64{3, 3}; !equalRates(got, want) {
+		t.Errorf("second window rates = %v, want %v", got, want)
+	}
+	if options.SampleRate() != 0 {
+		t.Error("options of caller are marked")
+	}
+
+	// Idle key starts over
+	time.Sleep(2*window + window/5)
+	flood(1)
+	if got, want := rec.reset(), []uint64{0}; !equalRates(got, want) {
+		t.Errorf("rates after idle = %v, want %v", got, want)
+	}
+}
+
+func TestRateLimitPrinterSummaryFingerprint(t *testing.T) {
+	var buf bytes.Buffer
+	p := NewRateLimitPrinter(NewJSONPrinter(&buf), 1, time.Minute, WithSummaryInterval(0))
+
+	for i := 0; i < 3; i++ {
+		p.Print("db", level.Error, "query failed", logkOption.Evaluate([]logkOption.SetterFunc{
+			logkOption.Error(errors.New("timeout")),
+		}))
+	}
+	p.Print("db", level.Warn, "slow query", logkOption.NewOptions())
+	p.Print("db", level.Warn, "slow query", logkOption.NewOptions())
+	if err := p.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	var entry map[string]interface{}
+	summaries := make(map[string]map[string]interface{})
+	dec := json.NewDecoder(&buf)
+	for dec.More() {
+		var line map[string]interface{}
+		if err := dec.Decode(&line); err != nil {
+			t.Fatal(err)
+		}
+		metadata, _ := line[logkOption.MetadataKey].(map[string]interface{})
+		if msg, ok := metadata[SuppressedMessageKey].(string); ok {
+			summaries[msg] = line
+		} else if line[logkOption.MessageKey] == "query failed" {
+			entry = line
+		}
+	}
+
+	// Summary of errors is grouped with suppressed entries, by the same key that printers attach to them
+	fp, _ := entry[logkOption.FingerprintKey].(string)
+	if fp == "" {
+		t.Fatalf("entry has no fingerprint: %v", entry)
+	}
+	if got := summaries["query failed"][logkOption.FingerprintKey]; got != fp {
+		t.Errorf("summary fingerprint = %v, want %s", got, fp)
+	}
+	if got, ok := summaries["slow query"][logkOption.FingerprintKey]; ok {
+		t.Errorf("summary of warnings has fingerprint %v", got)
+	}
+}
@@ -0,0 +1,94 @@
+package logk
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+
+	logkOption "github.com/go-konsultin/logk/option"
+)
+
+// Redactor returns redacted value of a field. Message is passed with logkOption.MessageKey and error message with
+// logkOption.ErrorKey, metadata is passed with its own keys including nested ones. Values that are not sensitive
+// must be returned unchanged
+type Redactor = func(key string, value interface{}) interface{}
+
+// Common patterns of sensitive values in free text
+var (
+	// CreditCardPattern matches 13 to 19 digit card numbers, optionally separated by spaces or dashes
+	CreditCardPattern = regexp.MustCompile(`\b(?:\d[ -]?){12,18}\d\b`)
+	// EmailPattern matches email addresses
+	EmailPattern = regexp.MustCompile(`[A-Za-z0-9._%+\-]+@[A-Za-z0-9.\-]+\.[A-Za-z]{2,}`)
+)
+
+// RedactKeys creates a redactor that masks values of fields which key matches a pattern, e.g. "password" or
+// "*token". Patterns are case-insensitive and support path.Match syntax
+func RedactKeys(patterns ...string) Redactor {
+	lower := make([]string, len(patterns))
+	for i, p := range patterns {
+		lower[i] = strings.ToLower(p)
+	}
+
+	return func(key string, value interface{}) interface{} {
+		if key == logkOption.MessageKey || key == logkOption.ErrorKey || !isSensitiveKey(lower, key) {
+			return value
+		}
+		return sensitiveMask
+	}
+}
+
+// RedactPattern creates a redactor that masks matches of re in message, error and string metadata values
+func RedactPattern(re *regexp.Regexp) Redactor {
+	return func(_ string, value interface{}) interface{} {
+		s, ok := value.(string)
+		if !ok {
+			return value
+		}
+		return re.ReplaceAllString(s, sensitiveMask)
+	}
+}
+
+// redact applies redactors to message, error and metadata of entry. Formatted message is resolved first, so
+// formatting arguments are redacted too. Metadata is copied, so maps owned by caller are never mutated
+func redact(redactors []Redactor, msg string, options *logkOption.Options) string {
+	if len(options.FmtArgs) > 0 {
+		msg = fmt.Sprintf(msg, options.FmtArgs...)
+		options.FmtArgs = nil
+	}
+
+	if s, ok := applyRedactors(redactors, logkOption.MessageKey, msg).(string); ok {
+		msg = s
+	}
+
+	if err := options.Error(); err != nil {
+		if s, ok := applyRedactors(redactors, logkOption.ErrorKey, err.Error()).(string); ok && s != err.Error() {
+			options.Values[logkOption.ErrorKey] = errors.New(s)
+		}
+	}
+
+	if len(options.Metadata) > 0 {
+		options.Metadata = redactMetadata(redactors, options.Metadata)
+	}
+
+	return msg
+}
+
+func redactMetadata(redactors []Redactor, meta map[string]interface{}) map[string]interface{} {
+	result := make(map[string]interface{}, len(meta))
+	for k, v := range meta {
+		v = applyRedactors(redactors, k, v)
+		if nested, ok := v.(map[string]interface{}); ok {
+			v = redactMetadata(redactors, nested)
+		}
+		result[k] = v
+	}
+	return result
+}
+
+func applyRedactors(redactors []Redactor, key string, value interface{}) interface{} {
+	for _, r := range redactors {
+		value = r(key, value)
+	}
+	return value
+}
@@ -0,0 +1,291 @@
+package logk
+
+import (
+	"context"
+	"path"
+	"slices"
+	"strings"
+	"sync"
+	"sync/atomic"
+
+	"github.com/go-konsultin/logk/level"
+	logkOption "github.com/go-konsultin/logk/option"
+)
+
+// LevelHook is called before an entry in the registered level is printed. Options must not be retained after it
+// returns, see Printer
+type LevelHook = func(namespace string, lv level.LogLevel, msg string, options *logkOption.Options)
+
+// Hook is called by StdLogger before an entry is printed. It may mutate options, e.g. to add or change metadata,
+// and returns false to drop the entry. Options must not be retained after it returns, see Printer
+type Hook = func(namespace string, lv level.LogLevel, msg string, options *logkOption.Options) bool
+
+// ContextExtractor retrieves fields from context that will be merged into entry metadata.
+// Metadata that is set on call takes precedence over extracted fields
+type ContextExtractor = func(ctx context.Context) map[string]interface{}
+
+// sensitiveMask replaces metadata values which key matches a sensitive key pattern
+const sensitiveMask = "***"
+
+var (
+	hooks         = make(map[level.LogLevel][]LevelHook)
+	fatalHooks    []func()
+	extractors    []ContextExtractor
+	redactors     []Redactor
+	sensitiveKeys []string
+	registryMutex sync.RWMutex
+
+	// onceKeys holds keys of entries written with logkOption.WithOnce
+	onceKeys sync.Map
+
+	// unsafeDebug reveals logkOption.SecretValue in printed entries
+	unsafeDebug atomic.Bool
+
+	// namespaceLevels is replaced on write under registryMutex, so it can be read without lock on every entry
+	namespaceLevels atomic.Pointer[map[string]level.LogLevel]
+)
+
+// OnLevel registers a callback that is called by StdLogger before an entry in level is printed
+func OnLevel(lv level.LogLevel, fn LevelHook) {
+	registryMutex.Lock()
+	defer registryMutex.Unlock()
+	hooks[lv] = append(hooks[lv], fn)
+}
+
+// Hooks returns a copy of registered level hooks
+func Hooks() map[level.LogLevel][]LevelHook {
+	registryMutex.RLock()
+	defer registryMutex.RUnlock()
+	result := make(map[level.LogLevel][]LevelHook, len(hooks))
+	for lv, fns := range hooks {
+		result[lv] = append([]LevelHook(nil), fns...)
+	}
+	return result
+}
+
+// ClearHooks removes all registered level hooks. It is primarily used to isolate test cases
+func ClearHooks() {
+	registryMutex.Lock()
+	defer registryMutex.Unlock()
+	hooks = make(map[level.LogLevel][]LevelHook)
+}
+
+// OnFatal registers a callback that is called by StdLogger after a FATAL entry is written, before printers are
+// flushed and the process exits or panics, e.g. to release resources. It is not called with FatalNoop behavior
+func OnFatal(fn func()) {
+	registryMutex.Lock()
+	defer registryMutex.Unlock()
+	fatalHooks = append(fatalHooks, fn)
+}
+
+// ClearFatalHooks removes all registered fatal hooks. It is primarily used to isolate test cases
+func ClearFatalHooks() {
+	registryMutex.Lock()
+	defer registryMutex.Unlock()
+	fatalHooks = nil
+}
+
+// AddExtractor registers a function to extract fields from entry context
+func AddExtractor(fn ContextExtractor) {
+	registryMutex.Lock()
+	defer registryMutex.Unlock()
+	extractors = append(extractors, fn)
+}
+
+// Extractors returns a copy of registered context extractors
+func Extractors() []ContextExtractor {
+	registryMutex.RLock()
+	defer registryMutex.RUnlock()
+	return append([]ContextExtractor(nil), extractors...)
+}
+
+// ClearExtractors removes all registered context extractors. It is primarily used to isolate test cases
+func ClearExtractors() {
+	registryMutex.Lock()
+	defer registryMutex.Unlock()
+	extractors = nil
+}
+
+// AddRedactor registers a redactor that is applied by StdLogger to message, error and metadata of every entry before
+// it is printed, e.g.
+//
+//	logk.AddRedactor(logk.RedactKeys("password", "*token", "authorization"))
+//	logk.AddRedactor(logk.RedactPattern(logk.CreditCardPattern))
+func AddRedactor(r Redactor) {
+	registryMutex.Lock()
+	defer registryMutex.Unlock()
+	redactors = append(redactors, r)
+}
+
+// Redactors returns a copy of registered redactors
+func Redactors() []Redactor {
+	registryMutex.RLock()
+	defer registryMutex.RUnlock()
+	return append([]Redactor(nil), redactors...)
+}
+
+// ClearRedactors removes all registered redactors. It is primarily used to isolate test cases
+func ClearRedactors() {
+	registryMutex.Lock()
+	defer registryMutex.Unlock()
+	redactors = nil
+}
+
+// SetUnsafeDebug toggles rendering actual value of logkOption.SecretValue instead of "***". It must only be
+// enabled for local debugging, as secrets are written to logs
+func SetUnsafeDebug(enabled bool) {
+	if enabled {
+		internalWarn("unsafe debug mode is enabled, secrets are written to logs")
+	}
+	unsafeDebug.Store(enabled)
+}
+
+// AddSensitiveKeys registers metadata key patterns which values are masked by printers. Patterns are case-insensitive
+// and support path.Match syntax, e.g. "*token"
+func AddSensitiveKeys(patterns ...string) {
+	registryMutex.Lock()
+	defer registryMutex.Unlock()
+	for _, p := range patterns {
+		sensitiveKeys = append(sensitiveKeys, strings.ToLower(p))
+	}
+}
+
+// SensitiveKeys returns a copy of registered sensitive key patterns
+func SensitiveKeys() []string {
+	registryMutex.RLock()
+	defer registryMutex.RUnlock()
+	return append([]string(nil), sensitiveKeys...)
+}
+
+// RemoveSensitiveKeys removes one registration of each sensitive key pattern, so patterns that are also registered
+// elsewhere stay in effect
+func RemoveSensitiveKeys(patterns ...string) {
+	registryMutex.Lock()
+	defer registryMutex.Unlock()
+	for _, p := range patterns {
+		if i := slices.Index(sensitiveKeys, strings.ToLower(p)); i >= 0 {
+			sensitiveKeys = slices.Delete(slices.Clone(sensitiveKeys), i, i+1)
+		}
+	}
+}
+
+// ClearSensitiveKeys removes all registered sensitive key patterns. It is primarily used to isolate test cases
+func ClearSensitiveKeys() {
+	registryMutex.Lock()
+	defer registryMutex.Unlock()
+	sensitiveKeys = nil
+}
+
+// SetNamespaceLevel overrides level of StdLogger instances in namespace, regardless of their own level
+func SetNamespaceLevel(namespace string, lv level.LogLevel) {
+	registryMutex.Lock()
+	defer registryMutex.Unlock()
+	levels := copyNamespaceLevels()
+	levels[namespace] = lv
+	namespaceLevels.Store(&levels)
+}
+
+// RemoveNamespaceLevel removes level override of namespace
+func RemoveNamespaceLevel(namespace string) {
+	registryMutex.Lock()
+	defer registryMutex.Unlock()
+	levels := copyNamespaceLevels()
+	delete(levels, namespace)
+	namespaceLevels.Store(&levels)
+}
+
+// NamespaceLevels returns a copy of namespace level overrides
+func NamespaceLevels() map[string]level.LogLevel {
+	return copyNamespaceLevels()
+}
+
+// ClearNamespaceLevels removes all namespace level overrides. It is primarily used to isolate test cases
+func ClearNamespaceLevels() {
+	registryMutex.Lock()
+	defer registryMutex.Unlock()
+	namespaceLevels.Store(nil)
+}
+
+// ResetOnce forgets keys of entries written with logkOption.WithOnce, so they can be written again.
+// It is primarily used to isolate test cases
+func ResetOnce() {
+	onceKeys.Clear()
+}
+
+// markOnce returns true if key has not been written before
+func markOnce(key string) bool {
+	_, loaded := onceKeys.LoadOrStore(key, struct{}{})
+	return !loaded
+}
+
+// namespaceLevel returns level override of namespace
+func namespaceLevel(namespace string) (level.LogLevel, bool) {
+	levels := namespaceLevels.Load()
+	if levels == nil {
+		return 0, false
+	}
+	lv, ok := (*levels)[namespace]
+	return lv, ok
+}
+
+func copyNamespaceLevels() map[string]level.LogLevel {
+	result := make(map[string]level.LogLevel)
+	if levels := namespaceLevels.Load(); levels != nil {
+		for ns, lv := range *levels {
+			result[ns] = lv
+		}
+	}
+	return result
+}
+
+func runHooks(namespace string, lv level.LogLevel, msg string, options *logkOption.Options) {
+	registryMutex.RLock()
+	fns := hooks[lv]
+	registryMutex.RUnlock()
+
+	for _, fn := range fns {
+		fn(namespace, lv, msg, options)
+	}
+}
+
+func runFatalHooks() {
+	registryMutex.RLock()
+	fns := fatalHooks
+	registryMutex.RUnlock()
+
+	for _, fn := range fns {
+		fn()
+	}
+}
+
+// extractContext returns merged fields of all registered extractors
+func extractContext(ctx context.Context) map[string]interface{} {
+	if ctx == nil {
+		return nil
+	}
+
+	registryMutex.RLock()
+	fns := extractors
+	registryMutex.RUnlock()
+
+	var result map[string]interface{}
+	for _, fn := range fns {
+		for k, v := range fn(ctx) {
+			if result == nil {
+				result = make(map[string]interface{})
+			}
+			result[k] = v
+		}
+	}
+	return result
+}
+
+func isSensitiveKey(patterns []string, key string) bool {
+	key = strings.ToLower(key)
+	for _, p := range patterns {
+		if ok, _ := path.Match(p, key); ok {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,41 @@
+package logk
+
+import (
+	"fmt"
+	"sync"
+	"testing"
+
+	"github.com/go-konsultin/logk/level"
+)
+
+func TestClearNamespaceLevelsConcurrent(t *testing.T) {
+	t.Cleanup(ClearNamespaceLevels)
+
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(2)
+		go func(i int) {
+			defer wg.Done()
+			for j := 0; j < 100; j++ {
+				SetNamespaceLevel(fmt.Sprintf("ns-%d-%d", i, j), level.Debug)
+			}
+		}(i)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 100; j++ {
+				ClearNamespaceLevels()
+			}
+		}()
+	}
+	wg.Wait()
+
+	// Clear must not be lost in between copy and store of a concurrent update
+	ClearNamespaceLevels()
+	SetNamespaceLevel("kept", level.Trace)
+	if _, ok := namespaceLevel("ns-0-0"); ok {
+		t.Error("cleared namespace level is restored by later update")
+	}
+	if lv, ok := namespaceLevel("kept"); !ok || lv != level.Trace {
+		t.Errorf("kept level = %s, %v, want trace", level.String(lv), ok)
+	}
+}
@@ -0,0 +1,225 @@
+package logk
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"sync"
+
+	"github.com/go-konsultin/logk/level"
+	logkOption "github.com/go-konsultin/logk/option"
+)
+
+// Default ring printer options
+const (
+	defaultRingSize        = 1000
+	defaultRingContentType = "application/x-ndjson"
+)
+
+type RingOptions struct {
+	// Size is number of the most recent entries that are retained
+	Size int
+	// Encoder formats retained entries, default is JSON
+	Encoder Encoder
+	// ContentType is content type of entries dumped by Handler
+	ContentType    string
+	PrinterOptions []PrinterOption
+}
+
+type RingOption = func(*RingOptions)
+
+func WithRingSize(n int) RingOption {
+	return func(o *RingOptions) {
+		o.Size = n
+	}
+}
+
+// WithRingEncoder sets encoder of retained entries, and content type of their format, e.g. "text/plain"
+func WithRingEncoder(enc Encoder, contentType string) RingOption {
+	return func(o *RingOptions) {
+		o.Encoder = enc
+		o.ContentType = contentType
+	}
+}
+
+func WithRingPrinterOptions(args ...PrinterOption) RingOption {
+	return func(o *RingOptions) {
+		o.PrinterOptions = append(o.PrinterOptions, args...)
+	}
+}
+
+// RingPrinter retains the last Size entries in memory, so recent history can be dumped from a running process with
+// DumpTo or Handler. It's meant to be combined with printers that write less verbose levels, e.g.
+//
+//	ring := logk.NewRingPrinter()
+//	logger := logk.NewStdLogger(logk.MultiPrinter(
+//		logk.LevelPrinter(nil, nil).Route(level.Fatal, level.Info, printer),
+//		ring,
+//	), logkOption.Level(level.Trace))
+//	mux.Handle("/debug/logs", ring.Handler())
+type RingPrinter struct {
+	options        RingOptions
+	printerOptions PrinterOptions
+
+	// mu guards fields below
+	mu      sync.Mutex
+	entries []ringEntry
+	// next is index where next entry is stored, once ring is full
+	next int
+}
+
+type ringEntry struct {
+	level level.LogLevel
+	data  []byte
+}
+
+func NewRingPrinter(args ...RingOption) *RingPrinter {
+	o := RingOptions{
+		Size:        defaultRingSize,
+		Encoder:     NewJSONEncoder(),
+		ContentType: defaultRingContentType,
+	}
+	for _, fn := range args {
+		fn(&o)
+	}
+
+	if o.Size < 1 {
+		o.Size = defaultRingSize
+	}
+
+	if o.Encoder == nil {
+		o.Encoder = NewJSONEncoder()
+	}
+
+	return &RingPrinter{
+		options:        o,
+		printerOptions: evaluatePrinterOptions(o.PrinterOptions),
+		entries:        make([]ringEntry, 0, o.Size),
+	}
+}
+
+func (p *RingPrinter) Print(namespace string, lv level.LogLevel, msg string, options *logkOption.Options) {
+	entry := acquireEntry(namespace, lv, msg, options, &p.printerOptions)
+	defer releaseEntry(entry)
+
+	buf := getBuffer()
+	defer putBuffer(buf)
+
+	if be, ok := p.options.Encoder.(BufferEncoder); ok {
+		if err := be.EncodeTo(buf, entry); err != nil {
+			return
+		}
+	} else {
+		b, err := p.options.Encoder.Encode(entry)
+		if err != nil {
+			return
+		}
+		buf.Write(b)
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if len(p.entries) < cap(p.entries) {
+		p.entries = append(p.entries, ringEntry{level: lv, data: bytes.Clone(buf.Bytes())})
+		return
+	}
+
+	// Overwrite the oldest entry, reusing its buffer
+	e := &p.entries[p.next]
+	e.level = lv
+	e.data = append(e.data[:0], buf.Bytes()...)
+	p.next = (p.next + 1) % len(p.entries)
+}
+
+// Len returns number of retained entries
+func (p *RingPrinter) Len() int {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return len(p.entries)
+}
+
+// Reset discards retained entries
+func (p *RingPrinter) Reset() {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	clear(p.entries)
+	p.entries = p.entries[:0]
+	p.next = 0
+}
+
+// DumpTo writes retained entries to w, the oldest first, and returns number of written bytes
+func (p *RingPrinter) DumpTo(w io.Writer) (int64, error) {
+	return p.dump(w, level.Trace, 0)
+}
+
+// dump writes the last limit entries in lv or more severe level to w. Zero limit writes all entries. Entries are
+// copied first, so slow writer doesn't block printing
+func (p *RingPrinter) dump(w io.Writer, lv level.LogLevel, limit int) (int64, error) {
+	p.mu.Lock()
+	var entries [][]byte
+	for i := range p.entries {
+		e := p.entries[(p.next+i)%len(p.entries)]
+		if e.level <= lv {
+			entries = append(entries, bytes.Clone(e.data))
+		}
+	}
+	p.mu.Unlock()
+
+	if limit > 0 && len(entries) > limit {
+		entries = entries[len(entries)-limit:]
+	}
+
+	var written int64
+	for _, b := range entries {
+		n, err := w.Write(b)
+		written += int64(n)
+		if err != nil {
+			return written, err
+		}
+	}
+	return written, nil
+}
+
+// Handler returns an http.Handler that dumps retained entries on GET, the oldest first. Query parameter "level"
+// only dumps entries in given or more severe level, and "limit" only dumps the last given number of entries, e.g.
+//
+//	GET /debug/logs?level=debug&limit=100
+func (p *RingPrinter) Handler() http.Handler {
+	return http.HandlerFunc(p.serveHTTP)
+}
+
+func (p *RingPrinter) serveHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet && r.Method != http.MethodHead {
+		w.Header().Set("Allow", "GET, HEAD")
+		http.Error(w, fmt.Sprintf("method %s is not allowed", r.Method), http.StatusMethodNotAllowed)
+		return
+	}
+
+	lv := level.Trace
+	if s := r.URL.Query().Get("level"); s != "" {
+		var err error
+		if lv, err = level.ParseStrict(s); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+	}
+
+	limit := 0
+	if s := r.URL.Query().Get("limit"); s != "" {
+		var err error
+		if limit, err = strconv.Atoi(s); err != nil || limit < 0 {
+			http.Error(w, fmt.Sprintf("invalid limit %q", s), http.StatusBadRequest)
+			return
+		}
+	}
+
+	w.Header().Set("Content-Type", p.options.ContentType)
+	w.Header().Set("Cache-Control", "no-store")
+	if r.Method == http.MethodHead {
+		return
+	}
+	_, _ = p.dump(w, lv, limit)
+}
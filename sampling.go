@@ -0,0 +1,219 @@
+package logk
+
+import (
+	"context"
+	"io"
+	"sync/atomic"
+
+	"github.com/go-konsultin/logk/level"
+	logkOption "github.com/go-konsultin/logk/option"
+)
+
+// SamplingLogger wraps a Logger and only writes 1 in N entries for each level, based on configured rates.
+// Levels that are absent from rates are logged unconditionally. Admitted entries of sampled levels are marked with
+// "sampled" and "sampleRate" fields, so downstream consumers know the stream is partial and can scale counts by rate
+type SamplingLogger struct {
+	logger   Logger
+	rates    map[level.LogLevel]uint64
+	counters map[level.LogLevel]*atomic.Uint64
+}
+
+// NewSamplingLogger creates a logger that samples entries with per-level rates, e.g. {level.Info: 100} writes
+// 1 in 100 info entries. Rates lower than 2 are treated as unsampled
+func NewSamplingLogger(logger Logger, rates map[level.LogLevel]int) *SamplingLogger {
+	s := SamplingLogger{
+		logger:   logger,
+		rates:    make(map[level.LogLevel]uint64),
+		counters: make(map[level.LogLevel]*atomic.Uint64),
+	}
+
+	// Copy rates, so maps are never mutated after construction and can be read without lock
+	for lv, rate := range rates {
+		if rate < 2 {
+			continue
+		}
+		s.rates[lv] = uint64(rate)
+		s.counters[lv] = new(atomic.Uint64)
+	}
+
+	return &s
+}
+
+func (s *SamplingLogger) Fatal(msg string, args ...logkOption.SetterFunc) {
+	if args, ok := s.sample(level.Fatal, args); ok {
+		s.logger.Fatal(msg, args...)
+	}
+}
+
+func (s *SamplingLogger) Fatalf(format string, args ...interface{}) {
+	opts, ok := s.sample(level.Fatal, nil)
+	switch {
+	case !ok:
+	case len(opts) == 0:
+		s.logger.Fatalf(format, args...)
+	default:
+		s.logger.Fatal(format, append(opts, logkOption.Format(args...))...)
+	}
+}
+
+func (s *SamplingLogger) Error(msg string, args ...logkOption.SetterFunc) {
+	if args, ok := s.sample(level.Error, args); ok {
+		s.logger.Error(msg, args...)
+	}
+}
+
+func (s *SamplingLogger) Errorf(format string, args ...interface{}) {
+	opts, ok := s.sample(level.Error, nil)
+	switch {
+	case !ok:
+	case len(opts) == 0:
+		s.logger.Errorf(format, args...)
+	default:
+		s.logger.Error(format, append(opts, logkOption.Format(args...))...)
+	}
+}
+
+func (s *SamplingLogger) Warn(msg string, args ...logkOption.SetterFunc) {
+	if args, ok := s.sample(level.Warn, args); ok {
+		s.logger.Warn(msg, args...)
+	}
+}
+
+func (s *SamplingLogger) Warnf(format string, args ...interface{}) {
+	opts, ok := s.sample(level.Warn, nil)
+	switch {
+	case !ok:
+	case len(opts) == 0:
+		s.logger.Warnf(format, args...)
+	default:
+		s.logger.Warn(format, append(opts, logkOption.Format(args...))...)
+	}
+}
+
+func (s *SamplingLogger) Info(msg string, args ...logkOption.SetterFunc) {
+	if args, ok := s.sample(level.Info, args); ok {
+		s.logger.Info(msg, args...)
+	}
+}
+
+func (s *SamplingLogger) Infof(format string, args ...interface{}) {
+	opts, ok := s.sample(level.Info, nil)
+	switch {
+	case !ok:
+	case len(opts) == 0:
+		s.logger.Infof(format, args...)
+	default:
+		s.logger.Info(format, append(opts, logkOption.Format(args...))...)
+	}
+}
+
+func (s *SamplingLogger) Debug(msg string, args ...logkOption.SetterFunc) {
+	if args, ok := s.sample(level.Debug, args); ok {
+		s.logger.Debug(msg, args...)
+	}
+}
+
+func (s *SamplingLogger) Debugf(format string, args ...interface{}) {
+	opts, ok := s.sample(level.Debug, nil)
+	switch {
+	case !ok:
+	case len(opts) == 0:
+		s.logger.Debugf(format, args...)
+	default:
+		s.logger.Debug(format, append(opts, logkOption.Format(args...))...)
+	}
+}
+
+func (s *SamplingLogger) Trace(msg string, args ...logkOption.SetterFunc) {
+	if args, ok := s.sample(level.Trace, args); ok {
+		s.logger.Trace(msg, args...)
+	}
+}
+
+func (s *SamplingLogger) Tracef(format string, args ...interface{}) {
+	opts, ok := s.sample(level.Trace, nil)
+	switch {
+	case !ok:
+	case len(opts) == 0:
+		s.logger.Tracef(format, args...)
+	default:
+		s.logger.Trace(format, append(opts, logkOption.Format(args...))...)
+	}
+}
+
+// NewChild creates a sampled child logger. Counters are shared with parent, so rates apply to the whole tree
+func (s *SamplingLogger) NewChild(args ...logkOption.SetterFunc) Logger {
+	return &SamplingLogger{
+		logger:   s.logger.NewChild(args...),
+		rates:    s.rates,
+		counters: s.counters,
+	}
+}
+
+func (s *SamplingLogger) NewChildCtx(ctx context.Context, args ...logkOption.SetterFunc) Logger {
+	return &SamplingLogger{
+		logger:   s.logger.NewChildCtx(ctx, args...),
+		rates:    s.rates,
+		counters: s.counters,
+	}
+}
+
+func (s *SamplingLogger) With(args ...logkOption.SetterFunc) Logger {
+	return &SamplingLogger{
+		logger:   s.logger.With(args...),
+		rates:    s.rates,
+		counters: s.counters,
+	}
+}
+
+// Enabled returns true if level is enabled on wrapped logger. Sampling is not taken into account
+func (s *SamplingLogger) Enabled(lv level.LogLevel) bool {
+	return IsEnabled(s.logger, lv)
+}
+
+// SetLevel changes level of wrapped logger if it implements LevelLogger
+func (s *SamplingLogger) SetLevel(lv level.LogLevel) {
+	if ll, ok := s.logger.(LevelLogger); ok {
+		ll.SetLevel(lv)
+	}
+}
+
+// GetLevel returns level of wrapped logger if it implements LevelLogger, otherwise level.Default
+func (s *SamplingLogger) GetLevel() level.LogLevel {
+	if ll, ok := s.logger.(LevelLogger); ok {
+		return ll.GetLevel()
+	}
+	return level.Default
+}
+
+func (s *SamplingLogger) Flush() error {
+	if f, ok := s.logger.(Flusher); ok {
+		return f.Flush()
+	}
+	return nil
+}
+
+func (s *SamplingLogger) Close() error {
+	if c, ok := s.logger.(io.Closer); ok {
+		return c.Close()
+	}
+	return nil
+}
+
+// sample returns true if entry in level should be written. Admitted entries of sampled levels are marked with
+// logkOption.Sampled, so returned args must be used. Formatted entries are written with non-formatted method then,
+// as formatting arguments can't carry sampled mark. Sampling is inlined in logger methods, rather than wrapping
+// them, so all methods are the same number of frames away from caller
+func (s *SamplingLogger) sample(lv level.LogLevel, args []logkOption.SetterFunc) ([]logkOption.SetterFunc, bool) {
+	counter, ok := s.counters[lv]
+	if !ok {
+		return args, true
+	}
+
+	// Write the first entry and every Nth afterwards
+	rate := s.rates[lv]
+	if (counter.Add(1)-1)%rate != 0 {
+		return nil, false
+	}
+	return append(args[:len(args):len(args)], logkOption.Sampled(rate)), true
+}
@@ -0,0 +1,95 @@
+package logk
+
+import (
+	"bytes"
+	"fmt"
+	"runtime"
+	"sync"
+	"testing"
+
+	"github.com/go-konsultin/logk/level"
+	logkOption "github.com/go-konsultin/logk/option"
+)
+
+// nextLine returns line that follows call of nextLine
+func nextLine() int {
+	_, _, line, _ := runtime.Caller(1)
+	return line + 1
+}
+
+func TestSamplingLoggerCaller(t *testing.T) {
+	var buf bytes.Buffer
+	logger := New().JSON().Output(&buf).Level(level.Trace).Sampling(level.Info, 2).Sampling(level.Warn, 2).
+		Caller().Build()
+
+	check := func(name string, line int) {
+		t.Helper()
+		want := fmt.Sprintf("sampling_test.go:%d", line)
+		if got := decodeLine(t, buf.Bytes())[logkOption.CallerKey]; got != want {
+			t.Errorf("%s: caller = %v, want %s", name, got, want)
+		}
+		buf.Reset()
+	}
+
+	// The first entry of each sampled level is admitted
+	line := nextLine()
+	logger.Infof("info %d", 1)
+	check("Infof", line)
+
+	line = nextLine()
+	logger.Warnf("warn %d", 1)
+	check("Warnf", line)
+
+	line = nextLine()
+	logger.Errorf("error %d", 1)
+	check("Errorf", line)
+
+	line = nextLine()
+	logger.Error("error")
+	check("Error", line)
+}
+
+// levelCountPrinter counts written entries by level
+type levelCountPrinter struct {
+	mu     sync.Mutex
+	counts map[level.LogLevel]int
+}
+
+func (p *levelCountPrinter) Print(_ string, lv level.LogLevel, _ string, _ *logkOption.Options) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.counts[lv]++
+}
+
+func TestSamplingLoggerKeepsUnsampledLevels(t *testing.T) {
+	const goroutines, entries = 4, 1000
+
+	p := &levelCountPrinter{counts: make(map[level.LogLevel]int)}
+	logger := NewSamplingLogger(NewStdLogger(p, logkOption.Level(level.Info)), map[level.LogLevel]int{level.Info: 100})
+
+	var wg sync.WaitGroup
+	for g := 0; g < goroutines; g++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := 0; i < entries; i++ {
+				logger.Error("error")
+				logger.Errorf("error %d", i)
+				logger.Warn("warn")
+				logger.Info("info")
+			}
+		}()
+	}
+	wg.Wait()
+
+	// Errors and warnings are absent from rates, so none is sampled out
+	if got, want := p.counts[level.Error], 2*goroutines*entries; got != want {
+		t.Errorf("error entries = %d, want %d", got, want)
+	}
+	if got, want := p.counts[level.Warn], goroutines*entries; got != want {
+		t.Errorf("warn entries = %d, want %d", got, want)
+	}
+	if got, want := p.counts[level.Info], goroutines*entries/100; got != want {
+		t.Errorf("info entries = %d, want %d", got, want)
+	}
+}
@@ -0,0 +1,212 @@
+package logk
+
+import (
+	"hash/fnv"
+	"io"
+	"sync/atomic"
+	"time"
+
+	"github.com/go-konsultin/logk/level"
+	logkOption "github.com/go-konsultin/logk/option"
+)
+
+// Sampling printer constants
+const (
+	defaultSamplingTick = time.Second
+	// samplingCounterSize is number of counters per level. Keys are hashed into counters, so memory is bounded
+	// regardless of number of distinct messages
+	samplingCounterSize = 4096
+)
+
+// SamplingRule writes the First entries with the same key in each tick, then every Thereafter-th entry.
+// Zero Thereafter drops all entries after the first ones
+type SamplingRule struct {
+	First      int
+	Thereafter int
+}
+
+// SamplingKeyFunc returns key which entries are counted together
+type SamplingKeyFunc = func(namespace string, lv level.LogLevel, msg string, options *logkOption.Options) string
+
+type SamplingOptions struct {
+	// Tick is interval at which counters are reset
+	Tick time.Duration
+	// Levels overrides the default rule of levels
+	Levels map[level.LogLevel]SamplingRule
+	// Key returns key of entry. Default is namespace and unformatted message
+	Key SamplingKeyFunc
+}
+
+type SamplingOption = func(*SamplingOptions)
+
+func WithSamplingTick(d time.Duration) SamplingOption {
+	return func(o *SamplingOptions) {
+		o.Tick = d
+	}
+}
+
+func WithLevelSampling(lv level.LogLevel, first, thereafter int) SamplingOption {
+	return func(o *SamplingOptions) {
+		o.Levels[lv] = SamplingRule{First: first, Thereafter: thereafter}
+	}
+}
+
+func WithSamplingKey(fn SamplingKeyFunc) SamplingOption {
+	return func(o *SamplingOptions) {
+		o.Key = fn
+	}
+}
+
+// SamplingPrinter limits entries with the same level and key that are written per tick, so a tight loop can't
+// flood underlying printer. Unlike SamplingLogger, which writes 1 in N entries, it writes everything until the
+// rate of an entry gets high. Entries written after the first ones are marked with logkOption.Sampled.
+// Entries reach it after logger has computed their lazy values, so dropping an entry doesn't save that work, see
+// logkOption.Lazy
+type SamplingPrinter struct {
+	printer  Printer
+	options  SamplingOptions
+	rule     SamplingRule
+	counters map[level.LogLevel]*samplingCounters
+	dropped  atomic.Uint64
+}
+
+type samplingCounters struct {
+	counters [samplingCounterSize]samplingCounter
+	dropped  atomic.Uint64
+}
+
+type samplingCounter struct {
+	resetAt atomic.Int64
+	count   atomic.Uint64
+}
+
+// NewSamplingPrinter creates a printer that writes the first entries with the same key in each second, then every
+// thereafter-th entry, e.g. NewSamplingPrinter(p, 100, 10)
+func NewSamplingPrinter(printer Printer, first, thereafter int, args ...SamplingOption) *SamplingPrinter {
+	o := SamplingOptions{
+		Tick:   defaultSamplingTick,
+		Levels: make(map[level.LogLevel]SamplingRule),
+		Key:    defaultSamplingKey,
+	}
+	for _, fn := range args {
+		fn(&o)
+	}
+
+	// Init printer if nil
+	if printer == nil {
+		printer = NewStdLogPrinter(nil, 0)
+	}
+
+	p := SamplingPrinter{
+		printer:  printer,
+		options:  o,
+		rule:     SamplingRule{First: first, Thereafter: thereafter},
+		counters: make(map[level.LogLevel]*samplingCounters),
+	}
+
+	// Create counters upfront, so map is never mutated and can be read without lock
+	for _, lv := range []level.LogLevel{level.Fatal, level.Error, level.Warn, level.Info, level.Debug, level.Trace} {
+		p.counters[lv] = new(samplingCounters)
+	}
+	for lv := range o.Levels {
+		if _, ok := p.counters[lv]; !ok {
+			p.counters[lv] = new(samplingCounters)
+		}
+	}
+
+	return &p
+}
+
+func (p *SamplingPrinter) Print(namespace string, lv level.LogLevel, msg string, options *logkOption.Options) {
+	counters, ok := p.counters[lv]
+	if !ok {
+		p.printer.Print(namespace, lv, msg, options)
+		return
+	}
+
+	rule, ok := p.options.Levels[lv]
+	if !ok {
+		rule = p.rule
+	}
+
+	// Count entry in counter of key
+	h := fnv.New32a()
+	_, _ = h.Write([]byte(p.options.Key(namespace, lv, msg, options)))
+	c := &counters.counters[h.Sum32()%samplingCounterSize]
+	n := c.inc(time.Now().UnixNano(), int64(p.options.Tick))
+
+	if n <= uint64(rule.First) {
+		p.printer.Print(namespace, lv, msg, options)
+		return
+	}
+
+	// Entries after the first ones stand for every thereafter-th entry
+	if rule.Thereafter > 0 && (n-uint64(rule.First))%uint64(rule.Thereafter) == 0 {
+		p.printer.Print(namespace, lv, msg, markSampled(options, uint64(rule.Thereafter)))
+		return
+	}
+
+	counters.dropped.Add(1)
+	p.dropped.Add(1)
+}
+
+// Dropped returns number of entries that are discarded by sampling
+func (p *SamplingPrinter) Dropped() uint64 {
+	return p.dropped.Load()
+}
+
+// DroppedByLevel returns number of entries that are discarded by sampling in each level
+func (p *SamplingPrinter) DroppedByLevel() map[level.LogLevel]uint64 {
+	result := make(map[level.LogLevel]uint64, len(p.counters))
+	for lv, c := range p.counters {
+		result[lv] = c.dropped.Load()
+	}
+	return result
+}
+
+func (p *SamplingPrinter) Flush() error {
+	if f, ok := p.printer.(Flusher); ok {
+		return f.Flush()
+	}
+	return nil
+}
+
+func (p *SamplingPrinter) Close() error {
+	if c, ok := p.printer.(io.Closer); ok {
+		return c.Close()
+	}
+	return nil
+}
+
+// inc increments counter and returns its value, resetting it when tick has elapsed
+func (c *samplingCounter) inc(now int64, tick int64) uint64 {
+	resetAt := c.resetAt.Load()
+	if now > resetAt {
+		// Only one goroutine resets counter, others count on the new tick
+		if c.resetAt.CompareAndSwap(resetAt, now+tick) {
+			c.count.Store(1)
+			return 1
+		}
+	}
+	return c.count.Add(1)
+}
+
+// markSampled returns copy of options marked as 1 in rate entries, as options are owned by caller. Rate of a
+// sampler upstream, e.g. SamplingLogger, is multiplied, so mark holds the overall rate
+func markSampled(options *logkOption.Options, rate uint64) *logkOption.Options {
+	if rate <= 1 {
+		return options
+	}
+
+	if prev := options.SampleRate(); prev > 0 {
+		rate *= prev
+	}
+
+	c := options.Clone()
+	logkOption.Sampled(rate)(c)
+	return c
+}
+
+func defaultSamplingKey(namespace string, _ level.LogLevel, msg string, _ *logkOption.Options) string {
+	return namespace + "\x00" + msg
+}
@@ -0,0 +1,68 @@
+package logk
+
+import (
+	"sync"
+	"testing"
+
+	"github.com/go-konsultin/logk/level"
+	logkOption "github.com/go-konsultin/logk/option"
+)
+
+// recordPrinter keeps sample rate of written entries
+type recordPrinter struct {
+	mu    sync.Mutex
+	rates []uint64
+}
+
+func (p *recordPrinter) Print(_ string, _ level.LogLevel, _ string, options *logkOption.Options) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.rates = append(p.rates, options.SampleRate())
+}
+
+func (p *recordPrinter) reset() []uint64 {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	rates := p.rates
+	p.rates = nil
+	return rates
+}
+
+func equalRates(a, b []uint64) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+func TestSamplingPrinterMarksSampled(t *testing.T) {
+	rec := &recordPrinter{}
+	p := NewSamplingPrinter(rec, 2, 3)
+
+	options := logkOption.NewOptions()
+	for i := 0; i < 8; i++ {
+		p.Print("", level.Info, "flood", options)
+	}
+
+	// The first 2 entries are written as is, then every 3rd entry stands for 3 entries
+	if got, want := rec.reset(), []uint64{0, 0, 3, 3}; !equalRates(got, want) {
+		t.Errorf("rates = %v, want %v", got, want)
+	}
+	if options.SampleRate() != 0 {
+		t.Error("options of caller are marked")
+	}
+
+	// Rate of upstream sampler is multiplied
+	sampled := logkOption.Evaluate([]logkOption.SetterFunc{logkOption.Sampled(10)})
+	for i := 0; i < 5; i++ {
+		p.Print("", level.Info, "sampled", sampled)
+	}
+	if got, want := rec.reset(), []uint64{10, 10, 30}; !equalRates(got, want) {
+		t.Errorf("rates = %v, want %v", got, want)
+	}
+}
@@ -0,0 +1,87 @@
+package logk
+
+import (
+	"fmt"
+	"sync"
+	"testing"
+
+	"github.com/go-konsultin/logk/level"
+	logkOption "github.com/go-konsultin/logk/option"
+)
+
+// sequencePrinter keeps sequence numbers of written entries by namespace
+type sequencePrinter struct {
+	mu        sync.Mutex
+	sequences map[string][]uint64
+}
+
+func (p *sequencePrinter) Print(namespace string, _ level.LogLevel, _ string, options *logkOption.Options) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.sequences[namespace] = append(p.sequences[namespace], options.Sequence())
+}
+
+// logConcurrently writes entries from goroutines, each with its own child of a logger returned by newLogger
+func logConcurrently(newLogger func(g int) Logger, goroutines, entries int) {
+	var wg sync.WaitGroup
+	for g := 0; g < goroutines; g++ {
+		wg.Add(1)
+		go func(g int) {
+			defer wg.Done()
+			l := newLogger(g).NewChild(logkOption.WithNamespace(fmt.Sprintf("g%d", g)))
+			for i := 0; i < entries; i++ {
+				l.Info("entry")
+			}
+		}(g)
+	}
+	wg.Wait()
+}
+
+// checkSequences checks that sequence numbers are strictly increasing on each goroutine and together form 1..n
+func checkSequences(t *testing.T, p *sequencePrinter, n int) {
+	t.Helper()
+
+	seen := make(map[uint64]bool, n)
+	for namespace, sequences := range p.sequences {
+		for i, seq := range sequences {
+			if i > 0 && seq <= sequences[i-1] {
+				t.Fatalf("%s: sequence %d follows %d", namespace, seq, sequences[i-1])
+			}
+			if seen[seq] {
+				t.Fatalf("sequence %d is stamped twice", seq)
+			}
+			seen[seq] = true
+		}
+	}
+
+	for seq := uint64(1); seq <= uint64(n); seq++ {
+		if !seen[seq] {
+			t.Fatalf("sequence %d is missing", seq)
+		}
+	}
+}
+
+func TestSequenceConcurrent(t *testing.T) {
+	const goroutines, entries = 8, 500
+
+	p := &sequencePrinter{sequences: make(map[string][]uint64)}
+	logger := NewStdLogger(p, logkOption.Level(level.Info), logkOption.WithSequence())
+
+	logConcurrently(func(int) Logger { return logger }, goroutines, entries)
+	checkSequences(t, p, goroutines*entries)
+}
+
+func TestGlobalSequenceConcurrent(t *testing.T) {
+	const goroutines, entries = 8, 500
+
+	start := globalSequence.Load()
+	t.Cleanup(func() { globalSequence.Store(start) })
+	globalSequence.Store(0)
+
+	// Separate loggers share global counter
+	p := &sequencePrinter{sequences: make(map[string][]uint64)}
+	logConcurrently(func(int) Logger {
+		return NewStdLogger(p, logkOption.Level(level.Info), logkOption.WithGlobalSequence())
+	}, goroutines, entries)
+	checkSequences(t, p, goroutines*entries)
+}
@@ -0,0 +1,420 @@
+package logkSink
+
+import (
+	"bytes"
+	"cmp"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/go-konsultin/logk"
+	logkExport "github.com/go-konsultin/logk/internal/export"
+	"github.com/go-konsultin/logk/level"
+	logkOption "github.com/go-konsultin/logk/option"
+)
+
+// CloudWatch Logs limits and defaults
+const (
+	// maxCloudWatchBatchSize is maximum number of events in a PutLogEvents request
+	maxCloudWatchBatchSize = 10000
+	// maxCloudWatchBatchBytes is maximum size of events in a PutLogEvents request, counting each event as size of
+	// message plus 26 bytes
+	maxCloudWatchBatchBytes = 1048576
+	// maxCloudWatchEventBytes is maximum size of a single event message
+	maxCloudWatchEventBytes  = 262144 - cloudWatchEventOverhead
+	cloudWatchEventOverhead  = 26
+	defaultCloudWatchBatch   = maxCloudWatchBatchSize
+	defaultCloudWatchTimeout = 30 * time.Second
+	cloudWatchService        = "logs"
+	cloudWatchTargetPrefix   = "Logs_20140328."
+	cloudWatchContentType    = "application/x-amz-json-1.1"
+)
+
+// CloudWatch Logs error types that are handled by printer
+const (
+	cwResourceNotFound      = "ResourceNotFoundException"
+	cwResourceAlreadyExists = "ResourceAlreadyExistsException"
+	cwInvalidSequenceToken  = "InvalidSequenceTokenException"
+	cwDataAlreadyAccepted   = "DataAlreadyAcceptedException"
+	cwThrottling            = "ThrottlingException"
+	cwServiceUnavailable    = "ServiceUnavailableException"
+)
+
+type CloudWatchOptions struct {
+	Client *http.Client
+	// Region of log group, default is AWS_REGION or AWS_DEFAULT_REGION environment variable
+	Region string
+	// Endpoint overrides URL of CloudWatch Logs API, e.g. for VPC endpoints or local emulators
+	Endpoint string
+	// Credentials is called for each request, so rotated credentials are picked up. Default reads environment
+	// variables with EnvAWSCredentials. Plug in credentials provider of AWS SDK to use roles and profiles
+	Credentials func() (AWSCredentials, error)
+	// CreateMissing creates log group and stream when they don't exist
+	CreateMissing bool
+	// BatchSize is maximum number of entries in a single request, up to 10000
+	BatchSize int
+	// BatchInterval is maximum time an entry waits before it is sent
+	BatchInterval time.Duration
+	// QueueSize limits number of batches waiting to be sent
+	QueueSize int
+	// MaxRetries is number of retries of a failed request on network errors, throttling and 5xx responses
+	MaxRetries int
+	// RetryBackoff is delay before the first retry, it is doubled on each retry
+	RetryBackoff time.Duration
+	// Overflow is applied when queue is full
+	Overflow logk.OverflowPolicy
+	// OnError is called from background goroutine when a batch can't be sent
+	OnError func(err error)
+	// Encoder formats message of events, default is JSON
+	Encoder        logk.Encoder
+	PrinterOptions []logk.PrinterOption
+}
+
+type CloudWatchOption = func(*CloudWatchOptions)
+
+func WithCloudWatchClient(c *http.Client) CloudWatchOption {
+	return func(o *CloudWatchOptions) {
+		o.Client = c
+	}
+}
+
+func WithCloudWatchRegion(region string) CloudWatchOption {
+	return func(o *CloudWatchOptions) {
+		o.Region = region
+	}
+}
+
+func WithCloudWatchEndpoint(endpoint string) CloudWatchOption {
+	return func(o *CloudWatchOptions) {
+		o.Endpoint = endpoint
+	}
+}
+
+func WithCloudWatchCredentials(fn func() (AWSCredentials, error)) CloudWatchOption {
+	return func(o *CloudWatchOptions) {
+		o.Credentials = fn
+	}
+}
+
+func WithCloudWatchCreateMissing(enabled bool) CloudWatchOption {
+	return func(o *CloudWatchOptions) {
+		o.CreateMissing = enabled
+	}
+}
+
+func WithCloudWatchBatch(size int, interval time.Duration) CloudWatchOption {
+	return func(o *CloudWatchOptions) {
+		o.BatchSize = size
+		o.BatchInterval = interval
+	}
+}
+
+func WithCloudWatchQueueSize(n int) CloudWatchOption {
+	return func(o *CloudWatchOptions) {
+		o.QueueSize = n
+	}
+}
+
+func WithCloudWatchRetry(maxRetries int, backoff time.Duration) CloudWatchOption {
+	return func(o *CloudWatchOptions) {
+		o.MaxRetries = maxRetries
+		o.RetryBackoff = backoff
+	}
+}
+
+func WithCloudWatchOverflowPolicy(p logk.OverflowPolicy) CloudWatchOption {
+	return func(o *CloudWatchOptions) {
+		o.Overflow = p
+	}
+}
+
+func WithCloudWatchOnError(fn func(err error)) CloudWatchOption {
+	return func(o *CloudWatchOptions) {
+		o.OnError = fn
+	}
+}
+
+func WithCloudWatchEncoder(enc logk.Encoder) CloudWatchOption {
+	return func(o *CloudWatchOptions) {
+		o.Encoder = enc
+	}
+}
+
+func WithCloudWatchPrinterOptions(args ...logk.PrinterOption) CloudWatchOption {
+	return func(o *CloudWatchOptions) {
+		o.PrinterOptions = append(o.PrinterOptions, args...)
+	}
+}
+
+// CloudWatchPrinter sends entries in batches to a log stream of AWS CloudWatch Logs with PutLogEvents. Requests are
+// signed with Signature Version 4, so AWS SDK is not required. Batches are sent one at a time in order, as
+// sequence token of each request is returned by the previous one
+type CloudWatchPrinter struct {
+	group    string
+	stream   string
+	endpoint string
+	options  CloudWatchOptions
+	batcher  *logkExport.Batcher
+
+	// sequenceToken is only accessed by the single dispatcher worker
+	sequenceToken string
+}
+
+// NewCloudWatchPrinter creates printer that sends entries to stream of log group. Missing group and stream are
+// created unless disabled with WithCloudWatchCreateMissing
+func NewCloudWatchPrinter(group, stream string, args ...CloudWatchOption) (*CloudWatchPrinter, error) {
+	o := CloudWatchOptions{
+		Client:        &http.Client{Timeout: defaultCloudWatchTimeout},
+		Region:        envAWSRegion(),
+		Credentials:   EnvAWSCredentials,
+		CreateMissing: true,
+		BatchSize:     defaultCloudWatchBatch,
+		BatchInterval: defaultOTLPBatchInterval,
+		QueueSize:     defaultOTLPQueueSize,
+		MaxRetries:    defaultMaxRetries,
+		RetryBackoff:  defaultRetryBackoff,
+		Encoder:       logk.NewJSONEncoder(),
+	}
+	for _, fn := range args {
+		fn(&o)
+	}
+
+	if group == "" || stream == "" {
+		return nil, fmt.Errorf("%s: cloudwatch log group and stream are required", pkgName)
+	}
+	if o.Region == "" && o.Endpoint == "" {
+		return nil, fmt.Errorf("%s: cloudwatch region is not set", pkgName)
+	}
+	o.BatchSize = min(o.BatchSize, maxCloudWatchBatchSize)
+
+	p := CloudWatchPrinter{group: group, stream: stream, endpoint: o.Endpoint, options: o}
+	if p.endpoint == "" {
+		p.endpoint = "https://logs." + o.Region + ".amazonaws.com"
+	}
+
+	// Request body other than events takes less than 1KB
+	d := logkExport.NewDispatcher(1, o.QueueSize, 0, o.Overflow, nil, p.send)
+	p.batcher = logkExport.NewBatcher(o.BatchSize, maxCloudWatchBatchBytes-1024, o.BatchInterval, p.encode, d)
+
+	return &p, nil
+}
+
+func (p *CloudWatchPrinter) Print(namespace string, lv level.LogLevel, msg string, options *logkOption.Options) {
+	entry := logk.NewEntry(namespace, lv, msg, options, p.options.PrinterOptions...)
+	message, err := p.options.Encoder.Encode(entry)
+	if err != nil {
+		return
+	}
+	message = bytes.TrimSuffix(message, []byte{'\n'})
+
+	// Truncate message that exceeds event size limit at rune boundary
+	if len(message) > maxCloudWatchEventBytes {
+		message = message[:maxCloudWatchEventBytes]
+		for len(message) > 0 {
+			if r, size := utf8.DecodeLastRune(message); r != utf8.RuneError || size != 1 {
+				break
+			}
+			message = message[:len(message)-1]
+		}
+	}
+
+	event, err := json.Marshal(cloudWatchEvent{Timestamp: entry.Time.UnixMilli(), Message: string(message)})
+	if err != nil {
+		return
+	}
+	p.batcher.Add(event)
+}
+
+// Dropped returns number of batches that are discarded by overflow policy
+func (p *CloudWatchPrinter) Dropped() uint64 {
+	return p.batcher.Dropped()
+}
+
+// Flush sends pending entries and waits until all batches are sent
+func (p *CloudWatchPrinter) Flush() error {
+	p.batcher.Flush()
+	return nil
+}
+
+// Close sends remaining entries and stops background worker
+func (p *CloudWatchPrinter) Close() error {
+	p.batcher.Close()
+	return nil
+}
+
+type cloudWatchEvent struct {
+	Timestamp int64  `json:"timestamp"`
+	Message   string `json:"message"`
+}
+
+type cloudWatchError struct {
+	Type                  string `json:"__type"`
+	Message               string `json:"message"`
+	ExpectedSequenceToken string `json:"expectedSequenceToken"`
+	status                int
+}
+
+func (e *cloudWatchError) Error() string {
+	return fmt.Sprintf("%s: cloudwatch responded with status %d: %s: %s", pkgName, e.status, e.Type, e.Message)
+}
+
+// encode joins events in chronological order, as required by PutLogEvents. Payload is completed with sequence
+// token when it's sent
+func (p *CloudWatchPrinter) encode(events [][]byte) []byte {
+	type event struct {
+		Timestamp int64 `json:"timestamp"`
+		raw       json.RawMessage
+	}
+
+	sorted := make([]event, len(events))
+	for i, e := range events {
+		_ = json.Unmarshal(e, &sorted[i])
+		sorted[i].raw = e
+	}
+	slices.SortStableFunc(sorted, func(a, b event) int {
+		return cmp.Compare(a.Timestamp, b.Timestamp)
+	})
+
+	raw := make([]json.RawMessage, len(sorted))
+	for i, e := range sorted {
+		raw[i] = e.raw
+	}
+	b, _ := json.Marshal(raw)
+	return b
+}
+
+func (p *CloudWatchPrinter) send(events []byte) {
+	err := logkExport.Retry(p.options.MaxRetries, p.options.RetryBackoff, func() error {
+		return p.putLogEvents(events)
+	})
+	if err != nil && p.options.OnError != nil {
+		p.options.OnError(err)
+	}
+}
+
+func (p *CloudWatchPrinter) putLogEvents(events []byte) error {
+	body := map[string]interface{}{
+		"logGroupName":  p.group,
+		"logStreamName": p.stream,
+		"logEvents":     json.RawMessage(events),
+	}
+	if p.sequenceToken != "" {
+		body["sequenceToken"] = p.sequenceToken
+	}
+
+	var resp struct {
+		NextSequenceToken string `json:"nextSequenceToken"`
+	}
+	err := p.call("PutLogEvents", body, &resp)
+
+	var cwErr *cloudWatchError
+	if errors.As(err, &cwErr) {
+		switch cwErr.Type {
+		case cwInvalidSequenceToken:
+			// Retry with the expected token
+			p.sequenceToken = cwErr.ExpectedSequenceToken
+			return logkExport.Retryable(err)
+		case cwDataAlreadyAccepted:
+			p.sequenceToken = cwErr.ExpectedSequenceToken
+			return nil
+		case cwResourceNotFound:
+			if !p.options.CreateMissing {
+				return err
+			}
+			if cErr := p.createMissing(); cErr != nil {
+				return cErr
+			}
+			p.sequenceToken = ""
+			return logkExport.Retryable(err)
+		}
+	}
+	if err != nil {
+		return err
+	}
+
+	p.sequenceToken = resp.NextSequenceToken
+	return nil
+}
+
+// createMissing creates log group and stream, ignoring those that already exist
+func (p *CloudWatchPrinter) createMissing() error {
+	for _, action := range []string{"CreateLogGroup", "CreateLogStream"} {
+		body := map[string]interface{}{"logGroupName": p.group}
+		if action == "CreateLogStream" {
+			body["logStreamName"] = p.stream
+		}
+
+		var cwErr *cloudWatchError
+		if err := p.call(action, body, nil); err != nil && !(errors.As(err, &cwErr) && cwErr.Type == cwResourceAlreadyExists) {
+			return err
+		}
+	}
+	return nil
+}
+
+// call sends signed request of action and decodes response into result. Network errors, throttling and 5xx
+// responses are returned as retryable errors
+func (p *CloudWatchPrinter) call(action string, body interface{}, result interface{}) error {
+	payload, err := json.Marshal(body)
+	if err != nil {
+		return err
+	}
+
+	creds, err := p.options.Credentials()
+	if err != nil {
+		return fmt.Errorf("%s: cloudwatch credentials: %w", pkgName, err)
+	}
+
+	req, err := http.NewRequest(http.MethodPost, p.endpoint, bytes.NewReader(payload))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", cloudWatchContentType)
+	req.Header.Set("X-Amz-Target", cloudWatchTargetPrefix+action)
+	signV4(req, payload, creds, p.options.Region, cloudWatchService, time.Now())
+
+	resp, err := p.options.Client.Do(req)
+	if err != nil {
+		return logkExport.Retryable(err)
+	}
+	defer resp.Body.Close()
+
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return logkExport.Retryable(err)
+	}
+
+	if resp.StatusCode >= 300 {
+		cwErr := cloudWatchError{status: resp.StatusCode}
+		_ = json.Unmarshal(data, &cwErr)
+		cwErr.Type = cloudWatchErrorType(cwErr.Type)
+		if cwErr.Type == "" {
+			cwErr.Type = strconv.Itoa(resp.StatusCode)
+		}
+
+		if resp.StatusCode >= 500 || cwErr.Type == cwThrottling || cwErr.Type == cwServiceUnavailable {
+			return logkExport.Retryable(&cwErr)
+		}
+		return &cwErr
+	}
+
+	if result != nil && len(data) > 0 {
+		return json.Unmarshal(data, result)
+	}
+	return nil
+}
+
+// cloudWatchErrorType strips namespace from error type, e.g. "com.amazonaws.logs#ThrottlingException"
+func cloudWatchErrorType(t string) string {
+	if i := strings.LastIndexByte(t, '#'); i >= 0 {
+		return t[i+1:]
+	}
+	return t
+}
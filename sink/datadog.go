@@ -0,0 +1,354 @@
+package logkSink
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/go-konsultin/logk"
+	logkExport "github.com/go-konsultin/logk/internal/export"
+	"github.com/go-konsultin/logk/level"
+	logkOption "github.com/go-konsultin/logk/option"
+)
+
+// Datadog logs intake limits and defaults
+const (
+	defaultDatadogSite   = "datadoghq.com"
+	defaultDatadogSource = "go"
+	// maxDatadogBatchSize is maximum number of logs in a single request
+	maxDatadogBatchSize = 1000
+	// maxDatadogBatchBytes is maximum size of uncompressed request
+	maxDatadogBatchBytes = 5 << 20
+	datadogAPIKeyHeader  = "DD-API-KEY"
+)
+
+// datadogStatus maps level to Datadog log status
+var datadogStatus = map[level.LogLevel]string{
+	level.Fatal: "critical",
+	level.Error: "error",
+	level.Warn:  "warning",
+	level.Info:  "info",
+	level.Debug: "debug",
+	level.Trace: "debug",
+}
+
+type DatadogOptions struct {
+	Client *http.Client
+	// Site is Datadog site of organization, e.g. datadoghq.eu or us5.datadoghq.com
+	Site string
+	// Endpoint overrides URL of logs intake, e.g. for proxies
+	Endpoint string
+	// Source is written as ddsource, which selects integration pipeline, default is "go"
+	Source string
+	// Service is written as service. Default is namespace of entry
+	Service  string
+	Hostname string
+	// Tags are added to ddtags of all logs, e.g. "env:prod"
+	Tags []string
+	// TagKeys are metadata keys whose values are written as tags. Nested keys are joined by dot, e.g. "http.method"
+	TagKeys []string
+	// Gzip compresses requests, default is true
+	Gzip bool
+	// BatchSize is maximum number of entries in a single request, up to 1000
+	BatchSize int
+	// BatchInterval is maximum time an entry waits before it is sent
+	BatchInterval time.Duration
+	// QueueSize limits number of batches waiting to be sent
+	QueueSize int
+	// MaxRetries is number of retries of a failed request on network errors, 429 and 5xx responses
+	MaxRetries int
+	// RetryBackoff is delay before the first retry, it is doubled on each retry
+	RetryBackoff time.Duration
+	// Overflow is applied when queue is full
+	Overflow logk.OverflowPolicy
+	// OnError is called from background goroutine when a batch can't be sent
+	OnError        func(err error)
+	PrinterOptions []logk.PrinterOption
+}
+
+type DatadogOption = func(*DatadogOptions)
+
+func WithDatadogClient(c *http.Client) DatadogOption {
+	return func(o *DatadogOptions) {
+		o.Client = c
+	}
+}
+
+func WithDatadogSite(site string) DatadogOption {
+	return func(o *DatadogOptions) {
+		o.Site = site
+	}
+}
+
+func WithDatadogEndpoint(endpoint string) DatadogOption {
+	return func(o *DatadogOptions) {
+		o.Endpoint = endpoint
+	}
+}
+
+func WithDatadogSource(source string) DatadogOption {
+	return func(o *DatadogOptions) {
+		o.Source = source
+	}
+}
+
+func WithDatadogService(service string) DatadogOption {
+	return func(o *DatadogOptions) {
+		o.Service = service
+	}
+}
+
+func WithDatadogHostname(hostname string) DatadogOption {
+	return func(o *DatadogOptions) {
+		o.Hostname = hostname
+	}
+}
+
+func WithDatadogTags(tags ...string) DatadogOption {
+	return func(o *DatadogOptions) {
+		o.Tags = append(o.Tags, tags...)
+	}
+}
+
+func WithDatadogTagKeys(keys ...string) DatadogOption {
+	return func(o *DatadogOptions) {
+		o.TagKeys = append(o.TagKeys, keys...)
+	}
+}
+
+func WithDatadogGzip(enabled bool) DatadogOption {
+	return func(o *DatadogOptions) {
+		o.Gzip = enabled
+	}
+}
+
+func WithDatadogBatch(size int, interval time.Duration) DatadogOption {
+	return func(o *DatadogOptions) {
+		o.BatchSize = size
+		o.BatchInterval = interval
+	}
+}
+
+func WithDatadogQueueSize(n int) DatadogOption {
+	return func(o *DatadogOptions) {
+		o.QueueSize = n
+	}
+}
+
+func WithDatadogRetry(maxRetries int, backoff time.Duration) DatadogOption {
+	return func(o *DatadogOptions) {
+		o.MaxRetries = maxRetries
+		o.RetryBackoff = backoff
+	}
+}
+
+func WithDatadogOverflowPolicy(p logk.OverflowPolicy) DatadogOption {
+	return func(o *DatadogOptions) {
+		o.Overflow = p
+	}
+}
+
+func WithDatadogOnError(fn func(err error)) DatadogOption {
+	return func(o *DatadogOptions) {
+		o.OnError = fn
+	}
+}
+
+func WithDatadogPrinterOptions(args ...logk.PrinterOption) DatadogOption {
+	return func(o *DatadogOptions) {
+		o.PrinterOptions = append(o.PrinterOptions, args...)
+	}
+}
+
+// DatadogPrinter sends entries in batches to Datadog logs intake API v2. Level is mapped to status, namespace to
+// service and logger.name, error and stack trace to error.message, error.kind and error.stack, and trace and span id
+// to dd.trace_id and dd.span_id, so logs are correlated with traces. Other fields and metadata are written as
+// attributes
+type DatadogPrinter struct {
+	url     string
+	header  http.Header
+	options DatadogOptions
+	batcher *logkExport.Batcher
+}
+
+// NewDatadogPrinter creates printer that sends entries authenticated with apiKey
+func NewDatadogPrinter(apiKey string, args ...DatadogOption) *DatadogPrinter {
+	if apiKey == "" {
+		panic(fmt.Errorf("%s: datadog api key is empty", pkgName))
+	}
+
+	o := DatadogOptions{
+		Client:        &http.Client{Timeout: defaultHTTPTimeout},
+		Site:          defaultDatadogSite,
+		Source:        defaultDatadogSource,
+		Gzip:          true,
+		BatchSize:     maxDatadogBatchSize,
+		BatchInterval: defaultOTLPBatchInterval,
+		QueueSize:     defaultOTLPQueueSize,
+		MaxRetries:    defaultMaxRetries,
+		RetryBackoff:  defaultRetryBackoff,
+	}
+	o.Hostname, _ = os.Hostname()
+	for _, fn := range args {
+		fn(&o)
+	}
+	o.BatchSize = min(o.BatchSize, maxDatadogBatchSize)
+
+	p := DatadogPrinter{url: o.Endpoint, header: make(http.Header), options: o}
+	if p.url == "" {
+		p.url = "https://http-intake.logs." + o.Site + "/api/v2/logs"
+	}
+	p.header.Set(datadogAPIKeyHeader, apiKey)
+	p.header.Set("Content-Type", "application/json")
+	if o.Gzip {
+		p.header.Set("Content-Encoding", "gzip")
+	}
+
+	// Array brackets and separators take a byte per entry at most
+	d := logkExport.NewDispatcher(defaultHTTPConcurrency, o.QueueSize, 0, o.Overflow, nil, p.send)
+	p.batcher = logkExport.NewBatcher(o.BatchSize, maxDatadogBatchBytes-maxDatadogBatchSize-2, o.BatchInterval, p.encode, d)
+
+	return &p
+}
+
+func (p *DatadogPrinter) Print(namespace string, lv level.LogLevel, msg string, options *logkOption.Options) {
+	entry := logk.NewEntry(namespace, lv, msg, options, p.options.PrinterOptions...)
+	log, err := json.Marshal(p.newLog(entry))
+	if err != nil {
+		return
+	}
+	p.batcher.Add(log)
+}
+
+// Dropped returns number of batches that are discarded by overflow policy
+func (p *DatadogPrinter) Dropped() uint64 {
+	return p.batcher.Dropped()
+}
+
+// Flush sends pending entries and waits until all batches are sent
+func (p *DatadogPrinter) Flush() error {
+	p.batcher.Flush()
+	return nil
+}
+
+// Close sends remaining entries and stops background workers
+func (p *DatadogPrinter) Close() error {
+	p.batcher.Close()
+	return nil
+}
+
+func (p *DatadogPrinter) newLog(entry *logk.Entry) map[string]interface{} {
+	log := entry.Fields()
+	for _, k := range []string{logkOption.TimeKey, logkOption.LevelKey, logkOption.NamespaceKey,
+		logkOption.ErrorKey, logkOption.StackTraceKey, logkOption.TraceIdKey, logkOption.SpanIdKey} {
+		delete(log, k)
+	}
+
+	log["message"] = entry.Message
+	log["timestamp"] = entry.Time.UnixMilli()
+	log["status"] = datadogStatus[entry.Level]
+	log["ddsource"] = p.options.Source
+	if p.options.Hostname != "" {
+		log["hostname"] = p.options.Hostname
+	}
+
+	service := p.options.Service
+	if service == "" {
+		service = entry.Namespace
+	}
+	if service != "" {
+		log["service"] = service
+	}
+
+	if entry.Namespace != "" {
+		log["logger"] = map[string]interface{}{"name": entry.Namespace}
+	}
+
+	if entry.Error != nil || entry.StackTrace != "" {
+		e := make(map[string]interface{})
+		if entry.Error != nil {
+			e["message"] = entry.Error.Error()
+			e["kind"] = fmt.Sprintf("%T", entry.Error)
+		}
+		if entry.StackTrace != "" {
+			e["stack"] = entry.StackTrace
+		}
+		log["error"] = e
+	}
+
+	if entry.TraceId != "" {
+		dd := map[string]interface{}{"trace_id": datadogId(entry.TraceId)}
+		if entry.SpanId != "" {
+			dd["span_id"] = datadogId(entry.SpanId)
+		}
+		log["dd"] = dd
+	}
+
+	if tags := p.tags(entry); tags != "" {
+		log["ddtags"] = tags
+	}
+	return log
+}
+
+// tags joins static tags and tags from metadata keys
+func (p *DatadogPrinter) tags(entry *logk.Entry) string {
+	tags := p.options.Tags
+	if len(p.options.TagKeys) > 0 && len(entry.Metadata) > 0 {
+		metadata := make(map[string]interface{})
+		flattenMetadata(metadata, "", entry.Metadata)
+
+		tags = append([]string(nil), tags...)
+		for _, k := range p.options.TagKeys {
+			if v, ok := metadata[k]; ok && v != nil {
+				tags = append(tags, k+":"+fmt.Sprint(v))
+			}
+		}
+	}
+	return strings.Join(tags, ",")
+}
+
+// encode joins logs into JSON array, and compresses it if Gzip is set
+func (p *DatadogPrinter) encode(logs [][]byte) []byte {
+	var buf bytes.Buffer
+	buf.WriteByte('[')
+	buf.Write(bytes.Join(logs, []byte{','}))
+	buf.WriteByte(']')
+	if !p.options.Gzip {
+		return buf.Bytes()
+	}
+
+	var compressed bytes.Buffer
+	zw := gzip.NewWriter(&compressed)
+	_, _ = zw.Write(buf.Bytes())
+	_ = zw.Close()
+	return compressed.Bytes()
+}
+
+func (p *DatadogPrinter) send(payload []byte) {
+	err := logkExport.Retry(p.options.MaxRetries, p.options.RetryBackoff, func() error {
+		return post(p.options.Client, p.url, p.header, payload)
+	})
+	if err != nil && p.options.OnError != nil {
+		p.options.OnError(err)
+	}
+}
+
+// datadogId converts W3C trace or span id in hex to decimal of its lower 64 bits, as Datadog correlates logs with
+// traces by 64-bit ids. Ids that are not hex are kept as is
+func datadogId(id string) string {
+	lower := id
+	if len(lower) > 16 {
+		lower = lower[len(lower)-16:]
+	}
+	n, err := strconv.ParseUint(lower, 16, 64)
+	if err != nil {
+		return id
+	}
+	return strconv.FormatUint(n, 10)
+}
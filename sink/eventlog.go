@@ -0,0 +1,74 @@
+package logkSink
+
+import (
+	"strings"
+
+	"github.com/go-konsultin/logk"
+	"github.com/go-konsultin/logk/level"
+	logkOption "github.com/go-konsultin/logk/option"
+)
+
+// defaultEventIds are event id of each level. They are within 1 to 1000, so they can be used with a source that is
+// registered with EventCreate.exe message file
+var defaultEventIds = map[level.LogLevel]uint32{
+	level.Fatal: 1,
+	level.Error: 2,
+	level.Warn:  3,
+	level.Info:  4,
+	level.Debug: 5,
+	level.Trace: 6,
+}
+
+// EventLogOptions are options of Windows Event Log printer
+type EventLogOptions struct {
+	// EventIds maps level to event id
+	EventIds       map[level.LogLevel]uint32
+	PrinterOptions []logk.PrinterOption
+}
+
+type EventLogOption = func(*EventLogOptions)
+
+// WithEventId sets event id of entries in level
+func WithEventId(lv level.LogLevel, id uint32) EventLogOption {
+	return func(o *EventLogOptions) {
+		o.EventIds[lv] = id
+	}
+}
+
+func WithEventLogPrinterOptions(args ...logk.PrinterOption) EventLogOption {
+	return func(o *EventLogOptions) {
+		o.PrinterOptions = append(o.PrinterOptions, args...)
+	}
+}
+
+func evaluateEventLogOptions(args []EventLogOption) EventLogOptions {
+	o := EventLogOptions{EventIds: make(map[level.LogLevel]uint32, len(defaultEventIds))}
+	for lv, id := range defaultEventIds {
+		o.EventIds[lv] = id
+	}
+	for _, fn := range args {
+		fn(&o)
+	}
+	return o
+}
+
+// formatEvent formats entry as event message, with namespace prefix and fields on the following lines
+func formatEvent(namespace string, lv level.LogLevel, msg string, options *logkOption.Options,
+	po []logk.PrinterOption) string {
+	entry := logk.NewEntry(namespace, lv, msg, options, po...)
+
+	var sb strings.Builder
+	if entry.Namespace != "" {
+		sb.WriteString("(" + entry.Namespace + ") ")
+	}
+	sb.WriteString(entry.Message)
+
+	params := syslogParams(entry)
+	for _, k := range sortedKeys(params) {
+		sb.WriteString("\r\n")
+		sb.WriteString(k)
+		sb.WriteString(": ")
+		sb.WriteString(syslogParamValue(params[k]))
+	}
+	return sb.String()
+}
@@ -0,0 +1,25 @@
+//go:build !windows
+
+package logkSink
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/go-konsultin/logk/level"
+	logkOption "github.com/go-konsultin/logk/option"
+)
+
+// EventLogPrinter is only available on Windows
+type EventLogPrinter struct{}
+
+// NewEventLogPrinter returns error, as Windows Event Log is only available on Windows
+func NewEventLogPrinter(source string, args ...EventLogOption) (*EventLogPrinter, error) {
+	return nil, fmt.Errorf("%s: %w", pkgName, errors.New("windows event log is only available on windows"))
+}
+
+func (p *EventLogPrinter) Print(string, level.LogLevel, string, *logkOption.Options) {}
+
+func (p *EventLogPrinter) Close() error {
+	return nil
+}
@@ -0,0 +1,106 @@
+//go:build windows
+
+package logkSink
+
+import (
+	"fmt"
+	"sync"
+	"syscall"
+	"unsafe"
+
+	"github.com/go-konsultin/logk/level"
+	logkOption "github.com/go-konsultin/logk/option"
+)
+
+// Event types of ReportEvent
+const (
+	eventLogErrorType       = 0x0001
+	eventLogWarningType     = 0x0002
+	eventLogInformationType = 0x0004
+)
+
+var (
+	advapi32                  = syscall.NewLazyDLL("advapi32.dll")
+	procRegisterEventSource   = advapi32.NewProc("RegisterEventSourceW")
+	procDeregisterEventSource = advapi32.NewProc("DeregisterEventSource")
+	procReportEvent           = advapi32.NewProc("ReportEventW")
+)
+
+// EventLogPrinter writes entries to Windows Event Log. Fatal and Error are written as error events, Warn as warning
+// and other levels as information events.
+// Source should be registered beforehand, e.g. with New-EventLog -LogName Application -Source <source>, otherwise
+// Event Viewer shows the message with a notice that the event description is not found
+type EventLogPrinter struct {
+	options EventLogOptions
+
+	mu     sync.Mutex
+	handle uintptr
+}
+
+// NewEventLogPrinter opens event log of source
+func NewEventLogPrinter(source string, args ...EventLogOption) (*EventLogPrinter, error) {
+	name, err := syscall.UTF16PtrFromString(source)
+	if err != nil {
+		return nil, fmt.Errorf("%s: invalid event source: %w", pkgName, err)
+	}
+
+	handle, _, err := procRegisterEventSource.Call(0, uintptr(unsafe.Pointer(name)))
+	if handle == 0 {
+		return nil, fmt.Errorf("%s: failed to register event source: %w", pkgName, err)
+	}
+
+	return &EventLogPrinter{handle: handle, options: evaluateEventLogOptions(args)}, nil
+}
+
+func (p *EventLogPrinter) Print(namespace string, lv level.LogLevel, msg string, options *logkOption.Options) {
+	message, err := syscall.UTF16PtrFromString(formatEvent(namespace, lv, msg, options, p.options.PrinterOptions))
+	if err != nil {
+		return
+	}
+
+	var eventType uint16
+	switch {
+	case lv <= level.Error:
+		eventType = eventLogErrorType
+	case lv == level.Warn:
+		eventType = eventLogWarningType
+	default:
+		eventType = eventLogInformationType
+	}
+
+	strs := []*uint16{message}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.handle == 0 {
+		return
+	}
+
+	_, _, _ = procReportEvent.Call(
+		p.handle,
+		uintptr(eventType),
+		0,
+		uintptr(p.options.EventIds[lv]),
+		0,
+		uintptr(len(strs)),
+		0,
+		uintptr(unsafe.Pointer(&strs[0])),
+		0)
+}
+
+// Close closes event log
+func (p *EventLogPrinter) Close() error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.handle == 0 {
+		return nil
+	}
+
+	ok, _, err := procDeregisterEventSource.Call(p.handle)
+	p.handle = 0
+	if ok == 0 {
+		return fmt.Errorf("%s: failed to deregister event source: %w", pkgName, err)
+	}
+	return nil
+}
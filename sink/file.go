@@ -0,0 +1,291 @@
+package logkSink
+
+import (
+	"compress/gzip"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Rotated file naming
+const (
+	backupTimeLayout = "20060102T150405.000000000"
+	compressedExt    = ".gz"
+)
+
+type FileOptions struct {
+	// MaxSize rotates file when writing would exceed it in bytes. Zero means unlimited
+	MaxSize int64
+	// MaxAge rotates file when it has been open longer than it. Zero means unlimited
+	MaxAge time.Duration
+	// MaxBackups limits number of retained rotated files. Zero means all files are retained
+	MaxBackups int
+	// Compress gzips rotated files
+	Compress bool
+	// Perm is permission of created files
+	Perm os.FileMode
+}
+
+type FileOption = func(*FileOptions)
+
+func WithMaxSize(bytes int64) FileOption {
+	return func(o *FileOptions) {
+		o.MaxSize = bytes
+	}
+}
+
+func WithMaxAge(d time.Duration) FileOption {
+	return func(o *FileOptions) {
+		o.MaxAge = d
+	}
+}
+
+func WithMaxBackups(n int) FileOption {
+	return func(o *FileOptions) {
+		o.MaxBackups = n
+	}
+}
+
+func WithCompress(enabled bool) FileOption {
+	return func(o *FileOptions) {
+		o.Compress = enabled
+	}
+}
+
+func WithPerm(perm os.FileMode) FileOption {
+	return func(o *FileOptions) {
+		o.Perm = perm
+	}
+}
+
+// File is an io.WriteCloser that writes to a file and rotates it by size and age. Rotated files are renamed with
+// a timestamp suffix, e.g. app-20240102T150405.000000000.log, and optionally compressed.
+// It can be used as output of any printer, e.g. logk.NewJSONPrinter(file)
+type File struct {
+	path    string
+	options FileOptions
+
+	mu       sync.Mutex
+	file     *os.File
+	size     int64
+	openedAt time.Time
+
+	// compressWg waits for background compression
+	compressWg sync.WaitGroup
+}
+
+// NewFile opens file at path for appending, creating it and its directory if they don't exist
+func NewFile(path string, args ...FileOption) (*File, error) {
+	o := FileOptions{Perm: 0644}
+	for _, fn := range args {
+		fn(&o)
+	}
+
+	f := File{path: path, options: o}
+	if err := f.open(); err != nil {
+		return nil, err
+	}
+	return &f, nil
+}
+
+func (f *File) Write(p []byte) (int, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if f.file == nil {
+		return 0, fmt.Errorf("%s: file %s is closed", pkgName, f.path)
+	}
+
+	if f.shouldRotate(int64(len(p))) {
+		if err := f.rotate(); err != nil {
+			return 0, err
+		}
+	}
+
+	n, err := f.file.Write(p)
+	f.size += int64(n)
+	return n, err
+}
+
+// Rotate closes current file, renames it as backup and opens a new file
+func (f *File) Rotate() error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.rotate()
+}
+
+// Flush commits written content to storage
+func (f *File) Flush() error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if f.file == nil {
+		return nil
+	}
+	return f.file.Sync()
+}
+
+// Close closes file and waits for background compression of rotated files
+func (f *File) Close() error {
+	f.mu.Lock()
+	var err error
+	if f.file != nil {
+		err = f.file.Close()
+		f.file = nil
+	}
+	f.mu.Unlock()
+
+	f.compressWg.Wait()
+	return err
+}
+
+func (f *File) shouldRotate(n int64) bool {
+	if f.options.MaxSize > 0 && f.size > 0 && f.size+n > f.options.MaxSize {
+		return true
+	}
+	return f.options.MaxAge > 0 && time.Since(f.openedAt) >= f.options.MaxAge
+}
+
+func (f *File) open() error {
+	if err := os.MkdirAll(filepath.Dir(f.path), 0755); err != nil {
+		return fmt.Errorf("%s: failed to create directory: %w", pkgName, err)
+	}
+
+	file, err := os.OpenFile(f.path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, f.options.Perm)
+	if err != nil {
+		return fmt.Errorf("%s: failed to open file: %w", pkgName, err)
+	}
+
+	info, err := file.Stat()
+	if err != nil {
+		_ = file.Close()
+		return fmt.Errorf("%s: failed to stat file: %w", pkgName, err)
+	}
+
+	f.file = file
+	f.size = info.Size()
+	f.openedAt = time.Now()
+	return nil
+}
+
+func (f *File) rotate() error {
+	if f.file != nil {
+		if err := f.file.Close(); err != nil {
+			return fmt.Errorf("%s: failed to close file: %w", pkgName, err)
+		}
+		f.file = nil
+	}
+
+	// Rename current file as backup
+	backup := f.backupName(time.Now())
+	if err := os.Rename(f.path, backup); err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("%s: failed to rename file: %w", pkgName, err)
+	}
+
+	if err := f.open(); err != nil {
+		return err
+	}
+
+	// Compress and remove old backups in background, so writes are not blocked
+	f.compressWg.Add(1)
+	go func() {
+		defer f.compressWg.Done()
+		if f.options.Compress {
+			_ = compressFile(backup)
+		}
+		f.prune()
+	}()
+
+	return nil
+}
+
+func (f *File) backupName(t time.Time) string {
+	ext := filepath.Ext(f.path)
+	base := strings.TrimSuffix(f.path, ext)
+	return fmt.Sprintf("%s-%s%s", base, t.Format(backupTimeLayout), ext)
+}
+
+// prune removes the oldest backups beyond MaxBackups. Backups are files named by backupName, compressed or not,
+// so files of other sinks in the same directory with a similar name, e.g. app-audit.log, are left alone
+func (f *File) prune() {
+	if f.options.MaxBackups <= 0 {
+		return
+	}
+
+	entries, err := os.ReadDir(filepath.Dir(f.path))
+	if err != nil {
+		return
+	}
+
+	ext := filepath.Ext(f.path)
+	prefix := strings.TrimSuffix(filepath.Base(f.path), ext) + "-"
+
+	type backup struct {
+		path string
+		time time.Time
+	}
+
+	var backups []backup
+	for _, e := range entries {
+		if e.IsDir() {
+			continue
+		}
+
+		name := e.Name()
+		middle, ok := strings.CutPrefix(name, prefix)
+		if !ok {
+			continue
+		}
+		middle = strings.TrimSuffix(middle, compressedExt)
+		if middle, ok = strings.CutSuffix(middle, ext); !ok {
+			continue
+		}
+
+		t, err := time.Parse(backupTimeLayout, middle)
+		if err != nil {
+			continue
+		}
+		backups = append(backups, backup{path: filepath.Join(filepath.Dir(f.path), name), time: t})
+	}
+
+	sort.SliceStable(backups, func(i, j int) bool {
+		return backups[i].time.Before(backups[j].time)
+	})
+	for len(backups) > f.options.MaxBackups {
+		_ = os.Remove(backups[0].path)
+		backups = backups[1:]
+	}
+}
+
+func compressFile(path string) error {
+	src, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer src.Close()
+
+	dst, err := os.Create(path + compressedExt)
+	if err != nil {
+		return err
+	}
+
+	zw := gzip.NewWriter(dst)
+	if _, err = io.Copy(zw, src); err == nil {
+		err = zw.Close()
+	}
+
+	if cErr := dst.Close(); err == nil {
+		err = cErr
+	}
+
+	if err != nil {
+		_ = os.Remove(path + compressedExt)
+		return err
+	}
+	return os.Remove(path)
+}
@@ -0,0 +1,62 @@
+package logkSink
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+	"time"
+)
+
+func TestFilePruneKeepsOtherFiles(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "app.log")
+	f := File{path: path, options: FileOptions{MaxBackups: 2}}
+
+	start := time.Date(2024, 1, 2, 15, 4, 5, 0, time.UTC)
+	backup := func(i int) string {
+		return filepath.Base(f.backupName(start.Add(time.Duration(i) * time.Hour)))
+	}
+
+	names := []string{
+		"app.log",
+		backup(0),
+		backup(1) + compressedExt,
+		backup(2),
+		backup(3) + compressedExt,
+		// Files of another sink in the same directory
+		"app-audit.log",
+		"app-audit-" + start.Format(backupTimeLayout) + ".log",
+		"app-notes.txt",
+	}
+	for _, name := range names {
+		if err := os.WriteFile(filepath.Join(dir, name), nil, 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	f.prune()
+
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got []string
+	for _, e := range entries {
+		got = append(got, e.Name())
+	}
+
+	want := []string{
+		"app.log",
+		backup(2),
+		backup(3) + compressedExt,
+		"app-audit.log",
+		"app-audit-" + start.Format(backupTimeLayout) + ".log",
+		"app-notes.txt",
+	}
+	slices.Sort(got)
+	slices.Sort(want)
+	if !slices.Equal(got, want) {
+		t.Errorf("files = %v, want %v", got, want)
+	}
+}
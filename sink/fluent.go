@@ -0,0 +1,470 @@
+package logkSink
+
+import (
+	"bufio"
+	"crypto/rand"
+	"crypto/tls"
+	"encoding/base64"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"net"
+	"strings"
+	"time"
+
+	"github.com/go-konsultin/logk"
+	logkExport "github.com/go-konsultin/logk/internal/export"
+	"github.com/go-konsultin/logk/level"
+	logkOption "github.com/go-konsultin/logk/option"
+)
+
+// Default Fluent printer options
+const (
+	defaultFluentTag          = "logk"
+	defaultFluentBatchSize    = 512
+	defaultFluentTimeout      = 10 * time.Second
+	defaultFluentAckTimeout   = 30 * time.Second
+	fluentEventTimeExtType    = 0x00
+	fluentChunkIdSize         = 16
+	maxFluentAckResponseBytes = 1024
+)
+
+type FluentOptions struct {
+	// Tag returns tag of entry, which Fluentd and Fluent Bit route by. Default is tag prefix followed by namespace,
+	// e.g. "logk.api.users"
+	Tag func(entry *logk.Entry) string
+	// TagPrefix is prefix of tags created by default Tag, default is "logk"
+	TagPrefix string
+	// RequireAck makes server acknowledge each chunk, so chunks that were lost with broken connection are resent
+	RequireAck bool
+	// TLS enables TLS on TCP connections, as accepted by secure forward input
+	TLS *tls.Config
+	// Timeout limits dialing and writing
+	Timeout time.Duration
+	// AckTimeout limits waiting for acknowledgement of a chunk
+	AckTimeout time.Duration
+	// BatchSize is maximum number of entries in a single forward message
+	BatchSize int
+	// BatchInterval is maximum time an entry waits before it is sent
+	BatchInterval time.Duration
+	// QueueSize limits number of batches waiting to be sent
+	QueueSize int
+	// MaxRetries is number of retries of a failed message, reconnecting before each one
+	MaxRetries int
+	// RetryBackoff is delay before the first retry, it is doubled on each retry
+	RetryBackoff time.Duration
+	// Overflow is applied when queue is full
+	Overflow logk.OverflowPolicy
+	// OnError is called from background goroutine when a batch can't be sent
+	OnError        func(err error)
+	PrinterOptions []logk.PrinterOption
+}
+
+type FluentOption = func(*FluentOptions)
+
+func WithFluentTag(fn func(entry *logk.Entry) string) FluentOption {
+	return func(o *FluentOptions) {
+		o.Tag = fn
+	}
+}
+
+func WithFluentTagPrefix(prefix string) FluentOption {
+	return func(o *FluentOptions) {
+		o.TagPrefix = prefix
+	}
+}
+
+func WithFluentRequireAck(enabled bool) FluentOption {
+	return func(o *FluentOptions) {
+		o.RequireAck = enabled
+	}
+}
+
+func WithFluentTLS(config *tls.Config) FluentOption {
+	return func(o *FluentOptions) {
+		o.TLS = config
+	}
+}
+
+func WithFluentTimeout(timeout, ackTimeout time.Duration) FluentOption {
+	return func(o *FluentOptions) {
+		o.Timeout = timeout
+		o.AckTimeout = ackTimeout
+	}
+}
+
+func WithFluentBatch(size int, interval time.Duration) FluentOption {
+	return func(o *FluentOptions) {
+		o.BatchSize = size
+		o.BatchInterval = interval
+	}
+}
+
+func WithFluentQueueSize(n int) FluentOption {
+	return func(o *FluentOptions) {
+		o.QueueSize = n
+	}
+}
+
+func WithFluentRetry(maxRetries int, backoff time.Duration) FluentOption {
+	return func(o *FluentOptions) {
+		o.MaxRetries = maxRetries
+		o.RetryBackoff = backoff
+	}
+}
+
+func WithFluentOverflowPolicy(p logk.OverflowPolicy) FluentOption {
+	return func(o *FluentOptions) {
+		o.Overflow = p
+	}
+}
+
+func WithFluentOnError(fn func(err error)) FluentOption {
+	return func(o *FluentOptions) {
+		o.OnError = fn
+	}
+}
+
+func WithFluentPrinterOptions(args ...logk.PrinterOption) FluentOption {
+	return func(o *FluentOptions) {
+		o.PrinterOptions = append(o.PrinterOptions, args...)
+	}
+}
+
+// FluentPrinter sends entries to Fluentd or Fluent Bit with Forward protocol, e.g. to a Fluent Bit sidecar, so
+// containers don't depend on scraping stdout. Entries are batched into forward messages per tag, encoded with
+// MessagePack, with time as EventTime and fields of entry as record.
+// See https://github.com/fluent/fluentd/wiki/Forward-Protocol-Specification-v1
+type FluentPrinter struct {
+	network string
+	addr    string
+	options FluentOptions
+	batcher *logkExport.Batcher
+
+	// conn and reader are only accessed by the single dispatcher worker, and by Close after it is stopped
+	conn   net.Conn
+	reader *bufio.Reader
+}
+
+// NewFluentPrinter creates printer that sends entries to a forward input. Network is "tcp" or "unix", addr is
+// host:port, e.g. localhost:24224, or path of unix socket. Connection is established lazily and re-established
+// when it breaks
+func NewFluentPrinter(network, addr string, args ...FluentOption) (*FluentPrinter, error) {
+	o := FluentOptions{
+		TagPrefix:     defaultFluentTag,
+		Timeout:       defaultFluentTimeout,
+		AckTimeout:    defaultFluentAckTimeout,
+		BatchSize:     defaultFluentBatchSize,
+		BatchInterval: defaultOTLPBatchInterval,
+		QueueSize:     defaultOTLPQueueSize,
+		MaxRetries:    defaultMaxRetries,
+		RetryBackoff:  defaultRetryBackoff,
+	}
+	for _, fn := range args {
+		fn(&o)
+	}
+
+	switch network {
+	case "tcp", "tcp4", "tcp6", "unix":
+	default:
+		return nil, fmt.Errorf("%s: unknown fluent network %q", pkgName, network)
+	}
+
+	if addr == "" {
+		return nil, fmt.Errorf("%s: fluent address is empty", pkgName)
+	}
+
+	if o.Tag == nil {
+		o.Tag = fluentTagFunc(o.TagPrefix)
+	}
+
+	p := FluentPrinter{network: network, addr: addr, options: o}
+	d := logkExport.NewDispatcher(1, o.QueueSize, 0, o.Overflow, nil, p.send)
+	p.batcher = logkExport.NewBatcher(o.BatchSize, 0, o.BatchInterval, p.encode, d)
+
+	return &p, nil
+}
+
+func (p *FluentPrinter) Print(namespace string, lv level.LogLevel, msg string, options *logkOption.Options) {
+	entry := logk.NewEntry(namespace, lv, msg, options, p.options.PrinterOptions...)
+
+	// Time is sent as EventTime of entry
+	record := entry.Fields()
+	delete(record, logkOption.TimeKey)
+
+	// Item holds tag followed by encoded entry [time, record], so encode can group entries by tag
+	tag := p.options.Tag(entry)
+	item := binary.AppendUvarint(nil, uint64(len(tag)))
+	item = append(item, tag...)
+	item = appendMsgpackArrayHeader(item, 2)
+	item = appendFluentEventTime(item, entry.Time)
+	item = appendMsgpack(item, record)
+	p.batcher.Add(item)
+}
+
+// Dropped returns number of batches that are discarded by overflow policy
+func (p *FluentPrinter) Dropped() uint64 {
+	return p.batcher.Dropped()
+}
+
+// Flush sends pending entries and waits until all batches are sent
+func (p *FluentPrinter) Flush() error {
+	p.batcher.Flush()
+	return nil
+}
+
+// Close sends remaining entries, stops background worker and closes connection
+func (p *FluentPrinter) Close() error {
+	p.batcher.Close()
+	if p.conn == nil {
+		return nil
+	}
+	err := p.conn.Close()
+	p.conn = nil
+	return err
+}
+
+// encode groups entries by tag into forward messages [tag, [entries...], option]. Each message is framed with its
+// chunk id, which is checked against acknowledgement
+func (p *FluentPrinter) encode(items [][]byte) []byte {
+	var order []string
+	entries := make(map[string][][]byte)
+	for _, item := range items {
+		n, size := binary.Uvarint(item)
+		tag := string(item[size : size+int(n)])
+		if _, ok := entries[tag]; !ok {
+			order = append(order, tag)
+		}
+		entries[tag] = append(entries[tag], item[size+int(n):])
+	}
+
+	var payload []byte
+	for _, tag := range order {
+		option := map[string]interface{}{"size": len(entries[tag])}
+		var chunk string
+		if p.options.RequireAck {
+			chunk = newFluentChunkId()
+			option["chunk"] = chunk
+		}
+
+		msg := appendMsgpackArrayHeader(nil, 3)
+		msg = appendMsgpackString(msg, tag)
+		msg = appendMsgpackArrayHeader(msg, len(entries[tag]))
+		for _, e := range entries[tag] {
+			msg = append(msg, e...)
+		}
+		msg = appendMsgpack(msg, option)
+
+		payload = binary.AppendUvarint(payload, uint64(len(chunk)))
+		payload = append(payload, chunk...)
+		payload = binary.AppendUvarint(payload, uint64(len(msg)))
+		payload = append(payload, msg...)
+	}
+	return payload
+}
+
+// send writes forward messages of payload in order. Each message is retried separately, so messages that were
+// already accepted are not sent again
+func (p *FluentPrinter) send(payload []byte) {
+	for len(payload) > 0 {
+		n, size := binary.Uvarint(payload)
+		chunk := string(payload[size : size+int(n)])
+		payload = payload[size+int(n):]
+
+		n, size = binary.Uvarint(payload)
+		msg := payload[size : size+int(n)]
+		payload = payload[size+int(n):]
+
+		err := logkExport.Retry(p.options.MaxRetries, p.options.RetryBackoff, func() error {
+			return p.write(msg, chunk)
+		})
+		if err != nil && p.options.OnError != nil {
+			p.options.OnError(err)
+		}
+	}
+}
+
+// write sends message and waits for acknowledgement of chunk if it's set. Connection is closed on error, so it is
+// re-established on retry
+func (p *FluentPrinter) write(msg []byte, chunk string) error {
+	err := p.writeConn(msg, chunk)
+	if err == nil {
+		return nil
+	}
+
+	if p.conn != nil {
+		_ = p.conn.Close()
+		p.conn = nil
+	}
+	return logkExport.Retryable(err)
+}
+
+func (p *FluentPrinter) writeConn(msg []byte, chunk string) error {
+	if p.conn == nil {
+		if err := p.connect(); err != nil {
+			return err
+		}
+	}
+
+	_ = p.conn.SetWriteDeadline(time.Now().Add(p.options.Timeout))
+	if _, err := p.conn.Write(msg); err != nil {
+		return err
+	}
+
+	if chunk == "" {
+		return nil
+	}
+
+	_ = p.conn.SetReadDeadline(time.Now().Add(p.options.AckTimeout))
+	ack, err := readFluentAck(p.reader)
+	if err != nil {
+		return err
+	}
+	if ack != chunk {
+		return fmt.Errorf("%s: fluent acknowledged chunk %q, expected %q", pkgName, ack, chunk)
+	}
+	return nil
+}
+
+func (p *FluentPrinter) connect() error {
+	dialer := net.Dialer{Timeout: p.options.Timeout}
+
+	var conn net.Conn
+	var err error
+	if p.options.TLS != nil && p.network != "unix" {
+		conn, err = tls.DialWithDialer(&dialer, p.network, p.addr, p.options.TLS)
+	} else {
+		conn, err = dialer.Dial(p.network, p.addr)
+	}
+	if err != nil {
+		return fmt.Errorf("%s: failed to connect to fluent input: %w", pkgName, err)
+	}
+
+	p.conn = conn
+	p.reader = bufio.NewReader(conn)
+	return nil
+}
+
+// fluentTagFunc returns tag function that appends namespace to prefix. Characters of namespace other than letters,
+// digits, dots, dashes and underscores are replaced with dots, so "api/users" becomes "prefix.api.users"
+func fluentTagFunc(prefix string) func(entry *logk.Entry) string {
+	return func(entry *logk.Entry) string {
+		if entry.Namespace == "" {
+			return prefix
+		}
+
+		ns := strings.Map(func(r rune) rune {
+			if r == '.' || r == '-' || r == '_' || r >= '0' && r <= '9' || r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' {
+				return r
+			}
+			return '.'
+		}, entry.Namespace)
+
+		if prefix == "" {
+			return ns
+		}
+		return prefix + "." + ns
+	}
+}
+
+// appendFluentEventTime appends t as EventTime extension, holding seconds and nanoseconds in big-endian
+func appendFluentEventTime(dst []byte, t time.Time) []byte {
+	dst = append(dst, 0xd7, fluentEventTimeExtType)
+	dst = binary.BigEndian.AppendUint32(dst, uint32(t.Unix()))
+	return binary.BigEndian.AppendUint32(dst, uint32(t.Nanosecond()))
+}
+
+func newFluentChunkId() string {
+	id := make([]byte, fluentChunkIdSize)
+	_, _ = rand.Read(id)
+	return base64.StdEncoding.EncodeToString(id)
+}
+
+// readFluentAck reads acknowledgement response {"ack": chunk}
+func readFluentAck(r *bufio.Reader) (string, error) {
+	n, err := readMsgpackMapHeader(r)
+	if err != nil {
+		return "", err
+	}
+
+	var ack string
+	for i := 0; i < n; i++ {
+		key, err := readMsgpackString(r)
+		if err != nil {
+			return "", err
+		}
+		value, err := readMsgpackString(r)
+		if err != nil {
+			return "", err
+		}
+		if key == "ack" {
+			ack = value
+		}
+	}
+	return ack, nil
+}
+
+func readMsgpackMapHeader(r *bufio.Reader) (int, error) {
+	b, err := r.ReadByte()
+	if err != nil {
+		return 0, err
+	}
+
+	switch {
+	case b&0xf0 == 0x80:
+		return int(b & 0x0f), nil
+	case b == 0xde:
+		n, err := readBigEndian(r, 2)
+		return int(n), err
+	case b == 0xdf:
+		n, err := readBigEndian(r, 4)
+		return int(n), err
+	}
+	return 0, fmt.Errorf("%s: unexpected msgpack type 0x%x, expected map", pkgName, b)
+}
+
+func readMsgpackString(r *bufio.Reader) (string, error) {
+	b, err := r.ReadByte()
+	if err != nil {
+		return "", err
+	}
+
+	var n uint64
+	switch {
+	case b&0xe0 == 0xa0:
+		n = uint64(b & 0x1f)
+	case b == 0xd9 || b == 0xc4:
+		n, err = readBigEndian(r, 1)
+	case b == 0xda || b == 0xc5:
+		n, err = readBigEndian(r, 2)
+	case b == 0xdb || b == 0xc6:
+		n, err = readBigEndian(r, 4)
+	default:
+		return "", fmt.Errorf("%s: unexpected msgpack type 0x%x, expected string", pkgName, b)
+	}
+	if err != nil {
+		return "", err
+	}
+
+	if n > maxFluentAckResponseBytes {
+		return "", fmt.Errorf("%s: msgpack string of %d bytes is too long", pkgName, n)
+	}
+
+	s := make([]byte, n)
+	if _, err = io.ReadFull(r, s); err != nil {
+		return "", err
+	}
+	return string(s), nil
+}
+
+func readBigEndian(r *bufio.Reader, size int) (uint64, error) {
+	var n uint64
+	for i := 0; i < size; i++ {
+		b, err := r.ReadByte()
+		if err != nil {
+			return 0, err
+		}
+		n = n<<8 | uint64(b)
+	}
+	return n, nil
+}
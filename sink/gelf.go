@@ -0,0 +1,404 @@
+package logkSink
+
+import (
+	"bytes"
+	"compress/gzip"
+	"compress/zlib"
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math/rand/v2"
+	"net"
+	"net/http"
+	"os"
+	"strings"
+	"sync"
+
+	"github.com/go-konsultin/logk"
+	logkExport "github.com/go-konsultin/logk/internal/export"
+	"github.com/go-konsultin/logk/level"
+	logkOption "github.com/go-konsultin/logk/option"
+)
+
+// GELFCompression is compression of GELF messages sent over UDP or HTTP. Messages sent over TCP are never compressed,
+// as Graylog doesn't support it
+type GELFCompression int
+
+const (
+	GELFCompressionNone GELFCompression = iota
+	GELFCompressionGzip
+	GELFCompressionZlib
+)
+
+// GELF printer constants
+const (
+	gelfVersion = "1.1"
+	// defaultGELFChunkSize fits in a single packet on most networks
+	defaultGELFChunkSize = 1420
+	gelfChunkHeaderSize  = 12
+	maxGELFChunks        = 128
+)
+
+// gelfChunkMagic starts each chunk of a chunked UDP message
+var gelfChunkMagic = []byte{0x1e, 0x0f}
+
+type GELFOptions struct {
+	// Host is source of messages, default is hostname
+	Host        string
+	Compression GELFCompression
+	// ChunkSize is maximum size of UDP datagrams, larger messages are chunked
+	ChunkSize int
+	// QueueSize limits number of entries waiting to be sent
+	QueueSize int
+	// Overflow is applied when queue is full
+	Overflow logk.OverflowPolicy
+	// OnError is called from background goroutine when an entry can't be sent
+	OnError func(err error)
+	// Client sends messages to HTTP input
+	Client         *http.Client
+	PrinterOptions []logk.PrinterOption
+}
+
+type GELFOption = func(*GELFOptions)
+
+func WithGELFHost(host string) GELFOption {
+	return func(o *GELFOptions) {
+		o.Host = host
+	}
+}
+
+func WithGELFCompression(c GELFCompression) GELFOption {
+	return func(o *GELFOptions) {
+		o.Compression = c
+	}
+}
+
+func WithGELFChunkSize(n int) GELFOption {
+	return func(o *GELFOptions) {
+		o.ChunkSize = n
+	}
+}
+
+func WithGELFQueueSize(n int) GELFOption {
+	return func(o *GELFOptions) {
+		o.QueueSize = n
+	}
+}
+
+func WithGELFOverflowPolicy(p logk.OverflowPolicy) GELFOption {
+	return func(o *GELFOptions) {
+		o.Overflow = p
+	}
+}
+
+func WithGELFOnError(fn func(err error)) GELFOption {
+	return func(o *GELFOptions) {
+		o.OnError = fn
+	}
+}
+
+func WithGELFClient(c *http.Client) GELFOption {
+	return func(o *GELFOptions) {
+		o.Client = c
+	}
+}
+
+func WithGELFPrinterOptions(args ...logk.PrinterOption) GELFOption {
+	return func(o *GELFOptions) {
+		o.PrinterOptions = append(o.PrinterOptions, args...)
+	}
+}
+
+// GELFPrinter sends entries to Graylog in GELF 1.1 format from background goroutine, see NewGELFEncoder
+type GELFPrinter struct {
+	network    string
+	addr       string
+	options    GELFOptions
+	encoder    *gelfEncoder
+	dispatcher *logkExport.Dispatcher
+
+	// mu guards connection of UDP and TCP transports
+	mu   sync.Mutex
+	conn net.Conn
+}
+
+// NewGELFPrinter creates printer that sends entries to a GELF input. Network is "udp", "tcp" or "http". Addr is
+// host:port of UDP and TCP inputs, or URL of HTTP input, e.g. http://graylog:12201/gelf
+func NewGELFPrinter(network, addr string, args ...GELFOption) (*GELFPrinter, error) {
+	o := GELFOptions{
+		ChunkSize: defaultGELFChunkSize,
+		QueueSize: defaultHTTPQueueSize,
+		Client:    &http.Client{Timeout: defaultHTTPTimeout},
+	}
+	o.Host, _ = os.Hostname()
+	for _, fn := range args {
+		fn(&o)
+	}
+
+	if o.ChunkSize <= gelfChunkHeaderSize {
+		return nil, fmt.Errorf("%s: gelf chunk size %d is too small", pkgName, o.ChunkSize)
+	}
+
+	p := GELFPrinter{
+		network: network,
+		addr:    addr,
+		options: o,
+		encoder: &gelfEncoder{host: o.Host},
+	}
+
+	// Messages are written to a single connection in order, requests to HTTP input are concurrent
+	concurrency := 1
+	switch network {
+	case "udp", "udp4", "udp6", "tcp", "tcp4", "tcp6":
+		if err := p.connect(); err != nil {
+			return nil, err
+		}
+	case "http":
+		if addr == "" {
+			return nil, fmt.Errorf("%s: gelf http input url is empty", pkgName)
+		}
+		concurrency = defaultHTTPConcurrency
+	default:
+		return nil, fmt.Errorf("%s: unknown gelf network %q", pkgName, network)
+	}
+
+	p.dispatcher = logkExport.NewDispatcher(concurrency, o.QueueSize, 0, o.Overflow, nil, p.send)
+	return &p, nil
+}
+
+func (p *GELFPrinter) Print(namespace string, lv level.LogLevel, msg string, options *logkOption.Options) {
+	entry := logk.NewEntry(namespace, lv, msg, options, p.options.PrinterOptions...)
+	payload, err := p.encoder.Encode(entry)
+	if err != nil {
+		return
+	}
+	p.dispatcher.Submit(bytes.TrimSuffix(payload, []byte{'\n'}))
+}
+
+// Dropped returns number of entries that are discarded by overflow policy
+func (p *GELFPrinter) Dropped() uint64 {
+	return p.dispatcher.Dropped()
+}
+
+// Flush waits until all queued entries are sent
+func (p *GELFPrinter) Flush() error {
+	p.dispatcher.Flush()
+	return nil
+}
+
+// Close sends remaining entries, stops background workers and closes connection
+func (p *GELFPrinter) Close() error {
+	p.dispatcher.Close()
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.conn == nil {
+		return nil
+	}
+	err := p.conn.Close()
+	p.conn = nil
+	return err
+}
+
+func (p *GELFPrinter) send(payload []byte) {
+	var err error
+	switch p.network {
+	case "http":
+		err = p.post(payload)
+	default:
+		err = p.write(payload)
+	}
+
+	if err != nil && p.options.OnError != nil {
+		p.options.OnError(err)
+	}
+}
+
+func (p *GELFPrinter) post(payload []byte) error {
+	header := make(http.Header)
+	header.Set("Content-Type", "application/json")
+
+	payload, err := p.compress(payload)
+	if err != nil {
+		return err
+	}
+	switch p.options.Compression {
+	case GELFCompressionGzip:
+		header.Set("Content-Encoding", "gzip")
+	case GELFCompressionZlib:
+		header.Set("Content-Encoding", "deflate")
+	}
+
+	return logkExport.Retry(defaultMaxRetries, defaultRetryBackoff, func() error {
+		return post(p.options.Client, p.addr, header, payload)
+	})
+}
+
+func (p *GELFPrinter) write(payload []byte) error {
+	var packets [][]byte
+	if strings.HasPrefix(p.network, "udp") {
+		compressed, err := p.compress(payload)
+		if err != nil {
+			return err
+		}
+
+		packets, err = p.chunk(compressed)
+		if err != nil {
+			return err
+		}
+	} else {
+		// TCP messages are delimited by null byte
+		packets = [][]byte{append(payload, 0)}
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if err := p.writePackets(packets); err != nil {
+		// Reconnect once, as input may have been restarted
+		if cErr := p.connect(); cErr != nil {
+			return cErr
+		}
+		return p.writePackets(packets)
+	}
+	return nil
+}
+
+func (p *GELFPrinter) writePackets(packets [][]byte) error {
+	if p.conn == nil {
+		return net.ErrClosed
+	}
+
+	for _, packet := range packets {
+		if _, err := p.conn.Write(packet); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// connect dials GELF input, closing previous connection
+func (p *GELFPrinter) connect() error {
+	if p.conn != nil {
+		_ = p.conn.Close()
+		p.conn = nil
+	}
+
+	conn, err := net.Dial(p.network, p.addr)
+	if err != nil {
+		return fmt.Errorf("%s: failed to connect to gelf input: %w", pkgName, err)
+	}
+	p.conn = conn
+	return nil
+}
+
+func (p *GELFPrinter) compress(payload []byte) ([]byte, error) {
+	var buf bytes.Buffer
+	var w io.WriteCloser
+	switch p.options.Compression {
+	case GELFCompressionGzip:
+		w = gzip.NewWriter(&buf)
+	case GELFCompressionZlib:
+		w = zlib.NewWriter(&buf)
+	default:
+		return payload, nil
+	}
+
+	if _, err := w.Write(payload); err != nil {
+		return nil, err
+	}
+	if err := w.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// chunk splits message that exceeds chunk size into chunks with header of magic bytes, message id, sequence number
+// and sequence count
+func (p *GELFPrinter) chunk(payload []byte) ([][]byte, error) {
+	if len(payload) <= p.options.ChunkSize {
+		return [][]byte{payload}, nil
+	}
+
+	size := p.options.ChunkSize - gelfChunkHeaderSize
+	count := (len(payload) + size - 1) / size
+	if count > maxGELFChunks {
+		return nil, fmt.Errorf("%s: gelf message of %d bytes exceeds %d chunks", pkgName, len(payload), maxGELFChunks)
+	}
+
+	id := rand.Uint64()
+	chunks := make([][]byte, 0, count)
+	for i := 0; i < count; i++ {
+		end := min((i+1)*size, len(payload))
+
+		chunk := make([]byte, 0, gelfChunkHeaderSize+end-i*size)
+		chunk = append(chunk, gelfChunkMagic...)
+		chunk = binary.BigEndian.AppendUint64(chunk, id)
+		chunk = append(chunk, byte(i), byte(count))
+		chunk = append(chunk, payload[i*size:end]...)
+		chunks = append(chunks, chunk)
+	}
+	return chunks, nil
+}
+
+// NewGELFEncoder creates encoder of GELF 1.1 messages from host. Namespace is written as _namespace, and other entry
+// fields and flattened metadata as additional fields, e.g. _requestId and _user.id. Stack trace is written as
+// full_message
+func NewGELFEncoder(host string) logk.Encoder {
+	return &gelfEncoder{host: host}
+}
+
+type gelfEncoder struct {
+	host string
+}
+
+func (e *gelfEncoder) Encode(entry *logk.Entry) ([]byte, error) {
+	message := map[string]interface{}{
+		"version":       gelfVersion,
+		"host":          e.host,
+		"short_message": entry.Message,
+		"timestamp":     float64(entry.Time.UnixMilli()) / 1000,
+		"level":         syslogPriority(entry.Level),
+	}
+
+	// Short message is required
+	if entry.Message == "" {
+		message["short_message"] = "-"
+	}
+
+	if entry.StackTrace != "" {
+		message["full_message"] = entry.StackTrace
+	}
+
+	params := syslogParams(entry)
+	delete(params, logkOption.StackTraceKey)
+	if entry.Namespace != "" {
+		params[logkOption.NamespaceKey] = entry.Namespace
+	}
+
+	for k, v := range params {
+		message[gelfFieldName(k)] = v
+	}
+
+	b, err := json.Marshal(message)
+	if err != nil {
+		return nil, err
+	}
+	return append(b, '\n'), nil
+}
+
+// gelfFieldName prefixes additional field name with underscore, and replaces characters that are not allowed.
+// Reserved _id is renamed to _id_
+func gelfFieldName(key string) string {
+	name := strings.Map(func(r rune) rune {
+		if r == '.' || r == '-' || r == '_' || r >= '0' && r <= '9' || r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' {
+			return r
+		}
+		return '_'
+	}, key)
+
+	if name == "id" {
+		return "_id_"
+	}
+	return "_" + name
+}
@@ -0,0 +1,273 @@
+package logkSink
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/go-konsultin/logk"
+	logkExport "github.com/go-konsultin/logk/internal/export"
+	"github.com/go-konsultin/logk/level"
+	logkOption "github.com/go-konsultin/logk/option"
+)
+
+// Default HTTP printer options
+const (
+	defaultHTTPConcurrency   = 4
+	defaultHTTPQueueSize     = 1024
+	defaultHTTPTimeout       = 10 * time.Second
+	defaultHTTPBatchInterval = time.Second
+)
+
+type HTTPOptions struct {
+	Client *http.Client
+	Header http.Header
+	// Concurrency limits number of in-flight requests
+	Concurrency int
+	// QueueSize limits number of entries waiting to be sent
+	QueueSize int
+	// MaxInFlightBytes limits size of queued and in-flight entries. Zero means unlimited
+	MaxInFlightBytes int64
+	// Overflow is applied when queue or in-flight bytes limit is exceeded
+	Overflow logk.OverflowPolicy
+	// OnOverflow is called when queue or in-flight bytes limit is exceeded, before Overflow is applied.
+	// It is called on the logging goroutine, so it must be fast
+	OnOverflow func()
+	// OnError is called from background goroutine when an entry can't be sent or endpoint responds with error status
+	OnError func(err error)
+	// BatchSize, BatchBytes and BatchInterval post entries in batches of newline-delimited entries. A batch is
+	// posted when it has BatchSize entries or BatchBytes, or BatchInterval after its first entry. BatchSize of zero
+	// or one posts each entry separately
+	BatchSize     int
+	BatchBytes    int
+	BatchInterval time.Duration
+	// Encoder formats entries that are posted. If it's nil, entries are posted as JSON, or newline-delimited JSON
+	// when batched. Set Content-Type with WithHeader when encoder doesn't produce JSON
+	Encoder        logk.Encoder
+	PrinterOptions []logk.PrinterOption
+}
+
+type HTTPOption = func(*HTTPOptions)
+
+func WithHTTPClient(c *http.Client) HTTPOption {
+	return func(o *HTTPOptions) {
+		o.Client = c
+	}
+}
+
+func WithHeader(key, value string) HTTPOption {
+	return func(o *HTTPOptions) {
+		o.Header.Add(key, value)
+	}
+}
+
+func WithConcurrency(n int) HTTPOption {
+	return func(o *HTTPOptions) {
+		o.Concurrency = n
+	}
+}
+
+func WithQueueSize(n int) HTTPOption {
+	return func(o *HTTPOptions) {
+		o.QueueSize = n
+	}
+}
+
+func WithMaxInFlightBytes(n int64) HTTPOption {
+	return func(o *HTTPOptions) {
+		o.MaxInFlightBytes = n
+	}
+}
+
+func WithOverflowPolicy(p logk.OverflowPolicy) HTTPOption {
+	return func(o *HTTPOptions) {
+		o.Overflow = p
+	}
+}
+
+func WithOnOverflow(fn func()) HTTPOption {
+	return func(o *HTTPOptions) {
+		o.OnOverflow = fn
+	}
+}
+
+func WithOnError(fn func(err error)) HTTPOption {
+	return func(o *HTTPOptions) {
+		o.OnError = fn
+	}
+}
+
+func WithEncoder(enc logk.Encoder) HTTPOption {
+	return func(o *HTTPOptions) {
+		o.Encoder = enc
+	}
+}
+
+// WithBatch posts entries in batches of up to size entries or maxBytes, sent at most interval after their first
+// entry, so high-volume services send fewer requests
+func WithBatch(size, maxBytes int, interval time.Duration) HTTPOption {
+	return func(o *HTTPOptions) {
+		o.BatchSize = size
+		o.BatchBytes = maxBytes
+		o.BatchInterval = interval
+	}
+}
+
+func WithPrinterOptions(args ...logk.PrinterOption) HTTPOption {
+	return func(o *HTTPOptions) {
+		o.PrinterOptions = append(o.PrinterOptions, args...)
+	}
+}
+
+// HTTPPrinter posts each entry as JSON, or in format of HTTPOptions.Encoder, to an HTTP endpoint in background,
+// with bounded concurrency and buffer
+type HTTPPrinter struct {
+	url        string
+	options    HTTPOptions
+	dispatcher *logkExport.Dispatcher
+	// batcher is set when entries are posted in batches
+	batcher *logkExport.Batcher
+}
+
+func NewHTTPPrinter(url string, args ...HTTPOption) *HTTPPrinter {
+	if url == "" {
+		panic(fmt.Errorf("%s: http printer url is empty", pkgName))
+	}
+
+	o := HTTPOptions{
+		Client:      &http.Client{Timeout: defaultHTTPTimeout},
+		Header:      make(http.Header),
+		Concurrency: defaultHTTPConcurrency,
+		QueueSize:   defaultHTTPQueueSize,
+	}
+	for _, fn := range args {
+		fn(&o)
+	}
+
+	if o.BatchSize > 1 && o.BatchInterval <= 0 {
+		o.BatchInterval = defaultHTTPBatchInterval
+	}
+
+	p := HTTPPrinter{url: url, options: o}
+	p.dispatcher = logkExport.NewDispatcher(o.Concurrency, o.QueueSize, o.MaxInFlightBytes, o.Overflow, o.OnOverflow,
+		p.send)
+	if o.BatchSize > 1 {
+		p.batcher = logkExport.NewBatcher(o.BatchSize, o.BatchBytes, o.BatchInterval, encodeLines, p.dispatcher)
+	}
+
+	return &p
+}
+
+func (p *HTTPPrinter) Print(namespace string, lv level.LogLevel, msg string, options *logkOption.Options) {
+	entry := logk.NewEntry(namespace, lv, msg, options, p.options.PrinterOptions...)
+
+	var payload []byte
+	var err error
+	if p.options.Encoder != nil {
+		payload, err = p.options.Encoder.Encode(entry)
+	} else {
+		payload, err = json.Marshal(entry)
+	}
+	if err != nil {
+		return
+	}
+
+	if p.batcher != nil {
+		p.batcher.Add(bytes.TrimSuffix(payload, []byte{'\n'}))
+		return
+	}
+	p.dispatcher.Submit(payload)
+}
+
+// QueueLen returns number of entries, or batches if entries are batched, waiting to be sent. Metrics are read
+// without lock, so they reflect an instantaneous view that may be stale
+func (p *HTTPPrinter) QueueLen() int {
+	return p.dispatcher.QueueLen()
+}
+
+// QueueCap returns maximum number of entries, or batches if entries are batched, waiting to be sent
+func (p *HTTPPrinter) QueueCap() int {
+	return p.dispatcher.QueueCap()
+}
+
+// InFlightBytes returns size of entries that are queued or being sent
+func (p *HTTPPrinter) InFlightBytes() int64 {
+	return p.dispatcher.InFlight()
+}
+
+// Dropped returns number of entries, or batches if entries are batched, that are discarded by overflow policy
+func (p *HTTPPrinter) Dropped() uint64 {
+	return p.dispatcher.Dropped()
+}
+
+// Flush waits until all queued entries are sent
+func (p *HTTPPrinter) Flush() error {
+	if p.batcher != nil {
+		p.batcher.Flush()
+		return nil
+	}
+	p.dispatcher.Flush()
+	return nil
+}
+
+// Close sends remaining entries and stops background workers
+func (p *HTTPPrinter) Close() error {
+	if p.batcher != nil {
+		p.batcher.Close()
+		return nil
+	}
+	p.dispatcher.Close()
+	return nil
+}
+
+func (p *HTTPPrinter) send(payload []byte) {
+	if err := p.post(payload); err != nil && p.options.OnError != nil {
+		p.options.OnError(err)
+	}
+}
+
+func (p *HTTPPrinter) post(payload []byte) error {
+	req, err := http.NewRequest(http.MethodPost, p.url, bytes.NewReader(payload))
+	if err != nil {
+		return err
+	}
+
+	req.Header = p.options.Header.Clone()
+	if req.Header.Get("Content-Type") == "" {
+		if p.batcher != nil {
+			req.Header.Set("Content-Type", "application/x-ndjson")
+		} else {
+			req.Header.Set("Content-Type", "application/json")
+		}
+	}
+
+	resp, err := p.options.Client.Do(req)
+	if err != nil {
+		return err
+	}
+	_, _ = io.Copy(io.Discard, resp.Body)
+	_ = resp.Body.Close()
+
+	if resp.StatusCode >= 300 {
+		return fmt.Errorf("%s: %s responded with status %d", pkgName, p.url, resp.StatusCode)
+	}
+	return nil
+}
+
+// encodeLines joins entries into newline-delimited payload
+func encodeLines(items [][]byte) []byte {
+	size := len(items)
+	for _, item := range items {
+		size += len(item)
+	}
+
+	payload := make([]byte, 0, size)
+	for _, item := range items {
+		payload = append(payload, item...)
+		payload = append(payload, '\n')
+	}
+	return payload
+}
@@ -0,0 +1,209 @@
+//go:build linux
+
+package logkSink
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"syscall"
+	"unicode"
+
+	"github.com/go-konsultin/logk"
+	"github.com/go-konsultin/logk/level"
+	logkOption "github.com/go-konsultin/logk/option"
+)
+
+// Journald field names
+const (
+	journalMessageKey     = "MESSAGE"
+	journalPriorityKey    = "PRIORITY"
+	journalIdentifierKey  = "SYSLOG_IDENTIFIER"
+	journalCodeFileKey    = "CODE_FILE"
+	journalCodeLineKey    = "CODE_LINE"
+	journalCodeFuncKey    = "CODE_FUNC"
+	maxJournalFieldName   = 64
+	defaultJournaldSocket = "/run/systemd/journal/socket"
+)
+
+// JournaldOptions are options of journald printer
+type JournaldOptions struct {
+	// Identifier is written as SYSLOG_IDENTIFIER. Default is executable name
+	Identifier string
+	// Socket is path of journald native socket
+	Socket         string
+	PrinterOptions []logk.PrinterOption
+}
+
+type JournaldOption = func(*JournaldOptions)
+
+func WithJournaldIdentifier(id string) JournaldOption {
+	return func(o *JournaldOptions) {
+		o.Identifier = id
+	}
+}
+
+func WithJournaldSocket(path string) JournaldOption {
+	return func(o *JournaldOptions) {
+		o.Socket = path
+	}
+}
+
+func WithJournaldPrinterOptions(args ...logk.PrinterOption) JournaldOption {
+	return func(o *JournaldOptions) {
+		o.PrinterOptions = append(o.PrinterOptions, args...)
+	}
+}
+
+// JournaldPrinter writes entries to systemd journal with native protocol. Level is written as PRIORITY, caller as
+// CODE_FILE, CODE_LINE and CODE_FUNC, and other fields and flattened metadata as upper snake case fields, e.g.
+// requestId as REQUEST_ID, so entries can be filtered with journalctl REQUEST_ID=...
+type JournaldPrinter struct {
+	conn    *net.UnixConn
+	addr    *net.UnixAddr
+	options JournaldOptions
+}
+
+// NewJournaldPrinter connects to journald socket
+func NewJournaldPrinter(args ...JournaldOption) (*JournaldPrinter, error) {
+	o := JournaldOptions{
+		Identifier: filepath.Base(os.Args[0]),
+		Socket:     defaultJournaldSocket,
+	}
+	for _, fn := range args {
+		fn(&o)
+	}
+
+	conn, err := net.ListenUnixgram("unixgram", &net.UnixAddr{Net: "unixgram"})
+	if err != nil {
+		return nil, fmt.Errorf("%s: failed to open journald socket: %w", pkgName, err)
+	}
+
+	addr := &net.UnixAddr{Name: o.Socket, Net: "unixgram"}
+	if _, err = os.Stat(o.Socket); err != nil {
+		_ = conn.Close()
+		return nil, fmt.Errorf("%s: journald socket is not available: %w", pkgName, err)
+	}
+
+	return &JournaldPrinter{conn: conn, addr: addr, options: o}, nil
+}
+
+func (p *JournaldPrinter) Print(namespace string, lv level.LogLevel, msg string, options *logkOption.Options) {
+	entry := logk.NewEntry(namespace, lv, msg, options, p.options.PrinterOptions...)
+
+	var buf bytes.Buffer
+	writeJournalField(&buf, journalMessageKey, entry.Message)
+	writeJournalField(&buf, journalPriorityKey, strconv.Itoa(syslogPriority(lv)))
+	if p.options.Identifier != "" {
+		writeJournalField(&buf, journalIdentifierKey, p.options.Identifier)
+	}
+
+	if entry.Caller.File != "" {
+		writeJournalField(&buf, journalCodeFileKey, entry.Caller.File)
+		writeJournalField(&buf, journalCodeLineKey, strconv.Itoa(entry.Caller.Line))
+		writeJournalField(&buf, journalCodeFuncKey, entry.Caller.Function)
+	}
+
+	fields := entry.Fields()
+	for _, k := range []string{logkOption.TimeKey, logkOption.LevelKey, logkOption.MessageKey,
+		logkOption.CallerKey, logkOption.MetadataKey, logkOption.BaggageKey} {
+		delete(fields, k)
+	}
+
+	for k, v := range entry.Baggage {
+		fields[logkOption.BaggageKey+"."+k] = v
+	}
+	flattenMetadata(fields, "", entry.Metadata)
+
+	for _, k := range sortedKeys(fields) {
+		writeJournalField(&buf, journalFieldName(k), syslogParamValue(fields[k]))
+	}
+
+	_ = p.send(buf.Bytes())
+}
+
+// Close closes journald socket
+func (p *JournaldPrinter) Close() error {
+	return p.conn.Close()
+}
+
+// send writes datagram. If it is too large, it is written to a temporary file which descriptor is sent instead
+func (p *JournaldPrinter) send(data []byte) error {
+	_, _, err := p.conn.WriteMsgUnix(data, nil, p.addr)
+	if err == nil {
+		return nil
+	}
+
+	if !errors.Is(err, syscall.EMSGSIZE) && !errors.Is(err, syscall.ENOBUFS) {
+		return err
+	}
+
+	f, err := os.CreateTemp("/dev/shm", "logk-journal-")
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	// Unlink file, journald reads it through the descriptor
+	if err = os.Remove(f.Name()); err != nil {
+		return err
+	}
+
+	if _, err = f.Write(data); err != nil {
+		return err
+	}
+
+	_, _, err = p.conn.WriteMsgUnix(nil, syscall.UnixRights(int(f.Fd())), p.addr)
+	return err
+}
+
+// writeJournalField writes field in native protocol. Values containing newline are written with explicit length
+func writeJournalField(buf *bytes.Buffer, key string, value string) {
+	buf.WriteString(key)
+	if !strings.ContainsRune(value, '\n') {
+		buf.WriteByte('=')
+		buf.WriteString(value)
+		buf.WriteByte('\n')
+		return
+	}
+
+	buf.WriteByte('\n')
+	_ = binary.Write(buf, binary.LittleEndian, uint64(len(value)))
+	buf.WriteString(value)
+	buf.WriteByte('\n')
+}
+
+// journalFieldName converts key to upper snake case, e.g. requestId to REQUEST_ID. Journal field names may only
+// contain uppercase letters, digits and underscores, and must not start with an underscore or digit
+func journalFieldName(key string) string {
+	var sb strings.Builder
+	var prev rune
+	for _, r := range key {
+		switch {
+		case unicode.IsUpper(r) && (unicode.IsLower(prev) || unicode.IsDigit(prev)):
+			sb.WriteByte('_')
+			sb.WriteRune(unicode.ToUpper(r))
+		case r < unicode.MaxASCII && (unicode.IsLetter(r) || unicode.IsDigit(r)):
+			sb.WriteRune(unicode.ToUpper(r))
+		default:
+			sb.WriteByte('_')
+		}
+		prev = r
+	}
+
+	name := strings.TrimLeft(sb.String(), "_")
+	if name == "" || unicode.IsDigit(rune(name[0])) {
+		name = "X_" + name
+	}
+
+	if len(name) > maxJournalFieldName {
+		name = name[:maxJournalFieldName]
+	}
+	return name
+}
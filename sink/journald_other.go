@@ -0,0 +1,53 @@
+//go:build !linux
+
+package logkSink
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/go-konsultin/logk"
+	"github.com/go-konsultin/logk/level"
+	logkOption "github.com/go-konsultin/logk/option"
+)
+
+// JournaldOptions are options of journald printer
+type JournaldOptions struct {
+	Identifier     string
+	Socket         string
+	PrinterOptions []logk.PrinterOption
+}
+
+type JournaldOption = func(*JournaldOptions)
+
+func WithJournaldIdentifier(id string) JournaldOption {
+	return func(o *JournaldOptions) {
+		o.Identifier = id
+	}
+}
+
+func WithJournaldSocket(path string) JournaldOption {
+	return func(o *JournaldOptions) {
+		o.Socket = path
+	}
+}
+
+func WithJournaldPrinterOptions(args ...logk.PrinterOption) JournaldOption {
+	return func(o *JournaldOptions) {
+		o.PrinterOptions = append(o.PrinterOptions, args...)
+	}
+}
+
+// JournaldPrinter is only available on Linux
+type JournaldPrinter struct{}
+
+// NewJournaldPrinter returns error, as journald is only available on Linux
+func NewJournaldPrinter(args ...JournaldOption) (*JournaldPrinter, error) {
+	return nil, fmt.Errorf("%s: %w", pkgName, errors.New("journald is only available on linux"))
+}
+
+func (p *JournaldPrinter) Print(string, level.LogLevel, string, *logkOption.Options) {}
+
+func (p *JournaldPrinter) Close() error {
+	return nil
+}
@@ -0,0 +1,334 @@
+package logkSink
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/binary"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/go-konsultin/logk"
+	logkExport "github.com/go-konsultin/logk/internal/export"
+	"github.com/go-konsultin/logk/level"
+	logkOption "github.com/go-konsultin/logk/option"
+)
+
+// Default Loki printer options
+const (
+	defaultLokiBatchSize  = 1024
+	defaultLokiBatchBytes = 1 << 20
+	lokiLevelLabel        = "level"
+	lokiNamespaceLabel    = "namespace"
+	lokiTenantHeader      = "X-Scope-OrgID"
+	lokiContentType       = "application/x-protobuf"
+)
+
+// Protobuf wire types used in push request
+const (
+	protoVarint = 0
+	protoBytes  = 2
+)
+
+type LokiOptions struct {
+	Client *http.Client
+	Header http.Header
+	// Labels are static labels added to all streams, e.g. app or env
+	Labels map[string]string
+	// LabelKeys are metadata keys whose values are written as labels. Nested keys are joined by dot, e.g.
+	// "http.method". Keep cardinality of these values low, as each label set is a separate stream in Loki
+	LabelKeys []string
+	// BatchSize is maximum number of entries in a single push request
+	BatchSize int
+	// BatchBytes is maximum size of lines in a single push request, before compression
+	BatchBytes int
+	// BatchInterval is maximum time an entry waits before it is pushed
+	BatchInterval time.Duration
+	// QueueSize limits number of batches waiting to be pushed
+	QueueSize int
+	// MaxRetries is number of retries of a failed push on network errors, 429 and 5xx responses
+	MaxRetries int
+	// RetryBackoff is delay before the first retry, it is doubled on each retry
+	RetryBackoff time.Duration
+	// Overflow is applied when queue is full
+	Overflow logk.OverflowPolicy
+	// OnError is called from background goroutine when a batch can't be pushed
+	OnError func(err error)
+	// Encoder formats log lines, default is JSON
+	Encoder        logk.Encoder
+	PrinterOptions []logk.PrinterOption
+}
+
+type LokiOption = func(*LokiOptions)
+
+func WithLokiClient(c *http.Client) LokiOption {
+	return func(o *LokiOptions) {
+		o.Client = c
+	}
+}
+
+func WithLokiHeader(key, value string) LokiOption {
+	return func(o *LokiOptions) {
+		o.Header.Add(key, value)
+	}
+}
+
+// WithLokiTenantId sets tenant of pushed entries in multi-tenant Loki
+func WithLokiTenantId(tenantId string) LokiOption {
+	return func(o *LokiOptions) {
+		o.Header.Set(lokiTenantHeader, tenantId)
+	}
+}
+
+func WithLokiBasicAuth(username, password string) LokiOption {
+	return func(o *LokiOptions) {
+		o.Header.Set("Authorization", "Basic "+base64.StdEncoding.EncodeToString([]byte(username+":"+password)))
+	}
+}
+
+func WithLokiLabel(key, value string) LokiOption {
+	return func(o *LokiOptions) {
+		o.Labels[key] = value
+	}
+}
+
+func WithLokiLabelKeys(keys ...string) LokiOption {
+	return func(o *LokiOptions) {
+		o.LabelKeys = append(o.LabelKeys, keys...)
+	}
+}
+
+func WithLokiBatch(size, maxBytes int, interval time.Duration) LokiOption {
+	return func(o *LokiOptions) {
+		o.BatchSize = size
+		o.BatchBytes = maxBytes
+		o.BatchInterval = interval
+	}
+}
+
+func WithLokiQueueSize(n int) LokiOption {
+	return func(o *LokiOptions) {
+		o.QueueSize = n
+	}
+}
+
+func WithLokiRetry(maxRetries int, backoff time.Duration) LokiOption {
+	return func(o *LokiOptions) {
+		o.MaxRetries = maxRetries
+		o.RetryBackoff = backoff
+	}
+}
+
+func WithLokiOverflowPolicy(p logk.OverflowPolicy) LokiOption {
+	return func(o *LokiOptions) {
+		o.Overflow = p
+	}
+}
+
+func WithLokiOnError(fn func(err error)) LokiOption {
+	return func(o *LokiOptions) {
+		o.OnError = fn
+	}
+}
+
+func WithLokiEncoder(enc logk.Encoder) LokiOption {
+	return func(o *LokiOptions) {
+		o.Encoder = enc
+	}
+}
+
+func WithLokiPrinterOptions(args ...logk.PrinterOption) LokiOption {
+	return func(o *LokiOptions) {
+		o.PrinterOptions = append(o.PrinterOptions, args...)
+	}
+}
+
+// LokiPrinter pushes entries in batches to Grafana Loki, e.g. to http://localhost:3100/loki/api/v1/push. Entries
+// are grouped into streams by level, namespace, static labels and labels from metadata keys. Push requests are
+// snappy-compressed protobuf, as sent by Promtail, so they are encoded without protobuf dependency
+type LokiPrinter struct {
+	url     string
+	options LokiOptions
+	// labels are static labels with sanitized names
+	labels  map[string]string
+	batcher *logkExport.Batcher
+}
+
+func NewLokiPrinter(url string, args ...LokiOption) *LokiPrinter {
+	if url == "" {
+		panic(fmt.Errorf("%s: loki printer url is empty", pkgName))
+	}
+
+	o := LokiOptions{
+		Client:        &http.Client{Timeout: defaultHTTPTimeout},
+		Header:        make(http.Header),
+		Labels:        make(map[string]string),
+		BatchSize:     defaultLokiBatchSize,
+		BatchBytes:    defaultLokiBatchBytes,
+		BatchInterval: defaultOTLPBatchInterval,
+		QueueSize:     defaultOTLPQueueSize,
+		MaxRetries:    defaultMaxRetries,
+		RetryBackoff:  defaultRetryBackoff,
+		Encoder:       logk.NewJSONEncoder(),
+	}
+	for _, fn := range args {
+		fn(&o)
+	}
+
+	p := LokiPrinter{url: url, options: o, labels: make(map[string]string, len(o.Labels))}
+	for k, v := range o.Labels {
+		p.labels[lokiLabelName(k)] = v
+	}
+
+	d := logkExport.NewDispatcher(1, o.QueueSize, 0, o.Overflow, nil, p.send)
+	p.batcher = logkExport.NewBatcher(o.BatchSize, o.BatchBytes, o.BatchInterval, p.encode, d)
+
+	return &p
+}
+
+func (p *LokiPrinter) Print(namespace string, lv level.LogLevel, msg string, options *logkOption.Options) {
+	entry := logk.NewEntry(namespace, lv, msg, options, p.options.PrinterOptions...)
+	line, err := p.options.Encoder.Encode(entry)
+	if err != nil {
+		return
+	}
+	line = bytes.TrimSuffix(line, []byte{'\n'})
+
+	// Item holds label set of stream followed by encoded entry, so encode can group entries into streams
+	labels := p.streamLabels(entry)
+	item := binary.AppendUvarint(nil, uint64(len(labels)))
+	item = append(item, labels...)
+	item = appendLokiEntry(item, entry.Time, line)
+	p.batcher.Add(item)
+}
+
+// Dropped returns number of batches that are discarded by overflow policy
+func (p *LokiPrinter) Dropped() uint64 {
+	return p.batcher.Dropped()
+}
+
+// Flush pushes pending entries and waits until all batches are pushed
+func (p *LokiPrinter) Flush() error {
+	p.batcher.Flush()
+	return nil
+}
+
+// Close pushes remaining entries and stops background worker
+func (p *LokiPrinter) Close() error {
+	p.batcher.Close()
+	return nil
+}
+
+// streamLabels returns label set of entry in LogQL selector form, e.g. {level="info", namespace="api"}, with labels
+// sorted by name so equal sets are grouped into the same stream
+func (p *LokiPrinter) streamLabels(entry *logk.Entry) string {
+	labels := make(map[string]string, len(p.labels)+len(p.options.LabelKeys)+2)
+	for k, v := range p.labels {
+		labels[k] = v
+	}
+
+	if len(p.options.LabelKeys) > 0 && len(entry.Metadata) > 0 {
+		metadata := make(map[string]interface{})
+		flattenMetadata(metadata, "", entry.Metadata)
+		for _, k := range p.options.LabelKeys {
+			if v, ok := metadata[k]; ok && v != nil {
+				labels[lokiLabelName(k)] = fmt.Sprint(v)
+			}
+		}
+	}
+
+	labels[lokiLevelLabel] = strings.ToLower(level.String(entry.Level))
+	if entry.Namespace != "" {
+		labels[lokiNamespaceLabel] = entry.Namespace
+	}
+
+	var b strings.Builder
+	b.WriteByte('{')
+	for i, k := range sortedKeys(labels) {
+		if i > 0 {
+			b.WriteString(", ")
+		}
+		b.WriteString(k)
+		b.WriteByte('=')
+		b.WriteString(strconv.Quote(labels[k]))
+	}
+	b.WriteByte('}')
+	return b.String()
+}
+
+// encode groups entries by label set into streams of PushRequest, and compresses it with snappy
+func (p *LokiPrinter) encode(items [][]byte) []byte {
+	var order []string
+	streams := make(map[string][]byte)
+	for _, item := range items {
+		n, size := binary.Uvarint(item)
+		labels := string(item[size : size+int(n)])
+		if _, ok := streams[labels]; !ok {
+			order = append(order, labels)
+		}
+		streams[labels] = append(streams[labels], item[size+int(n):]...)
+	}
+
+	// PushRequest { repeated StreamAdapter streams = 1; }
+	// StreamAdapter { string labels = 1; repeated EntryAdapter entries = 2; }
+	var req []byte
+	for _, labels := range order {
+		stream := appendProtoBytes(nil, 1, []byte(labels))
+		stream = append(stream, streams[labels]...)
+		req = appendProtoBytes(req, 1, stream)
+	}
+	return snappyEncode(req)
+}
+
+func (p *LokiPrinter) send(payload []byte) {
+	header := p.options.Header.Clone()
+	header.Set("Content-Type", lokiContentType)
+
+	err := logkExport.Retry(p.options.MaxRetries, p.options.RetryBackoff, func() error {
+		return post(p.options.Client, p.url, header, payload)
+	})
+	if err != nil && p.options.OnError != nil {
+		p.options.OnError(err)
+	}
+}
+
+// appendLokiEntry appends entries field of StreamAdapter, holding
+// EntryAdapter { google.protobuf.Timestamp timestamp = 1; string line = 2; }
+func appendLokiEntry(dst []byte, t time.Time, line []byte) []byte {
+	// Timestamp { int64 seconds = 1; int32 nanos = 2; }
+	var ts []byte
+	if s := t.Unix(); s != 0 {
+		ts = appendProtoVarint(ts, 1, uint64(s))
+	}
+	if ns := t.Nanosecond(); ns != 0 {
+		ts = appendProtoVarint(ts, 2, uint64(ns))
+	}
+
+	e := appendProtoBytes(nil, 1, ts)
+	e = appendProtoBytes(e, 2, line)
+	return appendProtoBytes(dst, 2, e)
+}
+
+func appendProtoVarint(dst []byte, field int, v uint64) []byte {
+	dst = binary.AppendUvarint(dst, uint64(field)<<3|protoVarint)
+	return binary.AppendUvarint(dst, v)
+}
+
+func appendProtoBytes(dst []byte, field int, b []byte) []byte {
+	dst = binary.AppendUvarint(dst, uint64(field)<<3|protoBytes)
+	dst = binary.AppendUvarint(dst, uint64(len(b)))
+	return append(dst, b...)
+}
+
+// lokiLabelName replaces characters that are not allowed in label names, which match [a-zA-Z_][a-zA-Z0-9_]*
+func lokiLabelName(key string) string {
+	name := []byte(key)
+	for i, c := range name {
+		if !(c == '_' || c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || i > 0 && c >= '0' && c <= '9') {
+			name[i] = '_'
+		}
+	}
+	return string(name)
+}
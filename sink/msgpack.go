@@ -0,0 +1,188 @@
+package logkSink
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"math"
+	"time"
+)
+
+// appendMsgpack appends v in MessagePack format, see https://github.com/msgpack/msgpack/blob/master/spec.md.
+// Values of unknown types are converted through their JSON form, so structs become maps
+func appendMsgpack(dst []byte, v interface{}) []byte {
+	switch val := v.(type) {
+	case nil:
+		return append(dst, 0xc0)
+	case bool:
+		if val {
+			return append(dst, 0xc3)
+		}
+		return append(dst, 0xc2)
+	case int:
+		return appendMsgpackInt(dst, int64(val))
+	case int8:
+		return appendMsgpackInt(dst, int64(val))
+	case int16:
+		return appendMsgpackInt(dst, int64(val))
+	case int32:
+		return appendMsgpackInt(dst, int64(val))
+	case int64:
+		return appendMsgpackInt(dst, val)
+	case uint:
+		return appendMsgpackUint(dst, uint64(val))
+	case uint8:
+		return appendMsgpackUint(dst, uint64(val))
+	case uint16:
+		return appendMsgpackUint(dst, uint64(val))
+	case uint32:
+		return appendMsgpackUint(dst, uint64(val))
+	case uint64:
+		return appendMsgpackUint(dst, val)
+	case float32:
+		dst = append(dst, 0xca)
+		return binary.BigEndian.AppendUint32(dst, math.Float32bits(val))
+	case float64:
+		dst = append(dst, 0xcb)
+		return binary.BigEndian.AppendUint64(dst, math.Float64bits(val))
+	case json.Number:
+		if i, err := val.Int64(); err == nil {
+			return appendMsgpackInt(dst, i)
+		}
+		f, _ := val.Float64()
+		return appendMsgpack(dst, f)
+	case string:
+		return appendMsgpackString(dst, val)
+	case []byte:
+		return appendMsgpackBin(dst, val)
+	case time.Time:
+		return appendMsgpackString(dst, val.Format(time.RFC3339Nano))
+	case error:
+		return appendMsgpackString(dst, val.Error())
+	case fmt.Stringer:
+		return appendMsgpackString(dst, val.String())
+	case map[string]string:
+		dst = appendMsgpackMapHeader(dst, len(val))
+		for _, k := range sortedKeys(val) {
+			dst = appendMsgpackString(dst, k)
+			dst = appendMsgpackString(dst, val[k])
+		}
+		return dst
+	case map[string]interface{}:
+		dst = appendMsgpackMapHeader(dst, len(val))
+		for _, k := range sortedKeys(val) {
+			dst = appendMsgpackString(dst, k)
+			dst = appendMsgpack(dst, val[k])
+		}
+		return dst
+	case []string:
+		dst = appendMsgpackArrayHeader(dst, len(val))
+		for _, item := range val {
+			dst = appendMsgpackString(dst, item)
+		}
+		return dst
+	case []interface{}:
+		dst = appendMsgpackArrayHeader(dst, len(val))
+		for _, item := range val {
+			dst = appendMsgpack(dst, item)
+		}
+		return dst
+	}
+
+	// Convert through JSON, so structs and typed slices become maps and arrays
+	b, err := json.Marshal(v)
+	if err != nil {
+		return appendMsgpackString(dst, fmt.Sprintf("%+v", v))
+	}
+
+	var generic interface{}
+	d := json.NewDecoder(bytes.NewReader(b))
+	d.UseNumber()
+	if err = d.Decode(&generic); err != nil {
+		return appendMsgpackString(dst, string(b))
+	}
+	return appendMsgpack(dst, generic)
+}
+
+func appendMsgpackInt(dst []byte, v int64) []byte {
+	switch {
+	case v >= 0:
+		return appendMsgpackUint(dst, uint64(v))
+	case v >= -32:
+		return append(dst, byte(v))
+	case v >= math.MinInt8:
+		return append(dst, 0xd0, byte(v))
+	case v >= math.MinInt16:
+		return binary.BigEndian.AppendUint16(append(dst, 0xd1), uint16(v))
+	case v >= math.MinInt32:
+		return binary.BigEndian.AppendUint32(append(dst, 0xd2), uint32(v))
+	default:
+		return binary.BigEndian.AppendUint64(append(dst, 0xd3), uint64(v))
+	}
+}
+
+func appendMsgpackUint(dst []byte, v uint64) []byte {
+	switch {
+	case v <= 0x7f:
+		return append(dst, byte(v))
+	case v <= math.MaxUint8:
+		return append(dst, 0xcc, byte(v))
+	case v <= math.MaxUint16:
+		return binary.BigEndian.AppendUint16(append(dst, 0xcd), uint16(v))
+	case v <= math.MaxUint32:
+		return binary.BigEndian.AppendUint32(append(dst, 0xce), uint32(v))
+	default:
+		return binary.BigEndian.AppendUint64(append(dst, 0xcf), v)
+	}
+}
+
+func appendMsgpackString(dst []byte, s string) []byte {
+	n := len(s)
+	switch {
+	case n < 32:
+		dst = append(dst, 0xa0|byte(n))
+	case n <= math.MaxUint8:
+		dst = append(dst, 0xd9, byte(n))
+	case n <= math.MaxUint16:
+		dst = binary.BigEndian.AppendUint16(append(dst, 0xda), uint16(n))
+	default:
+		dst = binary.BigEndian.AppendUint32(append(dst, 0xdb), uint32(n))
+	}
+	return append(dst, s...)
+}
+
+func appendMsgpackBin(dst []byte, b []byte) []byte {
+	n := len(b)
+	switch {
+	case n <= math.MaxUint8:
+		dst = append(dst, 0xc4, byte(n))
+	case n <= math.MaxUint16:
+		dst = binary.BigEndian.AppendUint16(append(dst, 0xc5), uint16(n))
+	default:
+		dst = binary.BigEndian.AppendUint32(append(dst, 0xc6), uint32(n))
+	}
+	return append(dst, b...)
+}
+
+func appendMsgpackArrayHeader(dst []byte, n int) []byte {
+	switch {
+	case n < 16:
+		return append(dst, 0x90|byte(n))
+	case n <= math.MaxUint16:
+		return binary.BigEndian.AppendUint16(append(dst, 0xdc), uint16(n))
+	default:
+		return binary.BigEndian.AppendUint32(append(dst, 0xdd), uint32(n))
+	}
+}
+
+func appendMsgpackMapHeader(dst []byte, n int) []byte {
+	switch {
+	case n < 16:
+		return append(dst, 0x80|byte(n))
+	case n <= math.MaxUint16:
+		return binary.BigEndian.AppendUint16(append(dst, 0xde), uint16(n))
+	default:
+		return binary.BigEndian.AppendUint32(append(dst, 0xdf), uint32(n))
+	}
+}
@@ -0,0 +1,337 @@
+package logkSink
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/go-konsultin/logk"
+	logkExport "github.com/go-konsultin/logk/internal/export"
+	"github.com/go-konsultin/logk/level"
+	logkOption "github.com/go-konsultin/logk/option"
+)
+
+// Default OTLP printer options
+const (
+	defaultOTLPBatchSize     = 512
+	defaultOTLPBatchInterval = time.Second
+	defaultOTLPQueueSize     = 64
+)
+
+type OTLPOptions struct {
+	Client *http.Client
+	Header http.Header
+	// Resource holds attributes of the resource producing entries, e.g. service.name
+	Resource map[string]string
+	// BatchSize is maximum number of entries in a single export request
+	BatchSize int
+	// BatchInterval is maximum time an entry waits before it is exported
+	BatchInterval time.Duration
+	// QueueSize limits number of batches waiting to be exported
+	QueueSize int
+	// MaxRetries is number of retries of a failed export on network errors, 429 and 5xx responses
+	MaxRetries int
+	// RetryBackoff is delay before the first retry, it is doubled on each retry
+	RetryBackoff time.Duration
+	// Overflow is applied when queue is full
+	Overflow logk.OverflowPolicy
+	// OnError is called from background goroutine when a batch can't be exported
+	OnError        func(err error)
+	PrinterOptions []logk.PrinterOption
+}
+
+type OTLPOption = func(*OTLPOptions)
+
+func WithOTLPClient(c *http.Client) OTLPOption {
+	return func(o *OTLPOptions) {
+		o.Client = c
+	}
+}
+
+func WithOTLPHeader(key, value string) OTLPOption {
+	return func(o *OTLPOptions) {
+		o.Header.Add(key, value)
+	}
+}
+
+func WithOTLPResource(key, value string) OTLPOption {
+	return func(o *OTLPOptions) {
+		o.Resource[key] = value
+	}
+}
+
+func WithOTLPServiceName(name string) OTLPOption {
+	return WithOTLPResource(logkExport.OTLPServiceNameKey, name)
+}
+
+func WithOTLPBatch(size int, interval time.Duration) OTLPOption {
+	return func(o *OTLPOptions) {
+		o.BatchSize = size
+		o.BatchInterval = interval
+	}
+}
+
+func WithOTLPQueueSize(n int) OTLPOption {
+	return func(o *OTLPOptions) {
+		o.QueueSize = n
+	}
+}
+
+func WithOTLPRetry(maxRetries int, backoff time.Duration) OTLPOption {
+	return func(o *OTLPOptions) {
+		o.MaxRetries = maxRetries
+		o.RetryBackoff = backoff
+	}
+}
+
+func WithOTLPOverflowPolicy(p logk.OverflowPolicy) OTLPOption {
+	return func(o *OTLPOptions) {
+		o.Overflow = p
+	}
+}
+
+func WithOTLPOnError(fn func(err error)) OTLPOption {
+	return func(o *OTLPOptions) {
+		o.OnError = fn
+	}
+}
+
+func WithOTLPPrinterOptions(args ...logk.PrinterOption) OTLPOption {
+	return func(o *OTLPOptions) {
+		o.PrinterOptions = append(o.PrinterOptions, args...)
+	}
+}
+
+// OTLPPrinter exports entries in batches to an OpenTelemetry collector with OTLP/HTTP JSON encoding, e.g. to
+// http://localhost:4318/v1/logs. Levels are mapped to OpenTelemetry severity numbers, and metadata, namespace and
+// other fields are written as log record attributes.
+// OTLP/gRPC is supported by logkotlp module, so this package doesn't depend on gRPC
+type OTLPPrinter struct {
+	url      string
+	options  OTLPOptions
+	resource []otlpKeyValue
+	batcher  *logkExport.Batcher
+}
+
+func NewOTLPPrinter(url string, args ...OTLPOption) *OTLPPrinter {
+	if url == "" {
+		panic(fmt.Errorf("%s: otlp printer url is empty", pkgName))
+	}
+
+	o := OTLPOptions{
+		Client:        &http.Client{Timeout: defaultHTTPTimeout},
+		Header:        make(http.Header),
+		Resource:      make(map[string]string),
+		BatchSize:     defaultOTLPBatchSize,
+		BatchInterval: defaultOTLPBatchInterval,
+		QueueSize:     defaultOTLPQueueSize,
+		MaxRetries:    defaultMaxRetries,
+		RetryBackoff:  defaultRetryBackoff,
+	}
+	for _, fn := range args {
+		fn(&o)
+	}
+
+	p := OTLPPrinter{url: url, options: o}
+	for _, k := range sortedKeys(o.Resource) {
+		p.resource = append(p.resource, otlpKeyValue{Key: k, Value: otlpAnyValue(o.Resource[k])})
+	}
+
+	d := logkExport.NewDispatcher(1, o.QueueSize, 0, o.Overflow, nil, p.send)
+	p.batcher = logkExport.NewBatcher(o.BatchSize, 0, o.BatchInterval, p.encode, d)
+
+	return &p
+}
+
+func (p *OTLPPrinter) Print(namespace string, lv level.LogLevel, msg string, options *logkOption.Options) {
+	entry := logk.NewEntry(namespace, lv, msg, options, p.options.PrinterOptions...)
+	record, err := json.Marshal(newOTLPLogRecord(entry))
+	if err != nil {
+		return
+	}
+	p.batcher.Add(record)
+}
+
+// Dropped returns number of batches that are discarded by overflow policy
+func (p *OTLPPrinter) Dropped() uint64 {
+	return p.batcher.Dropped()
+}
+
+// Flush exports pending entries and waits until all batches are exported
+func (p *OTLPPrinter) Flush() error {
+	p.batcher.Flush()
+	return nil
+}
+
+// Close exports remaining entries and stops background worker
+func (p *OTLPPrinter) Close() error {
+	p.batcher.Close()
+	return nil
+}
+
+// encode wraps log records into OTLP export request
+func (p *OTLPPrinter) encode(records [][]byte) []byte {
+	req := otlpExportRequest{
+		ResourceLogs: []otlpResourceLogs{{
+			Resource: otlpResource{Attributes: p.resource},
+			ScopeLogs: []otlpScopeLogs{{
+				Scope:      otlpScope{Name: logkExport.OTLPScopeName},
+				LogRecords: make([]json.RawMessage, len(records)),
+			}},
+		}},
+	}
+	for i, r := range records {
+		req.ResourceLogs[0].ScopeLogs[0].LogRecords[i] = r
+	}
+
+	b, _ := json.Marshal(req)
+	return b
+}
+
+func (p *OTLPPrinter) send(payload []byte) {
+	header := p.options.Header.Clone()
+	header.Set("Content-Type", "application/json")
+
+	err := logkExport.Retry(p.options.MaxRetries, p.options.RetryBackoff, func() error {
+		return post(p.options.Client, p.url, header, payload)
+	})
+	if err != nil && p.options.OnError != nil {
+		p.options.OnError(err)
+	}
+}
+
+// OTLP/HTTP JSON encoding of ExportLogsServiceRequest. 64-bit integers are encoded as strings and trace and span
+// id as hex, as specified by OTLP
+type otlpExportRequest struct {
+	ResourceLogs []otlpResourceLogs `json:"resourceLogs"`
+}
+
+type otlpResourceLogs struct {
+	Resource  otlpResource    `json:"resource"`
+	ScopeLogs []otlpScopeLogs `json:"scopeLogs"`
+}
+
+type otlpResource struct {
+	Attributes []otlpKeyValue `json:"attributes,omitempty"`
+}
+
+type otlpScopeLogs struct {
+	Scope      otlpScope         `json:"scope"`
+	LogRecords []json.RawMessage `json:"logRecords"`
+}
+
+type otlpScope struct {
+	Name string `json:"name"`
+}
+
+type otlpLogRecord struct {
+	TimeUnixNano         string                 `json:"timeUnixNano"`
+	ObservedTimeUnixNano string                 `json:"observedTimeUnixNano"`
+	SeverityNumber       int                    `json:"severityNumber,omitempty"`
+	SeverityText         string                 `json:"severityText"`
+	Body                 map[string]interface{} `json:"body"`
+	Attributes           []otlpKeyValue         `json:"attributes,omitempty"`
+	TraceId              string                 `json:"traceId,omitempty"`
+	SpanId               string                 `json:"spanId,omitempty"`
+}
+
+type otlpKeyValue struct {
+	Key   string                 `json:"key"`
+	Value map[string]interface{} `json:"value"`
+}
+
+func newOTLPLogRecord(entry *logk.Entry) *otlpLogRecord {
+	r := otlpLogRecord{
+		TimeUnixNano:         strconv.FormatInt(entry.Time.UnixNano(), 10),
+		ObservedTimeUnixNano: strconv.FormatInt(logk.Now().UnixNano(), 10),
+		SeverityNumber:       logkExport.OTLPSeverity[entry.Level],
+		SeverityText:         strings.ToUpper(level.String(entry.Level)),
+		Body:                 otlpAnyValue(entry.Message),
+		TraceId:              entry.TraceId,
+		SpanId:               entry.SpanId,
+	}
+
+	fields := logkExport.OTLPAttributes(entry)
+	for _, k := range sortedKeys(fields) {
+		r.Attributes = append(r.Attributes, otlpKeyValue{Key: k, Value: otlpAnyValue(fields[k])})
+	}
+
+	return &r
+}
+
+// otlpAnyValue converts v to OTLP AnyValue. Values of unknown types are converted through their JSON form
+func otlpAnyValue(v interface{}) map[string]interface{} {
+	switch val := v.(type) {
+	case nil:
+		return map[string]interface{}{}
+	case string:
+		return map[string]interface{}{"stringValue": val}
+	case bool:
+		return map[string]interface{}{"boolValue": val}
+	case int:
+		return map[string]interface{}{"intValue": strconv.FormatInt(int64(val), 10)}
+	case int8, int16, int32, int64, uint, uint8, uint16, uint32, uint64:
+		return map[string]interface{}{"intValue": fmt.Sprint(val)}
+	case float32:
+		return map[string]interface{}{"doubleValue": float64(val)}
+	case float64:
+		return map[string]interface{}{"doubleValue": val}
+	case json.Number:
+		if i, err := val.Int64(); err == nil {
+			return map[string]interface{}{"intValue": strconv.FormatInt(i, 10)}
+		}
+		f, _ := val.Float64()
+		return map[string]interface{}{"doubleValue": f}
+	case error:
+		return map[string]interface{}{"stringValue": val.Error()}
+	case fmt.Stringer:
+		return map[string]interface{}{"stringValue": val.String()}
+	case map[string]string:
+		values := make([]otlpKeyValue, 0, len(val))
+		for _, k := range sortedKeys(val) {
+			values = append(values, otlpKeyValue{Key: k, Value: otlpAnyValue(val[k])})
+		}
+		return map[string]interface{}{"kvlistValue": map[string]interface{}{"values": values}}
+	case map[string]interface{}:
+		values := make([]otlpKeyValue, 0, len(val))
+		for _, k := range sortedKeys(val) {
+			values = append(values, otlpKeyValue{Key: k, Value: otlpAnyValue(val[k])})
+		}
+		return map[string]interface{}{"kvlistValue": map[string]interface{}{"values": values}}
+	case []interface{}:
+		values := make([]map[string]interface{}, 0, len(val))
+		for _, item := range val {
+			values = append(values, otlpAnyValue(item))
+		}
+		return map[string]interface{}{"arrayValue": map[string]interface{}{"values": values}}
+	}
+
+	// Convert through JSON, so structs and typed slices become maps and arrays
+	b, err := json.Marshal(v)
+	if err != nil {
+		return map[string]interface{}{"stringValue": fmt.Sprintf("%+v", v)}
+	}
+
+	var generic interface{}
+	d := json.NewDecoder(bytes.NewReader(b))
+	d.UseNumber()
+	if err = d.Decode(&generic); err != nil {
+		return map[string]interface{}{"stringValue": string(b)}
+	}
+
+	return otlpAnyValue(generic)
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
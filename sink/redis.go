@@ -0,0 +1,438 @@
+package logkSink
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/tls"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"net"
+	"strconv"
+	"time"
+
+	"github.com/go-konsultin/logk"
+	logkExport "github.com/go-konsultin/logk/internal/export"
+	"github.com/go-konsultin/logk/level"
+	logkOption "github.com/go-konsultin/logk/option"
+)
+
+// Default Redis printer options
+const (
+	defaultRedisField     = "entry"
+	defaultRedisBatchSize = 256
+	defaultRedisTimeout   = 10 * time.Second
+	maxRedisReplyDepth    = 8
+)
+
+type RedisOptions struct {
+	// Key returns key of stream that entry is added to. Default is key of NewRedisPrinter
+	Key func(entry *logk.Entry) string
+	// Field is name of stream entry field holding encoded entry, default is "entry"
+	Field string
+	// MaxLen trims streams to about MaxLen entries on each add, so they don't grow without bound. Zero disables
+	// trimming
+	MaxLen int64
+	// ExactTrim trims streams to exactly MaxLen entries, which is slower than default approximate trimming
+	ExactTrim bool
+	// Username and Password authenticate connection. Username is only supported by Redis 6 ACL
+	Username string
+	Password string
+	// DB is selected after connecting
+	DB int
+	// TLS enables TLS on TCP connections
+	TLS *tls.Config
+	// Timeout limits dialing, writing and reading replies
+	Timeout time.Duration
+	// BatchSize is maximum number of entries that are pipelined in a single write
+	BatchSize int
+	// BatchInterval is maximum time an entry waits before it is sent
+	BatchInterval time.Duration
+	// QueueSize limits number of batches waiting to be sent
+	QueueSize int
+	// MaxRetries is number of retries of a failed batch, reconnecting before each one
+	MaxRetries int
+	// RetryBackoff is delay before the first retry, it is doubled on each retry
+	RetryBackoff time.Duration
+	// Overflow is applied when queue is full
+	Overflow logk.OverflowPolicy
+	// OnError is called from background goroutine when entries can't be added
+	OnError func(err error)
+	// Encoder formats field value, default is JSON
+	Encoder        logk.Encoder
+	PrinterOptions []logk.PrinterOption
+}
+
+type RedisOption = func(*RedisOptions)
+
+func WithRedisKeyFunc(fn func(entry *logk.Entry) string) RedisOption {
+	return func(o *RedisOptions) {
+		o.Key = fn
+	}
+}
+
+func WithRedisField(field string) RedisOption {
+	return func(o *RedisOptions) {
+		o.Field = field
+	}
+}
+
+func WithRedisMaxLen(maxLen int64, exact bool) RedisOption {
+	return func(o *RedisOptions) {
+		o.MaxLen = maxLen
+		o.ExactTrim = exact
+	}
+}
+
+func WithRedisAuth(username, password string) RedisOption {
+	return func(o *RedisOptions) {
+		o.Username = username
+		o.Password = password
+	}
+}
+
+func WithRedisDB(db int) RedisOption {
+	return func(o *RedisOptions) {
+		o.DB = db
+	}
+}
+
+func WithRedisTLS(config *tls.Config) RedisOption {
+	return func(o *RedisOptions) {
+		o.TLS = config
+	}
+}
+
+func WithRedisTimeout(timeout time.Duration) RedisOption {
+	return func(o *RedisOptions) {
+		o.Timeout = timeout
+	}
+}
+
+func WithRedisBatch(size int, interval time.Duration) RedisOption {
+	return func(o *RedisOptions) {
+		o.BatchSize = size
+		o.BatchInterval = interval
+	}
+}
+
+func WithRedisQueueSize(n int) RedisOption {
+	return func(o *RedisOptions) {
+		o.QueueSize = n
+	}
+}
+
+func WithRedisRetry(maxRetries int, backoff time.Duration) RedisOption {
+	return func(o *RedisOptions) {
+		o.MaxRetries = maxRetries
+		o.RetryBackoff = backoff
+	}
+}
+
+func WithRedisOverflowPolicy(p logk.OverflowPolicy) RedisOption {
+	return func(o *RedisOptions) {
+		o.Overflow = p
+	}
+}
+
+func WithRedisOnError(fn func(err error)) RedisOption {
+	return func(o *RedisOptions) {
+		o.OnError = fn
+	}
+}
+
+func WithRedisEncoder(enc logk.Encoder) RedisOption {
+	return func(o *RedisOptions) {
+		o.Encoder = enc
+	}
+}
+
+func WithRedisPrinterOptions(args ...logk.PrinterOption) RedisOption {
+	return func(o *RedisOptions) {
+		o.PrinterOptions = append(o.PrinterOptions, args...)
+	}
+}
+
+// RedisPrinter adds entries to Redis Streams with XADD, optionally trimming streams with MAXLEN. Batches are
+// pipelined over a single connection, so a batch takes one round trip. Commands are written in RESP without Redis
+// client dependency
+type RedisPrinter struct {
+	network string
+	addr    string
+	options RedisOptions
+	batcher *logkExport.Batcher
+
+	// conn and reader are only accessed by the single dispatcher worker, and by Close after it is stopped
+	conn   net.Conn
+	reader *bufio.Reader
+}
+
+// redisError is error reply of a command, e.g. WRONGTYPE. It fails the command, but not the connection
+type redisError string
+
+func (e redisError) Error() string {
+	return string(e)
+}
+
+// NewRedisPrinter creates printer that adds entries to stream key. Network is "tcp" or "unix", addr is host:port,
+// e.g. localhost:6379, or path of unix socket. Connection is established lazily and re-established when it breaks
+func NewRedisPrinter(network, addr, key string, args ...RedisOption) (*RedisPrinter, error) {
+	o := RedisOptions{
+		Field:         defaultRedisField,
+		Timeout:       defaultRedisTimeout,
+		BatchSize:     defaultRedisBatchSize,
+		BatchInterval: defaultOTLPBatchInterval,
+		QueueSize:     defaultOTLPQueueSize,
+		MaxRetries:    defaultMaxRetries,
+		RetryBackoff:  defaultRetryBackoff,
+		Encoder:       logk.NewJSONEncoder(),
+	}
+	for _, fn := range args {
+		fn(&o)
+	}
+
+	switch network {
+	case "tcp", "tcp4", "tcp6", "unix":
+	default:
+		return nil, fmt.Errorf("%s: unknown redis network %q", pkgName, network)
+	}
+
+	if addr == "" {
+		return nil, fmt.Errorf("%s: redis address is empty", pkgName)
+	}
+
+	if o.Key == nil {
+		if key == "" {
+			return nil, fmt.Errorf("%s: redis stream key is empty", pkgName)
+		}
+		o.Key = func(*logk.Entry) string {
+			return key
+		}
+	}
+
+	p := RedisPrinter{network: network, addr: addr, options: o}
+	d := logkExport.NewDispatcher(1, o.QueueSize, 0, o.Overflow, nil, p.send)
+	p.batcher = logkExport.NewBatcher(o.BatchSize, 0, o.BatchInterval, p.encode, d)
+
+	return &p, nil
+}
+
+func (p *RedisPrinter) Print(namespace string, lv level.LogLevel, msg string, options *logkOption.Options) {
+	entry := logk.NewEntry(namespace, lv, msg, options, p.options.PrinterOptions...)
+	value, err := p.options.Encoder.Encode(entry)
+	if err != nil {
+		return
+	}
+	value = bytes.TrimSuffix(value, []byte{'\n'})
+
+	args := [][]byte{[]byte("XADD"), []byte(p.options.Key(entry))}
+	if p.options.MaxLen > 0 {
+		trim := "~"
+		if p.options.ExactTrim {
+			trim = "="
+		}
+		args = append(args, []byte("MAXLEN"), []byte(trim), strconv.AppendInt(nil, p.options.MaxLen, 10))
+	}
+	args = append(args, []byte("*"), []byte(p.options.Field), value)
+	p.batcher.Add(appendRedisCommand(nil, args...))
+}
+
+// Dropped returns number of batches that are discarded by overflow policy
+func (p *RedisPrinter) Dropped() uint64 {
+	return p.batcher.Dropped()
+}
+
+// Flush sends pending entries and waits until all batches are sent
+func (p *RedisPrinter) Flush() error {
+	p.batcher.Flush()
+	return nil
+}
+
+// Close sends remaining entries, stops background worker and closes connection
+func (p *RedisPrinter) Close() error {
+	p.batcher.Close()
+	if p.conn == nil {
+		return nil
+	}
+	err := p.conn.Close()
+	p.conn = nil
+	return err
+}
+
+// encode frames commands with their length, so send can resend commands that didn't get a reply
+func (p *RedisPrinter) encode(commands [][]byte) []byte {
+	var payload []byte
+	for _, c := range commands {
+		payload = binary.AppendUvarint(payload, uint64(len(c)))
+		payload = append(payload, c...)
+	}
+	return payload
+}
+
+// send pipelines commands of payload. Replies are read in order, so on retry only commands without a reply are
+// resent and entries are not added twice
+func (p *RedisPrinter) send(payload []byte) {
+	var commands [][]byte
+	for len(payload) > 0 {
+		n, size := binary.Uvarint(payload)
+		commands = append(commands, payload[size:size+int(n)])
+		payload = payload[size+int(n):]
+	}
+
+	err := logkExport.Retry(p.options.MaxRetries, p.options.RetryBackoff, func() error {
+		done, err := p.pipeline(commands)
+		commands = commands[done:]
+		if err == nil {
+			return nil
+		}
+
+		if p.conn != nil {
+			_ = p.conn.Close()
+			p.conn = nil
+		}
+		return logkExport.Retryable(err)
+	})
+	if err != nil && p.options.OnError != nil {
+		p.options.OnError(fmt.Errorf("%s: failed to add %d entries to redis: %w", pkgName, len(commands), err))
+	}
+}
+
+// pipeline writes commands and reads their replies. It returns number of commands that got a reply, and error of
+// connection. Error replies are passed to OnError, as resending those commands fails again
+func (p *RedisPrinter) pipeline(commands [][]byte) (int, error) {
+	if p.conn == nil {
+		if err := p.connect(); err != nil {
+			return 0, err
+		}
+	}
+
+	_ = p.conn.SetWriteDeadline(time.Now().Add(p.options.Timeout))
+	for _, c := range commands {
+		if _, err := p.conn.Write(c); err != nil {
+			return 0, err
+		}
+	}
+
+	_ = p.conn.SetReadDeadline(time.Now().Add(p.options.Timeout))
+	for i := range commands {
+		err := readRedisReply(p.reader, 0)
+		var replyErr redisError
+		if errors.As(err, &replyErr) {
+			if p.options.OnError != nil {
+				p.options.OnError(fmt.Errorf("%s: redis: %w", pkgName, err))
+			}
+			continue
+		}
+		if err != nil {
+			return i, err
+		}
+	}
+	return len(commands), nil
+}
+
+// connect dials server, authenticates and selects database
+func (p *RedisPrinter) connect() error {
+	dialer := net.Dialer{Timeout: p.options.Timeout}
+
+	var conn net.Conn
+	var err error
+	if p.options.TLS != nil && p.network != "unix" {
+		conn, err = tls.DialWithDialer(&dialer, p.network, p.addr, p.options.TLS)
+	} else {
+		conn, err = dialer.Dial(p.network, p.addr)
+	}
+	if err != nil {
+		return fmt.Errorf("%s: failed to connect to redis: %w", pkgName, err)
+	}
+	reader := bufio.NewReader(conn)
+
+	var setup []byte
+	var replies int
+	if p.options.Password != "" {
+		if p.options.Username != "" {
+			setup = appendRedisCommand(setup, []byte("AUTH"), []byte(p.options.Username), []byte(p.options.Password))
+		} else {
+			setup = appendRedisCommand(setup, []byte("AUTH"), []byte(p.options.Password))
+		}
+		replies++
+	}
+	if p.options.DB != 0 {
+		setup = appendRedisCommand(setup, []byte("SELECT"), strconv.AppendInt(nil, int64(p.options.DB), 10))
+		replies++
+	}
+
+	if replies > 0 {
+		_ = conn.SetDeadline(time.Now().Add(p.options.Timeout))
+		_, err = conn.Write(setup)
+		for ; err == nil && replies > 0; replies-- {
+			err = readRedisReply(reader, 0)
+		}
+		if err != nil {
+			_ = conn.Close()
+			return fmt.Errorf("%s: failed to set up redis connection: %w", pkgName, err)
+		}
+	}
+
+	p.conn = conn
+	p.reader = reader
+	return nil
+}
+
+// appendRedisCommand appends command as RESP array of bulk strings
+func appendRedisCommand(dst []byte, args ...[]byte) []byte {
+	dst = append(dst, '*')
+	dst = strconv.AppendInt(dst, int64(len(args)), 10)
+	dst = append(dst, '\r', '\n')
+	for _, arg := range args {
+		dst = append(dst, '$')
+		dst = strconv.AppendInt(dst, int64(len(arg)), 10)
+		dst = append(dst, '\r', '\n')
+		dst = append(dst, arg...)
+		dst = append(dst, '\r', '\n')
+	}
+	return dst
+}
+
+// readRedisReply reads and discards a RESP reply. Error reply is returned as redisError
+func readRedisReply(r *bufio.Reader, depth int) error {
+	line, err := r.ReadSlice('\n')
+	if err != nil {
+		return err
+	}
+	if len(line) < 3 || line[len(line)-2] != '\r' {
+		return fmt.Errorf("%s: malformed redis reply %q", pkgName, line)
+	}
+	line = line[:len(line)-2]
+
+	switch line[0] {
+	case '+', ':':
+		return nil
+	case '-':
+		return redisError(line[1:])
+	case '$':
+		n, err := strconv.Atoi(string(line[1:]))
+		if err != nil {
+			return fmt.Errorf("%s: malformed redis reply %q", pkgName, line)
+		}
+		if n < 0 {
+			return nil
+		}
+		_, err = r.Discard(n + 2)
+		return err
+	case '*':
+		n, err := strconv.Atoi(string(line[1:]))
+		if err != nil || depth >= maxRedisReplyDepth {
+			return fmt.Errorf("%s: malformed redis reply %q", pkgName, line)
+		}
+		// Error replies of elements don't fail the array
+		for ; n > 0; n-- {
+			var replyErr redisError
+			if err := readRedisReply(r, depth+1); err != nil && !errors.As(err, &replyErr) {
+				return err
+			}
+		}
+		return nil
+	default:
+		return fmt.Errorf("%s: unexpected redis reply %q", pkgName, line)
+	}
+}
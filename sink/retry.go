@@ -0,0 +1,43 @@
+package logkSink
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	logkExport "github.com/go-konsultin/logk/internal/export"
+)
+
+// Default retry options
+const (
+	defaultMaxRetries   = 3
+	defaultRetryBackoff = 500 * time.Millisecond
+)
+
+// post sends payload and returns error marked with logkExport.Retryable on network errors, 429 and 5xx responses
+func post(client *http.Client, url string, header http.Header, payload []byte) error {
+	req, err := http.NewRequest(http.MethodPost, url, bytes.NewReader(payload))
+	if err != nil {
+		return err
+	}
+	req.Header = header.Clone()
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return logkExport.Retryable(err)
+	}
+	_, _ = io.Copy(io.Discard, resp.Body)
+	_ = resp.Body.Close()
+
+	if resp.StatusCode < 300 {
+		return nil
+	}
+
+	err = fmt.Errorf("%s: %s responded with status %d", pkgName, url, resp.StatusCode)
+	if resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode >= 500 {
+		return logkExport.Retryable(err)
+	}
+	return err
+}
@@ -0,0 +1,241 @@
+package logkSink
+
+import (
+	"errors"
+	"fmt"
+	"math/rand/v2"
+	"time"
+
+	"github.com/go-konsultin/logk"
+	logkExport "github.com/go-konsultin/logk/internal/export"
+	"github.com/go-konsultin/logk/level"
+	logkOption "github.com/go-konsultin/logk/option"
+)
+
+// Default retry printer options
+const (
+	defaultRetryMaxAttempts = 5
+	defaultRetryMultiplier  = 2
+	defaultRetryJitter      = 0.2
+)
+
+// SendFunc sends encoded entry. Errors are retried, unless they are wrapped with PermanentError
+type SendFunc = func(payload []byte) error
+
+// permanentError marks failures that fail again when retried, e.g. 400 response
+type permanentError struct {
+	err error
+}
+
+func (e *permanentError) Error() string {
+	return e.err.Error()
+}
+
+func (e *permanentError) Unwrap() error {
+	return e.err
+}
+
+// PermanentError wraps err, so RetryPrinter passes entry to dead letter callback without retrying it
+func PermanentError(err error) error {
+	if err == nil {
+		return nil
+	}
+	return &permanentError{err: err}
+}
+
+type RetryOptions struct {
+	// MaxAttempts is maximum number of attempts to send an entry, including the first one
+	MaxAttempts int
+	// InitialBackoff is delay before the first retry
+	InitialBackoff time.Duration
+	// MaxBackoff limits delay between retries
+	MaxBackoff time.Duration
+	// Multiplier grows delay after each retry
+	Multiplier float64
+	// Jitter randomizes each delay by up to this fraction of it, e.g. 0.2 gives delays within ±20%, so clients that
+	// failed together don't retry together
+	Jitter float64
+	// Concurrency is number of entries that are sent at the same time. Entries are sent in order only with 1
+	Concurrency int
+	// QueueSize limits number of entries waiting to be sent
+	QueueSize int
+	// Overflow is applied when queue is full
+	Overflow logk.OverflowPolicy
+	// DeadLetter is called from background goroutine with entries that failed permanently, after MaxAttempts or
+	// with PermanentError, e.g. to keep them in a local file
+	DeadLetter func(payload []byte, err error)
+	// Encoder formats entries that are sent, default is JSON
+	Encoder        logk.Encoder
+	PrinterOptions []logk.PrinterOption
+}
+
+type RetryOption = func(*RetryOptions)
+
+func WithRetryMaxAttempts(n int) RetryOption {
+	return func(o *RetryOptions) {
+		o.MaxAttempts = n
+	}
+}
+
+func WithRetryBackoff(initial, maxBackoff time.Duration, multiplier float64) RetryOption {
+	return func(o *RetryOptions) {
+		o.InitialBackoff = initial
+		o.MaxBackoff = maxBackoff
+		o.Multiplier = multiplier
+	}
+}
+
+func WithRetryJitter(fraction float64) RetryOption {
+	return func(o *RetryOptions) {
+		o.Jitter = fraction
+	}
+}
+
+func WithRetryConcurrency(n int) RetryOption {
+	return func(o *RetryOptions) {
+		o.Concurrency = n
+	}
+}
+
+func WithRetryQueueSize(n int) RetryOption {
+	return func(o *RetryOptions) {
+		o.QueueSize = n
+	}
+}
+
+func WithRetryOverflowPolicy(p logk.OverflowPolicy) RetryOption {
+	return func(o *RetryOptions) {
+		o.Overflow = p
+	}
+}
+
+func WithRetryDeadLetter(fn func(payload []byte, err error)) RetryOption {
+	return func(o *RetryOptions) {
+		o.DeadLetter = fn
+	}
+}
+
+func WithRetryEncoder(enc logk.Encoder) RetryOption {
+	return func(o *RetryOptions) {
+		o.Encoder = enc
+	}
+}
+
+func WithRetryPrinterOptions(args ...logk.PrinterOption) RetryOption {
+	return func(o *RetryOptions) {
+		o.PrinterOptions = append(o.PrinterOptions, args...)
+	}
+}
+
+// RetryPrinter encodes entries and sends them with send from background goroutines, retrying failed entries with
+// exponential backoff and jitter. Entries that fail permanently are passed to dead letter callback, so custom
+// network sinks only implement sending a single entry:
+//
+//	p := logkSink.NewRetryPrinter(func(payload []byte) error {
+//		return client.Publish(ctx, topic, payload)
+//	}, logkSink.WithRetryDeadLetter(spool.Write))
+type RetryPrinter struct {
+	send       SendFunc
+	options    RetryOptions
+	dispatcher *logkExport.Dispatcher
+}
+
+func NewRetryPrinter(send SendFunc, args ...RetryOption) *RetryPrinter {
+	if send == nil {
+		panic(fmt.Errorf("%s: retry printer send function is nil", pkgName))
+	}
+
+	o := RetryOptions{
+		MaxAttempts:    defaultRetryMaxAttempts,
+		InitialBackoff: defaultRetryBackoff,
+		MaxBackoff:     logkExport.MaxRetryBackoff,
+		Multiplier:     defaultRetryMultiplier,
+		Jitter:         defaultRetryJitter,
+		Concurrency:    1,
+		QueueSize:      defaultHTTPQueueSize,
+		Encoder:        logk.NewJSONEncoder(),
+	}
+	for _, fn := range args {
+		fn(&o)
+	}
+
+	if o.MaxAttempts < 1 {
+		o.MaxAttempts = 1
+	}
+
+	if o.Multiplier < 1 {
+		o.Multiplier = 1
+	}
+
+	o.Jitter = min(max(o.Jitter, 0), 1)
+
+	p := RetryPrinter{send: send, options: o}
+	p.dispatcher = logkExport.NewDispatcher(o.Concurrency, o.QueueSize, 0, o.Overflow, nil, p.deliver)
+
+	return &p
+}
+
+func (p *RetryPrinter) Print(namespace string, lv level.LogLevel, msg string, options *logkOption.Options) {
+	entry := logk.NewEntry(namespace, lv, msg, options, p.options.PrinterOptions...)
+	payload, err := p.options.Encoder.Encode(entry)
+	if err != nil {
+		return
+	}
+	p.dispatcher.Submit(payload)
+}
+
+// QueueLen returns number of entries waiting to be sent
+func (p *RetryPrinter) QueueLen() int {
+	return p.dispatcher.QueueLen()
+}
+
+// QueueCap returns maximum number of entries waiting to be sent
+func (p *RetryPrinter) QueueCap() int {
+	return p.dispatcher.QueueCap()
+}
+
+// Dropped returns number of entries that are discarded by overflow policy
+func (p *RetryPrinter) Dropped() uint64 {
+	return p.dispatcher.Dropped()
+}
+
+// Flush waits until all queued entries are sent or passed to dead letter callback
+func (p *RetryPrinter) Flush() error {
+	p.dispatcher.Flush()
+	return nil
+}
+
+// Close sends remaining entries, retrying them as usual, and stops background workers
+func (p *RetryPrinter) Close() error {
+	p.dispatcher.Close()
+	return nil
+}
+
+func (p *RetryPrinter) deliver(payload []byte) {
+	backoff := p.options.InitialBackoff
+	for attempt := 1; ; attempt++ {
+		err := p.send(payload)
+		if err == nil {
+			return
+		}
+
+		var permanent *permanentError
+		if errors.As(err, &permanent) || attempt >= p.options.MaxAttempts {
+			if p.options.DeadLetter != nil {
+				p.options.DeadLetter(payload, err)
+			}
+			return
+		}
+
+		time.Sleep(jitter(backoff, p.options.Jitter))
+		backoff = min(time.Duration(float64(backoff)*p.options.Multiplier), p.options.MaxBackoff)
+	}
+}
+
+// jitter returns d randomized by up to fraction of it in both directions
+func jitter(d time.Duration, fraction float64) time.Duration {
+	if fraction == 0 || d <= 0 {
+		return d
+	}
+	return time.Duration(float64(d) * (1 + fraction*(2*rand.Float64()-1)))
+}
@@ -0,0 +1,3 @@
+package logkSink
+
+const pkgName = "logk/sink"
@@ -0,0 +1,91 @@
+package logkSink
+
+import "encoding/binary"
+
+// Snappy block format constants, see https://github.com/google/snappy/blob/main/format_description.txt
+const (
+	// snappyBlockSize bounds copy offsets, so they fit in 2 bytes
+	snappyBlockSize    = 1 << 16
+	snappyMinBlockSize = 16
+	snappyTableBits    = 14
+	snappyMaxCopy      = 64
+	snappyTagLiteral   = 0x00
+	snappyTagCopy2     = 0x02
+)
+
+// snappyEncode compresses src in snappy block format, as expected by Loki and Prometheus remote APIs. It's a greedy
+// encoder that trades compression ratio for simplicity, and only emits literals and copies with 2-byte offsets
+func snappyEncode(src []byte) []byte {
+	dst := binary.AppendUvarint(make([]byte, 0, len(src)/2+16), uint64(len(src)))
+	for len(src) > 0 {
+		n := min(len(src), snappyBlockSize)
+		dst = snappyEncodeBlock(dst, src[:n])
+		src = src[n:]
+	}
+	return dst
+}
+
+func snappyEncodeBlock(dst, src []byte) []byte {
+	if len(src) < snappyMinBlockSize {
+		return snappyLiteral(dst, src)
+	}
+
+	// table holds position+1 of the last occurrence of 4-byte hash, zero means none
+	var table [1 << snappyTableBits]int32
+	literal := 0
+	for i := 0; i+4 <= len(src); {
+		v := binary.LittleEndian.Uint32(src[i:])
+		h := (v * 0x1e35a7bd) >> (32 - snappyTableBits)
+		candidate := int(table[h]) - 1
+		table[h] = int32(i + 1)
+
+		if candidate < 0 || binary.LittleEndian.Uint32(src[candidate:]) != v {
+			i++
+			continue
+		}
+
+		// Extend match
+		n := 4
+		for i+n < len(src) && src[candidate+n] == src[i+n] {
+			n++
+		}
+
+		dst = snappyLiteral(dst, src[literal:i])
+		dst = snappyCopy(dst, i-candidate, n)
+		i += n
+		literal = i
+	}
+	return snappyLiteral(dst, src[literal:])
+}
+
+// snappyLiteral appends literal element with length encoded in tag, or in up to 4 following bytes
+func snappyLiteral(dst, lit []byte) []byte {
+	if len(lit) == 0 {
+		return dst
+	}
+
+	n := uint32(len(lit) - 1)
+	switch {
+	case n < 60:
+		dst = append(dst, byte(n)<<2|snappyTagLiteral)
+	case n < 1<<8:
+		dst = append(dst, 60<<2|snappyTagLiteral, byte(n))
+	case n < 1<<16:
+		dst = append(dst, 61<<2|snappyTagLiteral, byte(n), byte(n>>8))
+	case n < 1<<24:
+		dst = append(dst, 62<<2|snappyTagLiteral, byte(n), byte(n>>8), byte(n>>16))
+	default:
+		dst = append(dst, 63<<2|snappyTagLiteral, byte(n), byte(n>>8), byte(n>>16), byte(n>>24))
+	}
+	return append(dst, lit...)
+}
+
+// snappyCopy appends copy elements with 2-byte offset, each copying up to 64 bytes
+func snappyCopy(dst []byte, offset, length int) []byte {
+	for length > 0 {
+		n := min(length, snappyMaxCopy)
+		dst = append(dst, byte(n-1)<<2|snappyTagCopy2, byte(offset), byte(offset>>8))
+		length -= n
+	}
+	return dst
+}
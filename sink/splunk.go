@@ -0,0 +1,376 @@
+package logkSink
+
+import (
+	"bytes"
+	"compress/gzip"
+	"crypto/rand"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/go-konsultin/logk"
+	logkExport "github.com/go-konsultin/logk/internal/export"
+	"github.com/go-konsultin/logk/level"
+	logkOption "github.com/go-konsultin/logk/option"
+)
+
+// Default Splunk printer options
+const (
+	defaultSplunkBatchSize       = 512
+	defaultSplunkAckTimeout      = time.Minute
+	defaultSplunkAckPollInterval = time.Second
+	splunkEventPath              = "/services/collector/event"
+	splunkAckPath                = "/services/collector/ack"
+	splunkChannelHeader          = "X-Splunk-Request-Channel"
+)
+
+type SplunkOptions struct {
+	Client *http.Client
+	// Index, Source, SourceType and Host are metadata of events. Empty values are omitted, so defaults of token
+	// apply, except Host which defaults to hostname
+	Index      string
+	Source     string
+	SourceType string
+	Host       string
+	// Gzip compresses requests
+	Gzip bool
+	// Ack waits for indexer acknowledgement of each batch, and resends batches that are not acknowledged in
+	// AckTimeout. Indexer acknowledgement must be enabled on token
+	Ack bool
+	// Channel identifies client for indexer acknowledgement, default is a random GUID
+	Channel string
+	// AckTimeout limits waiting for acknowledgement of a batch
+	AckTimeout time.Duration
+	// AckPollInterval is delay between acknowledgement queries
+	AckPollInterval time.Duration
+	// BatchSize is maximum number of events in a single request
+	BatchSize int
+	// BatchInterval is maximum time an event waits before it is sent
+	BatchInterval time.Duration
+	// QueueSize limits number of batches waiting to be sent
+	QueueSize int
+	// MaxRetries is number of retries of a failed request on network errors, 429, 5xx responses and
+	// unacknowledged batches
+	MaxRetries int
+	// RetryBackoff is delay before the first retry, it is doubled on each retry
+	RetryBackoff time.Duration
+	// Overflow is applied when queue is full
+	Overflow logk.OverflowPolicy
+	// OnError is called from background goroutine when a batch can't be sent
+	OnError        func(err error)
+	PrinterOptions []logk.PrinterOption
+}
+
+type SplunkOption = func(*SplunkOptions)
+
+func WithSplunkClient(c *http.Client) SplunkOption {
+	return func(o *SplunkOptions) {
+		o.Client = c
+	}
+}
+
+func WithSplunkIndex(index string) SplunkOption {
+	return func(o *SplunkOptions) {
+		o.Index = index
+	}
+}
+
+func WithSplunkSource(source, sourceType string) SplunkOption {
+	return func(o *SplunkOptions) {
+		o.Source = source
+		o.SourceType = sourceType
+	}
+}
+
+func WithSplunkHost(host string) SplunkOption {
+	return func(o *SplunkOptions) {
+		o.Host = host
+	}
+}
+
+func WithSplunkGzip(enabled bool) SplunkOption {
+	return func(o *SplunkOptions) {
+		o.Gzip = enabled
+	}
+}
+
+func WithSplunkAck(timeout, pollInterval time.Duration) SplunkOption {
+	return func(o *SplunkOptions) {
+		o.Ack = true
+		o.AckTimeout = timeout
+		o.AckPollInterval = pollInterval
+	}
+}
+
+func WithSplunkChannel(channel string) SplunkOption {
+	return func(o *SplunkOptions) {
+		o.Channel = channel
+	}
+}
+
+func WithSplunkBatch(size int, interval time.Duration) SplunkOption {
+	return func(o *SplunkOptions) {
+		o.BatchSize = size
+		o.BatchInterval = interval
+	}
+}
+
+func WithSplunkQueueSize(n int) SplunkOption {
+	return func(o *SplunkOptions) {
+		o.QueueSize = n
+	}
+}
+
+func WithSplunkRetry(maxRetries int, backoff time.Duration) SplunkOption {
+	return func(o *SplunkOptions) {
+		o.MaxRetries = maxRetries
+		o.RetryBackoff = backoff
+	}
+}
+
+func WithSplunkOverflowPolicy(p logk.OverflowPolicy) SplunkOption {
+	return func(o *SplunkOptions) {
+		o.Overflow = p
+	}
+}
+
+func WithSplunkOnError(fn func(err error)) SplunkOption {
+	return func(o *SplunkOptions) {
+		o.OnError = fn
+	}
+}
+
+func WithSplunkPrinterOptions(args ...logk.PrinterOption) SplunkOption {
+	return func(o *SplunkOptions) {
+		o.PrinterOptions = append(o.PrinterOptions, args...)
+	}
+}
+
+// SplunkPrinter sends entries in batches to Splunk HTTP Event Collector. Fields of entry are written as event, and
+// time, host, index, source and sourcetype as event metadata
+type SplunkPrinter struct {
+	eventURL string
+	ackURL   string
+	header   http.Header
+	options  SplunkOptions
+	batcher  *logkExport.Batcher
+}
+
+// NewSplunkPrinter creates printer that sends entries to HTTP Event Collector at url, e.g. https://splunk:8088,
+// authenticated with token
+func NewSplunkPrinter(url, token string, args ...SplunkOption) *SplunkPrinter {
+	if url == "" || token == "" {
+		panic(fmt.Errorf("%s: splunk url and token are required", pkgName))
+	}
+
+	o := SplunkOptions{
+		Client:          &http.Client{Timeout: defaultHTTPTimeout},
+		AckTimeout:      defaultSplunkAckTimeout,
+		AckPollInterval: defaultSplunkAckPollInterval,
+		BatchSize:       defaultSplunkBatchSize,
+		BatchInterval:   defaultOTLPBatchInterval,
+		QueueSize:       defaultOTLPQueueSize,
+		MaxRetries:      defaultMaxRetries,
+		RetryBackoff:    defaultRetryBackoff,
+	}
+	o.Host, _ = os.Hostname()
+	for _, fn := range args {
+		fn(&o)
+	}
+
+	if o.Ack && o.Channel == "" {
+		o.Channel = newSplunkChannel()
+	}
+
+	// Accept both base url and url of event endpoint
+	base := strings.TrimSuffix(strings.TrimSuffix(url, "/"), splunkEventPath)
+	p := SplunkPrinter{
+		eventURL: base + splunkEventPath,
+		ackURL:   base + splunkAckPath,
+		header:   make(http.Header),
+		options:  o,
+	}
+	p.header.Set("Authorization", "Splunk "+token)
+	p.header.Set("Content-Type", "application/json")
+	if o.Channel != "" {
+		p.header.Set(splunkChannelHeader, o.Channel)
+	}
+
+	// Batches are sent concurrently, so waiting for acknowledgement doesn't hold back other batches
+	d := logkExport.NewDispatcher(defaultHTTPConcurrency, o.QueueSize, 0, o.Overflow, nil, p.send)
+	p.batcher = logkExport.NewBatcher(o.BatchSize, 0, o.BatchInterval, p.encode, d)
+
+	return &p
+}
+
+func (p *SplunkPrinter) Print(namespace string, lv level.LogLevel, msg string, options *logkOption.Options) {
+	entry := logk.NewEntry(namespace, lv, msg, options, p.options.PrinterOptions...)
+
+	// Time is sent as event metadata
+	fields := entry.Fields()
+	delete(fields, logkOption.TimeKey)
+
+	event, err := json.Marshal(splunkEvent{
+		Time:       json.Number(strconv.FormatFloat(float64(entry.Time.UnixMicro())/1e6, 'f', -1, 64)),
+		Host:       p.options.Host,
+		Index:      p.options.Index,
+		Source:     p.options.Source,
+		SourceType: p.options.SourceType,
+		Event:      fields,
+	})
+	if err != nil {
+		return
+	}
+	p.batcher.Add(event)
+}
+
+// Dropped returns number of batches that are discarded by overflow policy
+func (p *SplunkPrinter) Dropped() uint64 {
+	return p.batcher.Dropped()
+}
+
+// Flush sends pending entries and waits until all batches are sent, and acknowledged if Ack is set
+func (p *SplunkPrinter) Flush() error {
+	p.batcher.Flush()
+	return nil
+}
+
+// Close sends remaining entries and stops background workers
+func (p *SplunkPrinter) Close() error {
+	p.batcher.Close()
+	return nil
+}
+
+type splunkEvent struct {
+	Time       json.Number            `json:"time"`
+	Host       string                 `json:"host,omitempty"`
+	Index      string                 `json:"index,omitempty"`
+	Source     string                 `json:"source,omitempty"`
+	SourceType string                 `json:"sourcetype,omitempty"`
+	Event      map[string]interface{} `json:"event"`
+}
+
+type splunkResponse struct {
+	Text  string `json:"text"`
+	Code  int    `json:"code"`
+	AckId *int64 `json:"ackId"`
+}
+
+// encode concatenates events, as expected by event endpoint, and compresses them if Gzip is set
+func (p *SplunkPrinter) encode(events [][]byte) []byte {
+	payload := bytes.Join(events, []byte{'\n'})
+	if !p.options.Gzip {
+		return payload
+	}
+
+	var buf bytes.Buffer
+	zw := gzip.NewWriter(&buf)
+	_, _ = zw.Write(payload)
+	_ = zw.Close()
+	return buf.Bytes()
+}
+
+func (p *SplunkPrinter) send(payload []byte) {
+	err := logkExport.Retry(p.options.MaxRetries, p.options.RetryBackoff, func() error {
+		ackId, err := p.postEvents(payload)
+		if err != nil || !p.options.Ack {
+			return err
+		}
+		return p.waitAck(ackId)
+	})
+	if err != nil && p.options.OnError != nil {
+		p.options.OnError(err)
+	}
+}
+
+// postEvents sends events and returns acknowledgement id of request
+func (p *SplunkPrinter) postEvents(payload []byte) (int64, error) {
+	header := p.header.Clone()
+	if p.options.Gzip {
+		header.Set("Content-Encoding", "gzip")
+	}
+
+	var resp splunkResponse
+	if err := p.call(p.eventURL, header, payload, &resp); err != nil {
+		return 0, err
+	}
+
+	if p.options.Ack && resp.AckId == nil {
+		return 0, fmt.Errorf("%s: splunk response has no ackId, indexer acknowledgement is not enabled on token",
+			pkgName)
+	}
+	if resp.AckId == nil {
+		return 0, nil
+	}
+	return *resp.AckId, nil
+}
+
+// waitAck polls acknowledgement of request until it's indexed. Batch is resent if acknowledgement doesn't arrive
+// in time
+func (p *SplunkPrinter) waitAck(ackId int64) error {
+	body, _ := json.Marshal(map[string][]int64{"acks": {ackId}})
+	deadline := time.Now().Add(p.options.AckTimeout)
+	for {
+		time.Sleep(p.options.AckPollInterval)
+
+		var resp struct {
+			Acks map[string]bool `json:"acks"`
+		}
+		err := p.call(p.ackURL, p.header, body, &resp)
+		if err == nil && resp.Acks[strconv.FormatInt(ackId, 10)] {
+			return nil
+		}
+
+		if time.Now().After(deadline) {
+			return logkExport.Retryable(fmt.Errorf("%s: splunk didn't acknowledge request %d in %s", pkgName, ackId,
+				p.options.AckTimeout))
+		}
+	}
+}
+
+// call sends payload and decodes response into result. Network errors, 429 and 5xx responses are returned as
+// retryable errors
+func (p *SplunkPrinter) call(url string, header http.Header, payload []byte, result interface{}) error {
+	req, err := http.NewRequest(http.MethodPost, url, bytes.NewReader(payload))
+	if err != nil {
+		return err
+	}
+	req.Header = header
+
+	resp, err := p.options.Client.Do(req)
+	if err != nil {
+		return logkExport.Retryable(err)
+	}
+	defer resp.Body.Close()
+
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return logkExport.Retryable(err)
+	}
+
+	if resp.StatusCode >= 300 {
+		var r splunkResponse
+		_ = json.Unmarshal(data, &r)
+		err = fmt.Errorf("%s: splunk responded with status %d: %s (code %d)", pkgName, resp.StatusCode, r.Text, r.Code)
+		if resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode >= 500 {
+			return logkExport.Retryable(err)
+		}
+		return err
+	}
+
+	return json.Unmarshal(data, result)
+}
+
+// newSplunkChannel returns random GUID, as required of channel identifiers
+func newSplunkChannel() string {
+	b := make([]byte, 16)
+	_, _ = rand.Read(b)
+	b[6] = b[6]&0x0f | 0x40
+	b[8] = b[8]&0x3f | 0x80
+	return fmt.Sprintf("%x-%x-%x-%x-%x", b[0:4], b[4:6], b[6:8], b[8:10], b[10:])
+}
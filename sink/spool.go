@@ -0,0 +1,445 @@
+package logkSink
+
+import (
+	"bytes"
+	"cmp"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"hash/crc32"
+	"io"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// Spool segment format. Each record is magic, payload length and CRC-32C of payload, in little endian, followed by
+// payload. Magic has bytes that don't occur in text, so records after a corrupted one are found by scanning for it
+const (
+	spoolMagic         = 0xe1f05c8d
+	spoolHeaderSize    = 12
+	spoolSegmentExt    = ".spool"
+	spoolCursorFile    = "cursor"
+	spoolScanChunk     = 64 << 10
+	maxSpoolRecordSize = 16 << 20
+)
+
+var spoolCRCTable = crc32.MakeTable(crc32.Castagnoli)
+
+var errSpoolClosed = errors.New("spool is closed")
+
+type spoolSegment struct {
+	seq     uint64
+	size    int64
+	records int
+}
+
+// spool is a bounded queue of records in segment files of a directory. Records are appended to the last segment and
+// read from the first one, which is deleted once it's read. Segments of previous runs are never appended to, so
+// a record torn by crash only affects the end of its segment. Read position is saved in cursor file, so records are
+// replayed after restart, at least once
+type spool struct {
+	dir         string
+	segmentSize int64
+	maxBytes    int64
+
+	// mu guards fields below
+	mu       sync.Mutex
+	cond     *sync.Cond
+	segments []*spoolSegment
+	size     int64
+	w        *os.File
+	r        *os.File
+	// roff is offset of the next record in the first segment, and consumed is number of records before it
+	roff     int64
+	consumed int
+	// rnext is offset after record returned by next, and rgen changes when reader segment is dropped
+	rnext     int64
+	rgen      uint64
+	pending   int
+	dropped   uint64
+	corrupted uint64
+	dirty     bool
+	stalled   bool
+	stopped   bool
+}
+
+func openSpool(dir string, segmentSize, maxBytes int64) (*spool, error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, err
+	}
+
+	names, err := filepath.Glob(filepath.Join(dir, "*"+spoolSegmentExt))
+	if err != nil {
+		return nil, err
+	}
+
+	s := spool{dir: dir, segmentSize: segmentSize, maxBytes: maxBytes}
+	s.cond = sync.NewCond(&s.mu)
+
+	for _, name := range names {
+		seq, err := strconv.ParseUint(strings.TrimSuffix(filepath.Base(name), spoolSegmentExt), 10, 64)
+		if err != nil {
+			continue
+		}
+		s.segments = append(s.segments, &spoolSegment{seq: seq})
+	}
+	slices.SortFunc(s.segments, func(a, b *spoolSegment) int {
+		return cmp.Compare(a.seq, b.seq)
+	})
+
+	// Segments before cursor are already read, they are left behind when process stops before deleting them
+	cursorSeq, cursorOff := s.readCursor()
+	for len(s.segments) > 0 && s.segments[0].seq < cursorSeq {
+		_ = os.Remove(s.segmentPath(s.segments[0].seq))
+		s.segments = s.segments[1:]
+	}
+
+	for i, seg := range s.segments {
+		var readFrom int64
+		if i == 0 && seg.seq == cursorSeq {
+			readFrom = cursorOff
+		}
+		if err := s.scanSegment(seg, readFrom); err != nil {
+			return nil, err
+		}
+		s.size += seg.size
+		s.pending += seg.records
+	}
+	s.pending -= s.consumed
+
+	// Sequence never goes back, as cursor can point to an empty segment that is removed on close
+	seq := cursorSeq + 1
+	if len(s.segments) > 0 {
+		seq = max(seq, s.segments[len(s.segments)-1].seq+1)
+	}
+	if err := s.createSegment(seq); err != nil {
+		return nil, err
+	}
+	return &s, nil
+}
+
+// scanSegment counts records of segment, and sets reader position if segment is read from readFrom
+func (s *spool) scanSegment(seg *spoolSegment, readFrom int64) error {
+	f, err := os.Open(s.segmentPath(seg.seq))
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	info, err := f.Stat()
+	if err != nil {
+		return err
+	}
+	seg.size = info.Size()
+
+	for off := int64(0); ; {
+		payload, start, err := readSpoolRecord(f, off, seg.size)
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return err
+		}
+
+		off = start + spoolHeaderSize + int64(len(payload))
+		seg.records++
+		if off <= readFrom {
+			s.roff = off
+			s.consumed++
+		}
+	}
+	return nil
+}
+
+func (s *spool) createSegment(seq uint64) error {
+	w, err := os.OpenFile(s.segmentPath(seq), os.O_CREATE|os.O_WRONLY|os.O_APPEND|os.O_EXCL, 0o644)
+	if err != nil {
+		return err
+	}
+
+	if s.w != nil {
+		_ = s.w.Close()
+	}
+	s.w = w
+	s.segments = append(s.segments, &spoolSegment{seq: seq})
+	return nil
+}
+
+func (s *spool) segmentPath(seq uint64) string {
+	return filepath.Join(s.dir, fmt.Sprintf("%020d%s", seq, spoolSegmentExt))
+}
+
+// append writes record to the last segment. If spool would exceed maxBytes, the oldest segments are dropped
+func (s *spool) append(payload []byte) error {
+	record := make([]byte, spoolHeaderSize, spoolHeaderSize+len(payload))
+	binary.LittleEndian.PutUint32(record[0:], spoolMagic)
+	binary.LittleEndian.PutUint32(record[4:], uint32(len(payload)))
+	binary.LittleEndian.PutUint32(record[8:], crc32.Checksum(payload, spoolCRCTable))
+	record = append(record, payload...)
+	n := int64(len(record))
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.stopped {
+		return errSpoolClosed
+	}
+
+	active := s.segments[len(s.segments)-1]
+	if active.size > 0 && active.size+n > s.segmentSize {
+		if err := s.createSegment(active.seq + 1); err != nil {
+			return err
+		}
+	}
+
+	for s.maxBytes > 0 && s.size > 0 && s.size+n > s.maxBytes {
+		// Active segment is closed first, so it can be dropped
+		if len(s.segments) == 1 {
+			if err := s.createSegment(s.segments[0].seq + 1); err != nil {
+				return err
+			}
+		}
+		s.dropFirst()
+	}
+
+	if _, err := s.w.Write(record); err != nil {
+		return err
+	}
+
+	active = s.segments[len(s.segments)-1]
+	active.size += n
+	active.records++
+	s.size += n
+	s.pending++
+	s.cond.Broadcast()
+	return nil
+}
+
+// dropFirst deletes the first segment with records that are not read yet
+func (s *spool) dropFirst() {
+	seg := s.segments[0]
+	s.dropped += uint64(seg.records - s.consumed)
+	s.pending -= seg.records - s.consumed
+	s.removeFirst()
+}
+
+func (s *spool) removeFirst() {
+	seg := s.segments[0]
+	if s.r != nil {
+		_ = s.r.Close()
+		s.r = nil
+	}
+	_ = os.Remove(s.segmentPath(seg.seq))
+
+	s.size -= seg.size
+	s.segments = s.segments[1:]
+	s.roff = 0
+	s.consumed = 0
+	s.rgen++
+	s.dirty = true
+}
+
+// next returns the next record and generation of reader, without consuming record. It waits until a record is
+// appended, and returns false when spool is stopped
+func (s *spool) next() ([]byte, uint64, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for !s.stopped {
+		seg := s.segments[0]
+		if s.roff < seg.size {
+			if s.r == nil {
+				r, err := os.Open(s.segmentPath(seg.seq))
+				if err != nil {
+					// Segment can't be read, so its records are lost
+					s.dropped += uint64(seg.records - s.consumed)
+					s.pending -= seg.records - s.consumed
+					s.consumed = seg.records
+					s.roff = seg.size
+					continue
+				}
+				s.r = r
+			}
+
+			payload, start, err := readSpoolRecord(s.r, s.roff, seg.size)
+			if err == nil {
+				s.corrupted += uint64(start - s.roff)
+				s.roff = start
+				s.rnext = start + spoolHeaderSize + int64(len(payload))
+				return payload, s.rgen, true
+			}
+
+			// Rest of segment is corrupted or torn
+			s.corrupted += uint64(seg.size - s.roff)
+			s.roff = seg.size
+			continue
+		}
+
+		if len(s.segments) > 1 {
+			s.removeFirst()
+			continue
+		}
+		s.cond.Wait()
+	}
+	return nil, 0, false
+}
+
+// commit consumes record returned by next, unless its segment is dropped in the meantime
+func (s *spool) commit(gen uint64) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if gen != s.rgen {
+		return
+	}
+	s.roff = s.rnext
+	s.consumed++
+	s.pending--
+	s.dirty = true
+	s.cond.Broadcast()
+}
+
+// setStalled marks reader as stalled, while records can't be delivered
+func (s *spool) setStalled(stalled bool) {
+	s.mu.Lock()
+	s.stalled = stalled
+	s.cond.Broadcast()
+	s.mu.Unlock()
+}
+
+// wait waits until all records are consumed, or reader is stalled or stopped
+func (s *spool) wait() {
+	s.mu.Lock()
+	for s.pending > 0 && !s.stalled && !s.stopped {
+		s.cond.Wait()
+	}
+	s.mu.Unlock()
+}
+
+// sync writes the last segment and cursor to disk
+func (s *spool) sync() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if err := s.saveCursor(); err != nil {
+		return err
+	}
+	return s.w.Sync()
+}
+
+// stop wakes up reader and rejects new records
+func (s *spool) stop() {
+	s.mu.Lock()
+	s.stopped = true
+	s.cond.Broadcast()
+	s.mu.Unlock()
+}
+
+// close saves cursor and closes files. Reader must be stopped
+func (s *spool) close() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	err := s.saveCursor()
+	if s.r != nil {
+		_ = s.r.Close()
+		s.r = nil
+	}
+	if wErr := s.w.Close(); wErr != nil && err == nil {
+		err = wErr
+	}
+
+	// Remove last segment if it's empty or read, so it's not left behind
+	if last := s.segments[len(s.segments)-1]; last.size == 0 || len(s.segments) == 1 && s.roff >= last.size {
+		_ = os.Remove(s.segmentPath(last.seq))
+	}
+	return err
+}
+
+// saveCursor replaces cursor file, so it's never partially written. It's called with mu held
+func (s *spool) saveCursor() error {
+	if !s.dirty {
+		return nil
+	}
+
+	tmp := filepath.Join(s.dir, spoolCursorFile+".tmp")
+	data := fmt.Sprintf("%d %d\n", s.segments[0].seq, s.roff)
+	if err := os.WriteFile(tmp, []byte(data), 0o644); err != nil {
+		return err
+	}
+	if err := os.Rename(tmp, filepath.Join(s.dir, spoolCursorFile)); err != nil {
+		return err
+	}
+	s.dirty = false
+	return nil
+}
+
+// readCursor returns saved read position, or zero position if there is none
+func (s *spool) readCursor() (uint64, int64) {
+	data, err := os.ReadFile(filepath.Join(s.dir, spoolCursorFile))
+	if err != nil {
+		return 0, 0
+	}
+
+	var seq uint64
+	var off int64
+	if _, err := fmt.Sscanf(string(data), "%d %d", &seq, &off); err != nil {
+		return 0, 0
+	}
+	return seq, off
+}
+
+// readSpoolRecord returns payload and offset of the first valid record at or after off, which ends before limit.
+// Corrupted bytes are skipped. It returns io.EOF if there is no valid record
+func readSpoolRecord(f *os.File, off, limit int64) ([]byte, int64, error) {
+	var header [spoolHeaderSize]byte
+	for off+spoolHeaderSize <= limit {
+		if _, err := f.ReadAt(header[:], off); err != nil {
+			return nil, off, err
+		}
+
+		if binary.LittleEndian.Uint32(header[0:]) == spoolMagic {
+			n := int64(binary.LittleEndian.Uint32(header[4:]))
+			if n <= maxSpoolRecordSize && off+spoolHeaderSize+n <= limit {
+				payload := make([]byte, n)
+				if _, err := f.ReadAt(payload, off+spoolHeaderSize); err != nil {
+					return nil, off, err
+				}
+				if crc32.Checksum(payload, spoolCRCTable) == binary.LittleEndian.Uint32(header[8:]) {
+					return payload, off, nil
+				}
+			}
+		}
+
+		next, err := findSpoolMagic(f, off+1, limit)
+		if err != nil {
+			return nil, off, err
+		}
+		off = next
+	}
+	return nil, limit, io.EOF
+}
+
+// findSpoolMagic returns offset of the next magic at or after off, or limit if there is none
+func findSpoolMagic(f *os.File, off, limit int64) (int64, error) {
+	var magic [4]byte
+	binary.LittleEndian.PutUint32(magic[:], spoolMagic)
+
+	buf := make([]byte, spoolScanChunk)
+	for off+4 <= limit {
+		n := min(int64(len(buf)), limit-off)
+		if _, err := f.ReadAt(buf[:n], off); err != nil {
+			return off, err
+		}
+		if i := bytes.Index(buf[:n], magic[:]); i >= 0 {
+			return off + int64(i), nil
+		}
+		// Chunks overlap, so magic on chunk boundary is found
+		off += n - 3
+	}
+	return limit, nil
+}
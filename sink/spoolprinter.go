@@ -0,0 +1,265 @@
+package logkSink
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/go-konsultin/logk"
+	logkExport "github.com/go-konsultin/logk/internal/export"
+	"github.com/go-konsultin/logk/level"
+	logkOption "github.com/go-konsultin/logk/option"
+)
+
+// Default spool printer options
+const (
+	defaultSpoolMaxBytes     = 256 << 20
+	defaultSpoolSegmentSize  = 8 << 20
+	defaultSpoolSyncInterval = time.Second
+)
+
+type SpoolOptions struct {
+	// MaxBytes limits disk usage of spool. When it's exceeded, the oldest segment is discarded, so recent entries are
+	// kept. Zero means unlimited
+	MaxBytes int64
+	// SegmentSize is size of segment files, which are deleted once they are sent
+	SegmentSize int64
+	// SyncInterval is interval at which spool and read position are written to disk, so at most entries of the last
+	// interval are lost on crash of machine
+	SyncInterval time.Duration
+	// RetryBackoff is delay before the first resend of a failed entry, it is doubled on each retry up to MaxBackoff
+	RetryBackoff time.Duration
+	MaxBackoff   time.Duration
+	// OnError is called from background goroutine when an entry can't be sent or spooled
+	OnError func(err error)
+	// Encoder formats entries that are spooled and sent, default is JSON
+	Encoder        logk.Encoder
+	PrinterOptions []logk.PrinterOption
+}
+
+type SpoolOption = func(*SpoolOptions)
+
+func WithSpoolMaxBytes(n int64) SpoolOption {
+	return func(o *SpoolOptions) {
+		o.MaxBytes = n
+	}
+}
+
+func WithSpoolSegmentSize(n int64) SpoolOption {
+	return func(o *SpoolOptions) {
+		o.SegmentSize = n
+	}
+}
+
+func WithSpoolSyncInterval(d time.Duration) SpoolOption {
+	return func(o *SpoolOptions) {
+		o.SyncInterval = d
+	}
+}
+
+func WithSpoolRetry(backoff, maxBackoff time.Duration) SpoolOption {
+	return func(o *SpoolOptions) {
+		o.RetryBackoff = backoff
+		o.MaxBackoff = maxBackoff
+	}
+}
+
+func WithSpoolOnError(fn func(err error)) SpoolOption {
+	return func(o *SpoolOptions) {
+		o.OnError = fn
+	}
+}
+
+func WithSpoolEncoder(enc logk.Encoder) SpoolOption {
+	return func(o *SpoolOptions) {
+		o.Encoder = enc
+	}
+}
+
+func WithSpoolPrinterOptions(args ...logk.PrinterOption) SpoolOption {
+	return func(o *SpoolOptions) {
+		o.PrinterOptions = append(o.PrinterOptions, args...)
+	}
+}
+
+// SpoolPrinter appends encoded entries to a write-ahead spool in a local directory, and sends them in order with send
+// from background goroutine. While sink is down, entries accumulate on disk up to MaxBytes and failed entry is
+// resent with backoff, so entries are replayed in order once sink recovers. Entries that are not sent when printer
+// is closed stay in spool, and are sent by the next printer on the same directory. Records of spool are checksummed,
+// so corrupted records, e.g. torn by crash, are skipped. Entries are sent at least once. Errors wrapped with
+// PermanentError skip the entry instead of resending it
+type SpoolPrinter struct {
+	send    SendFunc
+	options SpoolOptions
+	spool   *spool
+	stop    chan struct{}
+	wg      sync.WaitGroup
+
+	// closeMu guards closed
+	closeMu sync.Mutex
+	closed  bool
+}
+
+// NewSpoolPrinter creates printer that spools entries in dir. Only one printer may use a directory at a time
+func NewSpoolPrinter(dir string, send SendFunc, args ...SpoolOption) (*SpoolPrinter, error) {
+	if send == nil {
+		return nil, fmt.Errorf("%s: spool printer send function is nil", pkgName)
+	}
+
+	o := SpoolOptions{
+		MaxBytes:     defaultSpoolMaxBytes,
+		SegmentSize:  defaultSpoolSegmentSize,
+		SyncInterval: defaultSpoolSyncInterval,
+		RetryBackoff: defaultRetryBackoff,
+		MaxBackoff:   logkExport.MaxRetryBackoff,
+		Encoder:      logk.NewJSONEncoder(),
+	}
+	for _, fn := range args {
+		fn(&o)
+	}
+
+	if o.SegmentSize <= 0 {
+		o.SegmentSize = defaultSpoolSegmentSize
+	}
+
+	// Segments are dropped as a whole, so several of them must fit in limit
+	if o.MaxBytes > 0 {
+		o.SegmentSize = min(o.SegmentSize, max(o.MaxBytes/4, 1))
+	}
+
+	s, err := openSpool(dir, o.SegmentSize, o.MaxBytes)
+	if err != nil {
+		return nil, fmt.Errorf("%s: failed to open spool: %w", pkgName, err)
+	}
+
+	p := SpoolPrinter{send: send, options: o, spool: s, stop: make(chan struct{})}
+	p.wg.Add(1)
+	go p.work()
+
+	if o.SyncInterval > 0 {
+		p.wg.Add(1)
+		go p.sync()
+	}
+
+	return &p, nil
+}
+
+func (p *SpoolPrinter) Print(namespace string, lv level.LogLevel, msg string, options *logkOption.Options) {
+	entry := logk.NewEntry(namespace, lv, msg, options, p.options.PrinterOptions...)
+	payload, err := p.options.Encoder.Encode(entry)
+	if err != nil {
+		return
+	}
+
+	if err = p.spool.append(payload); err != nil && !errors.Is(err, errSpoolClosed) && p.options.OnError != nil {
+		p.options.OnError(fmt.Errorf("%s: failed to spool entry: %w", pkgName, err))
+	}
+}
+
+// QueueLen returns number of entries in spool that are not sent yet
+func (p *SpoolPrinter) QueueLen() int {
+	p.spool.mu.Lock()
+	defer p.spool.mu.Unlock()
+	return p.spool.pending
+}
+
+// Dropped returns number of entries that are discarded because spool exceeded MaxBytes, or its segment couldn't
+// be read
+func (p *SpoolPrinter) Dropped() uint64 {
+	p.spool.mu.Lock()
+	defer p.spool.mu.Unlock()
+	return p.spool.dropped
+}
+
+// Corrupted returns number of bytes of spool that are skipped as corrupted
+func (p *SpoolPrinter) Corrupted() uint64 {
+	p.spool.mu.Lock()
+	defer p.spool.mu.Unlock()
+	return p.spool.corrupted
+}
+
+// Flush writes spool to disk and waits until spooled entries are sent. It doesn't wait while sink is down, as
+// entries are kept in spool until it recovers
+func (p *SpoolPrinter) Flush() error {
+	if err := p.spool.sync(); err != nil {
+		return fmt.Errorf("%s: failed to sync spool: %w", pkgName, err)
+	}
+	p.spool.wait()
+	return nil
+}
+
+// Close sends spooled entries unless sink is down, stops background goroutines and closes spool. Entries that are
+// not sent are kept on disk
+func (p *SpoolPrinter) Close() error {
+	p.closeMu.Lock()
+	defer p.closeMu.Unlock()
+	if p.closed {
+		return nil
+	}
+	p.closed = true
+
+	p.spool.wait()
+	close(p.stop)
+	p.spool.stop()
+	p.wg.Wait()
+	return p.spool.close()
+}
+
+func (p *SpoolPrinter) work() {
+	defer p.wg.Done()
+
+	backoff := p.options.RetryBackoff
+	for {
+		payload, gen, ok := p.spool.next()
+		if !ok {
+			return
+		}
+
+		err := p.send(payload)
+		var permanent *permanentError
+		if err != nil && !errors.As(err, &permanent) {
+			p.spool.setStalled(true)
+			p.onError(err)
+
+			select {
+			case <-p.stop:
+				return
+			case <-time.After(jitter(backoff, defaultRetryJitter)):
+			}
+			backoff = min(backoff*2, p.options.MaxBackoff)
+			continue
+		}
+
+		if err != nil {
+			p.onError(err)
+		}
+		backoff = p.options.RetryBackoff
+		p.spool.commit(gen)
+		p.spool.setStalled(false)
+	}
+}
+
+// sync writes spool to disk periodically
+func (p *SpoolPrinter) sync() {
+	defer p.wg.Done()
+
+	ticker := time.NewTicker(p.options.SyncInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-p.stop:
+			return
+		case <-ticker.C:
+			if err := p.spool.sync(); err != nil {
+				p.onError(fmt.Errorf("%s: failed to sync spool: %w", pkgName, err))
+			}
+		}
+	}
+}
+
+func (p *SpoolPrinter) onError(err error) {
+	if p.options.OnError != nil {
+		p.options.OnError(err)
+	}
+}
@@ -0,0 +1,455 @@
+package logkSink
+
+import (
+	"crypto/tls"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/go-konsultin/logk"
+	"github.com/go-konsultin/logk/level"
+	logkOption "github.com/go-konsultin/logk/option"
+)
+
+// SyslogFormat is message format of syslog printer
+type SyslogFormat int
+
+const (
+	// RFC5424 is the structured syslog format, metadata is written as structured data
+	RFC5424 SyslogFormat = iota
+	// RFC3164 is the legacy BSD syslog format, metadata is appended to message as JSON
+	RFC3164
+)
+
+// SyslogFraming is framing of messages on stream transports, see RFC 6587
+type SyslogFraming int
+
+const (
+	// SyslogFramingAuto uses octet counting over TLS, as required by RFC 5425, and newline on other transports
+	SyslogFramingAuto SyslogFraming = iota
+	// SyslogFramingNewline terminates each message with newline
+	SyslogFramingNewline
+	// SyslogFramingOctetCounting prefixes each message with its length in bytes and space
+	SyslogFramingOctetCounting
+)
+
+// Syslog facilities
+const (
+	FacilityKern   = 0
+	FacilityUser   = 1
+	FacilityDaemon = 3
+	FacilityAuth   = 4
+	FacilityLocal0 = 16
+	FacilityLocal1 = 17
+	FacilityLocal2 = 18
+	FacilityLocal3 = 19
+	FacilityLocal4 = 20
+	FacilityLocal5 = 21
+	FacilityLocal6 = 22
+	FacilityLocal7 = 23
+)
+
+// Syslog printer constants
+const (
+	// defaultSyslogSDID uses enterprise number reserved for documentation, see RFC 5612
+	defaultSyslogSDID   = "meta@32473"
+	syslogNil           = "-"
+	syslogRFC3164Layout = time.Stamp
+	syslogRFC5424Layout = "2006-01-02T15:04:05.000000Z07:00"
+	maxSyslogParamName  = 32
+)
+
+// syslogSeverity maps level to syslog severity
+var syslogSeverity = map[level.LogLevel]int{
+	level.Fatal: 2,
+	level.Error: 3,
+	level.Warn:  4,
+	level.Info:  6,
+	level.Debug: 7,
+	level.Trace: 7,
+}
+
+// syslogLocalAddrs are paths of local syslog socket
+var syslogLocalAddrs = []string{"/dev/log", "/var/run/syslog", "/var/run/log"}
+
+type SyslogOptions struct {
+	Format   SyslogFormat
+	Facility int
+	// AppName is written as APP-NAME in RFC 5424 and TAG in RFC 3164. Default is executable name
+	AppName  string
+	Hostname string
+	// SDID is structured data element id of metadata in RFC 5424, e.g. meta@32473
+	SDID string
+	// TLS enables TLS on TCP connections, e.g. with client certificates loaded by NewTLSConfig
+	TLS *tls.Config
+	// Framing is framing of messages on stream transports
+	Framing        SyslogFraming
+	PrinterOptions []logk.PrinterOption
+}
+
+type SyslogOption = func(*SyslogOptions)
+
+func WithSyslogFormat(f SyslogFormat) SyslogOption {
+	return func(o *SyslogOptions) {
+		o.Format = f
+	}
+}
+
+func WithFacility(facility int) SyslogOption {
+	return func(o *SyslogOptions) {
+		o.Facility = facility
+	}
+}
+
+func WithAppName(name string) SyslogOption {
+	return func(o *SyslogOptions) {
+		o.AppName = name
+	}
+}
+
+func WithHostname(name string) SyslogOption {
+	return func(o *SyslogOptions) {
+		o.Hostname = name
+	}
+}
+
+func WithStructuredDataId(id string) SyslogOption {
+	return func(o *SyslogOptions) {
+		o.SDID = id
+	}
+}
+
+func WithSyslogTLS(config *tls.Config) SyslogOption {
+	return func(o *SyslogOptions) {
+		o.TLS = config
+	}
+}
+
+func WithSyslogFraming(f SyslogFraming) SyslogOption {
+	return func(o *SyslogOptions) {
+		o.Framing = f
+	}
+}
+
+func WithSyslogPrinterOptions(args ...logk.PrinterOption) SyslogOption {
+	return func(o *SyslogOptions) {
+		o.PrinterOptions = append(o.PrinterOptions, args...)
+	}
+}
+
+// SyslogPrinter writes entries to a syslog daemon. Connection is re-established once when writing fails
+type SyslogPrinter struct {
+	network string
+	addr    string
+	options SyslogOptions
+	pid     string
+
+	mu   sync.Mutex
+	conn net.Conn
+	// stream is true if connection is stream oriented, so messages must be framed
+	stream bool
+}
+
+// NewSyslogPrinter connects to syslog daemon. Network is "udp", "tcp", "tls", "unix" or "unixgram". If network is
+// empty, it connects to local syslog socket such as /dev/log. Network "tls" connects over TCP with TLS as specified
+// by RFC 5425, with default TLS config unless it is set with WithSyslogTLS
+func NewSyslogPrinter(network, addr string, args ...SyslogOption) (*SyslogPrinter, error) {
+	o := SyslogOptions{
+		Format:   RFC5424,
+		Facility: FacilityUser,
+		AppName:  filepath.Base(os.Args[0]),
+		SDID:     defaultSyslogSDID,
+	}
+	o.Hostname, _ = os.Hostname()
+	for _, fn := range args {
+		fn(&o)
+	}
+
+	p := SyslogPrinter{
+		network: network,
+		addr:    addr,
+		options: o,
+		pid:     strconv.Itoa(os.Getpid()),
+	}
+
+	if err := p.connect(); err != nil {
+		return nil, err
+	}
+	return &p, nil
+}
+
+func (p *SyslogPrinter) Print(namespace string, lv level.LogLevel, msg string, options *logkOption.Options) {
+	entry := logk.NewEntry(namespace, lv, msg, options, p.options.PrinterOptions...)
+
+	var line string
+	if p.options.Format == RFC3164 {
+		line = p.formatRFC3164(entry)
+	} else {
+		line = p.formatRFC5424(entry)
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if err := p.write(line); err != nil {
+		// Reconnect once, as daemon may have been restarted
+		if p.connect() == nil {
+			_ = p.write(line)
+		}
+	}
+}
+
+// Close closes connection to syslog daemon
+func (p *SyslogPrinter) Close() error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.conn == nil {
+		return nil
+	}
+	err := p.conn.Close()
+	p.conn = nil
+	return err
+}
+
+func (p *SyslogPrinter) write(line string) error {
+	if p.conn == nil {
+		return net.ErrClosed
+	}
+
+	if p.stream {
+		line = p.frame(line)
+	}
+
+	_, err := p.conn.Write([]byte(line))
+	return err
+}
+
+// connect dials syslog daemon, closing previous connection
+func (p *SyslogPrinter) connect() error {
+	if p.conn != nil {
+		_ = p.conn.Close()
+		p.conn = nil
+	}
+
+	if p.network == "tls" || p.options.TLS != nil && strings.HasPrefix(p.network, "tcp") {
+		network := p.network
+		if network == "tls" {
+			network = "tcp"
+		}
+
+		config := p.options.TLS
+		if config == nil {
+			config = &tls.Config{}
+		}
+
+		conn, err := tls.Dial(network, p.addr, config)
+		if err != nil {
+			return fmt.Errorf("%s: failed to connect to syslog: %w", pkgName, err)
+		}
+		p.conn = conn
+		p.stream = true
+		return nil
+	}
+
+	if p.network != "" {
+		conn, err := net.Dial(p.network, p.addr)
+		if err != nil {
+			return fmt.Errorf("%s: failed to connect to syslog: %w", pkgName, err)
+		}
+		p.conn = conn
+		p.stream = isStreamNetwork(p.network)
+		return nil
+	}
+
+	// Try local sockets
+	for _, network := range []string{"unixgram", "unix"} {
+		for _, addr := range syslogLocalAddrs {
+			if conn, err := net.Dial(network, addr); err == nil {
+				p.conn = conn
+				p.stream = isStreamNetwork(network)
+				return nil
+			}
+		}
+	}
+	return fmt.Errorf("%s: failed to connect to syslog: %w", pkgName, errors.New("no local syslog socket found"))
+}
+
+// frame frames message on stream transport
+func (p *SyslogPrinter) frame(line string) string {
+	framing := p.options.Framing
+	if framing == SyslogFramingAuto {
+		framing = SyslogFramingNewline
+		if _, ok := p.conn.(*tls.Conn); ok {
+			framing = SyslogFramingOctetCounting
+		}
+	}
+
+	if framing == SyslogFramingOctetCounting {
+		return strconv.Itoa(len(line)) + " " + line
+	}
+	return line + "\n"
+}
+
+func isStreamNetwork(network string) bool {
+	return strings.HasPrefix(network, "tcp") || network == "unix"
+}
+
+func (p *SyslogPrinter) priority(lv level.LogLevel) int {
+	return p.options.Facility*8 + syslogPriority(lv)
+}
+
+// syslogPriority returns syslog severity of level
+func syslogPriority(lv level.LogLevel) int {
+	severity, ok := syslogSeverity[lv]
+	if !ok {
+		return syslogSeverity[level.Info]
+	}
+	return severity
+}
+
+// formatRFC5424 formats entry as <PRI>1 TIMESTAMP HOSTNAME APP-NAME PROCID MSGID [SD-ELEMENT] MSG, with namespace
+// written as MSGID
+func (p *SyslogPrinter) formatRFC5424(entry *logk.Entry) string {
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "<%d>1 %s %s %s %s %s ",
+		p.priority(entry.Level),
+		entry.Time.Format(syslogRFC5424Layout),
+		syslogHeaderValue(p.options.Hostname, 255),
+		syslogHeaderValue(p.options.AppName, 48),
+		p.pid,
+		syslogHeaderValue(entry.Namespace, 32))
+
+	params := syslogParams(entry)
+	if len(params) == 0 {
+		sb.WriteString(syslogNil)
+	} else {
+		sb.WriteByte('[')
+		sb.WriteString(p.options.SDID)
+		for _, k := range sortedKeys(params) {
+			fmt.Fprintf(&sb, ` %s="%s"`, syslogParamName(k), syslogParamValue(params[k]))
+		}
+		sb.WriteByte(']')
+	}
+
+	sb.WriteByte(' ')
+	sb.WriteString(entry.Message)
+	return sb.String()
+}
+
+// formatRFC3164 formats entry as <PRI>TIMESTAMP HOSTNAME TAG[PID]: MSG, with fields appended as JSON
+func (p *SyslogPrinter) formatRFC3164(entry *logk.Entry) string {
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "<%d>%s %s %s[%s]: ",
+		p.priority(entry.Level),
+		entry.Time.Format(syslogRFC3164Layout),
+		syslogHeaderValue(p.options.Hostname, 255),
+		syslogHeaderValue(p.options.AppName, 32),
+		p.pid)
+
+	if entry.Namespace != "" {
+		sb.WriteString("(" + entry.Namespace + ") ")
+	}
+	sb.WriteString(entry.Message)
+
+	if params := syslogParams(entry); len(params) > 0 {
+		if b, err := json.Marshal(params); err == nil {
+			sb.WriteByte(' ')
+			sb.Write(b)
+		}
+	}
+	return sb.String()
+}
+
+// syslogParams returns entry fields that are not written in header, and flattened metadata. Fields take
+// precedence over metadata with the same key
+func syslogParams(entry *logk.Entry) map[string]interface{} {
+	params := make(map[string]interface{})
+	flattenMetadata(params, "", entry.Metadata)
+
+	fields := entry.Fields()
+	for _, k := range []string{logkOption.TimeKey, logkOption.LevelKey, logkOption.MessageKey,
+		logkOption.NamespaceKey, logkOption.MetadataKey} {
+		delete(fields, k)
+	}
+	for k, v := range fields {
+		params[k] = v
+	}
+	return params
+}
+
+// flattenMetadata flattens nested metadata maps into dst with dot separated keys
+func flattenMetadata(dst map[string]interface{}, prefix string, src map[string]interface{}) {
+	for k, v := range src {
+		if nested, ok := v.(map[string]interface{}); ok && len(nested) > 0 {
+			flattenMetadata(dst, prefix+k+".", nested)
+			continue
+		}
+		dst[prefix+k] = v
+	}
+}
+
+// syslogHeaderValue replaces characters that are not printable ASCII, and truncates value to maximum length
+func syslogHeaderValue(s string, maxLen int) string {
+	if s == "" {
+		return syslogNil
+	}
+
+	s = strings.Map(func(r rune) rune {
+		if r <= ' ' || r > '~' {
+			return '_'
+		}
+		return r
+	}, s)
+
+	if len(s) > maxLen {
+		s = s[:maxLen]
+	}
+	return s
+}
+
+// syslogParamName replaces characters that are not allowed in SD-NAME, and truncates it to 32 characters
+func syslogParamName(s string) string {
+	s = strings.Map(func(r rune) rune {
+		if r <= ' ' || r > '~' || r == '=' || r == ']' || r == '"' {
+			return '_'
+		}
+		return r
+	}, s)
+
+	if len(s) > maxSyslogParamName {
+		s = s[:maxSyslogParamName]
+	}
+	return s
+}
+
+// syslogParamValue formats value and escapes '"', '\' and ']'
+func syslogParamValue(v interface{}) string {
+	var s string
+	switch val := v.(type) {
+	case nil:
+		return ""
+	case string:
+		s = val
+	case error:
+		s = val.Error()
+	case fmt.Stringer:
+		s = val.String()
+	case bool, int, int8, int16, int32, int64, uint, uint8, uint16, uint32, uint64, float32, float64:
+		s = fmt.Sprint(val)
+	default:
+		if b, err := json.Marshal(val); err == nil {
+			s = string(b)
+		} else {
+			s = fmt.Sprintf("%+v", val)
+		}
+	}
+
+	return strings.NewReplacer(`\`, `\\`, `"`, `\"`, `]`, `\]`).Replace(s)
+}
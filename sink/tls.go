@@ -0,0 +1,37 @@
+package logkSink
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"os"
+)
+
+// NewTLSConfig creates TLS config of network printers. CA file holds PEM certificates that verify server, system
+// roots are used if it is empty. Certificate and key files hold PEM client certificate and its key, for servers
+// that require mutual TLS, and are optional
+func NewTLSConfig(caFile, certFile, keyFile string) (*tls.Config, error) {
+	config := tls.Config{MinVersion: tls.VersionTLS12}
+
+	if caFile != "" {
+		pem, err := os.ReadFile(caFile)
+		if err != nil {
+			return nil, fmt.Errorf("%s: failed to read ca file: %w", pkgName, err)
+		}
+
+		config.RootCAs = x509.NewCertPool()
+		if !config.RootCAs.AppendCertsFromPEM(pem) {
+			return nil, fmt.Errorf("%s: no certificates found in ca file %s", pkgName, caFile)
+		}
+	}
+
+	if certFile != "" || keyFile != "" {
+		cert, err := tls.LoadX509KeyPair(certFile, keyFile)
+		if err != nil {
+			return nil, fmt.Errorf("%s: failed to load client certificate: %w", pkgName, err)
+		}
+		config.Certificates = []tls.Certificate{cert}
+	}
+
+	return &config, nil
+}
@@ -0,0 +1,187 @@
+package logkSlog
+
+import (
+	"context"
+	"log/slog"
+
+	"github.com/go-konsultin/logk"
+	"github.com/go-konsultin/logk/level"
+	logkOption "github.com/go-konsultin/logk/option"
+)
+
+// Handler is a slog.Handler that writes records to a logk Logger, so libraries that log with slog share logk
+// printers and level. Attribute groups are rendered as nested metadata
+type Handler struct {
+	logger logk.Logger
+	attrs  map[string]interface{}
+	groups []string
+}
+
+// NewHandler creates slog.Handler backed by logger. If logger is nil, registered logger is used
+func NewHandler(logger logk.Logger) *Handler {
+	return &Handler{logger: logger}
+}
+
+// Enabled reports true for all levels, as filtering is done by logk logger
+func (h *Handler) Enabled(context.Context, slog.Level) bool {
+	return true
+}
+
+func (h *Handler) Handle(ctx context.Context, r slog.Record) error {
+	meta := cloneMap(h.attrs)
+	target := groupMap(meta, h.groups)
+
+	var err error
+	r.Attrs(func(a slog.Attr) bool {
+		// Attach error attribute at top level as logk error
+		if e, ok := a.Value.Resolve().Any().(error); ok && len(h.groups) == 0 && isErrorKey(a.Key) {
+			err = e
+			return true
+		}
+		addAttr(target, a)
+		return true
+	})
+
+	args := make([]logkOption.SetterFunc, 0, 3)
+	if len(meta) > 0 {
+		args = append(args, logkOption.Metadata(meta))
+	}
+	if err != nil {
+		args = append(args, logkOption.Error(err))
+	}
+	if ctx != nil {
+		args = append(args, logkOption.Context(ctx))
+	}
+
+	logger := h.logger
+	if logger == nil {
+		logger = logk.Get()
+	}
+
+	switch toLogkLevel(r.Level) {
+	case level.Fatal:
+		// slog never exits, so fatal entry is only written
+		logger.NewChild(logkOption.WithFatalBehavior(logkOption.FatalNoop)).Fatal(r.Message, args...)
+	case level.Error:
+		logger.Error(r.Message, args...)
+	case level.Warn:
+		logger.Warn(r.Message, args...)
+	case level.Info:
+		logger.Info(r.Message, args...)
+	case level.Debug:
+		logger.Debug(r.Message, args...)
+	default:
+		logger.Trace(r.Message, args...)
+	}
+	return nil
+}
+
+func (h *Handler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	if len(attrs) == 0 {
+		return h
+	}
+
+	c := Handler{logger: h.logger, attrs: cloneMap(h.attrs), groups: h.groups}
+	target := groupMap(c.attrs, c.groups)
+	for _, a := range attrs {
+		addAttr(target, a)
+	}
+	return &c
+}
+
+func (h *Handler) WithGroup(name string) slog.Handler {
+	if name == "" {
+		return h
+	}
+
+	groups := make([]string, len(h.groups), len(h.groups)+1)
+	copy(groups, h.groups)
+	return &Handler{logger: h.logger, attrs: h.attrs, groups: append(groups, name)}
+}
+
+// toLogkLevel maps slog level to the closest logk level
+func toLogkLevel(l slog.Level) level.LogLevel {
+	switch {
+	case l >= LevelFatal:
+		return level.Fatal
+	case l >= slog.LevelError:
+		return level.Error
+	case l >= slog.LevelWarn:
+		return level.Warn
+	case l >= slog.LevelInfo:
+		return level.Info
+	case l >= slog.LevelDebug:
+		return level.Debug
+	default:
+		return level.Trace
+	}
+}
+
+func isErrorKey(k string) bool {
+	return k == "err" || k == logkOption.ErrorKey
+}
+
+// addAttr adds resolved attribute to m, expanding groups into nested maps
+func addAttr(m map[string]interface{}, a slog.Attr) {
+	v := a.Value.Resolve()
+	if a.Equal(slog.Attr{}) {
+		return
+	}
+
+	if v.Kind() != slog.KindGroup {
+		m[a.Key] = attrValue(v)
+		return
+	}
+
+	// Inline group without key
+	target := m
+	if a.Key != "" {
+		nested, ok := m[a.Key].(map[string]interface{})
+		if !ok {
+			nested = make(map[string]interface{})
+			m[a.Key] = nested
+		}
+		target = nested
+	}
+
+	for _, ga := range v.Group() {
+		addAttr(target, ga)
+	}
+}
+
+func attrValue(v slog.Value) interface{} {
+	switch v.Kind() {
+	case slog.KindDuration:
+		return v.Duration().String()
+	default:
+		if err, ok := v.Any().(error); ok {
+			return err.Error()
+		}
+		return v.Any()
+	}
+}
+
+// groupMap returns nested map of groups in m, creating them if necessary
+func groupMap(m map[string]interface{}, groups []string) map[string]interface{} {
+	for _, g := range groups {
+		nested, ok := m[g].(map[string]interface{})
+		if !ok {
+			nested = make(map[string]interface{})
+			m[g] = nested
+		}
+		m = nested
+	}
+	return m
+}
+
+// cloneMap deep copies nested maps, so handlers can be derived without sharing state
+func cloneMap(m map[string]interface{}) map[string]interface{} {
+	result := make(map[string]interface{}, len(m))
+	for k, v := range m {
+		if nested, ok := v.(map[string]interface{}); ok {
+			v = cloneMap(nested)
+		}
+		result[k] = v
+	}
+	return result
+}
@@ -0,0 +1,44 @@
+package logkSlog
+
+import (
+	"context"
+	"log/slog"
+	"testing"
+
+	"github.com/go-konsultin/logk"
+	"github.com/go-konsultin/logk/level"
+	logkTest "github.com/go-konsultin/logk/logktest"
+	logkOption "github.com/go-konsultin/logk/option"
+)
+
+func TestHandlerLevels(t *testing.T) {
+	r := logkTest.NewRecorder()
+	// Fatal entry must not follow fatal behavior of logger, so panic would fail the test
+	logger := logk.NewStdLogger(r, logkOption.Level(level.Trace), logkOption.WithFatalBehavior(logkOption.FatalPanic))
+	sl := slog.New(NewHandler(logger))
+
+	tests := []struct {
+		slog slog.Level
+		want level.LogLevel
+	}{
+		{LevelFatal + 4, level.Fatal},
+		{LevelFatal, level.Fatal},
+		{slog.LevelError, level.Error},
+		{slog.LevelWarn, level.Warn},
+		{slog.LevelInfo, level.Info},
+		{slog.LevelDebug, level.Debug},
+		{LevelTrace, level.Trace},
+	}
+	for _, tc := range tests {
+		r.Reset()
+		sl.Log(context.Background(), tc.slog, "entry")
+
+		e := r.LastEntry()
+		if e == nil {
+			t.Fatalf("%s: no entry written", tc.slog)
+		}
+		if e.Level != tc.want {
+			t.Errorf("%s: level = %s, want %s", tc.slog, level.String(e.Level), level.String(tc.want))
+		}
+	}
+}
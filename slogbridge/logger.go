@@ -0,0 +1,201 @@
+package logkSlog
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"sort"
+
+	"github.com/go-konsultin/logk"
+	logkContext "github.com/go-konsultin/logk/context"
+	"github.com/go-konsultin/logk/level"
+	logkOption "github.com/go-konsultin/logk/option"
+)
+
+// LevelFatal and LevelTrace are slog levels for logk levels that slog doesn't define
+const (
+	LevelFatal slog.Level = slog.LevelError + 4
+	LevelTrace slog.Level = slog.LevelDebug - 4
+)
+
+// Logger implements logk.Logger by writing to *slog.Logger, so code using logk can share slog handlers and level
+type Logger struct {
+	logger    *slog.Logger
+	namespace string
+	ctx       context.Context
+}
+
+// NewLogger creates logk.Logger backed by logger. If logger is nil, slog.Default is used
+func NewLogger(logger *slog.Logger) *Logger {
+	if logger == nil {
+		logger = slog.Default()
+	}
+	return &Logger{logger: logger}
+}
+
+func (l *Logger) Fatal(msg string, args ...logkOption.SetterFunc) {
+	l.log(level.Fatal, msg, logkOption.Evaluate(args))
+}
+
+func (l *Logger) Fatalf(format string, args ...interface{}) {
+	l.log(level.Fatal, format, logkOption.NewFormatOptions(args...))
+}
+
+func (l *Logger) Error(msg string, args ...logkOption.SetterFunc) {
+	l.log(level.Error, msg, logkOption.Evaluate(args))
+}
+
+func (l *Logger) Errorf(format string, args ...interface{}) {
+	l.log(level.Error, format, logkOption.NewFormatOptions(args...))
+}
+
+func (l *Logger) Warn(msg string, args ...logkOption.SetterFunc) {
+	l.log(level.Warn, msg, logkOption.Evaluate(args))
+}
+
+func (l *Logger) Warnf(format string, args ...interface{}) {
+	l.log(level.Warn, format, logkOption.NewFormatOptions(args...))
+}
+
+func (l *Logger) Info(msg string, args ...logkOption.SetterFunc) {
+	l.log(level.Info, msg, logkOption.Evaluate(args))
+}
+
+func (l *Logger) Infof(format string, args ...interface{}) {
+	l.log(level.Info, format, logkOption.NewFormatOptions(args...))
+}
+
+func (l *Logger) Debug(msg string, args ...logkOption.SetterFunc) {
+	l.log(level.Debug, msg, logkOption.Evaluate(args))
+}
+
+func (l *Logger) Debugf(format string, args ...interface{}) {
+	l.log(level.Debug, format, logkOption.NewFormatOptions(args...))
+}
+
+func (l *Logger) Trace(msg string, args ...logkOption.SetterFunc) {
+	l.log(level.Trace, msg, logkOption.Evaluate(args))
+}
+
+func (l *Logger) Tracef(format string, args ...interface{}) {
+	l.log(level.Trace, format, logkOption.NewFormatOptions(args...))
+}
+
+// NewChild creates a child logger. Namespace is written as "namespace" attribute
+func (l *Logger) NewChild(args ...logkOption.SetterFunc) logk.Logger {
+	options := logkOption.Evaluate(args)
+
+	c := Logger{logger: l.logger, namespace: l.namespace, ctx: l.ctx}
+	if namespace := options.Namespace(); namespace != "" {
+		c.namespace = namespace
+	}
+
+	if options.Context != nil {
+		c.ctx = options.Context
+	}
+
+	return &c
+}
+
+func (l *Logger) NewChildCtx(ctx context.Context, args ...logkOption.SetterFunc) logk.Logger {
+	if ctx != nil {
+		args = append(args, logkOption.Context(ctx))
+	}
+	return l.NewChild(args...)
+}
+
+// With creates a child logger which slog.Logger carries metadata set in args as attributes
+func (l *Logger) With(args ...logkOption.SetterFunc) logk.Logger {
+	c := l.NewChild(args...).(*Logger)
+
+	metadata := logkOption.Evaluate(args).Metadata
+	if len(metadata) == 0 {
+		return c
+	}
+
+	keys := make([]string, 0, len(metadata))
+	for k := range metadata {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	attrs := make([]interface{}, 0, len(keys))
+	for _, k := range keys {
+		attrs = append(attrs, slog.Any(k, metadata[k]))
+	}
+	c.logger = c.logger.With(attrs...)
+	return c
+}
+
+// Enabled returns true if slog handler is enabled in level of lv, with context the logger is bound to
+func (l *Logger) Enabled(lv level.LogLevel) bool {
+	ctx := l.ctx
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	return l.logger.Enabled(ctx, toSlogLevel(lv))
+}
+
+func (l *Logger) log(lv level.LogLevel, msg string, options *logkOption.Options) {
+	ctx := options.Context
+	if ctx == nil {
+		ctx = l.ctx
+	}
+	if ctx == nil {
+		ctx = context.Background()
+	}
+
+	sl := toSlogLevel(lv)
+	if !l.logger.Enabled(ctx, sl) {
+		return
+	}
+
+	msg = logkOption.ResolveLazy(options, msg)
+
+	if len(options.FmtArgs) > 0 {
+		msg = fmt.Sprintf(msg, options.FmtArgs...)
+	}
+
+	attrs := make([]slog.Attr, 0, len(options.Metadata)+5)
+	if l.namespace != "" {
+		attrs = append(attrs, slog.String(logkOption.NamespaceKey, l.namespace))
+	}
+
+	if reqId := logkContext.GetRequestId(ctx); reqId != "" {
+		attrs = append(attrs, slog.String(logkOption.RequestIdKey, reqId))
+	}
+
+	if traceId, spanId := logkContext.GetTrace(ctx); traceId != "" {
+		attrs = append(attrs, slog.String(logkOption.TraceIdKey, traceId))
+		if spanId != "" {
+			attrs = append(attrs, slog.String(logkOption.SpanIdKey, spanId))
+		}
+	}
+
+	if err := options.Error(); err != nil {
+		attrs = append(attrs, slog.Any(logkOption.ErrorKey, err))
+	}
+
+	for k, v := range options.Metadata {
+		attrs = append(attrs, slog.Any(k, v))
+	}
+
+	l.logger.LogAttrs(ctx, sl, msg, attrs...)
+}
+
+func toSlogLevel(lv level.LogLevel) slog.Level {
+	switch lv {
+	case level.Fatal:
+		return LevelFatal
+	case level.Error:
+		return slog.LevelError
+	case level.Warn:
+		return slog.LevelWarn
+	case level.Info:
+		return slog.LevelInfo
+	case level.Debug:
+		return slog.LevelDebug
+	default:
+		return LevelTrace
+	}
+}
@@ -0,0 +1,249 @@
+package logkSql
+
+import (
+	"context"
+	"database/sql/driver"
+	"errors"
+	"time"
+
+	"github.com/go-konsultin/logk"
+)
+
+// NewConnector wraps connector, so queries of connections it creates are logged. If logger is nil, logger in
+// query context is used
+func NewConnector(connector driver.Connector, logger logk.Logger, args ...Option) driver.Connector {
+	l := newQueryLogger(logger, args)
+	return &logConnector{connector: connector, driver: &logDriver{Driver: connector.Driver(), logger: l}, logger: l}
+}
+
+// WrapDriver wraps d, so queries of connections it opens are logged. Register it to use with sql.Open, e.g.
+//
+//	sql.Register("logk-postgres", logkSql.WrapDriver(&pq.Driver{}, logger))
+//
+// If logger is nil, logger in query context is used
+func WrapDriver(d driver.Driver, logger logk.Logger, args ...Option) driver.Driver {
+	return &logDriver{Driver: d, logger: newQueryLogger(logger, args)}
+}
+
+type logDriver struct {
+	driver.Driver
+	logger *queryLogger
+}
+
+func (d *logDriver) Open(name string) (driver.Conn, error) {
+	c, err := d.Driver.Open(name)
+	if err != nil {
+		return nil, err
+	}
+	return &logConn{Conn: c, logger: d.logger}, nil
+}
+
+func (d *logDriver) OpenConnector(name string) (driver.Connector, error) {
+	dc, ok := d.Driver.(driver.DriverContext)
+	if !ok {
+		return &dsnConnector{name: name, driver: d}, nil
+	}
+
+	connector, err := dc.OpenConnector(name)
+	if err != nil {
+		return nil, err
+	}
+	return &logConnector{connector: connector, driver: d, logger: d.logger}, nil
+}
+
+type logConnector struct {
+	connector driver.Connector
+	driver    *logDriver
+	logger    *queryLogger
+}
+
+func (c *logConnector) Connect(ctx context.Context) (driver.Conn, error) {
+	conn, err := c.connector.Connect(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return &logConn{Conn: conn, logger: c.logger}, nil
+}
+
+func (c *logConnector) Driver() driver.Driver {
+	return c.driver
+}
+
+// dsnConnector opens connections of driver that doesn't implement driver.DriverContext
+type dsnConnector struct {
+	name   string
+	driver *logDriver
+}
+
+func (c *dsnConnector) Connect(context.Context) (driver.Conn, error) {
+	return c.driver.Open(c.name)
+}
+
+func (c *dsnConnector) Driver() driver.Driver {
+	return c.driver
+}
+
+// logConn implements optional interfaces of driver.Conn. Interfaces that wrapped connection doesn't implement fall
+// back to the behavior database/sql has when they are absent
+type logConn struct {
+	driver.Conn
+	logger *queryLogger
+}
+
+func (c *logConn) Prepare(query string) (driver.Stmt, error) {
+	s, err := c.Conn.Prepare(query)
+	if err != nil {
+		return nil, err
+	}
+	return &logStmt{Stmt: s, query: query, logger: c.logger}, nil
+}
+
+func (c *logConn) PrepareContext(ctx context.Context, query string) (driver.Stmt, error) {
+	pc, ok := c.Conn.(driver.ConnPrepareContext)
+	if !ok {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		return c.Prepare(query)
+	}
+
+	s, err := pc.PrepareContext(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	return &logStmt{Stmt: s, query: query, logger: c.logger}, nil
+}
+
+func (c *logConn) BeginTx(ctx context.Context, opts driver.TxOptions) (driver.Tx, error) {
+	if bc, ok := c.Conn.(driver.ConnBeginTx); ok {
+		return bc.BeginTx(ctx, opts)
+	}
+
+	if opts.Isolation != 0 {
+		return nil, errors.New("sql: driver does not support non-default isolation level")
+	}
+	if opts.ReadOnly {
+		return nil, errors.New("sql: driver does not support read-only transactions")
+	}
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	return c.Conn.Begin()
+}
+
+func (c *logConn) ExecContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Result, error) {
+	ec, ok := c.Conn.(driver.ExecerContext)
+	if !ok {
+		return nil, driver.ErrSkip
+	}
+
+	start := time.Now()
+	res, err := ec.ExecContext(ctx, query, args)
+	c.logger.log(ctx, query, args, start, res, err)
+	return res, err
+}
+
+func (c *logConn) QueryContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Rows, error) {
+	qc, ok := c.Conn.(driver.QueryerContext)
+	if !ok {
+		return nil, driver.ErrSkip
+	}
+
+	start := time.Now()
+	rows, err := qc.QueryContext(ctx, query, args)
+	c.logger.log(ctx, query, args, start, nil, err)
+	return rows, err
+}
+
+func (c *logConn) Ping(ctx context.Context) error {
+	if p, ok := c.Conn.(driver.Pinger); ok {
+		return p.Ping(ctx)
+	}
+	return nil
+}
+
+func (c *logConn) ResetSession(ctx context.Context) error {
+	if r, ok := c.Conn.(driver.SessionResetter); ok {
+		return r.ResetSession(ctx)
+	}
+	return nil
+}
+
+func (c *logConn) IsValid() bool {
+	if v, ok := c.Conn.(driver.Validator); ok {
+		return v.IsValid()
+	}
+	return true
+}
+
+func (c *logConn) CheckNamedValue(nv *driver.NamedValue) error {
+	if nc, ok := c.Conn.(driver.NamedValueChecker); ok {
+		return nc.CheckNamedValue(nv)
+	}
+	return driver.ErrSkip
+}
+
+type logStmt struct {
+	driver.Stmt
+	query  string
+	logger *queryLogger
+}
+
+func (s *logStmt) ExecContext(ctx context.Context, args []driver.NamedValue) (driver.Result, error) {
+	start := time.Now()
+
+	var res driver.Result
+	var err error
+	if ec, ok := s.Stmt.(driver.StmtExecContext); ok {
+		res, err = ec.ExecContext(ctx, args)
+	} else {
+		var values []driver.Value
+		if values, err = namedValues(args); err == nil {
+			if err = ctx.Err(); err == nil {
+				res, err = s.Stmt.Exec(values)
+			}
+		}
+	}
+
+	s.logger.log(ctx, s.query, args, start, res, err)
+	return res, err
+}
+
+func (s *logStmt) QueryContext(ctx context.Context, args []driver.NamedValue) (driver.Rows, error) {
+	start := time.Now()
+
+	var rows driver.Rows
+	var err error
+	if qc, ok := s.Stmt.(driver.StmtQueryContext); ok {
+		rows, err = qc.QueryContext(ctx, args)
+	} else {
+		var values []driver.Value
+		if values, err = namedValues(args); err == nil {
+			if err = ctx.Err(); err == nil {
+				rows, err = s.Stmt.Query(values)
+			}
+		}
+	}
+
+	s.logger.log(ctx, s.query, args, start, nil, err)
+	return rows, err
+}
+
+func (s *logStmt) CheckNamedValue(nv *driver.NamedValue) error {
+	if nc, ok := s.Stmt.(driver.NamedValueChecker); ok {
+		return nc.CheckNamedValue(nv)
+	}
+	return driver.ErrSkip
+}
+
+// namedValues converts args for statements that don't support named arguments
+func namedValues(args []driver.NamedValue) ([]driver.Value, error) {
+	values := make([]driver.Value, len(args))
+	for i, arg := range args {
+		if arg.Name != "" {
+			return nil, errors.New("sql: driver does not support the use of Named Parameters")
+		}
+		values[i] = arg.Value
+	}
+	return values, nil
+}
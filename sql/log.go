@@ -0,0 +1,85 @@
+package logkSql
+
+import (
+	"context"
+	"database/sql/driver"
+	"errors"
+	"strconv"
+	"time"
+
+	"github.com/go-konsultin/logk"
+	logkOption "github.com/go-konsultin/logk/option"
+)
+
+// queryLogger writes query entries, it is shared by connections of a wrapped driver
+type queryLogger struct {
+	logger  logk.Logger
+	options Options
+}
+
+func newQueryLogger(logger logk.Logger, args []Option) *queryLogger {
+	return &queryLogger{logger: logger, options: evaluateOptions(args)}
+}
+
+// log writes query entry. Queries skipped by driver are not written, as database/sql retries them another way
+func (l *queryLogger) log(ctx context.Context, query string, args []driver.NamedValue, start time.Time,
+	res driver.Result, err error) {
+	if errors.Is(err, driver.ErrSkip) {
+		return
+	}
+
+	d := time.Since(start)
+
+	logger := l.logger
+	if logger == nil {
+		logger = logk.FromContext(ctx)
+	}
+
+	opts := []logkOption.SetterFunc{
+		logkOption.Context(ctx),
+		logkOption.AddMetadata(QueryKey, query),
+		logkOption.Field.Duration(DurationKey, d),
+	}
+
+	if l.options.Args && len(args) > 0 {
+		opts = append(opts, logkOption.AddMetadata(ArgsKey, l.args(args)))
+	}
+
+	if res != nil && err == nil {
+		if n, rErr := res.RowsAffected(); rErr == nil {
+			opts = append(opts, logkOption.AddMetadata(RowsAffectedKey, n))
+		}
+	}
+
+	switch {
+	case err != nil:
+		logger.Error("query failed", append(opts, logkOption.Error(err))...)
+	case l.options.SlowThreshold > 0 && d >= l.options.SlowThreshold:
+		logger.Warn("slow query", opts...)
+	default:
+		logger.Debug("query executed", opts...)
+	}
+}
+
+// args returns arguments keyed by name, or by 1-based ordinal for positional arguments
+func (l *queryLogger) args(args []driver.NamedValue) map[string]interface{} {
+	m := make(map[string]interface{}, len(args))
+	for _, arg := range args {
+		k := arg.Name
+		if k == "" {
+			k = strconv.Itoa(arg.Ordinal)
+		}
+
+		v := arg.Value
+		if b, ok := v.([]byte); ok {
+			// Binary values are rarely readable, so only their size is written
+			v = "<" + strconv.Itoa(len(b)) + " bytes>"
+		}
+
+		if l.options.ArgRedactor != nil {
+			v = l.options.ArgRedactor(k, v)
+		}
+		m[k] = v
+	}
+	return m
+}
@@ -0,0 +1,65 @@
+// Package logkSql logs database/sql queries by wrapping a driver, e.g.
+//
+//	db := sql.OpenDB(logkSql.NewConnector(connector, logger))
+//
+// Queries are written as DEBUG with arguments, rows affected and duration. Queries slower than threshold are
+// written as WARN and failed queries as ERROR
+package logkSql
+
+import (
+	"time"
+
+	"github.com/go-konsultin/logk"
+)
+
+// Metadata keys of query entries
+const (
+	QueryKey        = "query"
+	ArgsKey         = "args"
+	RowsAffectedKey = "rowsAffected"
+	DurationKey     = "duration"
+)
+
+// DefaultSlowThreshold is the duration from which queries are written as WARN
+const DefaultSlowThreshold = 200 * time.Millisecond
+
+type Options struct {
+	// SlowThreshold is the duration from which queries are written as WARN. Zero disables slow query entries
+	SlowThreshold time.Duration
+	// Args attaches query arguments, keyed by name for named arguments and by 1-based ordinal otherwise.
+	// Arguments are metadata, so redactors added with logk.AddRedactor apply to them too
+	Args bool
+	// ArgRedactor is applied to every argument before it is attached, with the same key
+	ArgRedactor logk.Redactor
+}
+
+type Option = func(*Options)
+
+func WithSlowThreshold(d time.Duration) Option {
+	return func(o *Options) {
+		o.SlowThreshold = d
+	}
+}
+
+func WithArgs(enabled bool) Option {
+	return func(o *Options) {
+		o.Args = enabled
+	}
+}
+
+func WithArgRedactor(r logk.Redactor) Option {
+	return func(o *Options) {
+		o.ArgRedactor = r
+	}
+}
+
+func evaluateOptions(args []Option) Options {
+	o := Options{
+		SlowThreshold: DefaultSlowThreshold,
+		Args:          true,
+	}
+	for _, fn := range args {
+		fn(&o)
+	}
+	return o
+}
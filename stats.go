@@ -0,0 +1,163 @@
+package logk
+
+import (
+	"expvar"
+	"io"
+	"strings"
+	"sync/atomic"
+
+	"github.com/go-konsultin/logk/level"
+)
+
+// entryCounts counts entries printed by StdLogger, indexed by level
+var entryCounts [level.Trace + 1]atomic.Uint64
+
+// statsSources is guarded by registryMutex
+var statsSources = make(map[string]interface{})
+
+// Stats is a snapshot of logging subsystem statistics, so health dashboards can observe logging itself
+type Stats struct {
+	// Entries is number of entries printed by StdLogger by lowercase level name
+	Entries map[string]uint64 `json:"entries"`
+	// Sinks holds statistics of sources added with AddStatsSource by name
+	Sinks map[string]SinkStats `json:"sinks,omitempty"`
+}
+
+// SinkStats holds statistics that a sink supports. Unsupported statistics are zero and omitted in JSON
+type SinkStats struct {
+	BytesWritten uint64 `json:"bytesWritten,omitempty"`
+	QueueLen     int    `json:"queueLen,omitempty"`
+	QueueCap     int    `json:"queueCap,omitempty"`
+	Dropped      uint64 `json:"dropped,omitempty"`
+	LastError    string `json:"lastError,omitempty"`
+}
+
+// AddStatsSource adds a sink to Stats under name. Statistics are read from methods that source implements:
+// BytesWritten() uint64, QueueLen() int, QueueCap() int, Dropped() uint64 and LastError() error, as
+// StatsWriter, AsyncPrinter and sink printers do. Adding the same name again replaces the source
+func AddStatsSource(name string, source interface{}) {
+	registryMutex.Lock()
+	defer registryMutex.Unlock()
+	statsSources[name] = source
+}
+
+// ClearStatsSources removes all stats sources. It is primarily used to isolate test cases
+func ClearStatsSources() {
+	registryMutex.Lock()
+	defer registryMutex.Unlock()
+	statsSources = make(map[string]interface{})
+}
+
+// ReadStats returns current statistics. Counters are read without lock, so they reflect an instantaneous view
+func ReadStats() Stats {
+	s := Stats{Entries: make(map[string]uint64)}
+	for lv := range entryCounts {
+		if n := entryCounts[lv].Load(); n > 0 {
+			s.Entries[strings.ToLower(level.String(level.LogLevel(lv)))] = n
+		}
+	}
+
+	registryMutex.RLock()
+	defer registryMutex.RUnlock()
+
+	if len(statsSources) == 0 {
+		return s
+	}
+
+	s.Sinks = make(map[string]SinkStats, len(statsSources))
+	for name, source := range statsSources {
+		s.Sinks[name] = readSinkStats(source)
+	}
+	return s
+}
+
+// PublishStats publishes ReadStats as expvar variable with name, e.g. "logk". Like expvar.Publish, it panics if
+// name is already published
+func PublishStats(name string) {
+	expvar.Publish(name, expvar.Func(func() interface{} {
+		return ReadStats()
+	}))
+}
+
+func readSinkStats(source interface{}) SinkStats {
+	var s SinkStats
+	if v, ok := source.(interface{ BytesWritten() uint64 }); ok {
+		s.BytesWritten = v.BytesWritten()
+	}
+	if v, ok := source.(interface{ QueueLen() int }); ok {
+		s.QueueLen = v.QueueLen()
+	}
+	if v, ok := source.(interface{ QueueCap() int }); ok {
+		s.QueueCap = v.QueueCap()
+	}
+	if v, ok := source.(interface{ Dropped() uint64 }); ok {
+		s.Dropped = v.Dropped()
+	}
+	if v, ok := source.(interface{ LastError() error }); ok {
+		if err := v.LastError(); err != nil {
+			s.LastError = err.Error()
+		}
+	}
+	return s
+}
+
+// StatsWriter counts bytes written to w and records the last write error. Use it as output of a printer and add it
+// with AddStatsSource. Sinks that write in background report errors with RecordError, e.g.
+// logkSink.WithOnError(statsWriter.RecordError)
+type StatsWriter struct {
+	w       io.Writer
+	bytes   atomic.Uint64
+	lastErr atomic.Pointer[error]
+}
+
+// NewStatsWriter creates StatsWriter that writes to w. If w is nil, written bytes are only counted
+func NewStatsWriter(w io.Writer) *StatsWriter {
+	if w == nil {
+		w = io.Discard
+	}
+	return &StatsWriter{w: w}
+}
+
+func (w *StatsWriter) Write(p []byte) (int, error) {
+	n, err := w.w.Write(p)
+	w.bytes.Add(uint64(n))
+	if err != nil {
+		w.RecordError(err)
+	}
+	return n, err
+}
+
+// RecordError sets err as the last error
+func (w *StatsWriter) RecordError(err error) {
+	if err != nil {
+		w.lastErr.Store(&err)
+	}
+}
+
+func (w *StatsWriter) BytesWritten() uint64 {
+	return w.bytes.Load()
+}
+
+// LastError returns the last recorded error, or nil if there is none
+func (w *StatsWriter) LastError() error {
+	if err := w.lastErr.Load(); err != nil {
+		return *err
+	}
+	return nil
+}
+
+// Flush flushes w if it implements Flusher
+func (w *StatsWriter) Flush() error {
+	if f, ok := w.w.(Flusher); ok {
+		return f.Flush()
+	}
+	return nil
+}
+
+// Close closes w if it implements io.Closer
+func (w *StatsWriter) Close() error {
+	if c, ok := w.w.(io.Closer); ok {
+		return c.Close()
+	}
+	return nil
+}
@@ -0,0 +1,133 @@
+package logk
+
+import (
+	stdlog "log"
+	"strings"
+
+	"github.com/go-konsultin/logk/level"
+	logkOption "github.com/go-konsultin/logk/option"
+)
+
+type StdlibOptions struct {
+	// SniffLevel detects level from message prefix, e.g. "[WARN] ...", "error: ..." or "DEBUG ...". Detected prefix
+	// is removed from message. Messages without prefix are written with default level of writer
+	SniffLevel bool
+}
+
+type StdlibOption = func(*StdlibOptions)
+
+func WithSniffLevel(enabled bool) StdlibOption {
+	return func(o *StdlibOptions) {
+		o.SniffLevel = enabled
+	}
+}
+
+// sniffedLevels maps message prefixes to levels
+var sniffedLevels = map[string]level.LogLevel{
+	"fatal":   level.Fatal,
+	"panic":   level.Fatal,
+	"error":   level.Error,
+	"err":     level.Error,
+	"warning": level.Warn,
+	"warn":    level.Warn,
+	"info":    level.Info,
+	"debug":   level.Debug,
+	"trace":   level.Trace,
+}
+
+// StdlibWriter is an io.Writer that writes each write as an entry of a logk Logger, so libraries that only accept
+// standard library *log.Logger, e.g. http.Server.ErrorLog, route into logk. FATAL entries are written with FatalNoop
+// behavior, as log.Fatal exits by itself
+type StdlibWriter struct {
+	logger  Logger
+	level   level.LogLevel
+	options StdlibOptions
+}
+
+// NewStdlibWriter creates a writer that writes to logger with lv. If logger is nil, registered logger is used
+func NewStdlibWriter(logger Logger, lv level.LogLevel, args ...StdlibOption) *StdlibWriter {
+	var o StdlibOptions
+	for _, fn := range args {
+		fn(&o)
+	}
+	return &StdlibWriter{logger: logger, level: lv, options: o}
+}
+
+// NewStdlibLogger creates *log.Logger that writes to logger with lv, without prefix and flags as time is stamped by
+// logk. If logger is nil, registered logger is used
+func NewStdlibLogger(logger Logger, lv level.LogLevel, args ...StdlibOption) *stdlog.Logger {
+	return stdlog.New(NewStdlibWriter(logger, lv, args...), "", 0)
+}
+
+func (w *StdlibWriter) Write(p []byte) (int, error) {
+	msg := strings.TrimRight(string(p), "\r\n")
+
+	lv := w.level
+	if w.options.SniffLevel {
+		if sniffed, rest, ok := sniffLevel(msg); ok {
+			lv, msg = sniffed, rest
+		}
+	}
+
+	logger := w.logger
+	if logger == nil {
+		logger = Get()
+	}
+
+	switch lv {
+	case level.Fatal:
+		logger.NewChild(logkOption.WithFatalBehavior(logkOption.FatalNoop)).Fatal(msg)
+	case level.Error:
+		logger.Error(msg)
+	case level.Warn:
+		logger.Warn(msg)
+	case level.Info:
+		logger.Info(msg)
+	case level.Debug:
+		logger.Debug(msg)
+	default:
+		logger.Trace(msg)
+	}
+
+	return len(p), nil
+}
+
+// sniffLevel detects level name at the start of msg, optionally enclosed in brackets or followed by colon, and
+// returns msg without it
+func sniffLevel(msg string) (level.LogLevel, string, bool) {
+	s := strings.TrimLeft(msg, " ")
+	bracket := strings.HasPrefix(s, "[")
+	if bracket {
+		s = s[1:]
+	}
+
+	// Read level name
+	i := 0
+	for i < len(s) && (s[i] >= 'a' && s[i] <= 'z' || s[i] >= 'A' && s[i] <= 'Z') {
+		i++
+	}
+
+	name := s[:i]
+	lv, ok := sniffedLevels[strings.ToLower(name)]
+	if !ok {
+		return 0, msg, false
+	}
+	s = s[i:]
+
+	// Name must be terminated, so words such as "information" are not matched. Name followed by space must be
+	// uppercase, so sentences such as "error reading body" are not matched
+	switch {
+	case bracket && strings.HasPrefix(s, "]"):
+		s = s[1:]
+	case bracket:
+		return 0, msg, false
+	case strings.HasPrefix(s, ":"):
+		s = s[1:]
+	case name != strings.ToUpper(name):
+		return 0, msg, false
+	case s != "" && s[0] != ' ':
+		return 0, msg, false
+	}
+
+	return lv, strings.TrimLeft(s, " :"), true
+}
@@ -6,7 +6,13 @@ import (
 	"fmt"
 	"io"
 	stdLog "log"
+	"maps"
 	"os"
+	"runtime"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
 
 	logkContext "github.com/go-konsultin/logk/context"
 	"github.com/go-konsultin/logk/level"
@@ -22,100 +28,680 @@ var stdLevelPrefix = map[level.LogLevel]string{
 	level.Trace: "[TRACE] > ",
 }
 
+// processStart is used to calculate uptime that is rendered by loggers constructed with logkOption.WithUptime
+var processStart = time.Now()
+
+// stdCallerSkip is number of frames between captureCaller and the caller of logger methods, i.e. captureCaller,
+// print and the logger method
+const stdCallerSkip = 3
+
+// maxStackDepth limits number of frames in captured stack trace
+const maxStackDepth = 64
+
+// exit terminates the process after a FATAL entry. It is a variable, so it can be replaced in tests
+var exit = os.Exit
+
+// globalSequence is the counter used by loggers that are constructed with logkOption.WithGlobalSequence
+var globalSequence atomic.Uint64
+
 type StdLogger struct {
-	level     level.LogLevel
+	// level is own level state of logger. It's nil on children that follow level of levelParent, until they set
+	// their own level
+	level       atomic.Pointer[levelState]
+	levelParent *StdLogger
+
 	printer   Printer
 	namespace string
 	ctx       context.Context
+	tee       Printer
+	sequence  *atomic.Uint64
+	baggage   map[string]string
+	uptime    bool
+
+	// fatalBehavior and exitCode determine what logger does after writing a FATAL entry
+	fatalBehavior logkOption.FatalBehavior
+	exitCode      int
+
+	// hooks are replaced on write under hooksMu, so they can be read without lock on every entry
+	hooks atomic.Pointer[[]Hook]
+
+	// fields is persistent metadata set with With
+	fields map[string]interface{}
+
+	// caller enables capturing call site of every entry, skipping callerSkip additional frames
+	caller     bool
+	callerSkip int
+
+	// stackTrace enables capturing stack trace of entries in stackTraceLevel and more severe levels
+	stackTrace      bool
+	stackTraceLevel level.LogLevel
+
+	requestIdGenerator func() string
+	requestId          atomic.Pointer[string]
+
+	// hooksMu guards hook updates
+	hooksMu sync.Mutex
 }
 
+// inheritLevel is effective level of a level state that follows level of parent logger
+const inheritLevel = -1
+
+// levelState is level of a logger. Effective level is stored in effective, so it can be read without lock
+type levelState struct {
+	effective atomic.Int32
+
+	// mu guards base level and overrides. Base level is not set on state of a child that follows its parent
+	mu        sync.Mutex
+	base      level.LogLevel
+	hasBase   bool
+	overrides []*levelOverride
+}
+
+type levelOverride struct {
+	level level.LogLevel
+}
+
+func newLevelState(lv level.LogLevel) *levelState {
+	s := levelState{base: lv, hasBase: true}
+	s.effective.Store(int32(lv))
+	return &s
+}
+
+// Fatal writes entry in FATAL level, then exits, panics or returns according to logkOption.WithFatalBehavior
 func (l *StdLogger) Fatal(msg string, args ...logkOption.SetterFunc) {
-	l.print(level.Fatal, msg, logkOption.Evaluate(args))
+	options := logkOption.Evaluate(args)
+	l.print(level.Fatal, msg, options, 0)
+	logkOption.Release(options)
+	l.fatal(msg)
 }
 
 func (l *StdLogger) Fatalf(format string, args ...interface{}) {
-	l.print(level.Fatal, format, logkOption.NewFormatOptions(args...))
+	options := logkOption.NewFormatOptions(args...)
+	l.print(level.Fatal, format, options, 0)
+	logkOption.Release(options)
+	l.fatal(fmt.Sprintf(format, args...))
+}
+
+// Panic writes entry in FATAL level, flushes printers and panics with message regardless of fatal behavior
+func (l *StdLogger) Panic(msg string, args ...logkOption.SetterFunc) {
+	options := logkOption.Evaluate(args)
+	l.print(level.Fatal, msg, options, 0)
+	logkOption.Release(options)
+	_ = l.Flush()
+	panic(msg)
+}
+
+func (l *StdLogger) Panicf(format string, args ...interface{}) {
+	options := logkOption.NewFormatOptions(args...)
+	l.print(level.Fatal, format, options, 0)
+	logkOption.Release(options)
+	_ = l.Flush()
+	panic(fmt.Sprintf(format, args...))
 }
 
 func (l *StdLogger) Error(msg string, args ...logkOption.SetterFunc) {
-	l.print(level.Error, msg, logkOption.Evaluate(args))
+	if !l.Enabled(level.Error) {
+		return
+	}
+	options := logkOption.Evaluate(args)
+	l.print(level.Error, msg, options, 0)
+	logkOption.Release(options)
 }
 
 func (l *StdLogger) Errorf(format string, args ...interface{}) {
-	l.print(level.Error, format, logkOption.NewFormatOptions(args...))
+	if !l.Enabled(level.Error) {
+		return
+	}
+	options := logkOption.NewFormatOptions(args...)
+	l.print(level.Error, format, options, 0)
+	logkOption.Release(options)
 }
 
 func (l *StdLogger) Warn(msg string, args ...logkOption.SetterFunc) {
-	l.print(level.Warn, msg, logkOption.Evaluate(args))
+	if !l.Enabled(level.Warn) {
+		return
+	}
+	options := logkOption.Evaluate(args)
+	l.print(level.Warn, msg, options, 0)
+	logkOption.Release(options)
 }
 
 func (l *StdLogger) Warnf(format string, args ...interface{}) {
-	l.print(level.Warn, format, logkOption.NewFormatOptions(args...))
+	if !l.Enabled(level.Warn) {
+		return
+	}
+	options := logkOption.NewFormatOptions(args...)
+	l.print(level.Warn, format, options, 0)
+	logkOption.Release(options)
 }
 
 func (l *StdLogger) Info(msg string, args ...logkOption.SetterFunc) {
-	l.print(level.Info, msg, logkOption.Evaluate(args))
+	if !l.Enabled(level.Info) {
+		return
+	}
+	options := logkOption.Evaluate(args)
+	l.print(level.Info, msg, options, 0)
+	logkOption.Release(options)
 }
 
 func (l *StdLogger) Infof(format string, args ...interface{}) {
-	l.print(level.Info, format, logkOption.NewFormatOptions(args...))
+	if !l.Enabled(level.Info) {
+		return
+	}
+	options := logkOption.NewFormatOptions(args...)
+	l.print(level.Info, format, options, 0)
+	logkOption.Release(options)
 }
 
 func (l *StdLogger) Debug(msg string, args ...logkOption.SetterFunc) {
-	l.print(level.Debug, msg, logkOption.Evaluate(args))
+	if !l.Enabled(level.Debug) {
+		return
+	}
+	options := logkOption.Evaluate(args)
+	l.print(level.Debug, msg, options, 0)
+	logkOption.Release(options)
 }
 
 func (l *StdLogger) Debugf(format string, args ...interface{}) {
-	l.print(level.Debug, format, logkOption.NewFormatOptions(args...))
+	if !l.Enabled(level.Debug) {
+		return
+	}
+	options := logkOption.NewFormatOptions(args...)
+	l.print(level.Debug, format, options, 0)
+	logkOption.Release(options)
 }
 
 func (l *StdLogger) Trace(msg string, args ...logkOption.SetterFunc) {
-	l.print(level.Trace, msg, logkOption.Evaluate(args))
+	if !l.Enabled(level.Trace) {
+		return
+	}
+	options := logkOption.Evaluate(args)
+	l.print(level.Trace, msg, options, 0)
+	logkOption.Release(options)
 }
 
 func (l *StdLogger) Tracef(format string, args ...interface{}) {
-	l.print(level.Trace, format, logkOption.NewFormatOptions(args...))
+	if !l.Enabled(level.Trace) {
+		return
+	}
+	options := logkOption.NewFormatOptions(args...)
+	l.print(level.Trace, format, options, 0)
+	logkOption.Release(options)
 }
 
 func (l *StdLogger) NewChild(args ...logkOption.SetterFunc) Logger {
+	return l.newChild(args)
+}
+
+// With creates a logger that merges metadata set in args into every subsequent entry, e.g. user and tenant id of
+// a request. Unlike NewChild, it keeps namespace unless overridden. Metadata that is set on call takes precedence
+// over persistent fields, which take precedence over fields extracted from context. Children inherit and can extend
+// fields with With. Groups are merged key by key on every layer, see logkOption.MergeMetadata
+func (l *StdLogger) With(args ...logkOption.SetterFunc) Logger {
+	cl := l.newChild(args)
+	if metadata := logkOption.Evaluate(args).Metadata; len(metadata) > 0 {
+		// Copy metadata, as it may be a map that is owned by caller
+		cl.fields = logkOption.MergeMetadata(l.fields, metadata)
+		if len(l.fields) == 0 {
+			cl.fields = maps.Clone(metadata)
+		}
+	}
+	return cl
+}
+
+func (l *StdLogger) newChild(args []logkOption.SetterFunc) *StdLogger {
 	options := logkOption.Evaluate(args)
 
 	// Override namespace if option is set
-	namespace, _ := logkOption.GetString(options, logkOption.NamespaceKey)
+	namespace := options.Namespace()
 
 	// If not set and parent has namespace, then use parent namespace
 	if namespace == "" && l.namespace != "" {
 		args = append(args, logkOption.WithNamespace(l.namespace))
 	}
 
-	// Override level arguments
-	args = append(args, logkOption.Level(l.level))
+	// Inherit baggage, child baggage is merged on top of it
+	if len(l.baggage) > 0 {
+		args = append([]logkOption.SetterFunc{logkOption.WithBaggage(l.baggage)}, args...)
+	}
 
 	// Initiate new logger
 	cl := NewStdLogger(l.printer, args...)
 
+	// Follow level of parent if not overridden, so level changes reach children until they set their own
+	if !hasLevel(args) {
+		cl.level.Store(nil)
+		cl.levelParent = l
+	}
+
 	// Set context if available
 	if ctx := options.Context; ctx != nil {
 		cl.ctx = ctx
 	}
 
+	// Inherit tee if not overridden
+	if _, ok := logkOption.GetBool(options, logkOption.TeeKey); !ok {
+		cl.tee = l.tee
+	}
+
+	// Inherit request id generator if not overridden
+	if logkOption.GetRequestIdGenerator(options, logkOption.RequestIdGeneratorKey) == nil {
+		cl.requestIdGenerator = l.requestIdGenerator
+	}
+
+	// Inherit uptime if not overridden
+	if _, ok := logkOption.GetBool(options, logkOption.UptimeKey); !ok {
+		cl.uptime = l.uptime
+	}
+
+	// Inherit caller if not overridden
+	if _, ok := logkOption.GetInt(options, logkOption.CallerSkipKey); !ok {
+		cl.caller = l.caller
+		cl.callerSkip = l.callerSkip
+	}
+
+	// Inherit fatal behavior if not overridden
+	if _, ok := logkOption.GetString(options, logkOption.FatalBehaviorKey); !ok {
+		cl.fatalBehavior = l.fatalBehavior
+	}
+
+	if _, ok := logkOption.GetInt(options, logkOption.ExitCodeKey); !ok {
+		cl.exitCode = l.exitCode
+	}
+
+	// Inherit stack trace level if not overridden
+	if _, ok := logkOption.GetLevel(options, logkOption.StackTraceLevelKey); !ok {
+		cl.stackTrace = l.stackTrace
+		cl.stackTraceLevel = l.stackTraceLevel
+	}
+
+	// Inherit sequence counter if not overridden
+	if _, ok := logkOption.GetString(options, logkOption.SequenceModeKey); !ok {
+		cl.sequence = l.sequence
+	}
+
+	// Inherit hooks that are added so far
+	cl.hooks.Store(l.hooks.Load())
+
+	// Inherit persistent fields, they are never mutated so they can be shared
+	cl.fields = l.fields
+
 	return cl
 }
 
-func (l *StdLogger) print(outLevel level.LogLevel, msg string, options *logkOption.Options) {
+// hasLevel reports whether args set level with logkOption.Level
+func hasLevel(args []logkOption.SetterFunc) bool {
+	const unset level.LogLevel = -1
+
+	o := logkOption.NewOptions()
+	o.Level = unset
+	for _, fn := range args {
+		fn(o)
+	}
+	return o.Level != unset
+}
+
+// NewChildCtx creates a child logger that is bound to ctx. If ctx is nil, child is bound to parent context
+func (l *StdLogger) NewChildCtx(ctx context.Context, args ...logkOption.SetterFunc) Logger {
+	if ctx == nil {
+		ctx = l.ctx
+	}
+
+	if ctx != nil {
+		args = append(args, logkOption.Context(ctx))
+	}
+
+	return l.NewChild(args...)
+}
+
+// WithTemporaryLevel overrides logger level until the returned restore function is called. Scopes can be nested,
+// restoring a scope brings back the level of the most recent scope that is still active, or the prior level.
+// Children that follow level of logger are overridden as well, but parent and siblings are not, so a request-scoped
+// child can be raised without changing the global level. Child that follows its parent follows it again once all
+// scopes are restored
+func (l *StdLogger) WithTemporaryLevel(lv level.LogLevel) (restore func()) {
+	o := &levelOverride{level: lv}
+
+	s := l.ownLevelState()
+	s.mu.Lock()
+	s.overrides = append(s.overrides, o)
+	s.effective.Store(int32(lv))
+	s.mu.Unlock()
+
+	var once sync.Once
+	return func() {
+		once.Do(func() {
+			s.removeOverride(o)
+		})
+	}
+}
+
+func (s *levelState) removeOverride(o *levelOverride) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for i, v := range s.overrides {
+		if v == o {
+			s.overrides = append(s.overrides[:i], s.overrides[i+1:]...)
+			break
+		}
+	}
+
+	// Set effective level
+	switch n := len(s.overrides); {
+	case n > 0:
+		s.effective.Store(int32(s.overrides[n-1].level))
+	case s.hasBase:
+		s.effective.Store(int32(s.base))
+	default:
+		s.effective.Store(inheritLevel)
+	}
+}
+
+// AddHook adds a hook that is called before each entry is printed, in order of addition. Children that are created
+// afterwards inherit hooks added so far
+func (l *StdLogger) AddHook(hook Hook) {
+	l.hooksMu.Lock()
+	defer l.hooksMu.Unlock()
+
+	var hooks []Hook
+	if current := l.hooks.Load(); current != nil {
+		hooks = append(hooks, *current...)
+	}
+	hooks = append(hooks, hook)
+	l.hooks.Store(&hooks)
+}
+
+// SetLevel changes logger level. If temporary levels are active, it takes effect after they are restored.
+// Children that don't set their own level follow level of logger, so it changes their level as well, e.g. on
+// config reload. Child that sets level stops following its parent, and parent and siblings are not affected
+func (l *StdLogger) SetLevel(lv level.LogLevel) {
+	s := l.ownLevelState()
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.base = lv
+	s.hasBase = true
+	if len(s.overrides) == 0 {
+		s.effective.Store(int32(lv))
+	}
+}
+
+// GetLevel returns the current effective level
+func (l *StdLogger) GetLevel() level.LogLevel {
+	return level.LogLevel(l.effectiveLevelState().effective.Load())
+}
+
+// effectiveLevelState returns level state that determines level of logger, which is state of the closest
+// ancestor that has level set if logger follows its parent
+func (l *StdLogger) effectiveLevelState() *levelState {
+	for c := l; ; c = c.levelParent {
+		if s := c.level.Load(); (s != nil && s.effective.Load() != inheritLevel) || c.levelParent == nil {
+			return s
+		}
+	}
+}
+
+// ownLevelState returns level state of logger, creating one that follows parent if logger has none, so it can be
+// changed without affecting parent
+func (l *StdLogger) ownLevelState() *levelState {
+	if s := l.level.Load(); s != nil {
+		return s
+	}
+
+	s := new(levelState)
+	s.effective.Store(inheritLevel)
+	if !l.level.CompareAndSwap(nil, s) {
+		return l.level.Load()
+	}
+	return s
+}
+
+// Flush writes out buffered entries of printers that implement Flusher
+func (l *StdLogger) Flush() error {
+	var err error
+	for _, p := range []Printer{l.printer, l.tee} {
+		if f, ok := p.(Flusher); ok {
+			if fErr := f.Flush(); fErr != nil && err == nil {
+				err = fErr
+			}
+		}
+	}
+	return err
+}
+
+// Close flushes printers and closes them if they implement io.Closer
+func (l *StdLogger) Close() error {
+	err := l.Flush()
+	if c, ok := l.printer.(io.Closer); ok {
+		if cErr := c.Close(); cErr != nil && err == nil {
+			err = cErr
+		}
+	}
+	return err
+}
+
+// Enabled returns true if entry in outLevel passes logger level, or namespace level override. It's checked before
+// options are evaluated, so calls in disabled levels don't allocate
+func (l *StdLogger) Enabled(outLevel level.LogLevel) bool {
+	// Namespace override takes precedence over logger level
+	threshold := l.GetLevel()
+	if lv, ok := namespaceLevel(l.namespace); ok {
+		threshold = lv
+	}
+	return outLevel <= threshold
+}
+
+// Check returns entry that is written with CheckedEntry.Write if lv is enabled, or nil if it isn't
+func (l *StdLogger) Check(lv level.LogLevel, msg string) *CheckedEntry {
+	return Check(l, lv, msg)
+}
+
+// logAt writes entry in lv like the logger method of lv, including fatal behavior of FATAL entries. It's called by
+// wrappers that are skip frames away from caller, e.g. CheckedEntry.Write, so call site is captured correctly.
+// Options are released once entry is written
+func (l *StdLogger) logAt(lv level.LogLevel, msg string, options *logkOption.Options, skip int) {
+	l.print(lv, msg, options, skip)
+	logkOption.Release(options)
+	if lv == level.Fatal {
+		l.fatal(msg)
+	}
+}
+
+// print writes entry. Skip is number of frames between the logger method and print, in addition to those
+// in stdCallerSkip
+func (l *StdLogger) print(outLevel level.LogLevel, msg string, options *logkOption.Options, skip int) {
 	// if output level is greater than log level, don't print
-	if outLevel > l.level {
+	if !l.Enabled(outLevel) {
+		return
+	}
+
+	// Suppress entry that has been written once
+	if key, ok := logkOption.GetString(options, logkOption.OnceKey); ok && !markOnce(key) {
 		return
 	}
 
+	// Compute lazy message and metadata, now that entry is known to be written
+	msg = logkOption.ResolveLazy(options, msg)
+
+	// Set output level and namespace, so they are available on options snapshot
+	options.Level = outLevel
+	if l.namespace != "" {
+		options.Values[logkOption.NamespaceKey] = l.namespace
+	}
+
+	// Merge logger baggage, baggage that is set on call takes precedence
+	if len(l.baggage) > 0 {
+		callBaggage := options.Baggage()
+		merged := make(map[string]string, len(l.baggage)+len(callBaggage))
+		for k, v := range l.baggage {
+			merged[k] = v
+		}
+		for k, v := range callBaggage {
+			merged[k] = v
+		}
+		options.Values[logkOption.BaggageKey] = merged
+	}
+
+	// Merge persistent fields, metadata that is set on call takes precedence. Fields are copied, as hooks may
+	// mutate metadata
+	if len(l.fields) > 0 {
+		merged := make(map[string]interface{}, len(l.fields)+len(options.Metadata))
+		for k, v := range l.fields {
+			merged[k] = v
+		}
+		options.Metadata = logkOption.MergeMetadata(merged, options.Metadata)
+	}
+
+	// Stamp timestamp
+	stampTime(options)
+
+	// Stamp uptime
+	if l.uptime {
+		options.Values[logkOption.UptimeKey] = uptime()
+	}
+
+	// Capture call site if enabled on logger or entry
+	if captured, _ := logkOption.GetBool(options, logkOption.CallerKey); l.caller || captured {
+		if c, ok := captureCaller(l.callerSkip + skip); ok {
+			options.Values[logkOption.CallerKey] = c
+		} else {
+			delete(options.Values, logkOption.CallerKey)
+		}
+	}
+
+	// Capture stack trace if enabled on logger level or entry
+	captured, _ := logkOption.GetBool(options, logkOption.StackTraceKey)
+	if captured || (l.stackTrace && outLevel <= l.stackTraceLevel) {
+		options.Values[logkOption.StackTraceKey] = captureStackTrace(l.callerSkip + skip)
+	}
+
+	// Stamp sequence number
+	if l.sequence != nil {
+		options.Values[logkOption.SequenceKey] = l.sequence.Add(1)
+	}
+
 	// Inject context if not set
 	if l.ctx != nil && options.Context == nil {
 		options.Context = l.ctx
 	}
 
+	// Generate request id if not available in context
+	if l.requestIdGenerator != nil && logkContext.GetRequestId(options.Context) == "" {
+		ctx := options.Context
+		if ctx == nil {
+			ctx = context.Background()
+		}
+		options.Context = logkContext.SetRequestId(ctx, l.generatedRequestId())
+	}
+
+	// Run logger hooks, which may mutate or drop entry
+	if hooks := l.hooks.Load(); hooks != nil {
+		// Copy metadata, so hooks don't mutate map that is owned by caller
+		metadata := make(map[string]interface{}, len(options.Metadata))
+		for k, v := range options.Metadata {
+			metadata[k] = v
+		}
+		options.Metadata = metadata
+
+		for _, hook := range *hooks {
+			if !hook(l.namespace, outLevel, msg, options) {
+				return
+			}
+		}
+	}
+
+	// Redact sensitive data, so no printer sees it
+	registryMutex.RLock()
+	fns := redactors
+	registryMutex.RUnlock()
+	if len(fns) > 0 {
+		msg = redact(fns, msg, options)
+	}
+
+	// Run registered level hooks
+	runHooks(l.namespace, outLevel, msg, options)
+
+	if outLevel >= 0 && int(outLevel) < len(entryCounts) {
+		entryCounts[outLevel].Add(1)
+	}
+
 	l.printer.Print(l.namespace, outLevel, msg, options)
+
+	// Mirror to standard library logger
+	if l.tee != nil {
+		l.tee.Print(l.namespace, outLevel, msg, options)
+	}
+}
+
+// captureCaller returns call site of the logger method that calls print, skipping additional frames
+func captureCaller(skip int) (logkOption.Caller, bool) {
+	pc, file, line, ok := runtime.Caller(stdCallerSkip + skip)
+	if !ok {
+		return logkOption.Caller{}, false
+	}
+
+	c := logkOption.Caller{File: file, Line: line}
+	if fn := runtime.FuncForPC(pc); fn != nil {
+		c.Function = fn.Name()
+	}
+	return c, true
+}
+
+// captureStackTrace returns stack trace starting at the caller of logger method, skipping additional frames.
+// Each frame is written as function name and indented file:line, like runtime/debug.Stack
+func captureStackTrace(skip int) string {
+	pcs := make([]uintptr, maxStackDepth)
+	// Skip runtime.Callers in addition to frames skipped by captureCaller
+	n := runtime.Callers(stdCallerSkip+skip+1, pcs)
+	frames := runtime.CallersFrames(pcs[:n])
+
+	var sb strings.Builder
+	for {
+		frame, more := frames.Next()
+		if frame.Function != "" {
+			fmt.Fprintf(&sb, "%s\n\t%s:%d\n", frame.Function, frame.File, frame.Line)
+		}
+		if !more {
+			break
+		}
+	}
+	return sb.String()
+}
+
+// fatal runs fatal hooks, flushes printers of logger and registered logger and exits or panics according to fatal
+// behavior. Crash ring printer is dumped before exit, panics are left to HandlePanic
+func (l *StdLogger) fatal(msg string) {
+	if l.fatalBehavior == logkOption.FatalNoop {
+		return
+	}
+
+	runFatalHooks()
+	flushAll(l)
+
+	if l.fatalBehavior == logkOption.FatalPanic {
+		panic(msg)
+	}
+	dumpCrash()
+	exit(l.exitCode)
 }
 
+// generatedRequestId returns request id that is generated once for the lifetime of logger
+func (l *StdLogger) generatedRequestId() string {
+	if id := l.requestId.Load(); id != nil {
+		return *id
+	}
+
+	// Keep the first generated id if called concurrently
+	id := l.requestIdGenerator()
+	if l.requestId.CompareAndSwap(nil, &id) {
+		return id
+	}
+	return *l.requestId.Load()
+}
+
+// NewStdLogger creates a logger that writes to printer. Additional printers can be set with WithPrinter option,
+// so every entry is written to each printer in order. If no printer is set, entries are written to Stdout
 func NewStdLogger(printer Printer, args ...logkOption.SetterFunc) *StdLogger {
 	// Init standard logger instance
 	l := StdLogger{}
@@ -124,7 +710,7 @@ func NewStdLogger(printer Printer, args ...logkOption.SetterFunc) *StdLogger {
 	o := logkOption.Evaluate(args)
 
 	// Set level
-	l.level = o.Level
+	l.level.Store(newLevelState(o.Level))
 
 	// Get namespace
 	if namespace, _ := logkOption.GetString(o, logkOption.NamespaceKey); namespace != "" {
@@ -136,6 +722,50 @@ func NewStdLogger(printer Printer, args ...logkOption.SetterFunc) *StdLogger {
 		l.ctx = ctx
 	}
 
+	// Mirror output to standard library logger if enabled
+	if tee, _ := logkOption.GetBool(o, logkOption.TeeKey); tee {
+		l.tee = &stdLogPrinter{writer: stdLog.Default()}
+	}
+
+	// Get uptime
+	l.uptime, _ = logkOption.GetBool(o, logkOption.UptimeKey)
+
+	// Get caller
+	l.callerSkip, l.caller = logkOption.GetInt(o, logkOption.CallerSkipKey)
+
+	// Get fatal behavior
+	l.fatalBehavior = logkOption.FatalExit
+	if b, _ := logkOption.GetString(o, logkOption.FatalBehaviorKey); b != "" {
+		l.fatalBehavior = b
+	}
+
+	l.exitCode = 1
+	if code, ok := logkOption.GetInt(o, logkOption.ExitCodeKey); ok {
+		l.exitCode = code
+	}
+
+	// Get stack trace level
+	l.stackTraceLevel, l.stackTrace = logkOption.GetLevel(o, logkOption.StackTraceLevelKey)
+
+	// Get baggage
+	l.baggage, _ = logkOption.GetStringMap(o, logkOption.BaggageKey)
+
+	// Set request id generator
+	l.requestIdGenerator = logkOption.GetRequestIdGenerator(o, logkOption.RequestIdGeneratorKey)
+
+	// Set sequence counter
+	switch mode, _ := logkOption.GetString(o, logkOption.SequenceModeKey); mode {
+	case logkOption.SequenceLogger:
+		l.sequence = new(atomic.Uint64)
+	case logkOption.SequenceGlobal:
+		l.sequence = &globalSequence
+	}
+
+	// Wrap with additional printers
+	if printers, _ := o.Values[logkOption.PrintersKey].([]Printer); len(printers) > 0 {
+		printer = MultiPrinter(append([]Printer{printer}, printers...)...)
+	}
+
 	// Init printer if nil
 	if printer == nil {
 		l.printer = NewStdLogPrinter(os.Stdout, stdLog.LstdFlags)
@@ -146,60 +776,140 @@ func NewStdLogger(printer Printer, args ...logkOption.SetterFunc) *StdLogger {
 	return &l
 }
 
-func NewStdLogPrinter(out io.Writer, flag int) *stdLogPrinter {
+func NewStdLogPrinter(out io.Writer, flag int, args ...PrinterOption) *stdLogPrinter {
 	// If writer is nil, set default writer to Stdout
 	if out == nil {
 		out = os.Stdout
 	}
 
+	o := evaluatePrinterOptions(args)
+
+	// If time options are set, stamp entries with formatted entry time instead of time flags
+	var timeLayout string
+	if o.TimeFormat != "" || o.TimeLocation != nil {
+		timeLayout = stdLogTimeLayout(flag)
+		flag &^= stdLog.Ldate | stdLog.Ltime | stdLog.Lmicroseconds | stdLog.LUTC
+	}
+
 	// Init log.Logger
 	writer := stdLog.New(out, "", flag)
 
-	return &stdLogPrinter{writer: writer}
+	return &stdLogPrinter{writer: writer, options: o, timeLayout: timeLayout}
 }
 
 type stdLogPrinter struct {
-	writer *stdLog.Logger
+	writer  *stdLog.Logger
+	options PrinterOptions
+	// timeLayout is layout of time flags, used when time options are set
+	timeLayout string
+}
+
+// stdLogTimeLayout returns time layout that is equivalent to time flags of log package
+func stdLogTimeLayout(flag int) string {
+	var layout []string
+	if flag&stdLog.Ldate != 0 {
+		layout = append(layout, "2006/01/02")
+	}
+
+	switch {
+	case flag&stdLog.Lmicroseconds != 0:
+		layout = append(layout, "15:04:05.000000")
+	case flag&stdLog.Ltime != 0:
+		layout = append(layout, "15:04:05")
+	}
+	return strings.Join(layout, " ")
 }
 
 func (s *stdLogPrinter) Print(namespace string, lv level.LogLevel, msg string, options *logkOption.Options) {
 	writer := s.writer
+	entry := acquireEntry(namespace, lv, msg, options, &s.options)
+	defer releaseEntry(entry)
 
 	// Generate prefix
-	prefix := stdLevelPrefix[lv]
+	prefix, ok := s.options.LevelPrefix[lv]
+	if !ok {
+		prefix = stdLevelPrefix[lv]
+	}
 
 	// Append namespace
-	if namespace != "" {
-		prefix = fmt.Sprintf("%s(%s) ", prefix, namespace)
+	if entry.Namespace != "" {
+		prefix = prefix + "(" + entry.Namespace + ") "
 	}
 
-	// If options is existed
-	// If formatted arguments is available, then print as formatted
-	fmtArgs := options.FmtArgs
-	if len(fmtArgs) > 0 {
-		writer.Printf(prefix+msg+"\n", fmtArgs...)
-	} else {
-		writer.Printf("%s%s\n", prefix, msg)
+	// Prepend timestamp if it's not written by log flags
+	if s.options.TimeFormat != "" || s.timeLayout != "" {
+		prefix = fmt.Sprint(entry.formatTime(s.timeLayout)) + " " + prefix
+	}
+
+	writer.Print(prefix + entry.Message + "\n")
+
+	// Get sequence number
+	if entry.Sequence > 0 {
+		writer.Printf("  > Sequence: %d\n", entry.Sequence)
+	}
+
+	// Get uptime
+	if entry.Uptime > 0 {
+		writer.Printf("  > Uptime: %s\n", entry.Uptime)
+	}
+
+	// Get sample rate
+	if entry.SampleRate > 0 {
+		writer.Printf("  > Sampled: 1 in %d\n", entry.SampleRate)
+	}
+
+	// Get caller
+	if entry.Caller.File != "" {
+		writer.Printf("  > Caller: %s (%s)\n", entry.Caller, entry.Caller.Function)
 	}
 
 	// Get request id
-	if reqId := logkContext.GetRequestId(options.Context); reqId != "" {
+	if reqId := entry.RequestId; reqId != "" {
 		writer.Printf("  > Request ID: %s\n", reqId)
 	}
 
+	// Get trace and span id
+	if entry.TraceId != "" {
+		writer.Printf("  > Trace ID: %s\n", entry.TraceId)
+	}
+
+	if entry.SpanId != "" {
+		writer.Printf("  > Span ID: %s\n", entry.SpanId)
+	}
+
+	// Get baggage
+	if len(entry.Baggage) > 0 {
+		if baggage, err := json.Marshal(entry.Baggage); err == nil {
+			writer.Printf("  > Baggage: %s\n", baggage)
+		}
+	}
+
 	// If error exists, then print error
-	logErr := logkOption.GetError(options, logkOption.ErrorKey)
-	if logErr != nil && lv <= level.Error {
-		writer.Printf("  > Error: %s\n", logErr)
+	if entry.Error != nil && lv <= level.Error {
+		writer.Printf("  > Error: %s\n", entry.Error)
+	}
+
+	// Get stack trace
+	if entry.StackTrace != "" {
+		writer.Printf("  > Stack Trace:\n%s", indentStackTrace(entry.StackTrace))
 	}
 
-	meta := options.Metadata
-	if meta != nil && len(meta) > 0 {
-		// Serialize to json
-		metadata, err := json.Marshal(meta)
-		// If not error, then print
-		if err == nil {
+	if len(entry.Metadata) > 0 {
+		// Serialize to json, and print if not dropped
+		if metadata, ok := entry.MetadataString(); ok {
 			writer.Printf("  > Metadata: %s\n", metadata)
 		}
 	}
 }
+
+// indentStackTrace indents each line of stack trace under the entry
+func indentStackTrace(stack string) string {
+	var sb strings.Builder
+	for _, line := range strings.SplitAfter(stack, "\n") {
+		if line != "" {
+			sb.WriteString("    ")
+			sb.WriteString(line)
+		}
+	}
+	return sb.String()
+}
@@ -0,0 +1,164 @@
+package logk
+
+import (
+	"bytes"
+	"sync"
+	"testing"
+
+	"github.com/go-konsultin/logk/level"
+	logkOption "github.com/go-konsultin/logk/option"
+)
+
+func TestChildFollowsParentLevel(t *testing.T) {
+	parent := NewStdLogger(NewJSONPrinter(nil), logkOption.Level(level.Info))
+	child := parent.NewChild(logkOption.WithNamespace("child")).(*StdLogger)
+	sibling := parent.NewChild(logkOption.WithNamespace("sibling")).(*StdLogger)
+	grandchild := child.With(logkOption.AddMetadata("k", "v")).(*StdLogger)
+	own := parent.NewChild(logkOption.Level(level.Warn)).(*StdLogger)
+
+	check := func(name string, l *StdLogger, want level.LogLevel) {
+		t.Helper()
+		if got := l.GetLevel(); got != want {
+			t.Errorf("%s level = %s, want %s", name, level.String(got), level.String(want))
+		}
+	}
+
+	// Level of parent reaches children that don't set their own level
+	parent.SetLevel(level.Debug)
+	check("child", child, level.Debug)
+	check("grandchild", grandchild, level.Debug)
+	check("sibling", sibling, level.Debug)
+	check("child with own level", own, level.Warn)
+
+	// Level set on child doesn't affect parent and siblings, but reaches its own children
+	child.SetLevel(level.Error)
+	check("parent", parent, level.Debug)
+	check("sibling", sibling, level.Debug)
+	check("child", child, level.Error)
+	check("grandchild", grandchild, level.Error)
+
+	// Child that has set its level no longer follows parent
+	parent.SetLevel(level.Trace)
+	check("child", child, level.Error)
+	check("sibling", sibling, level.Trace)
+
+	restore := parent.WithTemporaryLevel(level.Warn)
+	check("sibling in parent scope", sibling, level.Warn)
+	restore()
+	check("sibling after parent scope", sibling, level.Trace)
+}
+
+func TestWithTemporaryLevelNestedScopes(t *testing.T) {
+	logger := NewStdLogger(NewJSONPrinter(nil), logkOption.Level(level.Warn))
+
+	check := func(name string, want level.LogLevel) {
+		t.Helper()
+		if got := logger.GetLevel(); got != want {
+			t.Errorf("%s: level = %s, want %s", name, level.String(got), level.String(want))
+		}
+	}
+
+	restoreDebug := logger.WithTemporaryLevel(level.Debug)
+	restoreTrace := logger.WithTemporaryLevel(level.Trace)
+	check("inner scope", level.Trace)
+
+	restoreTrace()
+	check("inner scope restored", level.Debug)
+
+	// Restoring twice doesn't restore the outer scope
+	restoreTrace()
+	check("inner scope restored twice", level.Debug)
+
+	restoreDebug()
+	check("outer scope restored", level.Warn)
+
+	// Scopes restored out of order bring back the most recent scope that is still active
+	restoreInfo := logger.WithTemporaryLevel(level.Info)
+	restoreTrace = logger.WithTemporaryLevel(level.Trace)
+	restoreInfo()
+	check("outer scope restored first", level.Trace)
+
+	// Level set within scope takes effect after the last scope is restored
+	logger.SetLevel(level.Error)
+	check("set within scope", level.Trace)
+	restoreTrace()
+	check("all scopes restored", level.Error)
+}
+
+func TestWithTemporaryLevelConcurrent(t *testing.T) {
+	logger := NewStdLogger(NewJSONPrinter(nil), logkOption.Level(level.Warn))
+
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 100; j++ {
+				restoreDebug := logger.WithTemporaryLevel(level.Debug)
+				restoreTrace := logger.WithTemporaryLevel(level.Trace)
+				_ = logger.Enabled(level.Debug)
+				restoreTrace()
+				restoreDebug()
+			}
+		}()
+	}
+	wg.Wait()
+
+	if got := logger.GetLevel(); got != level.Warn {
+		t.Errorf("level = %s after all scopes are restored, want warn", level.String(got))
+	}
+}
+
+func TestStdLogPrinterLevelPrefix(t *testing.T) {
+	bracketPrefix := map[level.LogLevel]string{level.Info: "[INFO] ", level.Warn: "[WARN] "}
+	shortPrefix := map[level.LogLevel]string{level.Info: "I: "}
+
+	var bracketBuf, shortBuf bytes.Buffer
+	bracket := NewStdLogger(NewStdLogPrinter(&bracketBuf, 0, WithLevelPrefix(bracketPrefix)),
+		logkOption.Level(level.Info))
+	short := NewStdLogger(NewStdLogPrinter(&shortBuf, 0, WithLevelPrefix(shortPrefix)), logkOption.Level(level.Info))
+
+	// Prefix map is copied, so changes of caller don't reach printer
+	shortPrefix[level.Info] = "changed: "
+
+	bracket.Info("started")
+	bracket.Warn("slow")
+	short.Info("started")
+	short.Warn("slow")
+
+	if got, want := bracketBuf.String(), "[INFO] started\n[WARN] slow\n"; got != want {
+		t.Errorf("bracket printer wrote %q, want %q", got, want)
+	}
+
+	// Levels absent from map fall back to default prefix
+	if got, want := shortBuf.String(), "I: started\n"+stdLevelPrefix[level.Warn]+"slow\n"; got != want {
+		t.Errorf("short printer wrote %q, want %q", got, want)
+	}
+}
+
+func TestWithTemporaryLevelScopedToLogger(t *testing.T) {
+	parent := NewStdLogger(NewJSONPrinter(nil), logkOption.Level(level.Info))
+	request := parent.NewChild(logkOption.WithNamespace("request")).(*StdLogger)
+	sibling := parent.NewChild(logkOption.WithNamespace("sibling")).(*StdLogger)
+	nested := request.NewChild(logkOption.WithNamespace("nested")).(*StdLogger)
+
+	restore := request.WithTemporaryLevel(level.Debug)
+	if !request.Enabled(level.Debug) || !nested.Enabled(level.Debug) {
+		t.Error("temporary level doesn't apply to logger and its children")
+	}
+	if parent.Enabled(level.Debug) || sibling.Enabled(level.Debug) {
+		t.Error("temporary level of child reaches parent or sibling")
+	}
+
+	// Level of parent changed within scope applies to child once scope is restored
+	parent.SetLevel(level.Warn)
+	if !request.Enabled(level.Debug) {
+		t.Error("level of parent overrides active scope")
+	}
+	restore()
+	for name, l := range map[string]*StdLogger{"request": request, "nested": nested, "sibling": sibling} {
+		if got := l.GetLevel(); got != level.Warn {
+			t.Errorf("%s level = %s after scope, want warn", name, level.String(got))
+		}
+	}
+}
@@ -0,0 +1,91 @@
+package logk
+
+import (
+	"io"
+	"sync"
+	"sync/atomic"
+
+	"github.com/go-konsultin/logk/level"
+	logkOption "github.com/go-konsultin/logk/option"
+)
+
+// SwapPrinter forwards entries to a printer that can be replaced at runtime, e.g. on reload of configuration.
+// Loggers and children that share it write to the new printer right after Swap
+type SwapPrinter struct {
+	current atomic.Pointer[swapTarget]
+}
+
+// swapTarget is a printer installed in SwapPrinter. mu is read-locked while an entry is printed, so Swap can wait
+// until in-flight entries are written before it returns the retired printer
+type swapTarget struct {
+	printer Printer
+	mu      sync.RWMutex
+	retired bool
+}
+
+// NewSwapPrinter creates a printer that forwards entries to p until it's swapped
+func NewSwapPrinter(p Printer) *SwapPrinter {
+	s := &SwapPrinter{}
+	s.current.Store(&swapTarget{printer: p})
+	return s
+}
+
+// Swap installs p and returns the previous printer once entries that are being printed by it are written. The
+// previous printer is not flushed or closed, so caller can do it without dropping entries
+func (s *SwapPrinter) Swap(p Printer) Printer {
+	old := s.current.Swap(&swapTarget{printer: p})
+
+	// Wait for in-flight entries, later entries are retried on the new printer
+	old.mu.Lock()
+	old.retired = true
+	old.mu.Unlock()
+	return old.printer
+}
+
+// Printer returns the current printer
+func (s *SwapPrinter) Printer() Printer {
+	return s.current.Load().printer
+}
+
+func (s *SwapPrinter) Print(namespace string, lv level.LogLevel, msg string, options *logkOption.Options) {
+	s.do(func(p Printer) error {
+		p.Print(namespace, lv, msg, options)
+		return nil
+	})
+}
+
+// Flush flushes the current printer if it implements Flusher
+func (s *SwapPrinter) Flush() error {
+	return s.do(func(p Printer) error {
+		if f, ok := p.(Flusher); ok {
+			return f.Flush()
+		}
+		return nil
+	})
+}
+
+// Close closes the current printer if it implements io.Closer
+func (s *SwapPrinter) Close() error {
+	return s.do(func(p Printer) error {
+		if c, ok := p.(io.Closer); ok {
+			return c.Close()
+		}
+		return nil
+	})
+}
+
+// do calls fn with the current printer, and retries if it's retired by Swap in the meantime
+func (s *SwapPrinter) do(fn func(p Printer) error) error {
+	for {
+		t := s.current.Load()
+		t.mu.RLock()
+		if t.retired {
+			t.mu.RUnlock()
+			continue
+		}
+
+		err := fn(t.printer)
+		t.mu.RUnlock()
+		return err
+	}
+}
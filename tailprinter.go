@@ -0,0 +1,201 @@
+package logk
+
+import (
+	"context"
+	"io"
+	"sync"
+
+	logkContext "github.com/go-konsultin/logk/context"
+	"github.com/go-konsultin/logk/level"
+	logkOption "github.com/go-konsultin/logk/option"
+)
+
+// Default tail printer options
+const (
+	defaultTailSize         = 100
+	defaultTailLevel        = level.Info
+	defaultTailTriggerLevel = level.Error
+)
+
+// TailKey is metadata key that marks entries written from tail buffer, so they can be told apart from entries
+// written as they happened
+const TailKey = "tail"
+
+type TailOptions struct {
+	// Level is the least severe level that is written as usual. Less severe entries are buffered in tail context,
+	// and discarded outside of it
+	Level level.LogLevel
+	// TriggerLevel is the least severe level that writes buffered entries of its context before itself
+	TriggerLevel level.LogLevel
+}
+
+type TailOption = func(*TailOptions)
+
+func WithTailLevel(lv level.LogLevel) TailOption {
+	return func(o *TailOptions) {
+		o.Level = lv
+	}
+}
+
+func WithTailTriggerLevel(lv level.LogLevel) TailOption {
+	return func(o *TailOptions) {
+		o.TriggerLevel = lv
+	}
+}
+
+// tailBuffer is ring buffer of entries of a context
+type tailBuffer struct {
+	mu      sync.Mutex
+	entries []tailEntry
+	// next is index where next entry is stored, once buffer is full
+	next    int
+	dropped int
+}
+
+type tailEntry struct {
+	namespace string
+	level     level.LogLevel
+	msg       string
+	options   *logkOption.Options
+}
+
+func (b *tailBuffer) add(e tailEntry) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if len(b.entries) < cap(b.entries) {
+		b.entries = append(b.entries, e)
+		return
+	}
+
+	// Overwrite the oldest entry
+	b.entries[b.next] = e
+	b.next = (b.next + 1) % len(b.entries)
+	b.dropped++
+}
+
+// drain returns buffered entries, the oldest first, and number of entries that are overwritten, then empties buffer
+func (b *tailBuffer) drain() ([]tailEntry, int) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	entries := make([]tailEntry, 0, len(b.entries))
+	entries = append(entries, b.entries[b.next:]...)
+	entries = append(entries, b.entries[:b.next]...)
+	dropped := b.dropped
+
+	clear(b.entries)
+	b.entries = b.entries[:0]
+	b.next = 0
+	b.dropped = 0
+	return entries, dropped
+}
+
+// NewTailContext returns a copy of ctx that buffers up to size entries for TailPrinter, e.g. entries of a request.
+// When buffer is full, the oldest entry is discarded. If size is zero or less, the default of 100 is used
+func NewTailContext(ctx context.Context, size int) context.Context {
+	if ctx == nil {
+		return ctx
+	}
+
+	if size <= 0 {
+		size = defaultTailSize
+	}
+	return context.WithValue(ctx, logkContext.TailKey, &tailBuffer{entries: make([]tailEntry, 0, size)})
+}
+
+// DiscardTail discards entries buffered in ctx, e.g. when a long-lived operation completes a step successfully
+func DiscardTail(ctx context.Context) {
+	if b := tailFromContext(ctx); b != nil {
+		b.drain()
+	}
+}
+
+func tailFromContext(ctx context.Context) *tailBuffer {
+	if ctx == nil {
+		return nil
+	}
+	b, _ := ctx.Value(logkContext.TailKey).(*tailBuffer)
+	return b
+}
+
+// TailPrinter holds entries that are less severe than Level, by default DEBUG and TRACE, in ring buffer of their
+// context, which is created with NewTailContext. They are written only if an entry in TriggerLevel or more severe
+// level is written in the same context, before that entry, and are discarded otherwise. Failed requests are logged
+// in detail, without writing DEBUG entries of requests that succeed. Written entries keep their timestamp and are
+// marked with "tail" metadata. Logger level must enable buffered levels, so they reach printer:
+//
+//	logger := logk.New().Level(level.Trace).Printer(logk.NewTailPrinter(printer)).Build()
+//	ctx = logk.NewTailContext(ctx, 200)
+//
+// Entries that are less severe than Level and have no tail context are discarded, so the logger writes as if its
+// level was Level outside of tail contexts
+type TailPrinter struct {
+	printer Printer
+	options TailOptions
+}
+
+func NewTailPrinter(printer Printer, args ...TailOption) *TailPrinter {
+	o := TailOptions{
+		Level:        defaultTailLevel,
+		TriggerLevel: defaultTailTriggerLevel,
+	}
+	for _, fn := range args {
+		fn(&o)
+	}
+
+	// Init printer if nil
+	if printer == nil {
+		printer = NewStdLogPrinter(nil, 0)
+	}
+
+	return &TailPrinter{printer: printer, options: o}
+}
+
+func (p *TailPrinter) Print(namespace string, lv level.LogLevel, msg string, options *logkOption.Options) {
+	b := tailFromContext(options.Context)
+
+	if lv > p.options.Level {
+		if b != nil {
+			// Retain a copy, as options are reused by logger once Print returns. Stamp time on call, as entry is
+			// printed later
+			options = options.Clone()
+			stampTime(options)
+			b.add(tailEntry{namespace: namespace, level: lv, msg: msg, options: options})
+		}
+		return
+	}
+
+	if b != nil && lv <= p.options.TriggerLevel {
+		entries, dropped := b.drain()
+		if dropped > 0 {
+			internalWarn("tail buffer discarded %d entries before %s entry, consider a larger buffer", dropped, level.String(lv))
+		}
+		for _, e := range entries {
+			// Options are a copy owned by buffer, so they are marked in place
+			if e.options.Metadata == nil {
+				e.options.Metadata = make(map[string]interface{}, 1)
+			}
+			e.options.Metadata[TailKey] = true
+			p.printer.Print(e.namespace, e.level, e.msg, e.options)
+		}
+	}
+
+	p.printer.Print(namespace, lv, msg, options)
+}
+
+// Flush flushes underlying printer if it implements Flusher. Buffered entries are not written
+func (p *TailPrinter) Flush() error {
+	if f, ok := p.printer.(Flusher); ok {
+		return f.Flush()
+	}
+	return nil
+}
+
+// Close closes underlying printer if it implements io.Closer
+func (p *TailPrinter) Close() error {
+	if c, ok := p.printer.(io.Closer); ok {
+		return c.Close()
+	}
+	return nil
+}
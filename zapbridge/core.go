@@ -0,0 +1,165 @@
+package logkZap
+
+import (
+	"strings"
+
+	"github.com/go-konsultin/logk"
+	logkIngest "github.com/go-konsultin/logk/internal/ingest"
+	"github.com/go-konsultin/logk/level"
+	logkOption "github.com/go-konsultin/logk/option"
+	"go.uber.org/zap/zapcore"
+)
+
+// errorKey is the key of zap.Error field
+const errorKey = "error"
+
+// keys are keys of zap production and development encoder configs
+var keys = logkIngest.Keys{
+	Time:       []string{"ts", "T"},
+	Level:      []string{"level", "L"},
+	Message:    []string{"msg", "M"},
+	Namespace:  []string{"logger", "N"},
+	Caller:     []string{"caller", "C"},
+	Function:   []string{"function", "F"},
+	Error:      []string{"error"},
+	StackTrace: []string{"stacktrace", "S"},
+}
+
+// WriteSyncer is a zapcore.WriteSyncer that converts entries encoded by zapcore.NewJSONEncoder to logk entries and
+// prints them with a logk Printer. It lets zap loggers of dependencies share logk printers:
+//
+//	core := zapcore.NewCore(zapcore.NewJSONEncoder(zap.NewProductionEncoderConfig()),
+//		logkZap.NewWriteSyncer(printer), zapcore.DebugLevel)
+//	zap.New(core)
+//
+// Encoder keys of production and development configs are recognized. Logger name becomes namespace, "error" field
+// becomes entry error and other fields are written as metadata. Level filtering is done by zap core
+type WriteSyncer struct {
+	*logkIngest.Writer
+}
+
+// NewWriteSyncer creates zapcore.WriteSyncer backed by printer. If printer is nil, std printer is used
+func NewWriteSyncer(printer logk.Printer) *WriteSyncer {
+	return &WriteSyncer{Writer: logkIngest.NewWriter(printer, keys, parseLevel)}
+}
+
+// parseLevel converts zap level name in lowercase or capital encoding to logk level
+func parseLevel(name string) (level.LogLevel, bool) {
+	switch strings.ToLower(name) {
+	case "debug":
+		return level.Debug, true
+	case "info":
+		return level.Info, true
+	case "warn":
+		return level.Warn, true
+	case "error", "dpanic":
+		return level.Error, true
+	case "panic", "fatal":
+		return level.Fatal, true
+	default:
+		return 0, false
+	}
+}
+
+// Core is a zapcore.Core that prints entries with a logk Printer, so zap loggers of dependencies share logk pipeline
+// without encoding entries twice:
+//
+//	zap.New(logkZap.NewCore(printer, zapcore.DebugLevel))
+//
+// Logger name becomes namespace, zap.Error field becomes entry error and other fields are written as metadata.
+// Objects and arrays are written as nested metadata
+type Core struct {
+	zapcore.LevelEnabler
+	printer logk.Printer
+	fields  []zapcore.Field
+}
+
+// NewCore creates zapcore.Core backed by printer that is enabled at enab. If printer is nil, std printer is used
+func NewCore(printer logk.Printer, enab zapcore.LevelEnabler) *Core {
+	// Init printer if nil
+	if printer == nil {
+		printer = logk.NewStdLogPrinter(nil, 0)
+	}
+
+	return &Core{LevelEnabler: enab, printer: printer}
+}
+
+// With creates a core that writes fields with every entry
+func (c *Core) With(fields []zapcore.Field) zapcore.Core {
+	// Copy fields, so siblings don't share backing array
+	merged := make([]zapcore.Field, 0, len(c.fields)+len(fields))
+	merged = append(merged, c.fields...)
+	merged = append(merged, fields...)
+	return &Core{LevelEnabler: c.LevelEnabler, printer: c.printer, fields: merged}
+}
+
+func (c *Core) Check(entry zapcore.Entry, ce *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	if c.Enabled(entry.Level) {
+		return ce.AddCore(entry, c)
+	}
+	return ce
+}
+
+func (c *Core) Write(entry zapcore.Entry, fields []zapcore.Field) error {
+	lv := toLogkLevel(entry.Level)
+	options := logkOption.NewOptions()
+	options.Level = lv
+	options.Values[logkOption.TimeKey] = entry.Time
+
+	if entry.Caller.Defined {
+		options.Values[logkOption.CallerKey] = logkOption.Caller{
+			File:     entry.Caller.File,
+			Line:     entry.Caller.Line,
+			Function: entry.Caller.Function,
+		}
+	}
+
+	if entry.Stack != "" {
+		options.Values[logkOption.StackTraceKey] = entry.Stack
+	}
+
+	enc := zapcore.NewMapObjectEncoder()
+	for _, fields := range [][]zapcore.Field{c.fields, fields} {
+		for _, f := range fields {
+			// Keep error value, so printers can unwrap it
+			if f.Type == zapcore.ErrorType && f.Key == errorKey {
+				if err, ok := f.Interface.(error); ok {
+					options.Values[logkOption.ErrorKey] = err
+					continue
+				}
+			}
+			f.AddTo(enc)
+		}
+	}
+
+	if len(enc.Fields) > 0 {
+		options.Metadata = enc.Fields
+	}
+
+	c.printer.Print(entry.LoggerName, lv, entry.Message, options)
+	return nil
+}
+
+// Sync flushes printer if it implements logk.Flusher
+func (c *Core) Sync() error {
+	if f, ok := c.printer.(logk.Flusher); ok {
+		return f.Flush()
+	}
+	return nil
+}
+
+// toLogkLevel converts zap level to logk level. DPANIC is written as ERROR, PANIC as FATAL
+func toLogkLevel(lv zapcore.Level) level.LogLevel {
+	switch {
+	case lv < zapcore.InfoLevel:
+		return level.Debug
+	case lv == zapcore.InfoLevel:
+		return level.Info
+	case lv == zapcore.WarnLevel:
+		return level.Warn
+	case lv <= zapcore.DPanicLevel:
+		return level.Error
+	default:
+		return level.Fatal
+	}
+}
@@ -0,0 +1,86 @@
+package logkZap
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/go-konsultin/logk/level"
+	logkTest "github.com/go-konsultin/logk/logktest"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+type user struct {
+	id   int
+	name string
+}
+
+func (u user) MarshalLogObject(enc zapcore.ObjectEncoder) error {
+	enc.AddInt("id", u.id)
+	enc.AddString("name", u.name)
+	return nil
+}
+
+func TestCore(t *testing.T) {
+	r := logkTest.NewRecorder()
+	err := errors.New("boom")
+
+	logger := zap.New(NewCore(r, zapcore.InfoLevel), zap.AddCaller()).Named("svc").With(zap.Int("attempt", 2))
+	logger.Debug("skipped")
+	logger.Error("failed", zap.Error(err), zap.Object("user", user{id: 7, name: "ann"}),
+		zap.Strings("tags", []string{"a", "b"}))
+
+	if r.Len() != 1 {
+		t.Fatalf("len = %d, want 1", r.Len())
+	}
+
+	e := r.LastEntry()
+	if e.Level != level.Error || e.Message != "failed" || e.Namespace != "svc" {
+		t.Errorf("entry = %s %q %q, want error \"failed\" \"svc\"", level.String(e.Level), e.Message, e.Namespace)
+	}
+	if !errors.Is(e.Error, err) {
+		t.Errorf("error = %v, want %v", e.Error, err)
+	}
+	if _, ok := e.Metadata["error"]; ok {
+		t.Error("error is written as metadata")
+	}
+	if e.Caller.File == "" {
+		t.Error("caller is not set")
+	}
+
+	r.AssertContainsField(t, "attempt", 2)
+	r.AssertContainsField(t, "user.id", 7)
+	r.AssertContainsField(t, "user.name", "ann")
+	r.AssertContainsField(t, "tags", []interface{}{"a", "b"})
+}
+
+func TestCoreWithDoesNotShareFields(t *testing.T) {
+	r := logkTest.NewRecorder()
+	parent := NewCore(r, zapcore.DebugLevel).With([]zapcore.Field{zap.Int("a", 1)})
+
+	first := zap.New(parent.With([]zapcore.Field{zap.Int("b", 2)}))
+	second := zap.New(parent.With([]zapcore.Field{zap.Int("c", 3)}))
+	first.Info("first")
+	second.Info("second")
+
+	if _, ok := r.LastEntry().Metadata["b"]; ok {
+		t.Errorf("second entry metadata = %v, want no b", r.LastEntry().Metadata)
+	}
+}
+
+func TestToLogkLevel(t *testing.T) {
+	cases := map[zapcore.Level]level.LogLevel{
+		zapcore.DebugLevel:  level.Debug,
+		zapcore.InfoLevel:   level.Info,
+		zapcore.WarnLevel:   level.Warn,
+		zapcore.ErrorLevel:  level.Error,
+		zapcore.DPanicLevel: level.Error,
+		zapcore.PanicLevel:  level.Fatal,
+		zapcore.FatalLevel:  level.Fatal,
+	}
+	for zl, want := range cases {
+		if got := toLogkLevel(zl); got != want {
+			t.Errorf("%s: got %s, want %s", zl, level.String(got), level.String(want))
+		}
+	}
+}
@@ -0,0 +1,196 @@
+package logkZap
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	"github.com/go-konsultin/logk"
+	logkContext "github.com/go-konsultin/logk/context"
+	"github.com/go-konsultin/logk/level"
+	logkOption "github.com/go-konsultin/logk/option"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// callerSkip skips Logger method and log, so zap reports call site of Logger method
+const callerSkip = 2
+
+// Logger implements logk.Logger by writing to a zap logger, so code using logk can share zap cores and level.
+// Namespace is written as zap logger name, metadata as fields and error as zap.Error field. TRACE entries are written
+// as DEBUG, as zap doesn't define a lower level. FATAL entries are written with zap Fatal, which lets zap exit the
+// process
+type Logger struct {
+	root      *zap.Logger
+	logger    *zap.Logger
+	namespace string
+	ctx       context.Context
+	fields    []zap.Field
+}
+
+// NewLogger creates logk.Logger backed by logger
+func NewLogger(logger *zap.Logger) *Logger {
+	root := logger.WithOptions(zap.AddCallerSkip(callerSkip))
+	return &Logger{root: root, logger: root}
+}
+
+func (l *Logger) Fatal(msg string, args ...logkOption.SetterFunc) {
+	l.log(level.Fatal, msg, logkOption.Evaluate(args))
+}
+
+func (l *Logger) Fatalf(format string, args ...interface{}) {
+	l.log(level.Fatal, format, logkOption.NewFormatOptions(args...))
+}
+
+func (l *Logger) Error(msg string, args ...logkOption.SetterFunc) {
+	l.log(level.Error, msg, logkOption.Evaluate(args))
+}
+
+func (l *Logger) Errorf(format string, args ...interface{}) {
+	l.log(level.Error, format, logkOption.NewFormatOptions(args...))
+}
+
+func (l *Logger) Warn(msg string, args ...logkOption.SetterFunc) {
+	l.log(level.Warn, msg, logkOption.Evaluate(args))
+}
+
+func (l *Logger) Warnf(format string, args ...interface{}) {
+	l.log(level.Warn, format, logkOption.NewFormatOptions(args...))
+}
+
+func (l *Logger) Info(msg string, args ...logkOption.SetterFunc) {
+	l.log(level.Info, msg, logkOption.Evaluate(args))
+}
+
+func (l *Logger) Infof(format string, args ...interface{}) {
+	l.log(level.Info, format, logkOption.NewFormatOptions(args...))
+}
+
+func (l *Logger) Debug(msg string, args ...logkOption.SetterFunc) {
+	l.log(level.Debug, msg, logkOption.Evaluate(args))
+}
+
+func (l *Logger) Debugf(format string, args ...interface{}) {
+	l.log(level.Debug, format, logkOption.NewFormatOptions(args...))
+}
+
+func (l *Logger) Trace(msg string, args ...logkOption.SetterFunc) {
+	l.log(level.Trace, msg, logkOption.Evaluate(args))
+}
+
+func (l *Logger) Tracef(format string, args ...interface{}) {
+	l.log(level.Trace, format, logkOption.NewFormatOptions(args...))
+}
+
+// NewChild creates a child logger. Namespace replaces name of zap logger
+func (l *Logger) NewChild(args ...logkOption.SetterFunc) logk.Logger {
+	options := logkOption.Evaluate(args)
+
+	c := Logger{root: l.root, logger: l.logger, namespace: l.namespace, ctx: l.ctx, fields: l.fields}
+	if namespace := options.Namespace(); namespace != "" && namespace != l.namespace {
+		c.namespace = namespace
+		c.logger = l.root.Named(namespace).With(c.fields...)
+	}
+
+	if options.Context != nil {
+		c.ctx = options.Context
+	}
+
+	return &c
+}
+
+func (l *Logger) NewChildCtx(ctx context.Context, args ...logkOption.SetterFunc) logk.Logger {
+	if ctx != nil {
+		args = append(args, logkOption.Context(ctx))
+	}
+	return l.NewChild(args...)
+}
+
+// With creates a child logger that writes metadata set in args as fields of every entry
+func (l *Logger) With(args ...logkOption.SetterFunc) logk.Logger {
+	c := l.NewChild(args...).(*Logger)
+
+	metadata := logkOption.Evaluate(args).Metadata
+	if len(metadata) == 0 {
+		return c
+	}
+
+	// Copy fields, so siblings don't share backing array
+	added := toFields(metadata)
+	fields := make([]zap.Field, 0, len(l.fields)+len(added))
+	fields = append(fields, l.fields...)
+	c.fields = append(fields, added...)
+	c.logger = c.logger.With(added...)
+	return c
+}
+
+// Flush syncs zap logger
+func (l *Logger) Flush() error {
+	return l.logger.Sync()
+}
+
+func (l *Logger) log(lv level.LogLevel, msg string, options *logkOption.Options) {
+	ce := l.logger.Check(toZapLevel(lv), msg)
+	if ce == nil {
+		return
+	}
+
+	ctx := options.Context
+	if ctx == nil {
+		ctx = l.ctx
+	}
+
+	ce.Message = logkOption.ResolveLazy(options, msg)
+	if len(options.FmtArgs) > 0 {
+		ce.Message = fmt.Sprintf(ce.Message, options.FmtArgs...)
+	}
+
+	fields := make([]zap.Field, 0, len(options.Metadata)+4)
+	if reqId := logkContext.GetRequestId(ctx); reqId != "" {
+		fields = append(fields, zap.String(logkOption.RequestIdKey, reqId))
+	}
+
+	if traceId, spanId := logkContext.GetTrace(ctx); traceId != "" {
+		fields = append(fields, zap.String(logkOption.TraceIdKey, traceId))
+		if spanId != "" {
+			fields = append(fields, zap.String(logkOption.SpanIdKey, spanId))
+		}
+	}
+
+	if err := options.Error(); err != nil {
+		fields = append(fields, zap.Error(err))
+	}
+
+	ce.Write(append(fields, toFields(options.Metadata)...)...)
+}
+
+// toZapLevel converts logk level to zap level
+func toZapLevel(lv level.LogLevel) zapcore.Level {
+	switch lv {
+	case level.Fatal:
+		return zapcore.FatalLevel
+	case level.Error:
+		return zapcore.ErrorLevel
+	case level.Warn:
+		return zapcore.WarnLevel
+	case level.Info:
+		return zapcore.InfoLevel
+	default:
+		return zapcore.DebugLevel
+	}
+}
+
+// toFields converts metadata to zap fields sorted by key
+func toFields(metadata map[string]interface{}) []zap.Field {
+	keys := make([]string, 0, len(metadata))
+	for k := range metadata {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	fields := make([]zap.Field, 0, len(keys))
+	for _, k := range keys {
+		fields = append(fields, zap.Any(k, metadata[k]))
+	}
+	return fields
+}
@@ -0,0 +1,99 @@
+package logkZap
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"path/filepath"
+	"runtime"
+	"testing"
+
+	logkContext "github.com/go-konsultin/logk/context"
+	logkOption "github.com/go-konsultin/logk/option"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+func newTestLogger(buf *bytes.Buffer, lv zapcore.Level) *Logger {
+	enc := zapcore.NewJSONEncoder(zap.NewProductionEncoderConfig())
+	return NewLogger(zap.New(zapcore.NewCore(enc, zapcore.AddSync(buf), lv), zap.AddCaller()))
+}
+
+func decode(t *testing.T, buf *bytes.Buffer) map[string]interface{} {
+	t.Helper()
+	var line map[string]interface{}
+	if err := json.Unmarshal(buf.Bytes(), &line); err != nil {
+		t.Fatalf("invalid JSON %q: %s", buf.Bytes(), err)
+	}
+	buf.Reset()
+	return line
+}
+
+func TestLogger(t *testing.T) {
+	var buf bytes.Buffer
+	logger := newTestLogger(&buf, zapcore.DebugLevel)
+
+	ctx := logkContext.SetRequestId(context.Background(), "req-1")
+	child := logger.NewChildCtx(ctx, logkOption.WithNamespace("svc")).
+		With(logkOption.AddMetadata("attempt", 2))
+
+	_, _, line, _ := runtime.Caller(0)
+	child.Error("failed", logkOption.Error(errors.New("boom")), logkOption.AddMetadata("user", "ann"))
+
+	got := decode(t, &buf)
+	want := map[string]interface{}{
+		"level":                 "error",
+		"logger":                "svc",
+		"msg":                   "failed",
+		"error":                 "boom",
+		"attempt":               float64(2),
+		"user":                  "ann",
+		logkOption.RequestIdKey: "req-1",
+		"caller":                fmt.Sprintf("zapbridge/logger_test.go:%d", line+1),
+	}
+	for k, v := range want {
+		if got[k] != v {
+			t.Errorf("%s = %v, want %v", k, got[k], v)
+		}
+	}
+
+	logger.Infof("hello %s", "world")
+	if got := decode(t, &buf); got["msg"] != "hello world" || got["attempt"] != nil {
+		t.Errorf("entry = %v, want hello world without attempt", got)
+	}
+}
+
+func TestLoggerLevel(t *testing.T) {
+	var buf bytes.Buffer
+	logger := newTestLogger(&buf, zapcore.InfoLevel)
+
+	logger.Debug("skipped")
+	logger.Trace("skipped")
+	if buf.Len() > 0 {
+		t.Errorf("output = %q, want nothing", buf.String())
+	}
+
+	logger.Warn("written")
+	if got := decode(t, &buf); got["level"] != "warn" {
+		t.Errorf("level = %v, want warn", got["level"])
+	}
+}
+
+func TestLoggerChildNamespace(t *testing.T) {
+	var buf bytes.Buffer
+	logger := newTestLogger(&buf, zapcore.DebugLevel)
+
+	child := logger.With(logkOption.AddMetadata("a", 1)).NewChild(logkOption.WithNamespace("first")).
+		NewChild(logkOption.WithNamespace("second"))
+	child.Info("entry")
+
+	got := decode(t, &buf)
+	if got["logger"] != "second" || got["a"] != float64(1) {
+		t.Errorf("entry = %v, want logger second with a=1", got)
+	}
+	if filepath.Base(fmt.Sprint(got["caller"])) == "logger.go" {
+		t.Errorf("caller = %v", got["caller"])
+	}
+}